@@ -0,0 +1,78 @@
+// tsbs_compare_results compares the query results captured by two
+// tsbs_run_queries_ runs (see --capture-results) of the same query file,
+// e.g. against Iginx and TimescaleDB loaded with the same generated data.
+//
+// It reports queries missing from either capture and, per query type, the
+// queries whose results differ by more than the given tolerance. The exit
+// status is non-zero if the captures do not match.
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+
+	"github.com/spf13/pflag"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// Program option vars:
+var (
+	dirA          string
+	dirB          string
+	tolerance     float64
+	maxMismatches int
+)
+
+// Parse args:
+func init() {
+	pflag.StringVar(&dirA, "a", "", "First capture directory (written with --capture-results)")
+	pflag.StringVar(&dirB, "b", "", "Second capture directory (written with --capture-results)")
+	pflag.Float64Var(&tolerance, "tolerance", 1e-6, "Maximum relative difference for two values to be considered equal")
+	pflag.IntVar(&maxMismatches, "max-mismatches", 10, "Number of mismatching queries to print per query type (0 = all)")
+	pflag.Parse()
+
+	if dirA == "" || dirB == "" {
+		log.Fatal("both --a and --b capture directories are required")
+	}
+}
+
+func main() {
+	a, err := query.ReadCapturedResults(dirA)
+	if err != nil {
+		log.Fatalf("cannot read capture %s: %v", dirA, err)
+	}
+	b, err := query.ReadCapturedResults(dirB)
+	if err != nil {
+		log.Fatalf("cannot read capture %s: %v", dirB, err)
+	}
+
+	c := query.CompareResults(a, b, tolerance)
+	fmt.Printf("compared %d queries (%d only in %s, %d only in %s)\n", c.Compared, len(c.OnlyInA), dirA, len(c.OnlyInB), dirB)
+
+	labels := make([]string, 0, len(c.ByLabel))
+	for l := range c.ByLabel {
+		labels = append(labels, l)
+	}
+	sort.Strings(labels)
+	for _, l := range labels {
+		lc := c.ByLabel[l]
+		fmt.Printf("%s: %d/%d mismatched\n", l, lc.Mismatched, lc.Compared)
+	}
+
+	printed := make(map[string]int)
+	for _, m := range c.Mismatches {
+		if maxMismatches > 0 && printed[m.Label] >= maxMismatches {
+			continue
+		}
+		printed[m.Label]++
+		fmt.Printf("  query %d (%s): %s\n", m.ID, m.Label, m.Reason)
+	}
+
+	if !c.Equal() {
+		fmt.Printf("mismatching query types: %v\n", c.MismatchedLabels())
+		os.Exit(1)
+	}
+	fmt.Println("results match")
+}
@@ -56,6 +56,7 @@ func init() {
 	if err := viper.Unmarshal(&config); err != nil {
 		panic(fmt.Errorf("unable to decode config: %s", err))
 	}
+	config.DisorderSet = common.DisorderChanceSet(viper.IsSet)
 
 	profileFile = viper.GetString("profile-file")
 }
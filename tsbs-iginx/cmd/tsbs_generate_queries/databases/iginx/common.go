@@ -6,7 +6,9 @@ import (
 
 	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/devops"
 	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/utils"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/industrial"
 	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/iot"
+	dataindustrial "github.com/timescale/tsbs/pkg/data/usecases/industrial"
 	"github.com/timescale/tsbs/pkg/query"
 )
 
@@ -60,4 +62,20 @@ func (g *BaseGenerator) NewIoT(start, end time.Time, scale int) (utils.QueryGene
 	}
 
 	return iot, nil
-}
\ No newline at end of file
+}
+
+// NewIndustrial creates a new industrial use case query generator.
+func (g *BaseGenerator) NewIndustrial(start, end time.Time, scale int, layout dataindustrial.Layout) (utils.QueryGenerator, error) {
+	core, err := industrial.NewCore(start, end, scale, layout)
+
+	if err != nil {
+		return nil, err
+	}
+
+	industrial := &Industrial{
+		BaseGenerator: g,
+		Core:          core,
+	}
+
+	return industrial, nil
+}
@@ -0,0 +1,153 @@
+package iginx
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/industrial"
+	dataindustrial "github.com/timescale/tsbs/pkg/data/usecases/industrial"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// Industrial produces IginX-specific queries for all the industrial query types.
+type Industrial struct {
+	*industrial.Core
+	*BaseGenerator
+}
+
+// SingleSensorScan selects the raw readings of a random sensor of a random
+// device over an hour.
+//
+// Queries:
+// single-sensor-scan
+func (i *Industrial) SingleSensorScan(qi query.Query) {
+	interval := i.Interval.MustRandWindow(industrial.SensorScanDuration)
+	device, err := i.GetRandomDevice()
+	panicIfErr(err)
+	sensor := i.GetRandomSensor()
+
+	sql := fmt.Sprintf(`{
+  		"start_absolute": %d,
+  		"end_absolute": %d,
+  		"metrics": [
+		{
+      		"name": "%s",
+      		"tags": {
+        		"device": [
+          			"%s"
+        		],
+        		"type": [
+          			"%s"
+        		]
+      		}
+    	}]
+	}`,
+		interval.StartUnixMillis(),
+		interval.EndUnixMillis(),
+		dataindustrial.SensorName(sensor),
+		device,
+		dataindustrial.BlockName(i.Layout.Block(sensor)))
+
+	humanLabel := "Iginx single sensor scan, random device, random 1h0m0s"
+	humanDesc := fmt.Sprintf("%s: %s %s", humanLabel, interval.StartString(), device)
+	i.fillInQuery(qi, humanLabel, humanDesc, sql)
+}
+
+// DeviceSnapshot selects the last reading of every sensor of a random device.
+//
+// Queries:
+// device-snapshot
+func (i *Industrial) DeviceSnapshot(qi query.Query) {
+	device, err := i.GetRandomDevice()
+	panicIfErr(err)
+
+	// a single bucket over the whole data, so that last returns one reading
+	bucket := i.Interval.EndUnixMillis() - i.Interval.StartUnixMillis()
+
+	// a device has up to 10k sensors, so the metrics are joined once rather
+	// than appended to the query one by one
+	metrics := make([]string, 0, i.Layout.Sensors)
+	for b := 0; b < i.Layout.Blocks(); b++ {
+		for _, sensor := range i.Layout.BlockSensors(b) {
+			metrics = append(metrics, fmt.Sprintf(`
+		{
+      		"name": "%s",
+      		"aggregators": [
+			{
+          		"name": "last",
+          		"sampling": {
+            		"value": %d,
+            		"unit": "milliseconds"
+          		}
+        	}],
+      		"tags": {
+        		"device": [
+          			"%s"
+        		],
+        		"type": [
+          			"%s"
+        		]
+      		}
+    	}`,
+				dataindustrial.SensorName(sensor),
+				bucket,
+				device,
+				dataindustrial.BlockName(b)))
+		}
+	}
+	sql := fmt.Sprintf(`{
+  		"start_absolute": %d,
+  		"end_absolute": %d,
+  		"metrics": [`, i.Interval.StartUnixMillis(), i.Interval.EndUnixMillis()) + strings.Join(metrics, ",") + "]}"
+
+	humanLabel := fmt.Sprintf("Iginx device snapshot, random device, %d sensors", i.Layout.Sensors)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, device)
+	i.fillInQuery(qi, humanLabel, humanDesc, sql)
+}
+
+// CrossDeviceSensorAggregate averages a random sensor over the devices of a
+// random plant, by minute over an hour.
+//
+// Queries:
+// cross-device-sensor-agg
+func (i *Industrial) CrossDeviceSensorAggregate(qi query.Query) {
+	interval := i.Interval.MustRandWindow(industrial.SensorAggregateDuration)
+	plant := i.GetRandomPlant()
+	sensor := i.GetRandomSensor()
+
+	sql := fmt.Sprintf(`{
+  		"start_absolute": %d,
+  		"end_absolute": %d,
+  		"metrics": [
+		{
+      		"name": "%s",
+      		"aggregators": [
+			{
+          		"name": "avg",
+          		"sampling": {
+            		"value": %d,
+            		"unit": "minutes"
+          		}
+        	}],
+      		"tags": {
+        		"plant": [
+          			"%s"
+        		],
+        		"type": [
+          			"%s"
+        		]
+      		}
+    	}]
+	}`,
+		interval.StartUnixMillis(),
+		interval.EndUnixMillis(),
+		dataindustrial.SensorName(sensor),
+		int(industrial.SensorAggregateInterval/time.Minute),
+		plant,
+		dataindustrial.BlockName(i.Layout.Block(sensor)))
+
+	humanLabel := "Iginx cross-device sensor average, random plant, random 1h0m0s by 1m"
+	humanDesc := fmt.Sprintf("%s: %s %s", humanLabel, interval.StartString(), plant)
+	i.fillInQuery(qi, humanLabel, humanDesc, sql)
+}
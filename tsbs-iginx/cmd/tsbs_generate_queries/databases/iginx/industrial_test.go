@@ -0,0 +1,129 @@
+package iginx
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/rand"
+	"testing"
+	"time"
+
+	dataindustrial "github.com/timescale/tsbs/pkg/data/usecases/industrial"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+const testScale = 10
+
+func TestIndustrialQueries(t *testing.T) {
+	cases := []struct {
+		desc               string
+		fill               func(*Industrial, query.Query)
+		expectedHumanLabel string
+		expectedHumanDesc  string
+		expectedBody       string
+	}{
+		{
+			desc: "single sensor scan",
+			fill: (*Industrial).SingleSensorScan,
+
+			expectedHumanLabel: "Iginx single sensor scan, random device, random 1h0m0s",
+			expectedHumanDesc:  "Iginx single sensor scan, random device, random 1h0m0s: 2016-01-01T20:16:22Z device_9",
+			expectedBody: `{
+			"start_absolute": 1451679382646,
+			"end_absolute": 1451682982646,
+			"metrics": [{
+				"name": "sensor_1",
+				"tags": {"device": ["device_9"], "type": ["block_0"]}
+			}]
+		}`,
+		},
+		{
+			desc: "device snapshot",
+			fill: (*Industrial).DeviceSnapshot,
+
+			expectedHumanLabel: "Iginx device snapshot, random device, 3 sensors",
+			expectedHumanDesc:  "Iginx device snapshot, random device, 3 sensors: device_5",
+			expectedBody: `{
+			"start_absolute": 1451606400000,
+			"end_absolute": 1451692800000,
+			"metrics": [{
+				"name": "sensor_0",
+				"aggregators": [{"name": "last", "sampling": {"value": 86400000, "unit": "milliseconds"}}],
+				"tags": {"device": ["device_5"], "type": ["block_0"]}
+			}, {
+				"name": "sensor_1",
+				"aggregators": [{"name": "last", "sampling": {"value": 86400000, "unit": "milliseconds"}}],
+				"tags": {"device": ["device_5"], "type": ["block_0"]}
+			}, {
+				"name": "sensor_2",
+				"aggregators": [{"name": "last", "sampling": {"value": 86400000, "unit": "milliseconds"}}],
+				"tags": {"device": ["device_5"], "type": ["block_1"]}
+			}]
+		}`,
+		},
+		{
+			desc: "cross-device sensor aggregate",
+			fill: (*Industrial).CrossDeviceSensorAggregate,
+
+			expectedHumanLabel: "Iginx cross-device sensor average, random plant, random 1h0m0s by 1m",
+			expectedHumanDesc:  "Iginx cross-device sensor average, random plant, random 1h0m0s by 1m: 2016-01-01T20:16:22Z Pune",
+			expectedBody: `{
+			"start_absolute": 1451679382646,
+			"end_absolute": 1451682982646,
+			"metrics": [{
+				"name": "sensor_1",
+				"aggregators": [{"name": "avg", "sampling": {"value": 1, "unit": "minutes"}}],
+				"tags": {"plant": ["Pune"], "type": ["block_0"]}
+				}]
+		}`,
+		},
+	}
+
+	s := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	e := s.Add(24 * time.Hour)
+	layout := dataindustrial.Layout{Sensors: 3, SensorsPerBlock: 2}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			rand.Seed(123) // Setting seed for testing purposes.
+			b := BaseGenerator{}
+			dq, err := b.NewIndustrial(s, e, testScale, layout)
+			if err != nil {
+				t.Fatalf("Error while creating industrial generator")
+			}
+			q := dq.GenerateEmptyQuery()
+			c.fill(dq.(*Industrial), q)
+
+			verifyQuery(t, q, c.expectedHumanLabel, c.expectedHumanDesc, c.expectedBody)
+		})
+	}
+}
+
+func verifyQuery(t *testing.T, q query.Query, humanLabel, humanDesc, body string) {
+	hq, ok := q.(*query.HTTP)
+	if !ok {
+		t.Fatal("Filled query is not *query.HTTP type")
+	}
+
+	if got := string(hq.HumanLabel); got != humanLabel {
+		t.Errorf("incorrect human label:\ngot\n%s\nwant\n%s", got, humanLabel)
+	}
+	if got := string(hq.HumanDescription); got != humanDesc {
+		t.Errorf("incorrect human description:\ngot\n%s\nwant\n%s", got, humanDesc)
+	}
+	if got := string(hq.Method); got != "POST" {
+		t.Errorf("incorrect method: got %s want POST", got)
+	}
+	if got := string(hq.Path); got != "/api/v1/datapoints/query" {
+		t.Errorf("incorrect path: got %s want /api/v1/datapoints/query", got)
+	}
+	// the bodies are compared without the whitespace of their JSON
+	var got, want bytes.Buffer
+	if err := json.Compact(&got, hq.Body); err != nil {
+		t.Fatalf("body is not valid JSON: %v\n%s", err, hq.Body)
+	}
+	if err := json.Compact(&want, []byte(body)); err != nil {
+		t.Fatalf("expected body is not valid JSON: %v", err)
+	}
+	if got.String() != want.String() {
+		t.Errorf("incorrect body:\ngot\n%s\nwant\n%s", got.String(), want.String())
+	}
+}
@@ -512,7 +512,7 @@ func (i *IoT) DailyTruckActivity(qi query.Query) {
 			}
 		}]
 		}
-	`,start,end,i.GetRandomFleet())
+	`, start, end)
 
 	humanLabel := "Iginx daily truck activity per fleet per model"
 	humanDesc := humanLabel
@@ -548,7 +548,7 @@ func (i *IoT) TruckBreakdownFrequency(qi query.Query) {
 			}
 		}]
 		}
-	`,start,end,i.GetRandomFleet())
+	`, start, end)
 
 	humanLabel := "Iginx truck breakdown frequency per model"
 	humanDesc := humanLabel
@@ -564,3 +564,83 @@ func tenMinutePeriods(minutesPerHour float64, duration time.Duration) int {
 	leftover := minutesPerHour * duration.Hours()
 	return int((durationMinutes - leftover) / 10)
 }
+
+// boundingBoxMetric returns a metric of the readings of each truck, keeping
+// the values between min and max. The filter aggregator keeps the points that
+// match its operation.
+func boundingBoxMetric(metric string, min, max float64) string {
+	return fmt.Sprintf(`
+		{
+			"name":"%s",
+			"aggregators":[
+				{
+					"name":"filter",
+					"filter_op":"gte",
+					"threshold":"%f"
+				},
+				{
+					"name":"filter",
+					"filter_op":"lte",
+					"threshold":"%f"
+				}
+			],
+			"group_by":[
+				{
+					"name":"tag",
+					"tags":["name"]
+				}
+			],
+			"tags":{
+				"type":["%s"]
+			}
+		}`, metric, min, max, iotReadingsTable)
+}
+
+// TrucksInBoundingBox finds the trucks that drove in a random bounding box
+// in the last hour.
+func (i *IoT) TrucksInBoundingBox(qi query.Query) {
+	// not all implemented limited by iginx sql grammar: the latitudes and
+	// longitudes inside the box are matched by truck and time by the client
+	interval := i.Interval.MustRandWindow(iot.BoundingBoxDuration)
+	box := i.GetRandomBoundingBox()
+
+	json := fmt.Sprintf(`
+		{
+		"start_absolute": %d,
+		"end_absolute": %d,
+		"metrics": [%s,%s]
+		}
+	`, interval.StartUnixMillis(), interval.EndUnixMillis(),
+		boundingBoxMetric("latitude", box.MinLatitude, box.MaxLatitude),
+		boundingBoxMetric("longitude", box.MinLongitude, box.MaxLongitude))
+
+	humanLabel := "Iginx trucks in bounding box"
+	humanDesc := fmt.Sprintf("%s: %s in last hour", humanLabel, box)
+
+	i.fillInQuery(qi, humanLabel, humanDesc, json)
+}
+
+// StopsInBoundingBox finds the readings of trucks stopped in a random bounding
+// box in the last hour.
+func (i *IoT) StopsInBoundingBox(qi query.Query) {
+	// not all implemented limited by iginx sql grammar: the stopped readings
+	// inside the box are matched by truck and time by the client
+	interval := i.Interval.MustRandWindow(iot.BoundingBoxDuration)
+	box := i.GetRandomBoundingBox()
+
+	json := fmt.Sprintf(`
+		{
+		"start_absolute": %d,
+		"end_absolute": %d,
+		"metrics": [%s,%s,%s]
+		}
+	`, interval.StartUnixMillis(), interval.EndUnixMillis(),
+		boundingBoxMetric("latitude", box.MinLatitude, box.MaxLatitude),
+		boundingBoxMetric("longitude", box.MinLongitude, box.MaxLongitude),
+		boundingBoxMetric("velocity", 0, 0))
+
+	humanLabel := "Iginx stops in bounding box"
+	humanDesc := fmt.Sprintf("%s: %s in last hour", humanLabel, box)
+
+	i.fillInQuery(qi, humanLabel, humanDesc, json)
+}
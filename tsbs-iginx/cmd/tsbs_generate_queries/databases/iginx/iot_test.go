@@ -0,0 +1,101 @@
+package iginx
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+func TestIoTBoundingBoxQueries(t *testing.T) {
+	cases := []struct {
+		desc               string
+		fill               func(*IoT, query.Query)
+		expectedHumanLabel string
+		expectedHumanDesc  string
+		expectedBody       string
+	}{
+		{
+			desc: "trucks in bounding box",
+			fill: (*IoT).TrucksInBoundingBox,
+
+			expectedHumanLabel: "Iginx trucks in bounding box",
+			expectedHumanDesc:  "Iginx trucks in bounding box: (30.42, -109.25)-(32.42, -107.25) in last hour",
+			expectedBody: `{
+			"start_absolute": 1451679382646,
+			"end_absolute": 1451682982646,
+			"metrics": [{
+				"name": "latitude",
+				"aggregators": [
+					{"name": "filter", "filter_op": "gte", "threshold": "30.419589"},
+					{"name": "filter", "filter_op": "lte", "threshold": "32.419589"}
+				],
+				"group_by": [{"name": "tag", "tags": ["name"]}],
+				"tags": {"type": ["readings"]}
+			}, {
+				"name": "longitude",
+				"aggregators": [
+					{"name": "filter", "filter_op": "gte", "threshold": "-109.251312"},
+					{"name": "filter", "filter_op": "lte", "threshold": "-107.251312"}
+				],
+				"group_by": [{"name": "tag", "tags": ["name"]}],
+				"tags": {"type": ["readings"]}
+			}]
+		}`,
+		},
+		{
+			desc: "stops in bounding box",
+			fill: (*IoT).StopsInBoundingBox,
+
+			expectedHumanLabel: "Iginx stops in bounding box",
+			expectedHumanDesc:  "Iginx stops in bounding box: (30.42, -109.25)-(32.42, -107.25) in last hour",
+			expectedBody: `{
+			"start_absolute": 1451679382646,
+			"end_absolute": 1451682982646,
+			"metrics": [{
+				"name": "latitude",
+				"aggregators": [
+					{"name": "filter", "filter_op": "gte", "threshold": "30.419589"},
+					{"name": "filter", "filter_op": "lte", "threshold": "32.419589"}
+				],
+				"group_by": [{"name": "tag", "tags": ["name"]}],
+				"tags": {"type": ["readings"]}
+			}, {
+				"name": "longitude",
+				"aggregators": [
+					{"name": "filter", "filter_op": "gte", "threshold": "-109.251312"},
+					{"name": "filter", "filter_op": "lte", "threshold": "-107.251312"}
+				],
+				"group_by": [{"name": "tag", "tags": ["name"]}],
+				"tags": {"type": ["readings"]}
+			}, {
+				"name": "velocity",
+				"aggregators": [
+					{"name": "filter", "filter_op": "gte", "threshold": "0.000000"},
+					{"name": "filter", "filter_op": "lte", "threshold": "0.000000"}
+				],
+				"group_by": [{"name": "tag", "tags": ["name"]}],
+				"tags": {"type": ["readings"]}
+			}]
+		}`,
+		},
+	}
+
+	s := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	e := s.Add(24 * time.Hour)
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			rand.Seed(123) // Setting seed for testing purposes.
+			b := BaseGenerator{}
+			dq, err := b.NewIoT(s, e, testScale)
+			if err != nil {
+				t.Fatalf("Error while creating iot generator")
+			}
+			q := dq.GenerateEmptyQuery()
+			c.fill(dq.(*IoT), q)
+
+			verifyQuery(t, q, c.expectedHumanLabel, c.expectedHumanDesc, c.expectedBody)
+		})
+	}
+}
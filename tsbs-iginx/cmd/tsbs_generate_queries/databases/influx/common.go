@@ -6,8 +6,10 @@ import (
 	"time"
 
 	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/devops"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/industrial"
 	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/iot"
 	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/utils"
+	dataindustrial "github.com/timescale/tsbs/pkg/data/usecases/industrial"
 	"github.com/timescale/tsbs/pkg/query"
 )
 
@@ -64,3 +66,19 @@ func (g *BaseGenerator) NewIoT(start, end time.Time, scale int) (utils.QueryGene
 
 	return devops, nil
 }
+
+// NewIndustrial creates a new industrial use case query generator.
+func (g *BaseGenerator) NewIndustrial(start, end time.Time, scale int, layout dataindustrial.Layout) (utils.QueryGenerator, error) {
+	core, err := industrial.NewCore(start, end, scale, layout)
+
+	if err != nil {
+		return nil, err
+	}
+
+	industrial := &Industrial{
+		BaseGenerator: g,
+		Core:          core,
+	}
+
+	return industrial, nil
+}
@@ -0,0 +1,76 @@
+package influx
+
+import (
+	"fmt"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/databases"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/industrial"
+	dataindustrial "github.com/timescale/tsbs/pkg/data/usecases/industrial"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// Industrial produces Influx-specific queries for all the industrial query types.
+type Industrial struct {
+	*industrial.Core
+	*BaseGenerator
+}
+
+// SingleSensorScan selects the raw readings of a random sensor of a random
+// device over an hour.
+func (i *Industrial) SingleSensorScan(qi query.Query) {
+	interval := i.Interval.MustRandWindow(industrial.SensorScanDuration)
+	device, err := i.GetRandomDevice()
+	databases.PanicIfErr(err)
+	sensor := i.GetRandomSensor()
+
+	influxql := fmt.Sprintf(`SELECT "%s" 
+		FROM "%s" 
+		WHERE "device" = '%s' AND time >= '%s' AND time < '%s'`,
+		dataindustrial.SensorName(sensor),
+		dataindustrial.BlockName(i.Layout.Block(sensor)),
+		device,
+		interval.StartString(),
+		interval.EndString())
+
+	humanLabel := "Influx single sensor scan, random device, random 1h0m0s"
+	humanDesc := fmt.Sprintf("%s: %s %s", humanLabel, interval.StartString(), device)
+	i.fillInQuery(qi, humanLabel, humanDesc, influxql)
+}
+
+// DeviceSnapshot selects the last reading of every sensor of a random
+// device, across all its blocks.
+func (i *Industrial) DeviceSnapshot(qi query.Query) {
+	device, err := i.GetRandomDevice()
+	databases.PanicIfErr(err)
+
+	influxql := fmt.Sprintf(`SELECT last(*) 
+		FROM /^block_/ 
+		WHERE "device" = '%s'`,
+		device)
+
+	humanLabel := fmt.Sprintf("Influx device snapshot, random device, %d sensors", i.Layout.Sensors)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, device)
+	i.fillInQuery(qi, humanLabel, humanDesc, influxql)
+}
+
+// CrossDeviceSensorAggregate averages a random sensor over the devices of a
+// random plant, by minute over an hour.
+func (i *Industrial) CrossDeviceSensorAggregate(qi query.Query) {
+	interval := i.Interval.MustRandWindow(industrial.SensorAggregateDuration)
+	plant := i.GetRandomPlant()
+	sensor := i.GetRandomSensor()
+
+	influxql := fmt.Sprintf(`SELECT mean("%s") 
+		FROM "%s" 
+		WHERE "plant" = '%s' AND time >= '%s' AND time < '%s' 
+		GROUP BY time(1m)`,
+		dataindustrial.SensorName(sensor),
+		dataindustrial.BlockName(i.Layout.Block(sensor)),
+		plant,
+		interval.StartString(),
+		interval.EndString())
+
+	humanLabel := "Influx cross-device sensor average, random plant, random 1h0m0s by 1m"
+	humanDesc := fmt.Sprintf("%s: %s %s", humanLabel, interval.StartString(), plant)
+	i.fillInQuery(qi, humanLabel, humanDesc, influxql)
+}
@@ -0,0 +1,75 @@
+package influx
+
+import (
+	"fmt"
+	"math/rand"
+	"net/url"
+	"testing"
+	"time"
+
+	dataindustrial "github.com/timescale/tsbs/pkg/data/usecases/industrial"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+func TestIndustrialQueries(t *testing.T) {
+	cases := []struct {
+		desc               string
+		fill               func(*Industrial, query.Query)
+		expectedHumanLabel string
+		expectedHumanDesc  string
+		expectedQuery      string
+	}{
+		{
+			desc: "single sensor scan",
+			fill: (*Industrial).SingleSensorScan,
+
+			expectedHumanLabel: "Influx single sensor scan, random device, random 1h0m0s",
+			expectedHumanDesc:  "Influx single sensor scan, random device, random 1h0m0s: 2016-01-01T20:16:22Z device_9",
+			expectedQuery: `SELECT "sensor_403" 
+		FROM "block_4" 
+		WHERE "device" = 'device_9' AND time >= '2016-01-01T20:16:22Z' AND time < '2016-01-01T21:16:22Z'`,
+		},
+		{
+			desc: "device snapshot",
+			fill: (*Industrial).DeviceSnapshot,
+
+			expectedHumanLabel: "Influx device snapshot, random device, 1000 sensors",
+			expectedHumanDesc:  "Influx device snapshot, random device, 1000 sensors: device_5",
+			expectedQuery: `SELECT last(*) 
+		FROM /^block_/ 
+		WHERE "device" = 'device_5'`,
+		},
+		{
+			desc: "cross-device sensor aggregate",
+			fill: (*Industrial).CrossDeviceSensorAggregate,
+
+			expectedHumanLabel: "Influx cross-device sensor average, random plant, random 1h0m0s by 1m",
+			expectedHumanDesc:  "Influx cross-device sensor average, random plant, random 1h0m0s by 1m: 2016-01-01T20:16:22Z Pune",
+			expectedQuery: `SELECT mean("sensor_403") 
+		FROM "block_4" 
+		WHERE "plant" = 'Pune' AND time >= '2016-01-01T20:16:22Z' AND time < '2016-01-01T21:16:22Z' 
+		GROUP BY time(1m)`,
+		},
+	}
+
+	s := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	e := s.Add(24 * time.Hour)
+	layout := dataindustrial.Layout{Sensors: 1000, SensorsPerBlock: 100}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			rand.Seed(123) // Setting seed for testing purposes.
+			b := BaseGenerator{}
+			dq, err := b.NewIndustrial(s, e, testScale, layout)
+			if err != nil {
+				t.Fatalf("Error while creating industrial generator")
+			}
+			q := dq.GenerateEmptyQuery()
+			c.fill(dq.(*Industrial), q)
+
+			v := url.Values{}
+			v.Set("q", c.expectedQuery)
+			expectedPath := fmt.Sprintf("/query?%s", v.Encode())
+			verifyQuery(t, q, c.expectedHumanLabel, c.expectedHumanDesc, expectedPath)
+		})
+	}
+}
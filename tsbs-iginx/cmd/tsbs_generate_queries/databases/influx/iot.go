@@ -315,6 +315,51 @@ func (i *IoT) TruckBreakdownFrequency(qi query.Query) {
 	i.fillInQuery(qi, humanLabel, humanDesc, influxql)
 }
 
+// TrucksInBoundingBox finds the trucks that drove in a random bounding box
+// over a random hour, with their last location in it.
+func (i *IoT) TrucksInBoundingBox(qi query.Query) {
+	interval := i.Interval.MustRandWindow(iot.BoundingBoxDuration)
+	box := i.GetRandomBoundingBox()
+	influxql := fmt.Sprintf(`SELECT last("latitude"), last("longitude") 
+		FROM "readings" 
+		WHERE "latitude" >= %f AND "latitude" <= %f 
+		AND "longitude" >= %f AND "longitude" <= %f 
+		AND time >= '%s' AND time < '%s' 
+		GROUP BY "name"`,
+		box.MinLatitude, box.MaxLatitude,
+		box.MinLongitude, box.MaxLongitude,
+		interval.Start().Format(time.RFC3339),
+		interval.End().Format(time.RFC3339))
+
+	humanLabel := "Influx trucks in bounding box"
+	humanDesc := fmt.Sprintf("%s: %s in 1 hour period", humanLabel, box)
+
+	i.fillInQuery(qi, humanLabel, humanDesc, influxql)
+}
+
+// StopsInBoundingBox counts the readings of each truck stopped in a random
+// bounding box over a random hour.
+func (i *IoT) StopsInBoundingBox(qi query.Query) {
+	interval := i.Interval.MustRandWindow(iot.BoundingBoxDuration)
+	box := i.GetRandomBoundingBox()
+	influxql := fmt.Sprintf(`SELECT count("velocity") AS "stopped" 
+		FROM "readings" 
+		WHERE "velocity" = 0 
+		AND "latitude" >= %f AND "latitude" <= %f 
+		AND "longitude" >= %f AND "longitude" <= %f 
+		AND time >= '%s' AND time < '%s' 
+		GROUP BY "name"`,
+		box.MinLatitude, box.MaxLatitude,
+		box.MinLongitude, box.MaxLongitude,
+		interval.Start().Format(time.RFC3339),
+		interval.End().Format(time.RFC3339))
+
+	humanLabel := "Influx stops in bounding box"
+	humanDesc := fmt.Sprintf("%s: %s in 1 hour period", humanLabel, box)
+
+	i.fillInQuery(qi, humanLabel, humanDesc, influxql)
+}
+
 // tenMinutePeriods calculates the number of 10 minute periods that can fit in
 // the time duration if we subtract the minutes specified by minutesPerHour value.
 // E.g.: 4 hours - 5 minutes per hour = 3 hours and 40 minutes = 22 ten minute periods
@@ -193,6 +193,65 @@ func TestStationaryTrucks(t *testing.T) {
 	}
 }
 
+func TestTrucksInBoundingBox(t *testing.T) {
+	cases := []IoTTestCase{
+		{
+			desc: "default",
+
+			expectedHumanLabel: "Influx trucks in bounding box",
+			expectedHumanDesc:  "Influx trucks in bounding box: (30.42, -109.25)-(32.42, -107.25) in 1 hour period",
+
+			expectedQuery: "/query?q=SELECT+last%28%22latitude%22%29%2C+last%28%22longitude%22%29+%0A%09%09" +
+				"FROM+%22readings%22+%0A%09%09" +
+				"WHERE+%22latitude%22+%3E%3D+30.419589+AND+%22latitude%22+%3C%3D+32.419589+%0A%09%09" +
+				"AND+%22longitude%22+%3E%3D+-109.251312+AND+%22longitude%22+%3C%3D+-107.251312+%0A%09%09" +
+				"AND+time+%3E%3D+%271970-01-01T00%3A16%3A22Z%27+AND+time+%3C+%271970-01-01T01%3A16%3A22Z%27+%0A%09%09" +
+				"GROUP+BY+%22name%22",
+		},
+	}
+
+	for _, c := range cases {
+		b := &BaseGenerator{}
+		g := NewIoT(time.Unix(0, 0), time.Unix(0, 0).Add(2*time.Hour), 10, b)
+
+		q := g.GenerateEmptyQuery()
+		rand.Seed(123)
+		g.TrucksInBoundingBox(q)
+
+		verifyQuery(t, q, c.expectedHumanLabel, c.expectedHumanDesc, c.expectedQuery)
+	}
+}
+
+func TestStopsInBoundingBox(t *testing.T) {
+	cases := []IoTTestCase{
+		{
+			desc: "default",
+
+			expectedHumanLabel: "Influx stops in bounding box",
+			expectedHumanDesc:  "Influx stops in bounding box: (30.42, -109.25)-(32.42, -107.25) in 1 hour period",
+
+			expectedQuery: "/query?q=SELECT+count%28%22velocity%22%29+AS+%22stopped%22+%0A%09%09" +
+				"FROM+%22readings%22+%0A%09%09" +
+				"WHERE+%22velocity%22+%3D+0+%0A%09%09" +
+				"AND+%22latitude%22+%3E%3D+30.419589+AND+%22latitude%22+%3C%3D+32.419589+%0A%09%09" +
+				"AND+%22longitude%22+%3E%3D+-109.251312+AND+%22longitude%22+%3C%3D+-107.251312+%0A%09%09" +
+				"AND+time+%3E%3D+%271970-01-01T00%3A16%3A22Z%27+AND+time+%3C+%271970-01-01T01%3A16%3A22Z%27+%0A%09%09" +
+				"GROUP+BY+%22name%22",
+		},
+	}
+
+	for _, c := range cases {
+		b := &BaseGenerator{}
+		g := NewIoT(time.Unix(0, 0), time.Unix(0, 0).Add(2*time.Hour), 10, b)
+
+		q := g.GenerateEmptyQuery()
+		rand.Seed(123)
+		g.StopsInBoundingBox(q)
+
+		verifyQuery(t, q, c.expectedHumanLabel, c.expectedHumanDesc, c.expectedQuery)
+	}
+}
+
 func TestTrucksWithLongDrivingSessions(t *testing.T) {
 	cases := []IoTTestCase{
 		{
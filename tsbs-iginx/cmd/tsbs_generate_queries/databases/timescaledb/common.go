@@ -1,15 +1,22 @@
 package timescaledb
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/devops"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/industrial"
 	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/iot"
 	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/utils"
+	dataindustrial "github.com/timescale/tsbs/pkg/data/usecases/industrial"
 	"github.com/timescale/tsbs/pkg/query"
 )
 
-const goTimeFmt = "2006-01-02 15:04:05.999999 -0700"
+const (
+	goTimeFmt = "2006-01-02 15:04:05.999999 -0700"
+
+	errBlockTooWideFmt = "timescaledb tables hold at most %d sensors per block, got %d"
+)
 
 // BaseGenerator contains settings specific for TimescaleDB
 type BaseGenerator struct {
@@ -63,3 +70,22 @@ func (g *BaseGenerator) NewIoT(start, end time.Time, scale int) (utils.QueryGene
 
 	return iot, nil
 }
+
+// NewIndustrial creates a new industrial use case query generator.
+func (g *BaseGenerator) NewIndustrial(start, end time.Time, scale int, layout dataindustrial.Layout) (utils.QueryGenerator, error) {
+	if layout.SensorsPerBlock > dataindustrial.MaxTimescaleDBSensorsPerBlock {
+		return nil, fmt.Errorf(errBlockTooWideFmt, dataindustrial.MaxTimescaleDBSensorsPerBlock, layout.SensorsPerBlock)
+	}
+	core, err := industrial.NewCore(start, end, scale, layout)
+
+	if err != nil {
+		return nil, err
+	}
+
+	industrial := &Industrial{
+		BaseGenerator: g,
+		Core:          core,
+	}
+
+	return industrial, nil
+}
@@ -0,0 +1,116 @@
+package timescaledb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/industrial"
+	dataindustrial "github.com/timescale/tsbs/pkg/data/usecases/industrial"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// Industrial produces TimescaleDB-specific queries for all the industrial query types.
+type Industrial struct {
+	*industrial.Core
+	*BaseGenerator
+}
+
+// getTagWhere creates a WHERE SQL statement for the rows of a tag value,
+// whether the tags are in their own table or in the block tables.
+// NOTE 'WHERE' itself is not included
+func (i *Industrial) getTagWhere(key, value string) string {
+	if i.UseJSON {
+		return fmt.Sprintf("tags_id IN (SELECT id FROM tags WHERE tagset @> '{\"%s\": \"%s\"}')", key, value)
+	}
+	if i.UseTags {
+		return fmt.Sprintf("tags_id IN (SELECT id FROM tags WHERE %s = '%s')", key, value)
+	}
+	return fmt.Sprintf("%s = '%s'", key, value)
+}
+
+func (i *Industrial) getTimeBucket(seconds int) string {
+	if i.UseTimeBucket {
+		return fmt.Sprintf(timeBucketFmt, seconds)
+	}
+	return fmt.Sprintf(nonTimeBucketFmt, seconds, seconds)
+}
+
+// SingleSensorScan selects the raw readings of a random sensor of a random
+// device over an hour.
+func (i *Industrial) SingleSensorScan(qi query.Query) {
+	interval := i.Interval.MustRandWindow(industrial.SensorScanDuration)
+	device, err := i.GetRandomDevice()
+	panicIfErr(err)
+	sensor := i.GetRandomSensor()
+	table := dataindustrial.BlockName(i.Layout.Block(sensor))
+
+	sql := fmt.Sprintf(`SELECT time, %s
+		FROM %s
+		WHERE %s AND time >= '%s' AND time < '%s'
+		ORDER BY time`,
+		dataindustrial.SensorName(sensor),
+		table,
+		i.getTagWhere("device", device),
+		interval.Start().Format(goTimeFmt),
+		interval.End().Format(goTimeFmt))
+
+	humanLabel := "TimescaleDB single sensor scan, random device, random 1h0m0s"
+	humanDesc := fmt.Sprintf("%s: %s %s", humanLabel, interval.StartString(), device)
+	i.fillInQuery(qi, humanLabel, humanDesc, table, sql)
+}
+
+// DeviceSnapshot selects the last reading of every sensor of a random
+// device: one row per block, with its readings in an array, so that the
+// target list stays well below the 1664 entries PostgreSQL allows however
+// many sensors the device has.
+func (i *Industrial) DeviceSnapshot(qi query.Query) {
+	device, err := i.GetRandomDevice()
+	panicIfErr(err)
+	where := i.getTagWhere("device", device)
+
+	blocks := make([]string, i.Layout.Blocks())
+	for b := range blocks {
+		sensors := make([]string, 0, i.Layout.SensorsPerBlock)
+		for _, sensor := range i.Layout.BlockSensors(b) {
+			sensors = append(sensors, dataindustrial.SensorName(sensor))
+		}
+		blocks[b] = fmt.Sprintf(`(SELECT %d AS block, time, ARRAY[%s] AS readings
+		FROM %s
+		WHERE %s
+		ORDER BY time DESC LIMIT 1)`,
+			b, strings.Join(sensors, ", "), dataindustrial.BlockName(b), where)
+	}
+	sql := fmt.Sprintf(`%s
+		ORDER BY block`,
+		strings.Join(blocks, "\n\t\tUNION ALL\n\t\t"))
+
+	humanLabel := fmt.Sprintf("TimescaleDB device snapshot, random device, %d sensors", i.Layout.Sensors)
+	humanDesc := fmt.Sprintf("%s: %s", humanLabel, device)
+	i.fillInQuery(qi, humanLabel, humanDesc, dataindustrial.BlockName(0), sql)
+}
+
+// CrossDeviceSensorAggregate averages a random sensor over the devices of a
+// random plant, by minute over an hour.
+func (i *Industrial) CrossDeviceSensorAggregate(qi query.Query) {
+	interval := i.Interval.MustRandWindow(industrial.SensorAggregateDuration)
+	plant := i.GetRandomPlant()
+	sensor := i.GetRandomSensor()
+	table := dataindustrial.BlockName(i.Layout.Block(sensor))
+
+	sql := fmt.Sprintf(`SELECT %s AS minute, avg(%s) AS mean_%s
+		FROM %s
+		WHERE %s AND time >= '%s' AND time < '%s'
+		GROUP BY minute
+		ORDER BY minute`,
+		i.getTimeBucket(int(industrial.SensorAggregateInterval.Seconds())),
+		dataindustrial.SensorName(sensor),
+		dataindustrial.SensorName(sensor),
+		table,
+		i.getTagWhere("plant", plant),
+		interval.Start().Format(goTimeFmt),
+		interval.End().Format(goTimeFmt))
+
+	humanLabel := "TimescaleDB cross-device sensor average, random plant, random 1h0m0s by 1m"
+	humanDesc := fmt.Sprintf("%s: %s %s", humanLabel, interval.StartString(), plant)
+	i.fillInQuery(qi, humanLabel, humanDesc, table, sql)
+}
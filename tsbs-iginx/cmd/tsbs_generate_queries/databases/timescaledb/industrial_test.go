@@ -0,0 +1,133 @@
+package timescaledb
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	dataindustrial "github.com/timescale/tsbs/pkg/data/usecases/industrial"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+func TestIndustrialQueries(t *testing.T) {
+	cases := []struct {
+		desc               string
+		fill               func(*Industrial, query.Query)
+		useJSON            bool
+		expectedHumanLabel string
+		expectedHumanDesc  string
+		expectedHypertable string
+		expectedSQLQuery   string
+	}{
+		{
+			desc: "single sensor scan",
+			fill: (*Industrial).SingleSensorScan,
+
+			expectedHumanLabel: "TimescaleDB single sensor scan, random device, random 1h0m0s",
+			expectedHumanDesc:  "TimescaleDB single sensor scan, random device, random 1h0m0s: 2016-01-01T20:16:22Z device_9",
+			expectedHypertable: "block_1",
+			expectedSQLQuery: `SELECT time, sensor_3
+		FROM block_1
+		WHERE device = 'device_9' AND time >= '2016-01-01 20:16:22.646325 +0000' AND time < '2016-01-01 21:16:22.646325 +0000'
+		ORDER BY time`,
+		},
+		{
+			desc: "device snapshot",
+			fill: (*Industrial).DeviceSnapshot,
+
+			expectedHumanLabel: "TimescaleDB device snapshot, random device, 5 sensors",
+			expectedHumanDesc:  "TimescaleDB device snapshot, random device, 5 sensors: device_5",
+			expectedHypertable: "block_0",
+			expectedSQLQuery: `(SELECT 0 AS block, time, ARRAY[sensor_0, sensor_1] AS readings
+		FROM block_0
+		WHERE device = 'device_5'
+		ORDER BY time DESC LIMIT 1)
+		UNION ALL
+		(SELECT 1 AS block, time, ARRAY[sensor_2, sensor_3] AS readings
+		FROM block_1
+		WHERE device = 'device_5'
+		ORDER BY time DESC LIMIT 1)
+		UNION ALL
+		(SELECT 2 AS block, time, ARRAY[sensor_4] AS readings
+		FROM block_2
+		WHERE device = 'device_5'
+		ORDER BY time DESC LIMIT 1)
+		ORDER BY block`,
+		},
+		{
+			desc:    "device snapshot use json",
+			fill:    (*Industrial).DeviceSnapshot,
+			useJSON: true,
+
+			expectedHumanLabel: "TimescaleDB device snapshot, random device, 5 sensors",
+			expectedHumanDesc:  "TimescaleDB device snapshot, random device, 5 sensors: device_5",
+			expectedHypertable: "block_0",
+			expectedSQLQuery: `(SELECT 0 AS block, time, ARRAY[sensor_0, sensor_1] AS readings
+		FROM block_0
+		WHERE tags_id IN (SELECT id FROM tags WHERE tagset @> '{"device": "device_5"}')
+		ORDER BY time DESC LIMIT 1)
+		UNION ALL
+		(SELECT 1 AS block, time, ARRAY[sensor_2, sensor_3] AS readings
+		FROM block_1
+		WHERE tags_id IN (SELECT id FROM tags WHERE tagset @> '{"device": "device_5"}')
+		ORDER BY time DESC LIMIT 1)
+		UNION ALL
+		(SELECT 2 AS block, time, ARRAY[sensor_4] AS readings
+		FROM block_2
+		WHERE tags_id IN (SELECT id FROM tags WHERE tagset @> '{"device": "device_5"}')
+		ORDER BY time DESC LIMIT 1)
+		ORDER BY block`,
+		},
+		{
+			desc: "cross-device sensor aggregate",
+			fill: (*Industrial).CrossDeviceSensorAggregate,
+
+			expectedHumanLabel: "TimescaleDB cross-device sensor average, random plant, random 1h0m0s by 1m",
+			expectedHumanDesc:  "TimescaleDB cross-device sensor average, random plant, random 1h0m0s by 1m: 2016-01-01T20:16:22Z Pune",
+			expectedHypertable: "block_1",
+			expectedSQLQuery: `SELECT to_timestamp(((extract(epoch from time)::int)/60)*60) AS minute, avg(sensor_3) AS mean_sensor_3
+		FROM block_1
+		WHERE plant = 'Pune' AND time >= '2016-01-01 20:16:22.646325 +0000' AND time < '2016-01-01 21:16:22.646325 +0000'
+		GROUP BY minute
+		ORDER BY minute`,
+		},
+	}
+
+	s := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	e := s.Add(24 * time.Hour)
+	layout := dataindustrial.Layout{Sensors: 5, SensorsPerBlock: 2}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			rand.Seed(123) // Setting seed for testing purposes.
+			b := BaseGenerator{}
+			b.UseJSON = c.useJSON
+			dq, err := b.NewIndustrial(s, e, testScale, layout)
+			if err != nil {
+				t.Fatalf("Error while creating industrial generator")
+			}
+			q := dq.GenerateEmptyQuery()
+			c.fill(dq.(*Industrial), q)
+
+			verifyQuery(t, q, c.expectedHumanLabel, c.expectedHumanDesc, c.expectedHypertable, c.expectedSQLQuery)
+		})
+	}
+}
+
+func TestNewIndustrialBlockTooWide(t *testing.T) {
+	s := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	e := s.Add(24 * time.Hour)
+	b := BaseGenerator{}
+
+	sensors := dataindustrial.MaxTimescaleDBSensorsPerBlock + 1
+	_, err := b.NewIndustrial(s, e, testScale, dataindustrial.Layout{Sensors: sensors, SensorsPerBlock: sensors})
+	want := fmt.Sprintf(errBlockTooWideFmt, dataindustrial.MaxTimescaleDBSensorsPerBlock, sensors)
+	if err == nil || err.Error() != want {
+		t.Errorf("incorrect error for a block too wide: got %v want %s", err, want)
+	}
+
+	layout := dataindustrial.Layout{Sensors: dataindustrial.MaxSensors, SensorsPerBlock: dataindustrial.MaxTimescaleDBSensorsPerBlock}
+	if _, err := b.NewIndustrial(s, e, testScale, layout); err != nil {
+		t.Errorf("unexpected error for the widest blocks: %v", err)
+	}
+}
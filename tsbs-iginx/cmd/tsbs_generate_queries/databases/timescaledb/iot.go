@@ -424,6 +424,67 @@ func (i *IoT) TruckBreakdownFrequency(qi query.Query) {
 	i.fillInQuery(qi, humanLabel, humanDesc, iot.DiagnosticsTableName, sql)
 }
 
+// TrucksInBoundingBox finds the trucks that drove in a random bounding box
+// over a random hour, with their last location in it.
+func (i *IoT) TrucksInBoundingBox(qi query.Query) {
+	name, driver := "name", "driver"
+
+	interval := i.Interval.MustRandWindow(iot.BoundingBoxDuration)
+	box := i.GetRandomBoundingBox()
+	sql := fmt.Sprintf(`SELECT DISTINCT ON (t.%s) t.%s, t.%s, r.time, r.latitude, r.longitude
+		FROM tags t 
+		INNER JOIN readings r ON r.tags_id = t.id 
+		WHERE time >= '%s' AND time < '%s'
+		AND r.latitude BETWEEN %f AND %f 
+		AND r.longitude BETWEEN %f AND %f 
+		AND t.%s IS NOT NULL
+		ORDER BY t.%s, r.time DESC`,
+		i.columnSelect(name),
+		i.withAlias(name),
+		i.withAlias(driver),
+		interval.Start().Format(goTimeFmt),
+		interval.End().Format(goTimeFmt),
+		box.MinLatitude, box.MaxLatitude,
+		box.MinLongitude, box.MaxLongitude,
+		i.columnSelect(name),
+		i.columnSelect(name))
+
+	humanLabel := "TimescaleDB trucks in bounding box"
+	humanDesc := fmt.Sprintf("%s: %s in 1 hour period", humanLabel, box)
+
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.ReadingsTableName, sql)
+}
+
+// StopsInBoundingBox counts the readings of each truck stopped in a random
+// bounding box over a random hour.
+func (i *IoT) StopsInBoundingBox(qi query.Query) {
+	name, driver := "name", "driver"
+
+	interval := i.Interval.MustRandWindow(iot.BoundingBoxDuration)
+	box := i.GetRandomBoundingBox()
+	sql := fmt.Sprintf(`SELECT t.%s, t.%s, count(*) AS stopped
+		FROM tags t 
+		INNER JOIN readings r ON r.tags_id = t.id 
+		WHERE time >= '%s' AND time < '%s'
+		AND r.velocity = 0 
+		AND r.latitude BETWEEN %f AND %f 
+		AND r.longitude BETWEEN %f AND %f 
+		AND t.%s IS NOT NULL
+		GROUP BY 1, 2`,
+		i.withAlias(name),
+		i.withAlias(driver),
+		interval.Start().Format(goTimeFmt),
+		interval.End().Format(goTimeFmt),
+		box.MinLatitude, box.MaxLatitude,
+		box.MinLongitude, box.MaxLongitude,
+		i.columnSelect(name))
+
+	humanLabel := "TimescaleDB stops in bounding box"
+	humanDesc := fmt.Sprintf("%s: %s in 1 hour period", humanLabel, box)
+
+	i.fillInQuery(qi, humanLabel, humanDesc, iot.ReadingsTableName, sql)
+}
+
 // tenMinutePeriods calculates the number of 10 minute periods that can fit in
 // the time duration if we subtract the minutes specified by minutesPerHour value.
 // E.g.: 4 hours - 5 minutes per hour = 3 hours and 40 minutes = 22 ten minute periods
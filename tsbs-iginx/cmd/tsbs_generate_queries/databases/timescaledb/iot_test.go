@@ -847,6 +847,108 @@ func TestTruckBreakdownFrequency(t *testing.T) {
 	}
 }
 
+func TestTrucksInBoundingBox(t *testing.T) {
+	cases := []testCase{
+		{
+			desc: "default to using tags",
+
+			expectedHumanLabel: "TimescaleDB trucks in bounding box",
+			expectedHumanDesc:  "TimescaleDB trucks in bounding box: (30.42, -109.25)-(32.42, -107.25) in 1 hour period",
+			expectedHypertable: iot.ReadingsTableName,
+			expectedSQLQuery: `SELECT DISTINCT ON (t.name) t.name AS name, t.driver AS driver, r.time, r.latitude, r.longitude
+		FROM tags t 
+		INNER JOIN readings r ON r.tags_id = t.id 
+		WHERE time >= '1970-01-01 00:16:22.646325 +0000' AND time < '1970-01-01 01:16:22.646325 +0000'
+		AND r.latitude BETWEEN 30.419589 AND 32.419589 
+		AND r.longitude BETWEEN -109.251312 AND -107.251312 
+		AND t.name IS NOT NULL
+		ORDER BY t.name, r.time DESC`,
+		},
+
+		{
+			desc: "use JSON",
+
+			useJSON:            true,
+			expectedHumanLabel: "TimescaleDB trucks in bounding box",
+			expectedHumanDesc:  "TimescaleDB trucks in bounding box: (30.42, -109.25)-(32.42, -107.25) in 1 hour period",
+			expectedHypertable: iot.ReadingsTableName,
+			expectedSQLQuery: `SELECT DISTINCT ON (t.tagset->>'name') t.tagset->>'name' AS name, t.tagset->>'driver' AS driver, r.time, r.latitude, r.longitude
+		FROM tags t 
+		INNER JOIN readings r ON r.tags_id = t.id 
+		WHERE time >= '1970-01-01 00:16:22.646325 +0000' AND time < '1970-01-01 01:16:22.646325 +0000'
+		AND r.latitude BETWEEN 30.419589 AND 32.419589 
+		AND r.longitude BETWEEN -109.251312 AND -107.251312 
+		AND t.tagset->>'name' IS NOT NULL
+		ORDER BY t.tagset->>'name', r.time DESC`,
+		},
+	}
+
+	for _, c := range cases {
+		b := &BaseGenerator{
+			UseJSON: c.useJSON,
+		}
+		g := NewIoT(time.Unix(0, 0), time.Unix(0, 0).Add(2*time.Hour), 10, b)
+
+		q := g.GenerateEmptyQuery()
+		rand.Seed(123)
+		g.TrucksInBoundingBox(q)
+
+		verifyQuery(t, q, c.expectedHumanLabel, c.expectedHumanDesc, c.expectedHypertable, c.expectedSQLQuery)
+	}
+}
+
+func TestStopsInBoundingBox(t *testing.T) {
+	cases := []testCase{
+		{
+			desc: "default to using tags",
+
+			expectedHumanLabel: "TimescaleDB stops in bounding box",
+			expectedHumanDesc:  "TimescaleDB stops in bounding box: (30.42, -109.25)-(32.42, -107.25) in 1 hour period",
+			expectedHypertable: iot.ReadingsTableName,
+			expectedSQLQuery: `SELECT t.name AS name, t.driver AS driver, count(*) AS stopped
+		FROM tags t 
+		INNER JOIN readings r ON r.tags_id = t.id 
+		WHERE time >= '1970-01-01 00:16:22.646325 +0000' AND time < '1970-01-01 01:16:22.646325 +0000'
+		AND r.velocity = 0 
+		AND r.latitude BETWEEN 30.419589 AND 32.419589 
+		AND r.longitude BETWEEN -109.251312 AND -107.251312 
+		AND t.name IS NOT NULL
+		GROUP BY 1, 2`,
+		},
+
+		{
+			desc: "use JSON",
+
+			useJSON:            true,
+			expectedHumanLabel: "TimescaleDB stops in bounding box",
+			expectedHumanDesc:  "TimescaleDB stops in bounding box: (30.42, -109.25)-(32.42, -107.25) in 1 hour period",
+			expectedHypertable: iot.ReadingsTableName,
+			expectedSQLQuery: `SELECT t.tagset->>'name' AS name, t.tagset->>'driver' AS driver, count(*) AS stopped
+		FROM tags t 
+		INNER JOIN readings r ON r.tags_id = t.id 
+		WHERE time >= '1970-01-01 00:16:22.646325 +0000' AND time < '1970-01-01 01:16:22.646325 +0000'
+		AND r.velocity = 0 
+		AND r.latitude BETWEEN 30.419589 AND 32.419589 
+		AND r.longitude BETWEEN -109.251312 AND -107.251312 
+		AND t.tagset->>'name' IS NOT NULL
+		GROUP BY 1, 2`,
+		},
+	}
+
+	for _, c := range cases {
+		b := &BaseGenerator{
+			UseJSON: c.useJSON,
+		}
+		g := NewIoT(time.Unix(0, 0), time.Unix(0, 0).Add(2*time.Hour), 10, b)
+
+		q := g.GenerateEmptyQuery()
+		rand.Seed(123)
+		g.StopsInBoundingBox(q)
+
+		verifyQuery(t, q, c.expectedHumanLabel, c.expectedHumanDesc, c.expectedHypertable, c.expectedSQLQuery)
+	}
+}
+
 func TestTenMinutePeriods(t *testing.T) {
 	cases := []struct {
 		minutesPerHour float64
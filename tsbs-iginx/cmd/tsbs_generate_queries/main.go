@@ -11,6 +11,7 @@ import (
 	"github.com/blagojts/viper"
 	"github.com/spf13/pflag"
 	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/devops"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/industrial"
 	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/iot"
 	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/utils"
 	"github.com/timescale/tsbs/internal/inputs"
@@ -50,6 +51,13 @@ var useCaseMatrix = map[string]map[string]utils.QueryFillerMaker{
 		iot.LabelAvgLoad:                       iot.NewAvgLoad,
 		iot.LabelDailyActivity:                 iot.NewDailyTruckActivity,
 		iot.LabelBreakdownFrequency:            iot.NewTruckBreakdownFrequency,
+		iot.LabelTrucksInBoundingBox:           iot.NewTrucksInBoundingBox,
+		iot.LabelStopsInBoundingBox:            iot.NewStopsInBoundingBox,
+	},
+	"industrial": {
+		industrial.LabelSingleSensorScan:           industrial.NewSingleSensorScan,
+		industrial.LabelDeviceSnapshot:             industrial.NewDeviceSnapshot,
+		industrial.LabelCrossDeviceSensorAggregate: industrial.NewCrossDeviceSensorAggregate,
 	},
 }
 
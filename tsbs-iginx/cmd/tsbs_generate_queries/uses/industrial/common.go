@@ -0,0 +1,80 @@
+package industrial
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/common"
+	"github.com/timescale/tsbs/pkg/data/usecases/industrial"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+const (
+	// SensorScanDuration is the time range of a single sensor scan.
+	SensorScanDuration = time.Hour
+	// SensorAggregateDuration is the time range of a cross-device sensor aggregate.
+	SensorAggregateDuration = time.Hour
+	// SensorAggregateInterval is the interval a cross-device sensor aggregate
+	// is grouped by.
+	SensorAggregateInterval = time.Minute
+
+	// LabelSingleSensorScan is the label for the single sensor scan query.
+	LabelSingleSensorScan = "single-sensor-scan"
+	// LabelDeviceSnapshot is the label for the device-wide snapshot query.
+	LabelDeviceSnapshot = "device-snapshot"
+	// LabelCrossDeviceSensorAggregate is the label for the cross-device sensor aggregate query.
+	LabelCrossDeviceSensorAggregate = "cross-device-sensor-agg"
+)
+
+// Core is the common component of all generators for all systems.
+type Core struct {
+	*common.Core
+	// Layout is how the sensors of the devices are grouped in blocks, as
+	// given to tsbs_generate_data
+	Layout industrial.Layout
+}
+
+// NewCore returns a new Core for the given time range, cardinality and
+// layout of the sensors.
+func NewCore(start, end time.Time, scale int, layout industrial.Layout) (*Core, error) {
+	if err := layout.Validate(); err != nil {
+		return nil, err
+	}
+	c, err := common.NewCore(start, end, scale)
+	return &Core{Core: c, Layout: layout}, err
+}
+
+// GetRandomDevice returns the name of a random device.
+func (c *Core) GetRandomDevice() (string, error) {
+	if c.Scale < 1 {
+		return "", fmt.Errorf("number of devices cannot be < 1; got %d", c.Scale)
+	}
+	return fmt.Sprintf("device_%d", rand.Intn(c.Scale)), nil
+}
+
+// GetRandomSensor returns a random sensor of the layout.
+func (c *Core) GetRandomSensor() int {
+	return rand.Intn(c.Layout.Sensors)
+}
+
+// GetRandomPlant returns one of the plant choices by random.
+func (c *Core) GetRandomPlant() string {
+	return industrial.PlantChoices[rand.Intn(len(industrial.PlantChoices))]
+}
+
+// SingleSensorScanFiller is a type that can fill in a single sensor scan query.
+type SingleSensorScanFiller interface {
+	SingleSensorScan(query.Query)
+}
+
+// DeviceSnapshotFiller is a type that can fill in a device-wide snapshot query.
+type DeviceSnapshotFiller interface {
+	DeviceSnapshot(query.Query)
+}
+
+// CrossDeviceSensorAggregateFiller is a type that can fill in a cross-device
+// sensor aggregate query.
+type CrossDeviceSensorAggregateFiller interface {
+	CrossDeviceSensorAggregate(query.Query)
+}
@@ -0,0 +1,29 @@
+package industrial
+
+import (
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/common"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/utils"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// CrossDeviceSensorAggregate contains info for filling in cross-device sensor aggregate queries.
+type CrossDeviceSensorAggregate struct {
+	core utils.QueryGenerator
+}
+
+// NewCrossDeviceSensorAggregate creates a new cross-device sensor aggregate query filler.
+func NewCrossDeviceSensorAggregate(core utils.QueryGenerator) utils.QueryFiller {
+	return &CrossDeviceSensorAggregate{
+		core: core,
+	}
+}
+
+// Fill fills in the query.Query with query details.
+func (i *CrossDeviceSensorAggregate) Fill(q query.Query) query.Query {
+	fc, ok := i.core.(CrossDeviceSensorAggregateFiller)
+	if !ok {
+		common.PanicUnimplementedQuery(i.core)
+	}
+	fc.CrossDeviceSensorAggregate(q)
+	return q
+}
@@ -0,0 +1,29 @@
+package industrial
+
+import (
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/common"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/utils"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// DeviceSnapshot contains info for filling in device-wide snapshot queries.
+type DeviceSnapshot struct {
+	core utils.QueryGenerator
+}
+
+// NewDeviceSnapshot creates a new device-wide snapshot query filler.
+func NewDeviceSnapshot(core utils.QueryGenerator) utils.QueryFiller {
+	return &DeviceSnapshot{
+		core: core,
+	}
+}
+
+// Fill fills in the query.Query with query details.
+func (i *DeviceSnapshot) Fill(q query.Query) query.Query {
+	fc, ok := i.core.(DeviceSnapshotFiller)
+	if !ok {
+		common.PanicUnimplementedQuery(i.core)
+	}
+	fc.DeviceSnapshot(q)
+	return q
+}
@@ -0,0 +1,29 @@
+package industrial
+
+import (
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/common"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/utils"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// SingleSensorScan contains info for filling in single sensor scan queries.
+type SingleSensorScan struct {
+	core utils.QueryGenerator
+}
+
+// NewSingleSensorScan creates a new single sensor scan query filler.
+func NewSingleSensorScan(core utils.QueryGenerator) utils.QueryFiller {
+	return &SingleSensorScan{
+		core: core,
+	}
+}
+
+// Fill fills in the query.Query with query details.
+func (i *SingleSensorScan) Fill(q query.Query) query.Query {
+	fc, ok := i.core.(SingleSensorScanFiller)
+	if !ok {
+		common.PanicUnimplementedQuery(i.core)
+	}
+	fc.SingleSensorScan(q)
+	return q
+}
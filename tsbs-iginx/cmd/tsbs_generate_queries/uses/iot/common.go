@@ -25,6 +25,10 @@ const (
 	LongDrivingSessionDuration = 4 * time.Hour
 	// DailyDrivingDuration is time duration of one day of driving.
 	DailyDrivingDuration = 24 * time.Hour
+	// BoundingBoxDuration is the time duration to look for trucks in a bounding box.
+	BoundingBoxDuration = time.Hour
+	// BoundingBoxSize is the side in degrees of a bounding box.
+	BoundingBoxSize = 2.0
 
 	// LabelLastLoc is the label for the last location query.
 	LabelLastLoc = "last-loc"
@@ -52,6 +56,10 @@ const (
 	LabelDailyActivity = "daily-activity"
 	// LabelBreakdownFrequency is the label for the breakdown frequency query.
 	LabelBreakdownFrequency = "breakdown-frequency"
+	// LabelTrucksInBoundingBox is the label for the trucks in bounding box query.
+	LabelTrucksInBoundingBox = "trucks-in-bounding-box"
+	// LabelStopsInBoundingBox is the label for the stops in bounding box query.
+	LabelStopsInBoundingBox = "stops-in-bounding-box"
 )
 
 // Core is the common component of all generators for all systems.
@@ -64,6 +72,30 @@ func (c Core) GetRandomFleet() string {
 	return iot.FleetChoices[rand.Intn(len(iot.FleetChoices))]
 }
 
+// BoundingBox is an area of latitudes and longitudes.
+type BoundingBox struct {
+	MinLatitude, MaxLatitude   float64
+	MinLongitude, MaxLongitude float64
+}
+
+// String returns the corners of the box.
+func (b BoundingBox) String() string {
+	return fmt.Sprintf("(%.2f, %.2f)-(%.2f, %.2f)", b.MinLatitude, b.MinLongitude, b.MaxLatitude, b.MaxLongitude)
+}
+
+// GetRandomBoundingBox returns a random box of BoundingBoxSize inside the
+// roads the trucks drive on with routes.
+func (c Core) GetRandomBoundingBox() BoundingBox {
+	lat := iot.RouteMinLatitude + rand.Float64()*(iot.RouteMaxLatitude-iot.RouteMinLatitude-BoundingBoxSize)
+	lon := iot.RouteMinLongitude + rand.Float64()*(iot.RouteMaxLongitude-iot.RouteMinLongitude-BoundingBoxSize)
+	return BoundingBox{
+		MinLatitude:  lat,
+		MaxLatitude:  lat + BoundingBoxSize,
+		MinLongitude: lon,
+		MaxLongitude: lon + BoundingBoxSize,
+	}
+}
+
 // NewCore returns a new Core for the given time range and cardinality
 func NewCore(start, end time.Time, scale int) (*Core, error) {
 	c, err := common.NewCore(start, end, scale)
@@ -164,3 +196,13 @@ type DailyTruckActivityFiller interface {
 type TruckBreakdownFrequencyFiller interface {
 	TruckBreakdownFrequency(query.Query)
 }
+
+// TrucksInBoundingBoxFiller is a type that can fill in the trucks in bounding box query.
+type TrucksInBoundingBoxFiller interface {
+	TrucksInBoundingBox(query.Query)
+}
+
+// StopsInBoundingBoxFiller is a type that can fill in the stops in bounding box query.
+type StopsInBoundingBoxFiller interface {
+	StopsInBoundingBox(query.Query)
+}
@@ -0,0 +1,29 @@
+package iot
+
+import (
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/common"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/utils"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// StopsInBoundingBox contains info for filling in stops in bounding box queries.
+type StopsInBoundingBox struct {
+	core utils.QueryGenerator
+}
+
+// NewStopsInBoundingBox creates a new stops in bounding box query filler.
+func NewStopsInBoundingBox(core utils.QueryGenerator) utils.QueryFiller {
+	return &StopsInBoundingBox{
+		core: core,
+	}
+}
+
+// Fill fills in the query.Query with query details.
+func (i *StopsInBoundingBox) Fill(q query.Query) query.Query {
+	fc, ok := i.core.(StopsInBoundingBoxFiller)
+	if !ok {
+		common.PanicUnimplementedQuery(i.core)
+	}
+	fc.StopsInBoundingBox(q)
+	return q
+}
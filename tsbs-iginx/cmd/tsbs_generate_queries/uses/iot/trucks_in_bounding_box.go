@@ -0,0 +1,29 @@
+package iot
+
+import (
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/uses/common"
+	"github.com/timescale/tsbs/cmd/tsbs_generate_queries/utils"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// TrucksInBoundingBox contains info for filling in trucks in bounding box queries.
+type TrucksInBoundingBox struct {
+	core utils.QueryGenerator
+}
+
+// NewTrucksInBoundingBox creates a new trucks in bounding box query filler.
+func NewTrucksInBoundingBox(core utils.QueryGenerator) utils.QueryFiller {
+	return &TrucksInBoundingBox{
+		core: core,
+	}
+}
+
+// Fill fills in the query.Query with query details.
+func (i *TrucksInBoundingBox) Fill(q query.Query) query.Query {
+	fc, ok := i.core.(TrucksInBoundingBoxFiller)
+	if !ok {
+		common.PanicUnimplementedQuery(i.core)
+	}
+	fc.TrucksInBoundingBox(q)
+	return q
+}
@@ -20,15 +20,34 @@ type RunnerConfig struct {
 	BatchSize       uint   `yaml:"batch-size" mapstructure:"batch-size"`
 	Workers         uint
 	Limit           uint64
+	Duration        time.Duration
 	DoLoad          bool          `yaml:"do-load" mapstructure:"do-load"`
 	DoCreateDB      bool          `yaml:"do-create-db" mapstructure:"do-create-db"`
 	DoAbortOnExist  bool          `yaml:"do-abort-on-exist" mapstructure:"do-abort-on-exist"`
 	ReportingPeriod time.Duration `yaml:"reporting-period" mapstructure:"reporting-period"`
 	Seed            int64
-	HashWorkers     bool   `yaml:"hash-workers" mapstructure:"hash-workers"`
-	InsertIntervals string `yaml:"insert-intervals" mapstructure:"insert-intervals"`
-	FlowControl     bool   `yaml:"flow-control" mapstructure:"flow-control"`
-	ChannelCapacity uint   `yaml:"channel-capacity" mapstructure:"channel-capacity"`
+	HashWorkers     bool          `yaml:"hash-workers" mapstructure:"hash-workers"`
+	InsertIntervals string        `yaml:"insert-intervals" mapstructure:"insert-intervals"`
+	FlowControl     bool          `yaml:"flow-control" mapstructure:"flow-control"`
+	ChannelCapacity uint          `yaml:"channel-capacity" mapstructure:"channel-capacity"`
+	TargetRate      float64       `yaml:"target-rate" mapstructure:"target-rate"`
+	TargetRateUnit  string        `yaml:"target-rate-unit" mapstructure:"target-rate-unit"`
+	OnError         string        `yaml:"on-error" mapstructure:"on-error"`
+	MaxRetries      uint          `yaml:"max-retries" mapstructure:"max-retries"`
+	RetryBackoff    time.Duration `yaml:"retry-backoff" mapstructure:"retry-backoff"`
+
+	CheckpointFile       string        `yaml:"checkpoint-file" mapstructure:"checkpoint-file"`
+	CheckpointInterval   time.Duration `yaml:"checkpoint-interval" mapstructure:"checkpoint-interval"`
+	ResumeFromCheckpoint bool          `yaml:"resume-from-checkpoint" mapstructure:"resume-from-checkpoint"`
+
+	BatchBytes        uint64 `yaml:"batch-bytes" mapstructure:"batch-bytes"`
+	AdaptiveBatchSize bool   `yaml:"adaptive-batch-size" mapstructure:"adaptive-batch-size"`
+
+	ResourceSampleInterval time.Duration `yaml:"resource-sample-interval" mapstructure:"resource-sample-interval"`
+	ServerPID              int32         `yaml:"server-pid" mapstructure:"server-pid"`
+
+	Verify   bool   `yaml:"verify" mapstructure:"verify"`
+	Manifest string `yaml:"manifest" mapstructure:"manifest"`
 }
 
 type DataSourceConfig struct {
@@ -51,4 +70,24 @@ type SimulatorDataSourceConfig struct {
 	Limit                 uint64        `yaml:"max-data-points" mapstructure:"max-data-points"`
 	LogInterval           time.Duration `yaml:"log-interval" mapstructure:"log-interval"`
 	MaxMetricCountPerHost uint64        `yaml:"max-metric-count" mapstructure:"max-metric-count"`
+	Schema                string        `yaml:"schema,omitempty" mapstructure:"schema,omitempty"`
+	SensorsPerDevice      uint          `yaml:"sensors-per-device" mapstructure:"sensors-per-device"`
+	SensorsPerBlock       uint          `yaml:"sensors-per-block" mapstructure:"sensors-per-block"`
+
+	OutOfOrderChance float64       `yaml:"out-of-order-chance" mapstructure:"out-of-order-chance"`
+	MaxLateness      time.Duration `yaml:"max-lateness" mapstructure:"max-lateness"`
+	MissingChance    float64       `yaml:"missing-chance" mapstructure:"missing-chance"`
+	DuplicateChance  float64       `yaml:"duplicate-chance" mapstructure:"duplicate-chance"`
+
+	NonNumericFields bool    `yaml:"non-numeric-fields" mapstructure:"non-numeric-fields"`
+	ChurnRate        float64 `yaml:"churn-rate" mapstructure:"churn-rate"`
+
+	TimestampJitter time.Duration   `yaml:"timestamp-jitter" mapstructure:"timestamp-jitter"`
+	SeriesIntervals []time.Duration `yaml:"series-intervals" mapstructure:"series-intervals"`
+	BurstChance     float64         `yaml:"burst-chance" mapstructure:"burst-chance"`
+	BurstPoints     uint            `yaml:"burst-points" mapstructure:"burst-points"`
+	TruckRoutes     bool            `yaml:"truck-routes" mapstructure:"truck-routes"`
+
+	Streaming        bool    `yaml:"streaming" mapstructure:"streaming"`
+	StreamingSpeedup float64 `yaml:"streaming-speedup" mapstructure:"streaming-speedup"`
 }
@@ -5,6 +5,7 @@ import (
 	"github.com/spf13/pflag"
 	"github.com/timescale/tsbs/load"
 	"github.com/timescale/tsbs/pkg/data/source"
+	"github.com/timescale/tsbs/pkg/resources"
 	"strings"
 	"time"
 )
@@ -34,6 +35,13 @@ func addLoaderRunnerFlags(fs *pflag.FlagSet) {
 		"Whether to abort if a database with the given name already exists.",
 	)
 	fs.Duration("loader.runner.reporting-period", 10*time.Second, "Period to report write stats")
+	load.AddTargetRateFlags(fs, "loader.runner.")
+	load.AddErrorPolicyFlags(fs, "loader.runner.")
+	load.AddCheckpointFlags(fs, "loader.runner.")
+	load.AddBatchSizeFlags(fs, "loader.runner.")
+	resources.AddFlags(fs, "loader.runner.")
+	load.AddVerifyFlags(fs, "loader.runner.")
+	load.AddManifestFlags(fs, "loader.runner.")
 	fs.Int64("loader.runner.seed", 0, "PRNG seed (default: 0, which uses the current timestamp)")
 	fs.Bool(
 		"loader.runner.do-load",
@@ -47,6 +55,7 @@ func addLoaderRunnerFlags(fs *pflag.FlagSet) {
 	)
 	fs.Uint("loader.runner.workers", 1, "Number of parallel clients inserting")
 	fs.Uint64("loader.runner.limit", 0, "Number of items to insert (0 = all of them).")
+	fs.Duration("loader.runner.duration", 0, "Stop reading items after this long and insert the ones read so far (0 = no time limit)")
 	fs.String("loader.runner.db-name", "benchmark", "Name of database")
 	fs.Uint(
 		"loader.runner.batch-size",
@@ -78,7 +87,7 @@ func addDataSourceFlags(fs *pflag.FlagSet) {
 	fs.String(
 		"data-source.file.location",
 		"./file-from-tsbs-generate-data",
-		"If data-source.type=FILE, load the data from this file location",
+		"If data-source.type=FILE, load the data from this file location, or a comma separated list of files and glob patterns for the targets that read several files",
 	)
 	fs.String("data-source.simulator.use-case", "devops-generic", fmt.Sprintf("Use case to generate."))
 	fs.String("data-source.simulator.timestamp-start", defaultTimeStart, "Beginning timestamp (RFC3339).")
@@ -96,9 +105,25 @@ func addDataSourceFlags(fs *pflag.FlagSet) {
 		100,
 		"Max number of metric fields to generate per host. Used only in devops-generic use-case",
 	)
+	fs.String("data-source.simulator.schema", "", "YAML file declaring the measurements, tags and fields to generate. Used only in custom use-case")
 	fs.Uint64(
 		"data-source.simulator.scale",
 		defaultScale,
 		"Scaling value specific to use case (e.g., devices in 'devops', trucks in iot).")
 	fs.Duration("data-source.simulator.log-interval", defaultLogInterval, "Duration between data points")
+	fs.Uint("data-source.simulator.sensors-per-device", 1000, "Number of sensors of each industrial device, up to 10000")
+	fs.Uint("data-source.simulator.sensors-per-block", 100, "Number of sensors of an industrial device written together in a block")
+	fs.Bool("data-source.simulator.streaming", false, "Stream the simulated points at wall clock time, with their timestamps rewritten to now")
+	fs.Float64("data-source.simulator.streaming-speedup", 1, "With streaming, how many times faster than real time simulated time passes")
+	fs.Float64("data-source.simulator.out-of-order-chance", 0, "Chance that a point is delayed by up to max-lateness, so it arrives after newer points")
+	fs.Duration("data-source.simulator.max-lateness", time.Minute, "Maximum delay, in simulated time, of an out-of-order point")
+	fs.Float64("data-source.simulator.missing-chance", 0, "Chance that a point is dropped")
+	fs.Float64("data-source.simulator.duplicate-chance", 0, "Chance that a point is emitted twice")
+	fs.Bool("data-source.simulator.non-numeric-fields", false, "Add string and boolean fields to the devops and iot measurements")
+	fs.Float64("data-source.simulator.churn-rate", 0, "Chance at each log interval that a devops host or iot truck is retired and replaced by one with a new name")
+	fs.Duration("data-source.simulator.timestamp-jitter", 0, "Each iot point is late on its schedule by a random duration below this")
+	fs.StringSlice("data-source.simulator.series-intervals", nil, "Intervals at which the iot trucks report, given to them in turn, each a multiple of log-interval")
+	fs.Float64("data-source.simulator.burst-chance", 0, "Chance that an iot point starts a burst of burst-points extra points")
+	fs.Uint("data-source.simulator.burst-points", 10, "Number of extra points of a burst")
+	fs.Bool("data-source.simulator.truck-routes", false, "Drive the iot trucks on a grid of roads, with their velocity and stops matching their moves")
 }
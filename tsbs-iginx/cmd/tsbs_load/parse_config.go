@@ -20,6 +20,11 @@ func parseConfig(target targets.ImplementedTarget, v *viper.Viper) (targets.Benc
 		return nil, nil, err
 	}
 	dataSourceInternal := convertDataSourceConfigToInternalRepresentation(target.TargetName(), dataSource)
+	if dataSourceInternal.Simulator != nil {
+		dataSourceInternal.Simulator.DisorderSet = common.DisorderChanceSet(func(key string) bool {
+			return v.IsSet("data-source.simulator." + key)
+		})
+	}
 
 	loaderViper := v.Sub("loader")
 	if loaderViper == nil {
@@ -37,6 +42,10 @@ func parseConfig(target targets.ImplementedTarget, v *viper.Viper) (targets.Benc
 	}
 
 	loaderConfigInternal := convertRunnerConfigToInternalRep(loaderConfig)
+	if loaderConfigInternal.Manifest != "" && dataSourceInternal.File != nil {
+		// the manifest is checked against the input file of the data source
+		loaderConfigInternal.FileName = dataSourceInternal.File.Location
+	}
 
 	dbSpecificViper := loaderViper.Sub("db-specific")
 	if dbSpecificViper == nil {
@@ -65,6 +74,7 @@ func convertRunnerConfigToInternalRep(r *RunnerConfig) *load.BenchmarkRunnerConf
 		BatchSize:       r.BatchSize,
 		Workers:         r.Workers,
 		Limit:           r.Limit,
+		Duration:        r.Duration,
 		DoLoad:          r.DoLoad,
 		DoCreateDB:      r.DoCreateDB,
 		DoAbortOnExist:  r.DoAbortOnExist,
@@ -74,6 +84,24 @@ func convertRunnerConfigToInternalRep(r *RunnerConfig) *load.BenchmarkRunnerConf
 		InsertIntervals: r.InsertIntervals,
 		NoFlowControl:   !r.FlowControl,
 		ChannelCapacity: r.ChannelCapacity,
+		TargetRate:      r.TargetRate,
+		TargetRateUnit:  r.TargetRateUnit,
+		OnError:         r.OnError,
+		MaxRetries:      r.MaxRetries,
+		RetryBackoff:    r.RetryBackoff,
+
+		CheckpointFile:       r.CheckpointFile,
+		CheckpointInterval:   r.CheckpointInterval,
+		ResumeFromCheckpoint: r.ResumeFromCheckpoint,
+
+		BatchBytes:        r.BatchBytes,
+		AdaptiveBatchSize: r.AdaptiveBatchSize,
+
+		ResourceSampleInterval: r.ResourceSampleInterval,
+		ServerPID:              r.ServerPID,
+
+		Verify:   r.Verify,
+		Manifest: r.Manifest,
 	}
 }
 
@@ -113,12 +141,16 @@ func parseDataSourceConfig(v *viper.Viper) (*DataSourceConfig, error) {
 		)
 		return nil, errors.New(errStr)
 	}
+	if conf.Simulator.Streaming && conf.Simulator.StreamingSpeedup <= 0 {
+		return nil, fmt.Errorf("streaming speedup must be positive, got %v", conf.Simulator.StreamingSpeedup)
+	}
 	return &conf, nil
 }
 
 func convertDataSourceConfigToInternalRepresentation(format string, d *DataSourceConfig) *source.DataSourceConfig {
 	var file *source.FileDataSourceConfig
 	var simulator *common.DataGeneratorConfig
+	var streaming *source.StreamingConfig
 	if d.Type == source.FileDataSourceType {
 		file = &source.FileDataSourceConfig{
 			Location: d.File.Location,
@@ -137,12 +169,30 @@ func convertDataSourceConfigToInternalRepresentation(format string, d *DataSourc
 			Limit:                 d.Simulator.Limit,
 			LogInterval:           d.Simulator.LogInterval,
 			MaxMetricCountPerHost: d.Simulator.MaxMetricCountPerHost,
+			Schema:                d.Simulator.Schema,
+			SensorsPerDevice:      d.Simulator.SensorsPerDevice,
+			SensorsPerBlock:       d.Simulator.SensorsPerBlock,
 			InterleavedNumGroups:  1,
+			OutOfOrderChance:      d.Simulator.OutOfOrderChance,
+			MaxLateness:           d.Simulator.MaxLateness,
+			MissingChance:         d.Simulator.MissingChance,
+			DuplicateChance:       d.Simulator.DuplicateChance,
+			NonNumericFields:      d.Simulator.NonNumericFields,
+			ChurnRate:             d.Simulator.ChurnRate,
+			TimestampJitter:       d.Simulator.TimestampJitter,
+			SeriesIntervals:       d.Simulator.SeriesIntervals,
+			BurstChance:           d.Simulator.BurstChance,
+			BurstPoints:           d.Simulator.BurstPoints,
+			TruckRoutes:           d.Simulator.TruckRoutes,
+		}
+		if d.Simulator.Streaming {
+			streaming = &source.StreamingConfig{Speedup: d.Simulator.StreamingSpeedup}
 		}
 	}
 	return &source.DataSourceConfig{
 		Type:      d.Type,
 		File:      file,
 		Simulator: simulator,
+		Streaming: streaming,
 	}
 }
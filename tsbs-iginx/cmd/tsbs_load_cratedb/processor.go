@@ -48,22 +48,29 @@ func (p *processor) createInsertStmt(table *tableDef) (string, error) {
 }
 
 // load.Processor interface implementation
-func (p *processor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uint64) {
+func (p *processor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uint64, error) {
 	eb := b.(*eventsBatch)
 	rowCnt := uint64(0)
 	metricCnt := uint64(0)
 
 	for table, rows := range eb.batches {
-		rowCnt += uint64(len(rows))
 		if doLoad {
-			metricCnt += p.InsertBatch(table, rows)
+			n, err := p.InsertBatch(table, rows)
+			if err != nil {
+				// the tables inserted so far are removed from the batch,
+				// a retry only inserts the rest
+				return metricCnt, rowCnt, err
+			}
+			metricCnt += n
 		}
+		rowCnt += uint64(len(rows))
+		delete(eb.batches, table)
 	}
-	return metricCnt, rowCnt
+	return metricCnt, rowCnt, nil
 }
 
 // load.Processor interface implementation
-func (p *processor) InsertBatch(table string, rows []*row) uint64 {
+func (p *processor) InsertBatch(table string, rows []*row) (uint64, error) {
 	metricCnt := uint64(0)
 	b := pgx.Batch{}
 	for _, row := range rows {
@@ -79,9 +86,9 @@ func (p *processor) InsertBatch(table string, rows []*row) uint64 {
 	}
 	batchResults := p.conn.SendBatch(context.Background(), &b)
 	if err := batchResults.Close(); err != nil {
-		fatal("failed to close a batch operation %v", err)
+		return 0, fmt.Errorf("failed to close a batch operation: %w", err)
 	}
-	return metricCnt
+	return metricCnt, nil
 }
 
 // load.ProcessorCloser interface implementation
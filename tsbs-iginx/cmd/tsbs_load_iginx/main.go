@@ -16,6 +16,7 @@ import (
 	"github.com/spf13/pflag"
 	"github.com/timescale/tsbs/internal/utils"
 	"github.com/timescale/tsbs/load"
+	"github.com/timescale/tsbs/pkg/resources"
 	"github.com/timescale/tsbs/pkg/targets"
 	"github.com/timescale/tsbs/pkg/targets/constants"
 	"github.com/timescale/tsbs/pkg/targets/initializers"
@@ -48,12 +49,22 @@ func init() {
 	pflag.CommandLine.Uint("batch-size", 10, "Number of items to batch together in a single insert")
 	pflag.CommandLine.Uint("workers", 1, "Number of parallel clients inserting")
 	pflag.CommandLine.Uint64("limit", 0, "Number of items to insert (0 = all of them).")
+	pflag.CommandLine.Duration("duration", 0, "Stop reading items after this long and insert the ones read so far (0 = no time limit)")
 	pflag.CommandLine.Bool("do-load", true, "Whether to write data. Set this flag to false to check input read speed.")
 	pflag.CommandLine.Duration("reporting-period", 10*time.Second, "Period to report write stats")
-	pflag.CommandLine.String("file", "/home/humanfy/tmp_data", "File name to read data from")
+	pflag.CommandLine.String("file", "/home/humanfy/tmp_data", "File name to read data from, or a comma separated list of files and glob patterns (gzip and zstd input is decompressed)")
 	pflag.CommandLine.Int64("seed", 0, "PRNG seed (default: 0, which uses the current timestamp)")
 	pflag.CommandLine.String("insert-intervals", "", "Time to wait between each insert, default '' => all workers insert ASAP. '1,2' = worker 1 waits 1s between inserts, worker 2 and others wait 2s")
 	pflag.CommandLine.Bool("hash-workers", false, "Whether to consistently hash insert data to the same workers (i.e., the data for a particular host always goes to the same worker)")
+	pflag.CommandLine.String("results-file", "", "Write the test results summary json to this file")
+	load.AddTargetRateFlags(pflag.CommandLine, "")
+	load.AddErrorPolicyFlags(pflag.CommandLine, "")
+	load.AddCheckpointFlags(pflag.CommandLine, "")
+	load.AddBatchSizeFlags(pflag.CommandLine, "")
+	resources.AddFlags(pflag.CommandLine, "")
+	load.AddVerifyFlags(pflag.CommandLine, "")
+	load.AddManifestFlags(pflag.CommandLine, "")
+	load.AddStreamingFlags(pflag.CommandLine, "")
 	target.TargetSpecificFlags("", pflag.CommandLine)
 	pflag.Parse()
 
@@ -76,7 +87,9 @@ func init() {
 type benchmark struct{}
 
 func (b *benchmark) GetDataSource() targets.DataSource {
-	return &fileDataSource{scanner: bufio.NewScanner(load.GetBufferedReader(config.FileName))}
+	return config.DataSource(target.Serializer(), func(br *bufio.Reader) targets.DataSource {
+		return &fileDataSource{scanner: bufio.NewScanner(br)}
+	})
 }
 
 func (b *benchmark) GetBatchFactory() targets.BatchFactory {
@@ -95,6 +108,10 @@ func (b *benchmark) GetDBCreator() targets.DBCreator {
 	return &dbCreator{}
 }
 
+func (b *benchmark) GetVerifier() targets.Verifier {
+	return &verifier{url: iginxRESTEndPoint}
+}
+
 func main() {
 	bufPool = sync.Pool{
 		New: func() interface{} {
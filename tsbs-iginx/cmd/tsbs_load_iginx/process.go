@@ -1,13 +1,16 @@
 package main
 
 import (
+	"bytes"
 	"fmt"
+	"io/ioutil"
 	"net"
 	"net/http"
 	"strconv"
 	"strings"
 
 	"github.com/timescale/tsbs/pkg/targets"
+	"github.com/timescale/tsbs/pkg/targets/influx"
 )
 
 // allows for testing
@@ -32,61 +35,104 @@ func (p *processor) Close(_ bool) {
 	defer p.ilpConn.Close()
 }
 
-func (p *processor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uint64) {
+func (p *processor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uint64, error) {
 	batch := b.(*batch)
 
-	// Write the batch: try until backoff is not needed.
 	if !doLoad {
-		return 0, 0
+		return 0, 0, nil
 	}
 
-	lines := strings.Split(batch.buf.String(), "\n")
-	lines = lines[0 : len(lines)-1]
-
-	json := "["
-	i := 0
-	for i = 0; i < len(lines); i++ {
-		tmp := strings.Split(lines[i], " ")
-		tmp[0] = "type=" + tmp[0]
-		fir := strings.Split(tmp[0], ",")
-		var tags map[string]string
-		tags = make(map[string]string)
-		j := 0
-		for j = 0; j < len(fir); j++ {
-			kv := strings.Split(fir[j], "=")
-			tags[kv[0]] = kv[1]
-		}
-		timestamp, _ := strconv.ParseInt(tmp[2], 10, 64)
-		timestamp /= 1000000
-		sec := strings.Split(tmp[1], ",")
-		for j = 0; j < len(sec); j++ {
-			// fmt.Sprintf("[DEBUG] name: %s",sec[j])
-			kv := strings.Split(sec[j], "=")
-			json += fmt.Sprintf(`{
+	body := bufPool.Get().(*bytes.Buffer)
+	writeBody(body, batch)
+	_, err := execQuery(iginxRESTEndPoint, body.String())
+	body.Reset()
+	bufPool.Put(body)
+	if err != nil {
+		// keep the batch buffer, the batch may be retried
+		return 0, 0, err
+	}
+	metricCnt := batch.metrics
+	rowCnt := batch.rows
+
+	// Return the batch buffer to the pool.
+	batch.buf.Reset()
+	bufPool.Put(batch.buf)
+	return metricCnt, uint64(rowCnt), nil
+}
+
+// Formats of the JSON object of a data point and of each of its tags
+const (
+	pointJSONFmt = `{
       			"name": "%s",
       			"timestamp": %d,
       			"value": %s,
-      			"tags": {`, kv[0], timestamp, kv[1])
-			for key, value := range tags {
-				json += fmt.Sprintf(`"%s" : "%s",`, key, value)
-			}
-			json = json[0 : len(json)-1]
-			json += "}},"
+      			"tags": {`
+	tagJSONFmt = `"%s" : "%s"`
+)
+
+// writeBody writes the REST insert request body of a batch
+func writeBody(w *bytes.Buffer, b *batch) {
+	w.WriteByte('[')
+	lines := strings.Split(strings.TrimSuffix(b.buf.String(), "\n"), "\n")
+	for i, line := range lines {
+		if i > 0 {
+			w.WriteByte(',')
 		}
+		writeJSON(w, line)
 	}
+	w.WriteByte(']')
+}
 
-	json = json[0 : len(json)-1]
-	json += "]"
-	fmt.Println(json)
+// writeJSON writes the data points of an influx line as comma separated JSON
+// objects, one for each field. A quoted string value is written as is: the
+// line protocol escapes the same bytes as JSON in it.
+func writeJSON(w *bytes.Buffer, line string) {
+	args := influx.SplitLine(line, ' ')
+	args[0] = "type=" + args[0]
+	tags := make(map[string]string)
+	for _, tag := range strings.Split(args[0], ",") {
+		kv := strings.SplitN(tag, "=", 2)
+		tags[kv[0]] = kv[1]
+	}
+	timestamp, _ := strconv.ParseInt(args[2], 10, 64)
+	timestamp /= 1000000
+	for i, field := range influx.SplitLine(args[1], ',') {
+		kv := strings.SplitN(field, "=", 2)
+		if i > 0 {
+			w.WriteByte(',')
+		}
+		fmt.Fprintf(w, pointJSONFmt, kv[0], timestamp, kv[1])
+		first := true
+		for key, value := range tags {
+			if !first {
+				w.WriteByte(',')
+			}
+			first = false
+			fmt.Fprintf(w, tagJSONFmt, key, value)
+		}
+		w.WriteString("}}")
+	}
+}
 
-	execQuery(iginxRESTEndPoint, json)
-	metricCnt := batch.metrics
-	rowCnt := batch.rows
+// jsonSize returns the size of what writeJSON writes for a line with the given
+// tags, fields and timestamp, without rendering it: every "key=value" turns
+// into the format around the key and the value.
+func jsonSize(tags, fields string, numFields int, timestamp string) uint64 {
+	numTags := strings.Count(tags, ",") + 1
+	tagsSize := len("type=") + len(tags) + numTags*(len(tagJSONFmt)-len("%s%s")-len("="))
+	ts, _ := strconv.ParseInt(timestamp, 10, 64)
+	pointSize := len(pointJSONFmt) - len("%s%d%s") + len(strconv.FormatInt(ts/1000000, 10)) + tagsSize + len("}}")
+	// the names and values of the fields, less the "=" and the "," between
+	// them, plus the "," between the objects
+	namesAndValues := len(fields) - numFields - (numFields - 1)
+	return uint64(numFields*pointSize + namesAndValues + numFields - 1)
+}
 
-	// Return the batch buffer to the pool.
+// DropBatch returns the buffer of a batch that will not be retried to the pool
+func (p *processor) DropBatch(b targets.Batch) {
+	batch := b.(*batch)
 	batch.buf.Reset()
 	bufPool.Put(batch.buf)
-	return metricCnt, uint64(rowCnt)
 }
 
 func execQuery(uriRoot string, query string) (QueryResponse, error) {
@@ -100,5 +146,9 @@ func execQuery(uriRoot string, query string) (QueryResponse, error) {
 		return qr, err
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return qr, targets.HTTPStatusError(resp.StatusCode, body)
+	}
 	return qr, nil
 }
@@ -3,11 +3,11 @@ package main
 import (
 	"bufio"
 	"bytes"
-	"strings"
 
 	"github.com/timescale/tsbs/pkg/data"
 	"github.com/timescale/tsbs/pkg/data/usecases/common"
 	"github.com/timescale/tsbs/pkg/targets"
+	"github.com/timescale/tsbs/pkg/targets/influx"
 )
 
 const errNotThreeTuplesFmt = "parse error: line does not have 3 tuples, has %d"
@@ -31,28 +31,61 @@ func (d *fileDataSource) NextItem() data.LoadedPoint {
 
 func (d *fileDataSource) Headers() *common.GeneratedDataHeaders { return nil }
 
+// batch holds the lines of its data points; they are rendered as the JSON
+// objects of an IginX REST insert request by the workers
 type batch struct {
 	buf     *bytes.Buffer
 	rows    uint
 	metrics uint64
+	// size is the estimated size of the JSON objects of the batch
+	size uint64
 }
 
 func (b *batch) Len() uint {
 	return b.rows
 }
 
+// Bytes implements targets.SizedBatch, it is the estimated size of the REST
+// request body
+func (b *batch) Bytes() uint64 {
+	return b.size + 2
+}
+
+// AppendBytes implements targets.SizedBatch
+func (b *batch) AppendBytes(item data.LoadedPoint) uint64 {
+	args := influx.SplitLine(string(item.Data.([]byte)), ' ')
+	if len(args) != 3 {
+		// Append rejects the line
+		return 0
+	}
+	return b.appendBytes(args)
+}
+
+// appendBytes returns the estimated size of the JSON objects of a line split
+// into its tags, fields and timestamp
+func (b *batch) appendBytes(args []string) uint64 {
+	size := jsonSize(args[0], args[1], len(influx.SplitLine(args[1], ',')), args[2])
+	if b.size > 0 {
+		// the "," between the objects of this line and the previous one
+		size++
+	}
+	return size
+}
+
 func (b *batch) Append(item data.LoadedPoint) {
 	that := item.Data.([]byte)
 	thatStr := string(that)
 	b.rows++
 	// Each influx line is format "csv-tags csv-fields timestamp", so we split by space
-	// and then on the middle element, we split by comma to count number of fields added
-	args := strings.Split(thatStr, " ")
+	// and then on the middle element, we split by comma to count number of fields added,
+	// leaving alone the spaces and commas of quoted string values
+	args := influx.SplitLine(thatStr, ' ')
 	if len(args) != 3 {
 		fatal(errNotThreeTuplesFmt, len(args))
 		return
 	}
-	b.metrics += uint64(len(strings.Split(args[1], ",")))
+	b.metrics += uint64(len(influx.SplitLine(args[1], ',')))
+	b.size += b.appendBytes(args)
 
 	b.buf.Write(that)
 	b.buf.Write(newLine)
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/timescale/tsbs/pkg/data"
+)
+
+func TestBatchBytes(t *testing.T) {
+	bufPool = sync.Pool{
+		New: func() interface{} {
+			return bytes.NewBuffer(make([]byte, 0, 1024))
+		},
+	}
+	lines := []string{
+		"cpu,hostname=host_0,region=eu-west-1 usage_user=58,usage_system=2.5 1451606400000000000",
+		"diagnostics,name=truck_1 load_capacity=1500,fuel_state=0.75 1451606410000000000",
+		"mem,hostname=host_10 used=9 14516064",
+		`cpu,hostname=host_1 log_level="a b,c=d \"e\" \\",throttled=true 1451606420000000000`,
+	}
+	f := &factory{}
+	b := f.New().(*batch)
+	var body bytes.Buffer
+	for _, line := range lines {
+		before := b.Bytes()
+		item := data.LoadedPoint{Data: []byte(line)}
+		appended := b.AppendBytes(item)
+		b.Append(item)
+		if got := b.Bytes() - before; got != appended {
+			t.Errorf("incorrect appended bytes of %q: got %d want %d", line, appended, got)
+		}
+
+		body.Reset()
+		writeBody(&body, b)
+		if !json.Valid(body.Bytes()) {
+			t.Fatalf("body is not valid JSON: %s", body.String())
+		}
+		if got, want := b.Bytes(), uint64(body.Len()); got != want {
+			t.Errorf("incorrect size after %d lines: got %d want %d", b.Len(), got, want)
+		}
+	}
+	if b.metrics != 7 {
+		t.Errorf("incorrect metric count: got %d want 7", b.metrics)
+	}
+
+	var points []struct {
+		Name  string
+		Value interface{}
+	}
+	if err := json.Unmarshal(body.Bytes(), &points); err != nil {
+		t.Fatalf("unexpected error decoding the body: %v", err)
+	}
+	if got := points[len(points)-2]; got.Name != "log_level" || got.Value != `a b,c=d "e" \` {
+		t.Errorf("incorrect string point: got %s=%v", got.Name, got.Value)
+	}
+	if got := points[len(points)-1]; got.Name != "throttled" || got.Value != true {
+		t.Errorf("incorrect boolean point: got %s=%v", got.Name, got.Value)
+	}
+}
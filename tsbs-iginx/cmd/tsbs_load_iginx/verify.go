@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/targets"
+	"github.com/timescale/tsbs/pkg/targets/influx"
+)
+
+// verifyRangeYears is the time range, from the epoch, the points of a
+// measurement are counted over
+const verifyRangeYears = 1000
+
+// verifier counts the points of every field of a measurement, stored as a
+// metric tagged with type=<measurement>, with the REST query API
+type verifier struct {
+	url string
+}
+
+// Inspect implements targets.Verifier, timestamps are stored in milliseconds
+func (v *verifier) Inspect(item data.LoadedPoint) (string, []string, time.Time, error) {
+	measurement, fields, ts, err := influx.ParseLine(item.Data.([]byte))
+	if err != nil {
+		return "", nil, time.Time{}, err
+	}
+	return measurement, fields, time.Unix(0, ts/1e6*1e6), nil
+}
+
+// metricQuery is a metric of a KairosDB compatible query
+type metricQuery struct {
+	Name        string              `json:"name"`
+	Tags        map[string][]string `json:"tags"`
+	Aggregators []aggregator        `json:"aggregators,omitempty"`
+	Limit       int                 `json:"limit,omitempty"`
+	Order       string              `json:"order,omitempty"`
+}
+
+type aggregator struct {
+	Name     string   `json:"name"`
+	Sampling sampling `json:"sampling"`
+}
+
+type sampling struct {
+	Value int    `json:"value"`
+	Unit  string `json:"unit"`
+}
+
+type verifyResponse struct {
+	Queries []struct {
+		Results []verifyResult `json:"results"`
+	} `json:"queries"`
+}
+
+// verifyResult holds the [timestamp, value] pairs of a metric, the values of
+// string and boolean fields not being numbers
+type verifyResult struct {
+	Values [][]interface{} `json:"values"`
+}
+
+// Stored implements targets.Verifier. For every field it queries the number
+// of points, and the first and last point.
+func (v *verifier) Stored(_ string, fields map[string][]string) (map[string]targets.MeasurementStats, error) {
+	stored := make(map[string]targets.MeasurementStats)
+	for measurement, names := range fields {
+		var metrics []metricQuery
+		tags := map[string][]string{"type": {measurement}}
+		for _, name := range names {
+			metrics = append(metrics,
+				metricQuery{Name: name, Tags: tags, Aggregators: []aggregator{{Name: "count", Sampling: sampling{Value: verifyRangeYears, Unit: "years"}}}},
+				metricQuery{Name: name, Tags: tags, Limit: 1, Order: "asc"},
+				metricQuery{Name: name, Tags: tags, Limit: 1, Order: "desc"},
+			)
+		}
+		resp, err := v.query(metrics)
+		if err != nil {
+			return nil, fmt.Errorf("measurement %s: %v", measurement, err)
+		}
+		if len(resp.Queries) != len(metrics) {
+			return nil, fmt.Errorf("measurement %s: expected %d query results, got %d", measurement, len(metrics), len(resp.Queries))
+		}
+		var stats targets.MeasurementStats
+		for i := 0; i < len(metrics); i += 3 {
+			for _, r := range resp.Queries[i].Results {
+				for _, value := range r.Values {
+					if len(value) == 2 {
+						if count, ok := value[1].(float64); ok {
+							stats.Points += uint64(count)
+						}
+					}
+				}
+			}
+			if first, ok := firstTimestamp(resp.Queries[i+1].Results); ok && (stats.First.IsZero() || first.Before(stats.First)) {
+				stats.First = first
+			}
+			if last, ok := firstTimestamp(resp.Queries[i+2].Results); ok && last.After(stats.Last) {
+				stats.Last = last
+			}
+		}
+		stored[measurement] = stats
+	}
+	return stored, nil
+}
+
+// firstTimestamp returns the timestamp of the first value of the results
+func firstTimestamp(results []verifyResult) (time.Time, bool) {
+	for _, r := range results {
+		if len(r.Values) > 0 && len(r.Values[0]) > 0 {
+			if ts, ok := r.Values[0][0].(float64); ok {
+				return time.Unix(0, int64(ts)*int64(time.Millisecond)), true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+func (v *verifier) query(metrics []metricQuery) (*verifyResponse, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"start_absolute": 0,
+		"end_absolute":   time.Now().AddDate(verifyRangeYears/2, 0, 0).UnixNano() / int64(time.Millisecond),
+		"metrics":        metrics,
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.Post(strings.TrimSuffix(v.url, "/")+"/api/v1/datapoints/query", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, targets.HTTPStatusError(resp.StatusCode, respBody)
+	}
+	var r verifyResponse
+	if err = json.Unmarshal(respBody, &r); err != nil {
+		return nil, fmt.Errorf("cannot decode response: %v", err)
+	}
+	return &r, nil
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/targets"
+)
+
+func TestVerifierInspect(t *testing.T) {
+	v := &verifier{}
+	measurement, fields, ts, err := v.Inspect(data.NewLoadedPoint([]byte("cpu,hostname=host_0 usage_user=1,usage_system=2 1451606400001000001")))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if measurement != "cpu" || len(fields) != 2 || !ts.Equal(time.Unix(0, 1451606400001000000)) {
+		t.Errorf("incorrect result: %s %v %v", measurement, fields, ts)
+	}
+}
+
+func TestVerifierStored(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/datapoints/query" {
+			t.Errorf("incorrect path: got %s", r.URL.Path)
+		}
+		var req struct {
+			Metrics []metricQuery `json:"metrics"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("cannot decode request: %v", err)
+		}
+		if len(req.Metrics) != 6 {
+			t.Fatalf("incorrect number of metrics: got %d want 6", len(req.Metrics))
+		}
+		for _, m := range req.Metrics {
+			if got := m.Tags["type"]; len(got) != 1 || got[0] != "cpu" {
+				t.Errorf("incorrect type tag of %s: %v", m.Name, got)
+			}
+		}
+		if m := req.Metrics[0]; m.Name != "usage_system" || len(m.Aggregators) != 1 || m.Aggregators[0].Name != "count" {
+			t.Errorf("incorrect count query: %+v", m)
+		}
+		if m := req.Metrics[4]; m.Name != "usage_user" || m.Order != "asc" || m.Limit != 1 {
+			t.Errorf("incorrect first point query: %+v", m)
+		}
+		// the counts of a field may come in several samples and results,
+		// the values of string fields are not numbers
+		fmt.Fprint(w, `{"queries":[
+			{"results":[{"values":[[0,10]]}]},
+			{"results":[{"values":[[1451606400000,"on"]]}]},
+			{"results":[{"values":[[1451606405000,"off"]]}]},
+			{"results":[{"values":[[0,8],[1000,3]]},{"values":[[0,1]]}]},
+			{"results":[{"values":[[1451606401000,1.5]]}]},
+			{"results":[{"values":[[1451606410000,2.5]]}]}
+		]}`)
+	}))
+	defer server.Close()
+
+	v := &verifier{url: server.URL}
+	stored, err := v.Stored("benchmark", map[string][]string{"cpu": {"usage_system", "usage_user"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := targets.MeasurementStats{Points: 22, First: time.Unix(1451606400, 0), Last: time.Unix(1451606410, 0)}
+	if got := stored["cpu"]; got.Points != want.Points || !got.First.Equal(want.First) || !got.Last.Equal(want.Last) {
+		t.Errorf("incorrect stats: got %+v want %+v", got, want)
+	}
+}
+
+func TestVerifierStoredError(t *testing.T) {
+	cases := []struct {
+		desc    string
+		handler http.HandlerFunc
+	}{
+		{
+			desc: "server error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "internal error", http.StatusInternalServerError)
+			},
+		},
+		{
+			desc: "missing query results",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"queries":[{"results":[]}]}`)
+			},
+		},
+		{
+			desc: "invalid response",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `not json`)
+			},
+		},
+	}
+	for _, c := range cases {
+		server := httptest.NewServer(c.handler)
+		v := &verifier{url: server.URL}
+		if _, err := v.Stored("benchmark", map[string][]string{"cpu": {"usage_user"}}); err == nil {
+			t.Errorf("%s: unexpected lack of error", c.desc)
+		}
+		server.Close()
+	}
+}
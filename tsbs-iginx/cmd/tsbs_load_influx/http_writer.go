@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"time"
 
+	"github.com/timescale/tsbs/pkg/targets"
 	"github.com/valyala/fasthttp"
 )
 
@@ -84,7 +85,8 @@ func (w *HTTPWriter) executeReq(req *fasthttp.Request, resp *fasthttp.Response)
 		if sc == 500 && backpressurePred(resp.Body()) {
 			err = errBackoff
 		} else if sc != fasthttp.StatusNoContent {
-			err = fmt.Errorf("[DebugInfo: %s] Invalid write response (status %d): %s", w.c.DebugInfo, sc, resp.Body())
+			err = targets.HTTPStatusError(sc, resp.Body())
+			err = fmt.Errorf("[DebugInfo: %s] Invalid write response: %w", w.c.DebugInfo, err)
 		}
 	}
 	return lat, err
@@ -91,7 +91,9 @@ func init() {
 type benchmark struct{}
 
 func (b *benchmark) GetDataSource() targets.DataSource {
-	return &fileDataSource{scanner: bufio.NewScanner(load.GetBufferedReader(config.FileName))}
+	return config.DataSource(target.Serializer(), func(br *bufio.Reader) targets.DataSource {
+		return &fileDataSource{scanner: bufio.NewScanner(br)}
+	})
 }
 
 func (b *benchmark) GetBatchFactory() targets.BatchFactory {
@@ -110,6 +112,10 @@ func (b *benchmark) GetDBCreator() targets.DBCreator {
 	return &dbCreator{}
 }
 
+func (b *benchmark) GetVerifier() targets.Verifier {
+	return &verifier{url: daemonURLs[0]}
+}
+
 func main() {
 	bufPool = sync.Pool{
 		New: func() interface{} {
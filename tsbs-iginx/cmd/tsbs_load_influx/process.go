@@ -43,7 +43,7 @@ func (p *processor) Close(_ bool) {
 	<-p.backingOffDone
 }
 
-func (p *processor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uint64) {
+func (p *processor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uint64, error) {
 	batch := b.(*batch)
 
 	// Write the batch: try until backoff is not needed.
@@ -70,7 +70,8 @@ func (p *processor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uint64)
 			}
 		}
 		if err != nil {
-			fatal("Error writing: %s\n", err.Error())
+			// keep the batch buffer, the batch may be retried
+			return 0, 0, err
 		}
 	}
 	metricCnt := batch.metrics
@@ -79,7 +80,14 @@ func (p *processor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uint64)
 	// Return the batch buffer to the pool.
 	batch.buf.Reset()
 	bufPool.Put(batch.buf)
-	return metricCnt, uint64(rowCnt)
+	return metricCnt, uint64(rowCnt), nil
+}
+
+// DropBatch returns the buffer of a batch that will not be retried to the pool
+func (p *processor) DropBatch(b targets.Batch) {
+	batch := b.(*batch)
+	batch.buf.Reset()
+	bufPool.Put(batch.buf)
 }
 
 func (p *processor) processBackoffMessages(workerID int) {
@@ -102,7 +102,7 @@ func TestProcessorProcessBatch(t *testing.T) {
 		doLoad        bool
 		useGzip       bool
 		shouldBackoff bool
-		shouldFatal   bool
+		shouldError   bool
 	}{
 		{
 			doLoad:  false,
@@ -123,22 +123,13 @@ func TestProcessorProcessBatch(t *testing.T) {
 		},
 		{
 			doLoad:      true,
-			shouldFatal: true,
+			shouldError: true,
 		},
 	}
 
 	for _, c := range cases {
 		var ch chan struct{}
-		fatalCalled := false
-		if c.shouldFatal {
-			fatal = func(format string, args ...interface{}) {
-				fatalCalled = true
-			}
-		} else {
-			fatal = func(format string, args ...interface{}) {
-				t.Errorf("fatal called for case %v unexpectedly\n", c)
-				fmt.Printf(format, args...)
-			}
+		if !c.shouldError {
 			ch = launchHTTPServer()
 		}
 
@@ -155,13 +146,24 @@ func TestProcessorProcessBatch(t *testing.T) {
 
 		p.initWithHTTPWriter(0, w)
 		useGzip = c.useGzip
-		mCnt, rCnt := p.ProcessBatch(b, c.doLoad)
-		if c.shouldFatal {
-			if !fatalCalled {
-				t.Errorf("fatal was not called when it should have been")
+		if c.shouldError {
+			// the buffer of b was released by the previous cases
+			b = f.New().(*batch)
+			b.Append(pt)
+		}
+		mCnt, rCnt, err := p.ProcessBatch(b, c.doLoad)
+		if c.shouldError {
+			if err == nil {
+				t.Errorf("no error returned when there should have been")
+			}
+			if b.buf.Len() == 0 {
+				t.Errorf("batch buffer released after an error")
 			}
 			continue
 		} else {
+			if err != nil {
+				t.Errorf("unexpected error for case %v: %v", c, err)
+			}
 			if mCnt != b.metrics {
 				t.Errorf("process batch returned less metrics than batch: got %d want %d", mCnt, b.metrics)
 			}
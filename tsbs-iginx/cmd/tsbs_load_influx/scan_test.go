@@ -49,6 +49,14 @@ func TestBatch(t *testing.T) {
 		t.Errorf("batch metric count is not 2 after first append")
 	}
 
+	p = data.LoadedPoint{
+		Data: []byte(`tag1=tag1val col1="a b,c=d",col2=1.0 200`),
+	}
+	b.Append(p)
+	if b.metrics != 6 {
+		t.Errorf("batch metric count is not 6 after a string field with a space and a comma")
+	}
+
 	p = data.LoadedPoint{
 		Data: []byte("bad_point"),
 	}
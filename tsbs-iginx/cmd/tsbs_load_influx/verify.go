@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/targets"
+	"github.com/timescale/tsbs/pkg/targets/influx"
+)
+
+// verifier counts the points of every measurement, and queries its first and
+// last point, with InfluxQL
+type verifier struct {
+	url string
+}
+
+// Inspect implements targets.Verifier
+func (v *verifier) Inspect(item data.LoadedPoint) (string, []string, time.Time, error) {
+	measurement, fields, ts, err := influx.ParseLine(item.Data.([]byte))
+	if err != nil {
+		return "", nil, time.Time{}, err
+	}
+	return measurement, fields, time.Unix(0, ts), nil
+}
+
+// queryResponse is the response of the /query endpoint, with one result per
+// statement
+type queryResponse struct {
+	Results []struct {
+		Error  string   `json:"error"`
+		Series []series `json:"series"`
+	} `json:"results"`
+}
+
+// series holds the rows of a statement, numbers are decoded as json.Number
+type series struct {
+	Columns []string        `json:"columns"`
+	Values  [][]interface{} `json:"values"`
+}
+
+// Stored implements targets.Verifier
+func (v *verifier) Stored(dbName string, fields map[string][]string) (map[string]targets.MeasurementStats, error) {
+	stored := make(map[string]targets.MeasurementStats)
+	for measurement := range fields {
+		q := fmt.Sprintf(`SELECT COUNT(*) FROM "%[1]s"; SELECT * FROM "%[1]s" ORDER BY time ASC LIMIT 1; SELECT * FROM "%[1]s" ORDER BY time DESC LIMIT 1`, measurement)
+		resp, err := v.query(dbName, q)
+		if err != nil {
+			return nil, fmt.Errorf("measurement %s: %v", measurement, err)
+		}
+		if len(resp.Results) != 3 {
+			return nil, fmt.Errorf("measurement %s: expected 3 results, got %d", measurement, len(resp.Results))
+		}
+		for _, r := range resp.Results {
+			if r.Error != "" {
+				return nil, fmt.Errorf("measurement %s: %s", measurement, r.Error)
+			}
+		}
+
+		var stats targets.MeasurementStats
+		for _, s := range resp.Results[0].Series {
+			for _, row := range s.Values {
+				for i, value := range row {
+					if i < len(s.Columns) && s.Columns[i] == "time" {
+						continue
+					}
+					if n, ok := value.(json.Number); ok {
+						if count, err := n.Int64(); err == nil {
+							stats.Points += uint64(count)
+						}
+					}
+				}
+			}
+		}
+		if stats.First, err = rowTime(resp.Results[1].Series); err != nil {
+			return nil, fmt.Errorf("measurement %s: %v", measurement, err)
+		}
+		if stats.Last, err = rowTime(resp.Results[2].Series); err != nil {
+			return nil, fmt.Errorf("measurement %s: %v", measurement, err)
+		}
+		stored[measurement] = stats
+	}
+	return stored, nil
+}
+
+// rowTime returns the time of the first row of the series, or the zero time
+// if there is none
+func rowTime(rows []series) (time.Time, error) {
+	for _, s := range rows {
+		if len(s.Values) == 0 {
+			continue
+		}
+		for i, c := range s.Columns {
+			if c != "time" || i >= len(s.Values[0]) {
+				continue
+			}
+			n, _ := s.Values[0][i].(json.Number)
+			ns, err := n.Int64()
+			if err != nil {
+				return time.Time{}, fmt.Errorf("invalid time %s: %v", s.Values[0][i], err)
+			}
+			return time.Unix(0, ns), nil
+		}
+	}
+	return time.Time{}, nil
+}
+
+func (v *verifier) query(dbName, q string) (*queryResponse, error) {
+	u := fmt.Sprintf("%s/query?db=%s&epoch=ns&q=%s", strings.TrimSuffix(v.url, "/"), url.QueryEscape(dbName), url.QueryEscape(q))
+	resp, err := http.Get(u)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, targets.HTTPStatusError(resp.StatusCode, body)
+	}
+	var r queryResponse
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err = dec.Decode(&r); err != nil {
+		return nil, fmt.Errorf("cannot decode response: %v", err)
+	}
+	return &r, nil
+}
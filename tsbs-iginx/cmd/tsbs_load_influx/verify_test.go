@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/targets"
+)
+
+func TestVerifierInspect(t *testing.T) {
+	v := &verifier{}
+	measurement, fields, ts, err := v.Inspect(data.NewLoadedPoint([]byte("cpu,hostname=host_0 usage_user=1,usage_system=2 1451606400000000001")))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if measurement != "cpu" || len(fields) != 2 || !ts.Equal(time.Unix(0, 1451606400000000001)) {
+		t.Errorf("incorrect result: %s %v %v", measurement, fields, ts)
+	}
+}
+
+func TestVerifierStored(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("db"); got != "benchmark" {
+			t.Errorf("incorrect db: got %s", got)
+		}
+		if r.URL.Query().Get("epoch") != "ns" {
+			t.Errorf("timestamps not requested in ns")
+		}
+		fmt.Fprint(w, `{"results":[
+			{"series":[{"name":"cpu","columns":["time","count_usage_user","count_usage_system"],"values":[[0,10,12]]}]},
+			{"series":[{"name":"cpu","columns":["time","hostname","usage_user"],"values":[[1451606400000000000,"host_0",1.5]]}]},
+			{"series":[{"name":"cpu","columns":["time","hostname","usage_user"],"values":[[1451606410000000000,"host_1",null]]}]}
+		]}`)
+	}))
+	defer server.Close()
+
+	v := &verifier{url: server.URL}
+	stored, err := v.Stored("benchmark", map[string][]string{"cpu": {"usage_system", "usage_user"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := targets.MeasurementStats{Points: 22, First: time.Unix(1451606400, 0), Last: time.Unix(1451606410, 0)}
+	if got := stored["cpu"]; got.Points != want.Points || !got.First.Equal(want.First) || !got.Last.Equal(want.Last) {
+		t.Errorf("incorrect stats: got %+v want %+v", got, want)
+	}
+}
+
+func TestVerifierStoredError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"results":[{"error":"database not found: benchmark"},{},{}]}`)
+	}))
+	defer server.Close()
+
+	v := &verifier{url: server.URL}
+	if _, err := v.Stored("benchmark", map[string][]string{"cpu": {"usage_user"}}); err == nil {
+		t.Errorf("unexpected lack of error")
+	}
+}
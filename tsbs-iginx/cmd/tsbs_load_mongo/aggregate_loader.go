@@ -3,7 +3,6 @@ package main
 import (
 	"fmt"
 	"hash/fnv"
-	"log"
 	"sync"
 	"time"
 
@@ -120,7 +119,7 @@ func (p *aggProcessor) Init(_ int, doLoad, _ bool) {
 //      ]
 //    ]
 //  }
-func (p *aggProcessor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uint64) {
+func (p *aggProcessor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uint64, error) {
 	docToEvents := make(map[string][]*point)
 	batch := b.(*batch)
 
@@ -175,8 +174,13 @@ func (p *aggProcessor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uint6
 	if doLoad {
 		// Checks if any new documents need to be made and does so
 		bulk := p.collection.Bulk()
-		bulk = insertNewAggregateDocs(p.collection, bulk, p.createQueue)
-		p.createQueue = p.createQueue[:0]
+		bulk, created, err := insertNewAggregateDocs(p.collection, bulk, p.createQueue)
+		// documents that were not created stay queued for the retry
+		p.createQueue = p.createQueue[created:]
+		if err != nil {
+			putPoints(docToEvents)
+			return 0, 0, fmt.Errorf("bulk aggregate docs err: %w", err)
+		}
 
 		// For each document, create one 'set' command for all records
 		// that belong to the document
@@ -198,24 +202,28 @@ func (p *aggProcessor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uint6
 		}
 
 		// All documents accounted for, finally run the operation
-		_, err := bulk.Run()
+		_, err = bulk.Run()
+		putPoints(docToEvents)
 		if err != nil {
-			log.Fatalf("Bulk aggregate update err: %s\n", err.Error())
+			return 0, 0, fmt.Errorf("bulk aggregate update err: %w", err)
 		}
+	}
+	return eventCnt, 0, nil
+}
 
-		for _, events := range docToEvents {
-			for _, e := range events {
-				delete(e.Fields, timestampField)
-				pPool.Put(e)
-			}
+// putPoints returns the points of a processed batch to the pool
+func putPoints(docToEvents map[string][]*point) {
+	for _, events := range docToEvents {
+		for _, e := range events {
+			delete(e.Fields, timestampField)
+			pPool.Put(e)
 		}
 	}
-	return eventCnt, 0
 }
 
 // insertNewAggregateDocs handles creating new aggregated documents when new devices
-// or time periods are encountered
-func insertNewAggregateDocs(collection *mgo.Collection, bulk *mgo.Bulk, createQueue []interface{}) *mgo.Bulk {
+// or time periods are encountered. It returns the number of documents created.
+func insertNewAggregateDocs(collection *mgo.Collection, bulk *mgo.Bulk, createQueue []interface{}) (*mgo.Bulk, int, error) {
 	b := bulk
 	if len(createQueue) > 0 {
 		off := 0
@@ -228,7 +236,7 @@ func insertNewAggregateDocs(collection *mgo.Collection, bulk *mgo.Bulk, createQu
 			b.Insert(createQueue[off:l]...)
 			_, err := b.Run()
 			if err != nil {
-				log.Fatalf("Bulk aggregate docs err: %s\n", err.Error())
+				return b, off, err
 			}
 			b = collection.Bulk()
 
@@ -236,5 +244,5 @@ func insertNewAggregateDocs(collection *mgo.Collection, bulk *mgo.Bulk, createQu
 		}
 	}
 
-	return b
+	return b, len(createQueue), nil
 }
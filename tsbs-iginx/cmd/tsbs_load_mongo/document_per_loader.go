@@ -1,7 +1,7 @@
 package main
 
 import (
-	"log"
+	"fmt"
 	"sync"
 
 	"github.com/globalsign/mgo"
@@ -56,7 +56,7 @@ func (p *naiveProcessor) Init(_ int, doLoad, _ bool) {
 // ProcessBatch creates a new document for each incoming event for a simpler
 // approach to storing the data. This is _NOT_ the default since the aggregation method
 // is recommended by Mongo and other blogs
-func (p *naiveProcessor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uint64) {
+func (p *naiveProcessor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uint64, error) {
 	batch := b.(*batch).arr
 	if cap(p.pvs) < len(batch) {
 		p.pvs = make([]interface{}, len(batch))
@@ -84,17 +84,18 @@ func (p *naiveProcessor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uin
 		metricCnt += uint64(event.FieldsLength())
 	}
 
+	var err error
 	if doLoad {
 		bulk := p.collection.Bulk()
 		bulk.Insert(p.pvs...)
-		_, err := bulk.Run()
-		if err != nil {
-			log.Fatalf("Bulk insert docs err: %s\n", err.Error())
-		}
+		_, err = bulk.Run()
 	}
 	for _, p := range p.pvs {
 		spPool.Put(p)
 	}
+	if err != nil {
+		return 0, 0, fmt.Errorf("bulk insert docs err: %w", err)
+	}
 
-	return metricCnt, 0
+	return metricCnt, 0, nil
 }
@@ -29,7 +29,7 @@ func (p *processor) Close(_ bool) {
 	defer p.ilpConn.Close()
 }
 
-func (p *processor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uint64) {
+func (p *processor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uint64, error) {
 	batch := b.(*batch)
 
 	// Write the batch: try until backoff is not needed.
@@ -37,7 +37,9 @@ func (p *processor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uint64)
 		var err error
 		_, err = p.ilpConn.Write(batch.buf.Bytes())
 		if err != nil {
-			fatal("Error writing: %s\n", err.Error())
+			// the connection is broken after a failed write, so the batch
+			// can't be retried on it
+			return 0, 0, targets.NewBatchError(targets.ErrorTypeConnection, false, fmt.Errorf("error writing: %w", err))
 		}
 	}
 
@@ -47,5 +49,5 @@ func (p *processor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uint64)
 	// Return the batch buffer to the pool.
 	batch.buf.Reset()
 	bufPool.Put(batch.buf)
-	return metricCnt, uint64(rowCnt)
+	return metricCnt, uint64(rowCnt), nil
 }
@@ -110,7 +110,10 @@ func TestProcessorProcessBatch(t *testing.T) {
 
 		p := &processor{}
 		p.Init(0, true, true)
-		mCnt, rCnt := p.ProcessBatch(b, c.doLoad)
+		mCnt, rCnt, err := p.ProcessBatch(b, c.doLoad)
+		if err != nil {
+			t.Errorf("process batch returned an error: %v", err)
+		}
 		if mCnt != b.metrics {
 			t.Errorf("process batch returned less metrics than batch: got %d want %d", mCnt, b.metrics)
 		}
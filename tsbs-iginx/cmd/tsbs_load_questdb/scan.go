@@ -3,11 +3,11 @@ package main
 import (
 	"bufio"
 	"bytes"
-	"strings"
 
 	"github.com/timescale/tsbs/pkg/data"
 	"github.com/timescale/tsbs/pkg/data/usecases/common"
 	"github.com/timescale/tsbs/pkg/targets"
+	"github.com/timescale/tsbs/pkg/targets/influx"
 )
 
 const errNotThreeTuplesFmt = "parse error: line does not have 3 tuples, has %d"
@@ -41,18 +41,29 @@ func (b *batch) Len() uint {
 	return b.rows
 }
 
+// Bytes implements targets.SizedBatch
+func (b *batch) Bytes() uint64 {
+	return uint64(b.buf.Len())
+}
+
+// AppendBytes implements targets.SizedBatch, the line is appended with a newline
+func (b *batch) AppendBytes(item data.LoadedPoint) uint64 {
+	return uint64(len(item.Data.([]byte)) + len(newLine))
+}
+
 func (b *batch) Append(item data.LoadedPoint) {
 	that := item.Data.([]byte)
 	thatStr := string(that)
 	b.rows++
 	// Each influx line is format "csv-tags csv-fields timestamp", so we split by space
-	// and then on the middle element, we split by comma to count number of fields added
-	args := strings.Split(thatStr, " ")
+	// and then on the middle element, we split by comma to count number of fields added,
+	// leaving alone the spaces and commas of quoted string values
+	args := influx.SplitLine(thatStr, ' ')
 	if len(args) != 3 {
 		fatal(errNotThreeTuplesFmt, len(args))
 		return
 	}
-	b.metrics += uint64(len(strings.Split(args[1], ",")))
+	b.metrics += uint64(len(influx.SplitLine(args[1], ',')))
 
 	b.buf.Write(that)
 	b.buf.Write(newLine)
@@ -0,0 +1,134 @@
+// tsbs_merge_results combines the output of several tsbs_run_queries_
+// processes that ran concurrently against the same database, e.g. from
+// multiple client machines, into one aggregate result.
+//
+// Results files (--results-file) are merged exactly: throughput is summed,
+// latency quantiles are computed from the merged histograms, and the wall
+// clock window spans all processes. HDR latency files (--hdr-latencies) can be
+// merged too, approximately, when results files are not available.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"sort"
+
+	"github.com/spf13/pflag"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// Program option vars:
+var (
+	resultsFiles []string
+	hdrFiles     []string
+	outputFile   string
+	hdrOutput    string
+)
+
+// Parse args:
+func init() {
+	pflag.StringSliceVar(&resultsFiles, "results-files", nil, "Comma-separated results files (written with --results-file) to merge")
+	pflag.StringSliceVar(&hdrFiles, "hdr-files", nil, "Comma-separated HDR latency files (written with --hdr-latencies) to merge, used when no results files are given")
+	pflag.StringVar(&outputFile, "output", "", "Write the merged results json to this file")
+	pflag.StringVar(&hdrOutput, "hdr-latencies", "", "Write the merged HDR Histogram of Response Latencies to this file")
+	pflag.Parse()
+
+	if len(resultsFiles) == 0 && len(hdrFiles) == 0 {
+		log.Fatal("nothing to merge: set --results-files or --hdr-files")
+	}
+}
+
+func main() {
+	if len(resultsFiles) > 0 {
+		mergeResultsFiles()
+		return
+	}
+	mergeHDRFiles()
+}
+
+func mergeResultsFiles() {
+	results := make([]*query.LoaderTestResult, len(resultsFiles))
+	for i, f := range resultsFiles {
+		r, err := query.ReadTestResult(f)
+		if err != nil {
+			log.Fatal(err)
+		}
+		results[i] = r
+	}
+	merged, err := query.MergeTestResults(results)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("Merged %d results with %d workers in total\n", len(results), merged.RunnerConfig.Workers)
+	fmt.Printf("wall clock window: %dms, all processes running for: %dms\n", merged.DurationMillis, merged.Totals["overlapMillis"])
+	rates := merged.Totals["overallQueryRates"].(map[string]float64)
+	labels := make([]string, 0, len(merged.Histograms))
+	for l := range merged.Histograms {
+		labels = append(labels, l)
+	}
+	sort.Strings(labels)
+	for _, l := range labels {
+		h := merged.Histograms[l]
+		fmt.Printf("%s:\nquery rate: %0.2f queries/sec, count: %d, min: %8.2fms, med: %8.2fms, q95: %8.2fms, q99: %8.2fms, max: %8.2fms\n",
+			l, rates[l], h.TotalCount(),
+			float64(h.Min())/1e3,
+			float64(h.ValueAtQuantile(50))/1e3,
+			float64(h.ValueAtQuantile(95))/1e3,
+			float64(h.ValueAtQuantile(99))/1e3,
+			float64(h.Max())/1e3)
+	}
+	if merged.Interrupted {
+		fmt.Println("warning: at least one of the runs was interrupted, statistics are partial")
+	}
+
+	if outputFile != "" {
+		fmt.Printf("Saving merged results json file to %s\n", outputFile)
+		b, err := json.MarshalIndent(merged, "", " ")
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err = ioutil.WriteFile(outputFile, b, 0644); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if hdrOutput != "" {
+		all := merged.AllQueries()
+		if all == nil {
+			log.Fatal("merged results have no histogram for all queries")
+		}
+		fmt.Printf("Saving merged High Dynamic Range (HDR) Histogram of Response Latencies to %s\n", hdrOutput)
+		if err = query.WriteHDRLatencies(hdrOutput, all); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func mergeHDRFiles() {
+	readers := make([]io.Reader, len(hdrFiles))
+	for i, f := range hdrFiles {
+		file, err := os.Open(f)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer file.Close()
+		readers[i] = file
+	}
+	merged, err := query.MergeHDRLatencies(readers)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Merged %d HDR latency files, %d queries in total (approximate, merge results files for exact values)\n", len(hdrFiles), merged.TotalCount())
+	if hdrOutput == "" {
+		_, err = merged.PercentilesPrint(os.Stdout, 10, 1000.0)
+	} else {
+		err = query.WriteHDRLatencies(hdrOutput, merged)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
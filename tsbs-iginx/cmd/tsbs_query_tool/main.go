@@ -0,0 +1,280 @@
+// tsbs_query_tool inspects and edits query files generated by
+// tsbs_generate_queries.
+//
+// Query files are gob streams, which can be dumped to JSON lines (one query
+// per line, with SQL and request bodies as plain strings), edited, and
+// converted back. Query files in either format can be filtered by label or ID
+// range, sampled, and summarized per label. The tsbs_run_queries_ programs
+// read JSON lines directly with --input-format=jsonl.
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// Program option vars:
+var (
+	queryType    string
+	inputFile    string
+	inputFormat  string
+	outputFile   string
+	outputFormat string
+
+	labelPattern string
+	fromID       uint64
+	toID         uint64
+	sampleSize   int
+	seed         int64
+)
+
+var rootCmd = &cobra.Command{
+	Use:   "tsbs_query_tool",
+	Short: "Inspect, convert, filter and sample query files",
+}
+
+func init() {
+	pf := rootCmd.PersistentFlags()
+	pf.StringVar(&queryType, "query-type", "http", "Type of the queries in the file, valid: "+strings.Join(query.PoolNames(), ", "))
+	pf.StringVar(&inputFile, "file", "", "File name to read queries from (default STDIN)")
+	pf.StringVar(&inputFormat, "input-format", query.FormatGob, "Format of the input, valid: "+strings.Join(query.ValidFormats, ", "))
+	pf.StringVar(&outputFile, "output", "", "File name to write queries to (default STDOUT)")
+
+	dumpCmd := &cobra.Command{
+		Use:   "dump",
+		Short: "Write queries as JSON lines",
+		Run: func(_ *cobra.Command, _ []string) {
+			outputFormat = query.FormatJSONLines
+			copyQueries(acceptAll())
+		},
+	}
+	convertCmd := &cobra.Command{
+		Use:   "convert",
+		Short: "Write queries as a gob stream, as read by default by the tsbs_run_queries_ programs",
+		Run: func(_ *cobra.Command, _ []string) {
+			outputFormat = query.FormatGob
+			copyQueries(acceptAll())
+		},
+	}
+	filterCmd := &cobra.Command{
+		Use:   "filter",
+		Short: "Write only the queries with a matching label and ID",
+		Long:  "Write only the queries with a matching label and ID. Query IDs are only preserved in the jsonl output format.",
+		Run: func(_ *cobra.Command, _ []string) {
+			copyQueries(newFilter())
+		},
+	}
+	filterCmd.Flags().StringVar(&labelPattern, "label", "", "Regular expression the query label must match")
+	filterCmd.Flags().Uint64Var(&fromID, "from-id", 0, "Lowest query ID to keep")
+	filterCmd.Flags().Uint64Var(&toID, "to-id", 0, "Keep queries with IDs below this (0 = no limit)")
+	filterCmd.Flags().StringVar(&outputFormat, "output-format", query.FormatGob, "Format of the output, valid: "+strings.Join(query.ValidFormats, ", "))
+
+	sampleCmd := &cobra.Command{
+		Use:   "sample",
+		Short: "Write a uniform random sample of the queries, in their original order",
+		Run: func(_ *cobra.Command, _ []string) {
+			sample()
+		},
+	}
+	sampleCmd.Flags().IntVar(&sampleSize, "n", 100, "Number of queries to sample")
+	sampleCmd.Flags().Int64Var(&seed, "seed", 0, "PRNG seed (default: 0)")
+	sampleCmd.Flags().StringVar(&outputFormat, "output-format", query.FormatGob, "Format of the output, valid: "+strings.Join(query.ValidFormats, ", "))
+
+	statsCmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Print the number of queries per label",
+		Run: func(_ *cobra.Command, _ []string) {
+			stats()
+		},
+	}
+
+	rootCmd.AddCommand(dumpCmd, convertCmd, filterCmd, sampleCmd, statsCmd)
+}
+
+func main() {
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+func fatal(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
+}
+
+// forEachQuery decodes every query of the input and passes it to fn
+func forEachQuery(fn func(q query.Query)) {
+	pool, ok := query.Pools[queryType]
+	if !ok {
+		fatal("unknown query type '%s', valid: %s", queryType, strings.Join(query.PoolNames(), ", "))
+	}
+	var r io.Reader = os.Stdin
+	if inputFile != "" {
+		f, err := os.Open(inputFile)
+		if err != nil {
+			fatal("cannot open file for read %s: %v", inputFile, err)
+		}
+		defer f.Close()
+		r = f
+	}
+	dec, err := query.NewDecoder(bufio.NewReaderSize(r, 4<<20), inputFormat)
+	if err != nil {
+		fatal("%v", err)
+	}
+	for n := uint64(0); ; n++ {
+		// new queries are used rather than pooled ones, since fn may keep them
+		q := pool.New().(query.Query)
+		err = dec.Decode(q)
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			fatal("cannot decode query %d: %v", n, err)
+		}
+		if inputFormat != query.FormatJSONLines {
+			q.SetID(n)
+		}
+		fn(q)
+	}
+}
+
+// queryWriter writes queries to the output in outputFormat
+type queryWriter struct {
+	w   *bufio.Writer
+	f   *os.File
+	enc query.Encoder
+}
+
+func newQueryWriter() *queryWriter {
+	f := os.Stdout
+	if outputFile != "" {
+		var err error
+		f, err = os.Create(outputFile)
+		if err != nil {
+			fatal("cannot create output file %s: %v", outputFile, err)
+		}
+	}
+	w := bufio.NewWriter(f)
+	enc, err := query.NewEncoder(w, outputFormat)
+	if err != nil {
+		fatal("%v", err)
+	}
+	return &queryWriter{w: w, f: f, enc: enc}
+}
+
+func (qw *queryWriter) write(q query.Query) {
+	if err := qw.enc.Encode(q); err != nil {
+		fatal("cannot encode query %d: %v", q.GetID(), err)
+	}
+}
+
+func (qw *queryWriter) close() {
+	if err := qw.w.Flush(); err != nil {
+		fatal("cannot write output: %v", err)
+	}
+	if qw.f != os.Stdout {
+		qw.f.Close()
+	}
+}
+
+func acceptAll() func(query.Query) bool {
+	return func(query.Query) bool { return true }
+}
+
+func newFilter() func(query.Query) bool {
+	var re *regexp.Regexp
+	if labelPattern != "" {
+		var err error
+		re, err = regexp.Compile(labelPattern)
+		if err != nil {
+			fatal("invalid --label: %v", err)
+		}
+	}
+	return func(q query.Query) bool {
+		id := q.GetID()
+		if id < fromID || (toID > 0 && id >= toID) {
+			return false
+		}
+		return re == nil || re.Match(q.HumanLabelName())
+	}
+}
+
+// copyQueries writes the queries accepted by keep to the output
+func copyQueries(keep func(query.Query) bool) {
+	qw := newQueryWriter()
+	forEachQuery(func(q query.Query) {
+		if keep(q) {
+			qw.write(q)
+		}
+	})
+	qw.close()
+}
+
+// sample keeps a uniform random sample of sampleSize queries (reservoir
+// sampling) and writes it ordered by query ID
+func sample() {
+	if sampleSize <= 0 {
+		fatal("--n must be positive")
+	}
+	r := rand.New(rand.NewSource(seed))
+	reservoir := make([]query.Query, 0, sampleSize)
+	seen := 0
+	forEachQuery(func(q query.Query) {
+		seen++
+		if len(reservoir) < sampleSize {
+			reservoir = append(reservoir, q)
+		} else if j := r.Intn(seen); j < sampleSize {
+			reservoir[j] = q
+		}
+	})
+	sort.Slice(reservoir, func(i, j int) bool { return reservoir[i].GetID() < reservoir[j].GetID() })
+
+	qw := newQueryWriter()
+	for _, q := range reservoir {
+		qw.write(q)
+	}
+	qw.close()
+}
+
+type labelStats struct {
+	count   uint64
+	firstID uint64
+	lastID  uint64
+}
+
+// stats prints the number of queries and their ID range per label
+func stats() {
+	byLabel := make(map[string]*labelStats)
+	total := uint64(0)
+	forEachQuery(func(q query.Query) {
+		total++
+		l := string(q.HumanLabelName())
+		s, ok := byLabel[l]
+		if !ok {
+			s = &labelStats{firstID: q.GetID()}
+			byLabel[l] = s
+		}
+		s.count++
+		s.lastID = q.GetID()
+	})
+
+	labels := make([]string, 0, len(byLabel))
+	for l := range byLabel {
+		labels = append(labels, l)
+	}
+	sort.Strings(labels)
+	for _, l := range labels {
+		s := byLabel[l]
+		fmt.Printf("%s: %d queries (%.1f%%), IDs %d-%d\n", l, s.count, 100*float64(s.count)/float64(total), s.firstID, s.lastID)
+	}
+	fmt.Printf("total: %d queries\n", total)
+}
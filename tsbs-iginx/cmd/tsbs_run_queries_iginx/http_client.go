@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -28,6 +29,7 @@ type HTTPClient struct {
 type HTTPClientDoOptions struct {
 	Debug                int
 	PrettyPrintResponses bool
+	CaptureResults       bool
 	chunkSize            uint64
 	database             string
 }
@@ -59,8 +61,9 @@ func NewHTTPClient(host string) *HTTPClient {
 }
 
 // Do performs the action specified by the given Query. It uses fasthttp, and
-// tries to minimize heap allocations.
-func (w *HTTPClient) Do(q *query.HTTP, opts *HTTPClientDoOptions) (lag float64, err error) {
+// tries to minimize heap allocations. The request is aborted, returning the
+// context's error, once ctx is done.
+func (w *HTTPClient) Do(ctx context.Context, q *query.HTTP, opts *HTTPClientDoOptions) (lag float64, err error) {
 	// populate uri from the reusable byte slice:
 	w.uri = w.uri[:0]
 	w.uri = append(w.uri, w.Host...)
@@ -71,11 +74,15 @@ func (w *HTTPClient) Do(q *query.HTTP, opts *HTTPClientDoOptions) (lag float64,
 	if err != nil {
 		panic(err)
 	}
+	req = req.WithContext(ctx)
 
 	// Perform the request while tracking latency:
 	start := time.Now()
 	resp, err := w.client.Do(req)
 	if err != nil {
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
 		panic(err)
 	}
 	defer resp.Body.Close()
@@ -87,12 +94,24 @@ func (w *HTTPClient) Do(q *query.HTTP, opts *HTTPClientDoOptions) (lag float64,
 	body, err = ioutil.ReadAll(resp.Body)
 
 	if err != nil {
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
 		panic(err)
 	}
 
 	lag = float64(time.Since(start).Nanoseconds()) / 1e6 // milliseconds
 
 	if opts != nil {
+		// Store the normalized response for cross-database comparison, if applicable:
+		if opts.CaptureResults {
+			rows, err := normalizeResponse(body)
+			if err != nil {
+				return lag, fmt.Errorf("query %d: %v", q.GetID(), err)
+			}
+			runner.CaptureResult(q, rows)
+		}
+
 		// Print debug messages, if applicable:
 		switch opts.Debug {
 		case 1:
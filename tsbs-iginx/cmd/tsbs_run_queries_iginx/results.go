@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/timescale/tsbs/pkg/query"
+)
+
+// iginxResponse is the KairosDB compatible response of the Iginx REST query API
+type iginxResponse struct {
+	Queries []struct {
+		Results []struct {
+			Name    string `json:"name"`
+			GroupBy []struct {
+				Name  string            `json:"name"`
+				Group map[string]string `json:"group"`
+			} `json:"group_by"`
+			Values [][]interface{} `json:"values"`
+		} `json:"results"`
+	} `json:"queries"`
+}
+
+// normalizeResponse converts an Iginx query response into canonical result rows.
+// Only tags the result was grouped by become part of the series key, so a
+// result filtered to a set of hosts but not grouped by them matches the
+// equivalent SQL query without a hostname column.
+func normalizeResponse(body []byte) ([]query.ResultRow, error) {
+	var resp iginxResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("cannot decode response: %v", err)
+	}
+	var rows []query.ResultRow
+	for _, q := range resp.Queries {
+		for _, r := range q.Results {
+			tags := make(map[string]string)
+			for _, g := range r.GroupBy {
+				for k, v := range g.Group {
+					tags[k] = v
+				}
+			}
+			series := query.SeriesKey(query.CanonicalFieldName(r.Name), tags)
+			for _, v := range r.Values {
+				if len(v) != 2 {
+					return nil, fmt.Errorf("series %s: expected [timestamp, value] pair, got %v", r.Name, v)
+				}
+				ts, ok := v[0].(float64)
+				if !ok {
+					return nil, fmt.Errorf("series %s: invalid timestamp %v", r.Name, v[0])
+				}
+				val, ok := v[1].(float64)
+				if !ok {
+					return nil, fmt.Errorf("series %s: non-numeric value %v", r.Name, v[1])
+				}
+				rows = append(rows, query.ResultRow{Series: series, Bucket: int64(ts), Value: val})
+			}
+		}
+	}
+	return rows, nil
+}
@@ -6,6 +6,7 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -168,9 +169,10 @@ func mapRows(r *sql.Rows) []map[string]interface{} {
 }
 
 type queryExecutorOptions struct {
-	showExplain   bool
-	debug         bool
-	printResponse bool
+	showExplain    bool
+	debug          bool
+	printResponse  bool
+	captureResults bool
 }
 
 type processor struct {
@@ -187,13 +189,18 @@ func (p *processor) Init(workerNumber int) {
 	}
 	p.db = db
 	p.opts = &queryExecutorOptions{
-		showExplain:   showExplain,
-		debug:         runner.DebugLevel() > 0,
-		printResponse: runner.DoPrintResponses(),
+		showExplain:    showExplain,
+		debug:          runner.DebugLevel() > 0,
+		printResponse:  runner.DoPrintResponses(),
+		captureResults: runner.DoCaptureResults(),
 	}
 }
 
 func (p *processor) ProcessQuery(q query.Query, isWarm bool) ([]*query.Stat, error) {
+	return p.ProcessQueryContext(context.Background(), q, isWarm)
+}
+
+func (p *processor) ProcessQueryContext(ctx context.Context, q query.Query, isWarm bool) ([]*query.Stat, error) {
 	// No need to run again for EXPLAIN
 	if isWarm && p.opts.showExplain {
 		return nil, nil
@@ -205,7 +212,7 @@ func (p *processor) ProcessQuery(q query.Query, isWarm bool) ([]*query.Stat, err
 	if showExplain {
 		qry = "EXPLAIN ANALYZE " + qry
 	}
-	rows, err := p.db.Query(qry)
+	rows, err := p.db.QueryContext(ctx, qry)
 	if err != nil {
 		return nil, err
 	}
@@ -225,6 +232,13 @@ func (p *processor) ProcessQuery(q query.Query, isWarm bool) ([]*query.Stat, err
 		fmt.Printf("%s\n\n%s\n-----\n\n", qry, text)
 	} else if p.opts.printResponse {
 		prettyPrintResponse(rows, tq)
+	} else if p.opts.captureResults && !isWarm {
+		res, err := query.NormalizeSQLRows(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		runner.CaptureResult(q, res)
 	}
 	// Fetching all the rows to confirm that the query is fully completed.
 	for rows.Next() {
@@ -4,11 +4,11 @@ import (
 	"bufio"
 	"fmt"
 	"io"
-	"math/rand"
 	"os"
 	"sort"
 
 	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/manifest"
 	"github.com/timescale/tsbs/pkg/data/serialize"
 	"github.com/timescale/tsbs/pkg/data/usecases"
 	"github.com/timescale/tsbs/pkg/data/usecases/common"
@@ -36,6 +36,20 @@ type DataGenerator struct {
 	// bufOut represents the buffered writer that should actually be passed to
 	// any operations that write out data.
 	bufOut *bufio.Writer
+
+	// recorder builds the manifest of the data, if one is written
+	recorder *manifest.Recorder
+
+	// outputs are the files of the goroutines with ParallelFiles, bufOut then
+	// writing to all of them
+	outputs []*dataOutput
+}
+
+// dataOutput is a file of generated data, with the recorder of its manifest
+type dataOutput struct {
+	w        *bufio.Writer
+	recorder *manifest.Recorder
+	manifest string
 }
 
 func (g *DataGenerator) init(config common.GeneratorConfig) error {
@@ -57,53 +71,135 @@ func (g *DataGenerator) init(config common.GeneratorConfig) error {
 	if g.Out == nil {
 		g.Out = os.Stdout
 	}
-	g.bufOut, err = getBufferedWriter(g.config.File, g.Out)
+	g.outputs = nil
+	if g.config.ParallelFiles {
+		return g.initParallelFiles()
+	}
+	out, err := getWriter(g.config.File, g.Out)
 	if err != nil {
 		return err
 	}
+	g.recorder = nil
+	if manifestFile(g.config) != "" {
+		g.recorder = manifest.NewRecorder(g.config)
+		out = io.MultiWriter(out, g.recorder)
+	}
+	g.bufOut = bufio.NewWriterSize(out, defaultWriteSize)
 
 	return nil
 }
 
+// initParallelFiles creates a file, and the recorder of its manifest, for
+// each of the goroutines serializing the points.
+func (g *DataGenerator) initParallelFiles() error {
+	writers := make([]io.Writer, 0, g.config.Parallelism)
+	for i := uint(0); i < g.config.Parallelism; i++ {
+		fileName := fmt.Sprintf("%s.%d", g.config.File, i)
+		out, err := getWriter(fileName, nil)
+		if err != nil {
+			return err
+		}
+		recorder := manifest.NewRecorder(g.config)
+		w := bufio.NewWriterSize(io.MultiWriter(out, recorder), defaultWriteSize)
+		g.outputs = append(g.outputs, &dataOutput{w: w, recorder: recorder, manifest: fileName + manifest.Suffix})
+		writers = append(writers, w)
+	}
+	g.recorder = nil
+	g.bufOut = bufio.NewWriter(io.MultiWriter(writers...))
+	return nil
+}
+
+// manifestFile returns where to write the manifest of the data: the path of
+// the config, else next to the output file.
+func manifestFile(c *common.DataGeneratorConfig) string {
+	if c.Manifest != "" {
+		return c.Manifest
+	}
+	if c.File != "" {
+		return c.File + manifest.Suffix
+	}
+	return ""
+}
+
 func (g *DataGenerator) Generate(config common.GeneratorConfig, target targets.ImplementedTarget) error {
 	err := g.init(config)
 	if err != nil {
 		return err
 	}
 
-	rand.Seed(g.config.Seed)
+	common.Seed(g.config.Seed)
 
 	scfg, err := usecases.GetSimulatorConfig(g.config)
 	if err != nil {
 		return err
 	}
 
-	sim := scfg.NewSimulator(g.config.LogInterval, g.config.Limit)
-	serializer, err := g.getSerializer(sim, target)
+	if g.config.Parallelism > 1 {
+		err = g.runParallel(scfg, target, g.config)
+	} else {
+		err = g.generate(scfg, target)
+	}
 	if err != nil {
 		return err
 	}
+	return g.writeManifests()
+}
 
+// generate generates the points with a single simulator.
+func (g *DataGenerator) generate(scfg common.SimulatorConfig, target targets.ImplementedTarget) error {
+	sim := scfg.NewSimulator(common.Rand(), g.config.LogInterval, g.config.Limit)
+	serializer, err := g.getSerializer(sim, target)
+	if err != nil {
+		return err
+	}
 	return g.runSimulator(sim, serializer, g.config)
 }
 
+// writeManifests writes the manifests of the generated data, if any.
+func (g *DataGenerator) writeManifests() error {
+	for _, o := range g.outputs {
+		if err := o.recorder.Manifest().Write(o.manifest); err != nil {
+			return err
+		}
+	}
+	if g.recorder != nil {
+		return g.recorder.Manifest().Write(manifestFile(g.config))
+	}
+	return nil
+}
+
 func (g *DataGenerator) CreateSimulator(config *common.DataGeneratorConfig) (common.Simulator, error) {
 	err := g.init(config)
 	if err != nil {
 		return nil, err
 	}
-	rand.Seed(g.config.Seed)
+	common.Seed(g.config.Seed)
 	scfg, err := usecases.GetSimulatorConfig(g.config)
 	if err != nil {
 		return nil, err
 	}
 
-	return scfg.NewSimulator(g.config.LogInterval, g.config.Limit), nil
+	return scfg.NewSimulator(common.Rand(), g.config.LogInterval, g.config.Limit), nil
 }
 
 func (g *DataGenerator) runSimulator(sim common.Simulator, serializer serialize.PointSerializer, dgc *common.DataGeneratorConfig) error {
 	defer g.bufOut.Flush()
 
+	return simulate(sim, dgc.InterleavedGroupID, dgc.InterleavedNumGroups, func(point *data.Point) error {
+		err := serializer.Serialize(point, g.bufOut)
+		if err != nil {
+			return fmt.Errorf("can not serialize point: %s", err)
+		}
+		if g.recorder != nil {
+			g.recorder.Record(point)
+		}
+		return nil
+	})
+}
+
+// simulate runs the simulator to the end, passing emit the points of
+// interleaved group groupID among numGroups, until emit fails.
+func simulate(sim common.Simulator, groupID, numGroups uint, emit func(*data.Point) error) error {
 	currGroupID := uint(0)
 	point := data.NewPoint()
 	for !sim.Finished() {
@@ -114,15 +210,27 @@ func (g *DataGenerator) runSimulator(sim common.Simulator, serializer serialize.
 		}
 
 		// in the default case this is always true
-		if currGroupID == dgc.InterleavedGroupID {
-			err := serializer.Serialize(point, g.bufOut)
-			if err != nil {
-				return fmt.Errorf("can not serialize point: %s", err)
+		if currGroupID == groupID {
+			if err := emit(point); err != nil {
+				return err
 			}
 		}
 		point.Reset()
 
-		currGroupID = (currGroupID + 1) % dgc.InterleavedNumGroups
+		currGroupID = (currGroupID + 1) % numGroups
+	}
+
+	return simulatorErr(sim)
+}
+
+// simulatorErr returns the error that finished the simulator, if any: an
+// imported dataset can end early on an invalid row.
+func simulatorErr(sim common.Simulator) error {
+	switch s := sim.(type) {
+	case interface{ Err() error }:
+		if err := s.Err(); err != nil {
+			return fmt.Errorf("can not import data: %s", err)
+		}
 	}
 	return nil
 }
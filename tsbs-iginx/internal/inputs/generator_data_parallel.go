@@ -0,0 +1,338 @@
+package inputs
+
+import (
+	"bytes"
+	"container/heap"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/serialize"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+	"github.com/timescale/tsbs/pkg/targets"
+)
+
+// parallelChunkSize is the number of points a shard hands the merge at a time
+const parallelChunkSize = 1000
+
+// errStopped stops the shards once one of them, or the merge, has failed
+var errStopped = errors.New("generation stopped")
+
+// shard is one of the interleaved simulators of runParallel. All the shards
+// simulate the whole data from the same seed, each with its own source of
+// randomness, and keep the points of their interleaved group.
+type shard struct {
+	sim        common.Simulator
+	serializer serialize.PointSerializer
+	// groupID is the interleaved group of the shard among numGroups
+	groupID   uint
+	numGroups uint
+	err       error
+
+	// chunks are the serialized points sent to the merge, free the chunks
+	// it is done with
+	chunks chan *pointChunk
+	free   chan *pointChunk
+
+	// the point of the shard next in the merge: the i-th point of chunk,
+	// rank-th in the serial order of the points
+	chunk *pointChunk
+	i     int
+	rank  uint64
+}
+
+// run simulates the points of the shard, passing them to emit until it fails
+// or done is closed.
+func (s *shard) run(done <-chan struct{}, emit func(*data.Point) error) {
+	s.err = simulate(s.sim, s.groupID, s.numGroups, func(p *data.Point) error {
+		select {
+		case <-done:
+			return errStopped
+		default:
+		}
+		return emit(p)
+	})
+}
+
+// send serializes the points of the shard into chunks for the merge.
+func (s *shard) send(done <-chan struct{}) {
+	defer close(s.chunks)
+	chunk := <-s.free
+	s.run(done, func(p *data.Point) error {
+		full, err := chunk.add(p, s.serializer)
+		if err != nil || !full {
+			return err
+		}
+		select {
+		case s.chunks <- chunk:
+		case <-done:
+			return errStopped
+		}
+		select {
+		case chunk = <-s.free:
+		case <-done:
+			return errStopped
+		}
+		chunk.reset()
+		return nil
+	})
+	if s.err == nil && chunk.n > 0 {
+		select {
+		case s.chunks <- chunk:
+		case <-done:
+		}
+	}
+}
+
+// next moves the shard to its next point in the merge, and tells whether it
+// has one. Once it has none, err tells whether the shard failed.
+func (s *shard) next() bool {
+	if s.chunk != nil {
+		s.i++
+		if s.i < s.chunk.n {
+			return true
+		}
+		s.free <- s.chunk
+	}
+	chunk, ok := <-s.chunks
+	if !ok {
+		return false
+	}
+	s.chunk, s.i = chunk, 0
+	return true
+}
+
+// timestamp returns the time of the point of the shard next in the merge,
+// the zero time if it has none.
+func (s *shard) timestamp() time.Time {
+	if ts := s.chunk.points[s.i].Timestamp(); ts != nil {
+		return *ts
+	}
+	return time.Time{}
+}
+
+// pointChunk is a run of consecutive points of a shard, serialized into one
+// buffer. Chunks are reused once merged.
+type pointChunk struct {
+	points []*data.Point
+	// ends are where each point ends in buf
+	ends []int
+	n    int
+	buf  bytes.Buffer
+}
+
+func newPointChunk() *pointChunk {
+	c := &pointChunk{
+		points: make([]*data.Point, parallelChunkSize),
+		ends:   make([]int, parallelChunkSize),
+	}
+	for i := range c.points {
+		c.points[i] = data.NewPoint()
+	}
+	return c
+}
+
+// reset empties the chunk.
+func (c *pointChunk) reset() {
+	c.n = 0
+	c.buf.Reset()
+}
+
+// add serializes a point into the chunk, with a copy of it so the point can
+// be reused, and tells whether the chunk is full.
+func (c *pointChunk) add(p *data.Point, serializer serialize.PointSerializer) (bool, error) {
+	if err := serializer.Serialize(p, &c.buf); err != nil {
+		return false, fmt.Errorf("can not serialize point: %s", err)
+	}
+	copyPoint(c.points[c.n], p)
+	c.ends[c.n] = c.buf.Len()
+	c.n++
+	return c.n == len(c.points), nil
+}
+
+// bytes returns the serialization of the i-th point of the chunk.
+func (c *pointChunk) bytes(i int) []byte {
+	start := 0
+	if i > 0 {
+		start = c.ends[i-1]
+	}
+	return c.buf.Bytes()[start:c.ends[i]]
+}
+
+// shardHeap orders the shards by the time of their next point, then by the
+// serial order of the points, so that points of the same time come out as
+// they would from a single simulator.
+type shardHeap []*shard
+
+func (h shardHeap) Len() int { return len(h) }
+
+func (h shardHeap) Less(i, j int) bool {
+	ti, tj := h[i].timestamp(), h[j].timestamp()
+	if !ti.Equal(tj) {
+		return ti.Before(tj)
+	}
+	return h[i].rank < h[j].rank
+}
+
+func (h shardHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *shardHeap) Push(x interface{}) { *h = append(*h, x.(*shard)) }
+
+func (h *shardHeap) Pop() interface{} {
+	old := *h
+	s := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return s
+}
+
+// runParallel generates the points with Parallelism simulator shards, each on
+// its own goroutine. Shard j simulates the data from the seed with a source
+// of randomness of its own, and keeps the points of interleaved group
+// InterleavedGroupID + j*InterleavedNumGroups among Parallelism times
+// InterleavedNumGroups, as a separate process would. With ParallelFiles each
+// shard writes its own file, else the points of the shards are merged by
+// time into one stream.
+func (g *DataGenerator) runParallel(scfg common.SimulatorConfig, target targets.ImplementedTarget, dgc *common.DataGeneratorConfig) error {
+	shards := make([]*shard, dgc.Parallelism)
+	// the simulators are created in turn, as the first one of a use case may
+	// set up state shared by all of them
+	for j := range shards {
+		r := rand.New(rand.NewSource(dgc.Seed))
+		shards[j] = &shard{
+			sim:        scfg.NewSimulator(r, dgc.LogInterval, dgc.Limit),
+			serializer: target.Serializer(),
+			groupID:    dgc.InterleavedGroupID + uint(j)*dgc.InterleavedNumGroups,
+			numGroups:  dgc.Parallelism * dgc.InterleavedNumGroups,
+		}
+	}
+	// the header, written to all the outputs
+	if _, err := g.getSerializer(shards[0].sim, target); err != nil {
+		return err
+	}
+	if err := g.bufOut.Flush(); err != nil {
+		return err
+	}
+	if g.outputs != nil {
+		return g.runShardFiles(shards)
+	}
+	return g.mergeShards(shards)
+}
+
+// runShardFiles runs the shards, each writing its points to its own output.
+func (g *DataGenerator) runShardFiles(shards []*shard) error {
+	done := make(chan struct{})
+	var stop sync.Once
+	var wg sync.WaitGroup
+	for j, s := range shards {
+		wg.Add(1)
+		go func(s *shard, o *dataOutput) {
+			defer wg.Done()
+			s.run(done, func(p *data.Point) error {
+				if err := s.serializer.Serialize(p, o.w); err != nil {
+					return fmt.Errorf("can not serialize point: %s", err)
+				}
+				if o.recorder != nil {
+					o.recorder.Record(p)
+				}
+				return nil
+			})
+			if s.err == nil {
+				s.err = o.w.Flush()
+			}
+			if s.err != nil {
+				stop.Do(func() { close(done) })
+			}
+		}(s, g.outputs[j])
+	}
+	wg.Wait()
+	return shardsErr(shards)
+}
+
+// mergeShards runs the shards and writes their points to bufOut, merged by
+// time.
+func (g *DataGenerator) mergeShards(shards []*shard) (err error) {
+	defer g.bufOut.Flush()
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for _, s := range shards {
+		s.chunks = make(chan *pointChunk, 1)
+		s.free = make(chan *pointChunk, 2)
+		s.free <- newPointChunk()
+		s.free <- newPointChunk()
+		wg.Add(1)
+		go func(s *shard) {
+			defer wg.Done()
+			s.send(done)
+		}(s)
+	}
+	defer func() {
+		if err != nil {
+			close(done)
+		}
+		wg.Wait()
+	}()
+
+	h := make(shardHeap, 0, len(shards))
+	for j, s := range shards {
+		if !s.next() {
+			if s.err != nil {
+				return s.err
+			}
+			continue
+		}
+		s.rank = uint64(j)
+		h = append(h, s)
+	}
+	heap.Init(&h)
+	for h.Len() > 0 {
+		s := h[0]
+		if _, err := g.bufOut.Write(s.chunk.bytes(s.i)); err != nil {
+			return err
+		}
+		if g.recorder != nil {
+			g.recorder.Record(s.chunk.points[s.i])
+		}
+		if s.next() {
+			s.rank += uint64(len(shards))
+			heap.Fix(&h, 0)
+			continue
+		}
+		if s.err != nil {
+			return s.err
+		}
+		heap.Pop(&h)
+	}
+	return nil
+}
+
+// shardsErr returns the first error of the shards, if any.
+func shardsErr(shards []*shard) error {
+	for _, s := range shards {
+		if s.err != nil && s.err != errStopped {
+			return s.err
+		}
+	}
+	return nil
+}
+
+// copyPoint copies a point into the slices of another.
+func copyPoint(c, p *data.Point) {
+	c.Reset()
+	c.SetMeasurementName(p.MeasurementName())
+	tagValues := p.TagValues()
+	for i, k := range p.TagKeys() {
+		c.AppendTag(k, tagValues[i])
+	}
+	fieldValues := p.FieldValues()
+	for i, k := range p.FieldKeys() {
+		c.AppendField(k, fieldValues[i])
+	}
+	if ts := p.Timestamp(); ts != nil {
+		t := *ts
+		c.SetTimestamp(&t)
+	}
+}
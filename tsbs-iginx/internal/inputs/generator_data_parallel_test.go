@@ -0,0 +1,182 @@
+package inputs
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+	"github.com/timescale/tsbs/pkg/targets/constants"
+	"github.com/timescale/tsbs/pkg/targets/influx"
+)
+
+// testSimulatorConfig creates testSimulators, for runParallel to shard.
+type testSimulatorConfig struct {
+	limit            uint64
+	shouldWriteLimit uint64
+}
+
+func (c *testSimulatorConfig) NewSimulator(_ *rand.Rand, _ time.Duration, _ uint64) common.Simulator {
+	return &testSimulator{limit: c.limit, shouldWriteLimit: c.shouldWriteLimit}
+}
+
+// runTestSimulator returns the output of runSimulator or, with a
+// parallelism, of runParallel, on testSimulators.
+func runTestSimulator(t *testing.T, groupID, totalGroups, parallelism uint) string {
+	var buf bytes.Buffer
+	dgc := &common.DataGeneratorConfig{
+		InterleavedGroupID:   groupID,
+		InterleavedNumGroups: totalGroups,
+		Parallelism:          parallelism,
+	}
+	g := &DataGenerator{config: dgc, bufOut: bufio.NewWriter(&buf)}
+	scfg := &testSimulatorConfig{limit: 2500, shouldWriteLimit: 2400}
+	var err error
+	if parallelism > 1 {
+		err = g.runParallel(scfg, &mockTarget{serializer: &testSerializer{}}, dgc)
+	} else {
+		err = g.runSimulator(scfg.NewSimulator(nil, 0, 0), &testSerializer{}, dgc)
+	}
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return buf.String()
+}
+
+func TestRunParallel(t *testing.T) {
+	for _, groups := range []uint{1, 3} {
+		for groupID := uint(0); groupID < groups; groupID++ {
+			want := runTestSimulator(t, groupID, groups, 1)
+			for _, parallelism := range []uint{2, 4} {
+				if got := runTestSimulator(t, groupID, groups, parallelism); got != want {
+					t.Errorf("group %d of %d: output with parallelism %d differs from the serial output", groupID, groups, parallelism)
+				}
+			}
+		}
+	}
+}
+
+func TestRunParallelFiles(t *testing.T) {
+	const parallelism = 3
+	dgc := &common.DataGeneratorConfig{
+		InterleavedNumGroups: 1,
+		Parallelism:          parallelism,
+		ParallelFiles:        true,
+	}
+	g := &DataGenerator{config: dgc}
+	bufs := make([]bytes.Buffer, parallelism)
+	for i := range bufs {
+		g.outputs = append(g.outputs, &dataOutput{w: bufio.NewWriter(&bufs[i])})
+	}
+	g.bufOut = bufio.NewWriter(&bytes.Buffer{})
+	scfg := &testSimulatorConfig{limit: 2500, shouldWriteLimit: 2400}
+	if err := g.runParallel(scfg, &mockTarget{serializer: &testSerializer{}}, dgc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := range bufs {
+		want := runTestSimulator(t, uint(i), parallelism, 1)
+		if got := bufs[i].String(); got != want {
+			t.Errorf("file %d differs from the output of interleaved group %d", i, i)
+		}
+	}
+}
+
+func TestRunParallelError(t *testing.T) {
+	scfg := &testSimulatorConfig{limit: 5000, shouldWriteLimit: 5000}
+	for _, files := range []bool{false, true} {
+		dgc := &common.DataGeneratorConfig{InterleavedNumGroups: 1, Parallelism: 2, ParallelFiles: files}
+		g := &DataGenerator{config: dgc, bufOut: bufio.NewWriter(&bytes.Buffer{})}
+		if files {
+			for i := uint(0); i < dgc.Parallelism; i++ {
+				g.outputs = append(g.outputs, &dataOutput{w: bufio.NewWriter(&bytes.Buffer{})})
+			}
+		}
+		if err := g.runParallel(scfg, &mockTarget{serializer: &testSerializer{shouldError: true}}, dgc); err == nil {
+			t.Errorf("files %v: unexpected lack of error", files)
+		}
+	}
+}
+
+// generateUseCase generates a day of data of a use case in the influx format,
+// to the file, or to a string when file is empty.
+func generateUseCase(t *testing.T, use, file string, groupID, groups, parallelism uint) string {
+	c := &common.DataGeneratorConfig{
+		BaseConfig: common.BaseConfig{
+			Seed:      123,
+			Format:    constants.FormatInflux,
+			Use:       use,
+			Scale:     5,
+			TimeStart: defaultTimeStart,
+			TimeEnd:   defaultTimeEnd,
+			File:      file,
+		},
+		InitialScale:         5,
+		LogInterval:          time.Minute,
+		InterleavedGroupID:   groupID,
+		InterleavedNumGroups: groups,
+		Parallelism:          parallelism,
+		ParallelFiles:        parallelism > 1 && file != "",
+	}
+	var buf bytes.Buffer
+	dg := &DataGenerator{Out: &buf}
+	target := &mockTarget{name: constants.FormatInflux, serializer: &influx.Serializer{}}
+	if err := dg.Generate(c, target); err != nil {
+		t.Fatalf("%s: unexpected error when generating: %v", use, err)
+	}
+	return buf.String()
+}
+
+func sortedLines(s string) []string {
+	lines := strings.Split(s, "\n")
+	sort.Strings(lines)
+	return lines
+}
+
+func TestRunParallelUseCases(t *testing.T) {
+	// the devops points come in time order, so merging the shards by time
+	// gives back the serial output
+	want := generateUseCase(t, common.UseCaseDevops, "", 0, 1, 1)
+	if got := generateUseCase(t, common.UseCaseDevops, "", 0, 1, 3); got != want {
+		t.Errorf("devops: output with parallelism 3 differs from the serial output")
+	}
+
+	// the iot batches are out of order, and the merge only orders the shards
+	// between them
+	want = generateUseCase(t, common.UseCaseIoT, "", 0, 1, 1)
+	got := generateUseCase(t, common.UseCaseIoT, "", 0, 1, 3)
+	if fmt.Sprint(sortedLines(got)) != fmt.Sprint(sortedLines(want)) {
+		t.Errorf("iot: points with parallelism 3 differ from the serial points")
+	}
+}
+
+func TestRunParallelFilesUseCases(t *testing.T) {
+	dir, err := ioutil.TempDir("", "parallel")
+	if err != nil {
+		t.Fatalf("could not create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	const parallelism = 3
+	for _, use := range []string{common.UseCaseDevops, common.UseCaseIoT} {
+		file := filepath.Join(dir, use)
+		generateUseCase(t, use, file, 1, 2, parallelism)
+		for i := uint(0); i < parallelism; i++ {
+			got, err := ioutil.ReadFile(fmt.Sprintf("%s.%d", file, i))
+			if err != nil {
+				t.Fatalf("%s: could not read file %d: %v", use, i, err)
+			}
+			// file i is interleaved group 1 + 2i of 2 * parallelism
+			if want := generateUseCase(t, use, "", 1+2*i, 2*parallelism, 1); string(got) != want {
+				t.Errorf("%s: file %d differs from the output of interleaved group %d", use, i, 1+2*i)
+			}
+		}
+	}
+}
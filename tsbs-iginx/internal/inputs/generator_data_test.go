@@ -5,13 +5,16 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/blagojts/viper"
 	"github.com/spf13/pflag"
 	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/manifest"
 	"github.com/timescale/tsbs/pkg/data/serialize"
 	"github.com/timescale/tsbs/pkg/data/source"
 	"github.com/timescale/tsbs/pkg/data/usecases"
@@ -77,6 +80,20 @@ func TestDataGeneratorInit(t *testing.T) {
 	} else if dg.Out != &buf {
 		t.Errorf("Out not set to explicit io.Writer")
 	}
+
+	// Test that parallel files need an output file
+	c.Parallelism = 2
+	c.ParallelFiles = true
+	if err = dg.init(c); err == nil {
+		t.Errorf("unexpected lack of error with parallel files to stdout")
+	}
+
+	// Test that stateful serializers cannot run in parallel
+	c.ParallelFiles = false
+	c.Format = constants.FormatAkumuli
+	if err = dg.init(c); err == nil {
+		t.Errorf("unexpected lack of error with parallel akumuli")
+	}
 }
 
 func TestDataGeneratorGenerate(t *testing.T) {
@@ -118,6 +135,66 @@ func TestDataGeneratorGenerate(t *testing.T) {
 	}
 }
 
+func TestDataGeneratorGenerateManifest(t *testing.T) {
+	dir, err := ioutil.TempDir("", "manifest")
+	if err != nil {
+		t.Fatalf("could not create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	c := &common.DataGeneratorConfig{
+		BaseConfig: common.BaseConfig{
+			Seed:      123,
+			Format:    constants.FormatInflux,
+			Use:       common.UseCaseCPUOnly,
+			Scale:     2,
+			TimeStart: defaultTimeStart,
+			TimeEnd:   defaultTimeEnd,
+			File:      filepath.Join(dir, "data"),
+		},
+		Limit:                10,
+		InitialScale:         2,
+		LogInterval:          time.Minute,
+		InterleavedNumGroups: 1,
+	}
+	dg := &DataGenerator{}
+	target := &mockTarget{name: constants.FormatInflux, serializer: &testPointSerializer{}}
+	if err := dg.Generate(c, target); err != nil {
+		t.Fatalf("unexpected error when generating: %v", err)
+	}
+
+	m, err := manifest.Read(c.File + manifest.Suffix)
+	if err != nil {
+		t.Fatalf("manifest not written: %v", err)
+	}
+	if m.Points != c.Limit || m.Measurements["cpu"] == nil || m.Measurements["cpu"].Points != c.Limit {
+		t.Errorf("incorrect point counts: got %d, per measurement %v", m.Points, m.Measurements)
+	}
+	if m.Config.Seed != 123 || m.Config.Scale != 2 {
+		t.Errorf("incorrect config in manifest: %+v", m.Config.BaseConfig)
+	}
+	wantEnd, _ := time.Parse(time.RFC3339, "2016-01-01T00:04:00Z")
+	if !m.End.Equal(wantEnd) {
+		t.Errorf("incorrect end of data: got %v want %v", m.End, wantEnd)
+	}
+	f, err := os.Open(c.File)
+	if err != nil {
+		t.Fatalf("could not open data: %v", err)
+	}
+	defer f.Close()
+	if err := m.Verify(f); err != nil {
+		t.Errorf("data does not match its manifest: %v", err)
+	}
+}
+
+// testPointSerializer writes the measurement and timestamp of points
+type testPointSerializer struct{}
+
+func (s *testPointSerializer) Serialize(p *data.Point, w io.Writer) error {
+	_, err := fmt.Fprintf(w, "%s %d\n", p.MeasurementName(), p.Timestamp().UnixNano())
+	return err
+}
+
 var keyIteration = []byte("iteration")
 
 type testSimulator struct {
@@ -306,7 +383,7 @@ func TestGetSerializer(t *testing.T) {
 		t.Errorf("unexpected error creating scfg: %v", err)
 	}
 
-	sim := scfg.NewSimulator(dgc.LogInterval, 0)
+	sim := scfg.NewSimulator(common.Rand(), dgc.LogInterval, 0)
 	checkWriteHeader := func(format string, shouldWriteHeader bool) {
 		var buf bytes.Buffer
 		g.bufOut = bufio.NewWriter(&buf)
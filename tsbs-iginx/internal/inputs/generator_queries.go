@@ -12,7 +12,9 @@ import (
 
 	queryUtils "github.com/timescale/tsbs/cmd/tsbs_generate_queries/utils"
 	internalUtils "github.com/timescale/tsbs/internal/utils"
+	"github.com/timescale/tsbs/pkg/data/manifest"
 	"github.com/timescale/tsbs/pkg/data/usecases/common"
+	"github.com/timescale/tsbs/pkg/data/usecases/industrial"
 	"github.com/timescale/tsbs/pkg/query/config"
 	"github.com/timescale/tsbs/pkg/query/factories"
 )
@@ -40,6 +42,11 @@ type IoTGeneratorMaker interface {
 	NewIoT(start, end time.Time, scale int) (queryUtils.QueryGenerator, error)
 }
 
+// IndustrialGeneratorMaker creates a query generator for industrial use case
+type IndustrialGeneratorMaker interface {
+	NewIndustrial(start, end time.Time, scale int, layout industrial.Layout) (queryUtils.QueryGenerator, error)
+}
+
 // QueryGenerator is a type of Generator for creating queries to test against a
 // database. The output is specific to the type of database (due to each using
 // different querying techniques, e.g. SQL or REST), but is consumed by TSBS
@@ -108,6 +115,16 @@ func (g *QueryGenerator) init(conf common.GeneratorConfig) error {
 		return err
 	}
 
+	if g.conf.Manifest != "" {
+		m, err := manifest.Read(g.conf.Manifest)
+		if err != nil {
+			return err
+		}
+		if err := m.VerifyConfig(&g.conf.BaseConfig); err != nil {
+			return fmt.Errorf("queries do not match manifest %s: %v", g.conf.Manifest, err)
+		}
+	}
+
 	if err := g.initFactories(); err != nil {
 		return err
 	}
@@ -158,7 +175,7 @@ func (g *QueryGenerator) addFactory(database string, factory interface{}) error
 	validFactory := false
 
 	switch factory.(type) {
-	case DevopsGeneratorMaker, IoTGeneratorMaker:
+	case DevopsGeneratorMaker, IoTGeneratorMaker, IndustrialGeneratorMaker:
 		validFactory = true
 	}
 
@@ -196,6 +213,17 @@ func (g *QueryGenerator) getUseCaseGenerator(c *config.QueryGeneratorConfig) (qu
 		}
 
 		return devopsFactory.NewDevops(g.tsStart, g.tsEnd, scale)
+	case common.UseCaseIndustrial:
+		industrialFactory, ok := factory.(IndustrialGeneratorMaker)
+		if !ok {
+			return nil, fmt.Errorf(errUseCaseNotImplementedFmt, c.Use, c.Format)
+		}
+
+		layout := industrial.Layout{
+			Sensors:         int(c.SensorsPerDevice),
+			SensorsPerBlock: int(c.SensorsPerBlock),
+		}
+		return industrialFactory.NewIndustrial(g.tsStart, g.tsEnd, scale, layout)
 	default:
 		return nil, fmt.Errorf(errUnknownUseCaseFmt, c.Use)
 	}
@@ -14,14 +14,22 @@ const (
 const defaultWriteSize = 4 << 20 // 4 MB
 
 func getBufferedWriter(filename string, fallback io.Writer) (*bufio.Writer, error) {
+	w, err := getWriter(filename, fallback)
+	if err != nil {
+		return nil, err
+	}
+	return bufio.NewWriterSize(w, defaultWriteSize), nil
+}
+
+func getWriter(filename string, fallback io.Writer) (io.Writer, error) {
 	// If filename is given, output should go to a file
 	if len(filename) > 0 {
 		file, err := os.Create(filename)
 		if err != nil {
 			return nil, fmt.Errorf("cannot open file for write %s: %v", filename, err)
 		}
-		return bufio.NewWriterSize(file, defaultWriteSize), nil
+		return file, nil
 	}
 
-	return bufio.NewWriterSize(fallback, defaultWriteSize), nil
+	return fallback, nil
 }
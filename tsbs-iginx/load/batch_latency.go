@@ -0,0 +1,106 @@
+package load
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/HdrHistogram/hdrhistogram-go"
+)
+
+// Batch latencies are recorded in microseconds, between 1us and one hour,
+// with 3 significant digits.
+const (
+	latencyMin     = 1
+	latencyMax     = int64(time.Hour / time.Microsecond)
+	latencySigFigs = 3
+)
+
+// latencyRecorder collects the latency of every processed batch, both over
+// the whole run and per reporting interval. It is safe for concurrent use by
+// the workers; a nil recorder records nothing.
+type latencyRecorder struct {
+	mu       sync.Mutex
+	total    *hdrhistogram.Histogram
+	interval *hdrhistogram.Histogram
+}
+
+func newLatencyRecorder() *latencyRecorder {
+	return &latencyRecorder{total: newLatencyHistogram(), interval: newLatencyHistogram()}
+}
+
+func newLatencyHistogram() *hdrhistogram.Histogram {
+	return hdrhistogram.New(latencyMin, latencyMax, latencySigFigs)
+}
+
+// record adds the latency of one batch
+func (r *latencyRecorder) record(took time.Duration) {
+	if r == nil {
+		return
+	}
+	us := took.Microseconds()
+	if us < latencyMin {
+		us = latencyMin
+	} else if us > latencyMax {
+		us = latencyMax
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_ = r.total.RecordValue(us)
+	_ = r.interval.RecordValue(us)
+}
+
+// takeInterval returns the latencies recorded since the previous call and
+// starts a new interval
+func (r *latencyRecorder) takeInterval() *hdrhistogram.Histogram {
+	if r == nil {
+		return newLatencyHistogram()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	h := r.interval
+	r.interval = newLatencyHistogram()
+	return h
+}
+
+// overall returns a copy of the latencies recorded over the whole run
+func (r *latencyRecorder) overall() *hdrhistogram.Histogram {
+	if r == nil {
+		return newLatencyHistogram()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return hdrhistogram.Import(r.total.Export())
+}
+
+// latencyMillis returns the latency at quantile q (0-100) in milliseconds
+func latencyMillis(h *hdrhistogram.Histogram, q float64) float64 {
+	return float64(h.ValueAtQuantile(q)) / 1e3
+}
+
+// minLatencyMillis returns the lowest recorded latency in milliseconds. Unlike
+// the other quantiles it is not read with ValueAtQuantile, which returns 0 for
+// quantile 0.
+func minLatencyMillis(h *hdrhistogram.Histogram) float64 {
+	return float64(h.Min()) / 1e3
+}
+
+// latencyQuantiles returns the latency quantiles, in milliseconds, in the
+// same form as the query runners' results
+func latencyQuantiles(h *hdrhistogram.Histogram) map[string]float64 {
+	return map[string]float64{
+		"q0":   minLatencyMillis(h),
+		"q50":  latencyMillis(h, 50),
+		"q95":  latencyMillis(h, 95),
+		"q99":  latencyMillis(h, 99),
+		"q999": latencyMillis(h, 99.9),
+		"q100": latencyMillis(h, 100),
+	}
+}
+
+// latencySummary describes the latency distribution in one line
+func latencySummary(h *hdrhistogram.Histogram) string {
+	return fmt.Sprintf("min %0.2fms, p50 %0.2fms, p95 %0.2fms, p99 %0.2fms, p99.9 %0.2fms, max %0.2fms",
+		minLatencyMillis(h), latencyMillis(h, 50), latencyMillis(h, 95),
+		latencyMillis(h, 99), latencyMillis(h, 99.9), latencyMillis(h, 100))
+}
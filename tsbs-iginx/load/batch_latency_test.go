@@ -0,0 +1,95 @@
+package load
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/HdrHistogram/hdrhistogram-go"
+)
+
+func TestLatencyRecorderIntervals(t *testing.T) {
+	r := newLatencyRecorder()
+	r.record(time.Millisecond)
+	r.record(3 * time.Millisecond)
+	if got := r.takeInterval().TotalCount(); got != 2 {
+		t.Errorf("incorrect interval count: got %d want 2", got)
+	}
+	r.record(2 * time.Hour) // clamped to the histogram's range
+	h := r.takeInterval()
+	if got := h.TotalCount(); got != 1 {
+		t.Errorf("incorrect interval count: got %d want 1", got)
+	}
+	if got := latencyMillis(h, 100); got < float64(time.Hour/time.Millisecond)*0.99 {
+		t.Errorf("out of range latency not clamped: got %0.2fms", got)
+	}
+	if got := r.takeInterval().TotalCount(); got != 0 {
+		t.Errorf("interval not reset: got %d", got)
+	}
+
+	overall := r.overall()
+	if got := overall.TotalCount(); got != 3 {
+		t.Errorf("incorrect overall count: got %d want 3", got)
+	}
+	if got := minLatencyMillis(overall); got != 1 {
+		t.Errorf("incorrect min: got %0.2f want 1", got)
+	}
+	// overall returns a copy
+	overall.RecordValue(1)
+	if got := r.overall().TotalCount(); got != 3 {
+		t.Errorf("overall histogram shared with the recorder")
+	}
+}
+
+func TestLatencyRecorderNil(t *testing.T) {
+	var r *latencyRecorder
+	r.record(time.Millisecond)
+	if r.takeInterval().TotalCount() != 0 || r.overall().TotalCount() != 0 {
+		t.Errorf("nil recorder should be empty")
+	}
+}
+
+func TestSaveTestResultBatchLatencies(t *testing.T) {
+	dir, err := ioutil.TempDir("", "load-results")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	br := &CommonBenchmarkRunner{batchLatencies: newLatencyRecorder()}
+	br.ResultsFile = filepath.Join(dir, "results.json")
+	br.batchLatencies.record(2 * time.Millisecond)
+	br.batchLatencies.record(4 * time.Millisecond)
+	oldPrintFn := printFn
+	printFn = func(string, ...interface{}) (int, error) { return 0, nil }
+	defer func() { printFn = oldPrintFn }()
+	now := time.Now()
+	br.saveTestResult(time.Second, now, now.Add(time.Second), 1, 0, false)
+
+	b, err := ioutil.ReadFile(br.ResultsFile)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var result LoaderTestResult
+	if err = json.Unmarshal(b, &result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := result.Totals["batches"]; got != float64(2) {
+		t.Errorf("incorrect batch count: got %v want 2", got)
+	}
+	quantiles := result.Totals["batchLatencyQuantiles"].(map[string]interface{})
+	// values are only as precise as the histogram buckets
+	if got := quantiles["q100"].(float64); got < 4 || got > 4.01 {
+		t.Errorf("incorrect max latency: got %v want 4", got)
+	}
+	h, err := hdrhistogram.Decode([]byte(result.Totals["batchLatencyHistogram"].(string)))
+	if err != nil {
+		t.Fatalf("cannot decode histogram: %v", err)
+	}
+	if h.TotalCount() != 2 {
+		t.Errorf("incorrect histogram count: got %d want 2", h.TotalCount())
+	}
+}
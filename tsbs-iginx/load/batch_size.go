@@ -0,0 +1,202 @@
+package load
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/targets"
+)
+
+const (
+	// adaptiveWindow is the number of batches the throughput of a batch size
+	// is measured over before the size is changed
+	adaptiveWindow = 10
+	// adaptiveStep is the factor the batch size grows or shrinks by
+	adaptiveStep = 1.25
+	// adaptiveMaxFactor bounds the adaptive batch size to this many times
+	// --batch-size
+	adaptiveMaxFactor = 16
+)
+
+// AddBatchSizeFlags adds the flags that cut batches by bytes or adapt their
+// size to the flag set, for loaders that do not use AddToFlagSet.
+func AddBatchSizeFlags(fs *pflag.FlagSet, flagPrefix string) {
+	fs.Uint64(flagPrefix+"batch-bytes", 0, "Also cut batches so that their payload stays within this many bytes, for targets that report it; a single item larger than that is sent alone (0 = no limit)")
+	fs.Bool(flagPrefix+"adaptive-batch-size", false, fmt.Sprintf("Grow or shrink the batch size of each channel, i.e. of each worker with --hash-workers and of all the workers together without it, starting from --batch-size and up to %d times it, to the size with the best throughput", adaptiveMaxFactor))
+}
+
+// batchSizer decides when the batch of a channel is full: once it holds the
+// current number of items of the channel, or once its payload reaches
+// maxBytes. A batch is also sent before an item that would take its payload
+// over maxBytes. It is read by the scanner and updated by the workers.
+type batchSizer struct {
+	items    uint
+	maxBytes uint64
+	// adaptive holds the batch size of each channel, nil unless the size is
+	// adaptive
+	adaptive []*adaptiveBatchSize
+
+	warnOnce sync.Once
+}
+
+func newBatchSizer(items uint, maxBytes uint64, adaptive bool, numChannels uint) *batchSizer {
+	s := &batchSizer{items: items, maxBytes: maxBytes}
+	if adaptive {
+		s.adaptive = make([]*adaptiveBatchSize, numChannels)
+		for i := range s.adaptive {
+			s.adaptive[i] = newAdaptiveBatchSize(items, items*adaptiveMaxFactor)
+		}
+	}
+	return s
+}
+
+// size returns the current number of items of the batches of a channel
+func (s *batchSizer) size(channel uint) uint {
+	if s.adaptive == nil {
+		return s.items
+	}
+	return s.adaptive[channel].get()
+}
+
+// full reports whether the batch of a channel should be sent
+func (s *batchSizer) full(channel uint, b targets.Batch) bool {
+	if b.Len() >= s.size(channel) {
+		return true
+	}
+	if s.maxBytes == 0 {
+		return false
+	}
+	switch sb := b.(type) {
+	case targets.SizedBatch:
+		return sb.Bytes() >= s.maxBytes
+	default:
+		s.warnOnce.Do(func() {
+			log.Printf("warning: --batch-bytes is ignored, batches of this target do not report their size")
+		})
+		return false
+	}
+}
+
+// overflows reports whether appending the item would take the payload of the
+// non-empty batch of a channel over maxBytes, so the batch must be sent first
+func (s *batchSizer) overflows(b targets.Batch, item data.LoadedPoint) bool {
+	if s.maxBytes == 0 || b.Len() == 0 {
+		return false
+	}
+	sb, ok := b.(targets.SizedBatch)
+	return ok && sb.Bytes()+sb.AppendBytes(item) > s.maxBytes
+}
+
+// record adds the time a worker took to process a batch of the given number
+// of items to the batch size of the channel it reads from. The size adapts
+// per channel rather than per worker: the scanner cuts the batches of a
+// channel before any of its workers takes them.
+func (s *batchSizer) record(workerNum uint, items uint, took time.Duration) {
+	if s == nil || s.adaptive == nil {
+		return
+	}
+	s.adaptive[workerChannel(workerNum, uint(len(s.adaptive)))].record(items, took)
+}
+
+// workerChannel returns the channel a worker reads its batches from. The
+// workers are given the channels in turn, so without --hash-workers they all
+// share the one channel.
+func workerChannel(workerNum, numChannels uint) uint {
+	return workerNum % numChannels
+}
+
+// summary describes the final adaptive batch sizes, empty unless the size is
+// adaptive
+func (s *batchSizer) summary() string {
+	if s == nil || s.adaptive == nil {
+		return ""
+	}
+	sizes := make([]string, len(s.adaptive))
+	for i, size := range s.finalSizes() {
+		sizes[i] = fmt.Sprint(size)
+	}
+	return "adaptive batch size at the end: " + strings.Join(sizes, ", ")
+}
+
+// finalSizes returns the current batch size of each channel
+func (s *batchSizer) finalSizes() []uint {
+	sizes := make([]uint, len(s.adaptive))
+	for i := range s.adaptive {
+		sizes[i] = s.size(uint(i))
+	}
+	return sizes
+}
+
+// adaptiveBatchSize searches the batch size with the best throughput by hill
+// climbing: the throughput of the current size is measured over a window of
+// batches, and the size keeps moving in the same direction as long as the
+// throughput improves, otherwise it turns around.
+type adaptiveBatchSize struct {
+	current uint64
+	min     uint
+	max     uint
+
+	mu             sync.Mutex
+	items          uint64
+	took           time.Duration
+	batches        int
+	lastThroughput float64
+	grow           bool
+}
+
+func newAdaptiveBatchSize(start, max uint) *adaptiveBatchSize {
+	return &adaptiveBatchSize{current: uint64(start), min: 1, max: max, grow: true}
+}
+
+// get returns the current batch size
+func (a *adaptiveBatchSize) get() uint {
+	return uint(atomic.LoadUint64(&a.current))
+}
+
+// record adds a processed batch to the window, and changes the batch size
+// once the window is complete. Batches smaller than the current size, such as
+// the last ones, are measured as well: throughput is per item.
+func (a *adaptiveBatchSize) record(items uint, took time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.items += uint64(items)
+	a.took += took
+	a.batches++
+	if a.batches < adaptiveWindow {
+		return
+	}
+	var throughput float64
+	if a.took > 0 {
+		throughput = float64(a.items) / a.took.Seconds()
+	}
+	if throughput < a.lastThroughput {
+		a.grow = !a.grow
+	}
+	a.lastThroughput = throughput
+	a.items, a.took, a.batches = 0, 0, 0
+
+	size := float64(a.get())
+	if a.grow {
+		size = size*adaptiveStep + 1
+	} else {
+		size = size / adaptiveStep
+	}
+	next := uint(size)
+	if next < a.min {
+		next = a.min
+	} else if next > a.max {
+		next = a.max
+	}
+	atomic.StoreUint64(&a.current, uint64(next))
+}
+
+// newBatchSizer returns the batch sizer of the run
+func (l *CommonBenchmarkRunner) newBatchSizer(numChannels uint) *batchSizer {
+	return newBatchSizer(l.BatchSize, l.BatchBytes, l.AdaptiveBatchSize, numChannels)
+}
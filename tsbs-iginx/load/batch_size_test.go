@@ -0,0 +1,239 @@
+package load
+
+import (
+	"bufio"
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/targets"
+)
+
+// sizedTestBatch is a testBatch whose items are 10 bytes each
+type sizedTestBatch struct {
+	testBatch
+}
+
+func (b *sizedTestBatch) Bytes() uint64 { return uint64(b.len) * 10 }
+
+func (b *sizedTestBatch) AppendBytes(data.LoadedPoint) uint64 { return 10 }
+
+type sizedTestFactory struct{}
+
+func (f *sizedTestFactory) New() targets.Batch {
+	return &sizedTestBatch{}
+}
+
+func TestBatchSizerFull(t *testing.T) {
+	cases := []struct {
+		desc     string
+		sizer    *batchSizer
+		sized    bool
+		len      uint
+		wantFull bool
+	}{
+		{
+			desc:     "below batch size",
+			sizer:    &batchSizer{items: 5},
+			len:      4,
+			wantFull: false,
+		},
+		{
+			desc:     "at batch size",
+			sizer:    &batchSizer{items: 5},
+			len:      5,
+			wantFull: true,
+		},
+		{
+			desc:     "below batch bytes",
+			sizer:    &batchSizer{items: 5, maxBytes: 30},
+			sized:    true,
+			len:      2,
+			wantFull: false,
+		},
+		{
+			desc:     "at batch bytes",
+			sizer:    &batchSizer{items: 5, maxBytes: 30},
+			sized:    true,
+			len:      3,
+			wantFull: true,
+		},
+		{
+			desc:     "batch bytes ignored without size",
+			sizer:    &batchSizer{items: 5, maxBytes: 30},
+			len:      3,
+			wantFull: false,
+		},
+		{
+			desc:     "adaptive size",
+			sizer:    newBatchSizer(5, 0, true, 2),
+			len:      5,
+			wantFull: true,
+		},
+	}
+	for _, c := range cases {
+		var full bool
+		if c.sized {
+			full = c.sizer.full(0, &sizedTestBatch{testBatch{len: c.len}})
+		} else {
+			full = c.sizer.full(0, &testBatch{len: c.len})
+		}
+		if full != c.wantFull {
+			t.Errorf("%s: incorrect full: got %v want %v", c.desc, full, c.wantFull)
+		}
+	}
+}
+
+func TestBatchSizerOverflows(t *testing.T) {
+	cases := []struct {
+		desc     string
+		sizer    *batchSizer
+		sized    bool
+		len      uint
+		wantOver bool
+	}{
+		{
+			desc:     "no batch bytes",
+			sizer:    &batchSizer{items: 5},
+			sized:    true,
+			len:      4,
+			wantOver: false,
+		},
+		{
+			desc:     "next item fits",
+			sizer:    &batchSizer{items: 5, maxBytes: 25},
+			sized:    true,
+			len:      1,
+			wantOver: false,
+		},
+		{
+			desc:     "next item crosses batch bytes",
+			sizer:    &batchSizer{items: 5, maxBytes: 25},
+			sized:    true,
+			len:      2,
+			wantOver: true,
+		},
+		{
+			desc:     "empty batch takes an item larger than batch bytes",
+			sizer:    &batchSizer{items: 5, maxBytes: 5},
+			sized:    true,
+			len:      0,
+			wantOver: false,
+		},
+		{
+			desc:     "batch bytes ignored without size",
+			sizer:    &batchSizer{items: 5, maxBytes: 5},
+			len:      2,
+			wantOver: false,
+		},
+	}
+	for _, c := range cases {
+		var over bool
+		if c.sized {
+			over = c.sizer.overflows(&sizedTestBatch{testBatch{len: c.len}}, data.LoadedPoint{})
+		} else {
+			over = c.sizer.overflows(&testBatch{len: c.len}, data.LoadedPoint{})
+		}
+		if over != c.wantOver {
+			t.Errorf("%s: incorrect overflows: got %v want %v", c.desc, over, c.wantOver)
+		}
+	}
+}
+
+func TestScanBatchBytes(t *testing.T) {
+	testData := []byte{0x00, 0x01, 0x02, 0x03, 0x04}
+	sizer := &batchSizer{items: 10, maxBytes: 25}
+
+	channels := []chan targets.Batch{make(chan targets.Batch, len(testData))}
+	ds := &testDataSource{br: bufio.NewReader(bytes.NewReader(testData))}
+	scanWithoutFlowControl(ds, &targets.ConstantIndexer{}, &sizedTestFactory{}, channels, sizer, 0, nil)
+	close(channels[0])
+	var lens []uint
+	for b := range channels[0] {
+		if got := b.(targets.SizedBatch).Bytes(); got > sizer.maxBytes {
+			t.Errorf("batch of %d bytes is over batch bytes %d", got, sizer.maxBytes)
+		}
+		lens = append(lens, b.Len())
+	}
+	if len(lens) != 3 || lens[0] != 2 || lens[1] != 2 || lens[2] != 1 {
+		t.Errorf("incorrect batch lengths: got %v want [2 2 1]", lens)
+	}
+}
+
+func TestAdaptiveBatchSize(t *testing.T) {
+	a := newAdaptiveBatchSize(100, 200)
+	// recordWindow records a window of batches of the current size that
+	// take perItem per item
+	recordWindow := func(perItem time.Duration) {
+		for i := 0; i < adaptiveWindow; i++ {
+			size := a.get()
+			a.record(size, time.Duration(size)*perItem)
+		}
+	}
+
+	recordWindow(time.Millisecond)
+	if got := a.get(); got != 126 {
+		t.Fatalf("size did not grow: got %d want 126", got)
+	}
+	// throughput improves, keep growing up to the maximum
+	recordWindow(time.Millisecond / 2)
+	if got := a.get(); got != 158 {
+		t.Fatalf("size did not keep growing: got %d want 158", got)
+	}
+	recordWindow(time.Millisecond / 2)
+	recordWindow(time.Millisecond / 2)
+	if got := a.get(); got != 200 {
+		t.Fatalf("size not bounded: got %d want 200", got)
+	}
+	// throughput drops, turn around
+	recordWindow(time.Millisecond)
+	if got := a.get(); got != 160 {
+		t.Fatalf("size did not shrink: got %d want 160", got)
+	}
+	// a window is needed before the size changes again
+	a.record(a.get(), time.Millisecond)
+	if got := a.get(); got != 160 {
+		t.Fatalf("size changed before the window was complete: got %d", got)
+	}
+}
+
+func TestAdaptiveBatchSizeMin(t *testing.T) {
+	a := newAdaptiveBatchSize(1, 16)
+	a.grow = false
+	for i := 0; i < adaptiveWindow; i++ {
+		a.record(1, time.Millisecond)
+	}
+	if got := a.get(); got != 1 {
+		t.Errorf("size below minimum: got %d want 1", got)
+	}
+}
+
+func TestBatchSizerRecord(t *testing.T) {
+	s := newBatchSizer(100, 0, true, 2)
+	for i := 0; i < adaptiveWindow; i++ {
+		// worker 3 uses channel 1
+		s.record(3, 100, time.Millisecond)
+	}
+	if got := s.finalSizes(); got[0] != 100 || got[1] != 126 {
+		t.Errorf("incorrect batch sizes: got %v want [100 126]", got)
+	}
+	if got, want := s.summary(), "adaptive batch size at the end: 100, 126"; got != want {
+		t.Errorf("incorrect summary: got %q want %q", got, want)
+	}
+
+	// without --hash-workers the workers share the size of the one channel
+	shared := newBatchSizer(100, 0, true, 1)
+	for i := 0; i < adaptiveWindow; i++ {
+		shared.record(uint(i%2), 100, time.Millisecond)
+	}
+	if got := shared.finalSizes(); got[0] != 126 {
+		t.Errorf("incorrect shared batch size: got %v want [126]", got)
+	}
+
+	var fixed *batchSizer
+	fixed.record(0, 100, time.Millisecond)
+	if got := fixed.summary(); got != "" {
+		t.Errorf("unexpected summary for fixed batch size: %q", got)
+	}
+}
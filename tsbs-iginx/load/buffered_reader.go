@@ -2,25 +2,153 @@ package load
 
 import (
 	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
 )
 
 const (
 	defaultReadSize = 4 << 20 // 4 MB
 )
 
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
 // GetBufferedReader returns the buffered Reader that should be used by the file loader
-// if no file name is specified a buffer for STDIN is returned
+// if no file name is specified a buffer for STDIN is returned.
+// The file name may be a glob pattern, see InputFiles, but must match a single
+// file: the formats of loaders that read their input with GetBufferedReader
+// may have a header, so several files are only read with GetDataSource. Input
+// compressed with gzip or zstd is decompressed; the decompressor is released
+// once the input is read to the end, see OpenBufferedReader to release it
+// earlier.
 func GetBufferedReader(fileName string) *bufio.Reader {
+	br, _ := OpenBufferedReader(fileName)
+	return br
+}
+
+// OpenBufferedReader returns the same reader as GetBufferedReader, along with
+// a function that closes the input file and releases its decompressor, for
+// readers that may stop before the end of the input.
+func OpenBufferedReader(fileName string) (br *bufio.Reader, close func()) {
 	if len(fileName) == 0 {
 		// Read from STDIN
-		return bufio.NewReaderSize(os.Stdin, defaultReadSize)
+		return newInputReader(os.Stdin, "STDIN")
+	}
+	files, err := InputFiles(fileName)
+	if err != nil {
+		fatal("cannot open file for read %s: %v", fileName, err)
+		return nil, nil
 	}
-	// Read from specified file
+	if len(files) > 1 {
+		fatal("cannot read several files %s: this loader reads a single input file", strings.Join(files, ", "))
+		return nil, nil
+	}
+	return openInput(files[0])
+}
+
+// InputFiles returns the files to read for the value of a --file flag: a
+// comma separated list of file names or glob patterns, whose matches are
+// sorted. A pattern that matches no file is an error.
+func InputFiles(fileName string) ([]string, error) {
+	var files []string
+	for _, pattern := range strings.Split(fileName, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %s: %v", pattern, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no file matches %s", pattern)
+		}
+		sort.Strings(matches)
+		files = append(files, matches...)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no file in %q", fileName)
+	}
+	return files, nil
+}
+
+// openInput opens a file for reading, decompressing it if needed. close
+// closes the file.
+func openInput(fileName string) (br *bufio.Reader, close func()) {
 	file, err := os.Open(fileName)
 	if err != nil {
 		fatal("cannot open file for read %s: %v", fileName, err)
-		return nil
+		return nil, nil
 	}
-	return bufio.NewReaderSize(file, defaultReadSize)
+	br, closeDecompressor := newInputReader(file, fileName)
+	return br, func() {
+		closeDecompressor()
+		_ = file.Close()
+	}
+}
+
+// newInputReader returns a buffered reader of r, which decompresses it if it
+// starts with the magic bytes of gzip or zstd. close releases the
+// decompressor, not r; the decompressor is also released once it returns an
+// error, io.EOF included.
+func newInputReader(r io.Reader, name string) (br *bufio.Reader, close func()) {
+	br = bufio.NewReaderSize(r, defaultReadSize)
+	// Peek returns fewer bytes, and an error, for inputs shorter than the
+	// magic bytes: they are read as they are
+	magic, _ := br.Peek(len(zstdMagic))
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			fatal("cannot read gzip input %s: %v", name, err)
+			return nil, nil
+		}
+		cr := newClosingReader(gz, func() { _ = gz.Close() })
+		return bufio.NewReaderSize(cr, defaultReadSize), cr.close
+	case bytes.HasPrefix(magic, zstdMagic):
+		zr, err := zstd.NewReader(br)
+		if err != nil {
+			fatal("cannot read zstd input %s: %v", name, err)
+			return nil, nil
+		}
+		cr := newClosingReader(zr, zr.Close)
+		return bufio.NewReaderSize(cr, defaultReadSize), cr.close
+	default:
+		return br, func() {}
+	}
+}
+
+// closingReader calls a close function once, when the reader returns an error
+// or when close is called, whichever comes first
+type closingReader struct {
+	r       io.Reader
+	closeFn func()
+	once    sync.Once
+}
+
+func newClosingReader(r io.Reader, closeFn func()) *closingReader {
+	return &closingReader{r: r, closeFn: closeFn}
+}
+
+func (c *closingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	if err != nil {
+		c.close()
+	}
+	return n, err
+}
+
+func (c *closingReader) close() {
+	c.once.Do(c.closeFn)
 }
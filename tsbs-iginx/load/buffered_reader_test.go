@@ -0,0 +1,242 @@
+package load
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+	"github.com/timescale/tsbs/pkg/targets"
+)
+
+// writeInputs writes the given contents to files in a new directory, gzip or
+// zstd compressed depending on the file extension
+func writeInputs(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "load-input")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		var buf bytes.Buffer
+		switch filepath.Ext(name) {
+		case ".gz":
+			w := gzip.NewWriter(&buf)
+			_, _ = w.Write([]byte(content))
+			_ = w.Close()
+		case ".zst":
+			w, err := zstd.NewWriter(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, _ = w.Write([]byte(content))
+			_ = w.Close()
+		default:
+			buf.WriteString(content)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, name), buf.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestGetBufferedReader(t *testing.T) {
+	dir := writeInputs(t, map[string]string{
+		"plain.txt":  "a\nb\n",
+		"part-0.gz":  "c\nd\n",
+		"part-1.zst": "e\n",
+		"short":      "f",
+	})
+	defer os.RemoveAll(dir)
+	cases := []struct {
+		desc string
+		file string
+		want string
+	}{
+		{desc: "plain", file: "plain.txt", want: "a\nb\n"},
+		{desc: "gzip", file: "part-0.gz", want: "c\nd\n"},
+		{desc: "zstd", file: "part-1.zst", want: "e\n"},
+		{desc: "shorter than magic bytes", file: "short", want: "f"},
+		{desc: "glob", file: "plain.*", want: "a\nb\n"},
+	}
+	for _, c := range cases {
+		var names []string
+		for _, name := range strings.Split(c.file, ",") {
+			names = append(names, filepath.Join(dir, name))
+		}
+		got, err := ioutil.ReadAll(GetBufferedReader(strings.Join(names, ",")))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", c.desc, err)
+		} else if string(got) != c.want {
+			t.Errorf("%s: incorrect content: got %q want %q", c.desc, got, c.want)
+		}
+	}
+}
+
+func TestGetBufferedReaderSeveralFiles(t *testing.T) {
+	dir := writeInputs(t, map[string]string{"part-0": "a\n", "part-1": "b\n"})
+	defer os.RemoveAll(dir)
+	oldFatal := fatal
+	defer func() { fatal = oldFatal }()
+	fatalCalled := false
+	fatal = func(string, ...interface{}) { fatalCalled = true }
+
+	for _, file := range []string{"part-*", "part-0,part-1"} {
+		fatalCalled = false
+		var names []string
+		for _, name := range strings.Split(file, ",") {
+			names = append(names, filepath.Join(dir, name))
+		}
+		if br := GetBufferedReader(strings.Join(names, ",")); br != nil || !fatalCalled {
+			t.Errorf("%s: several files were not rejected", file)
+		}
+	}
+}
+
+func TestInputFiles(t *testing.T) {
+	dir := writeInputs(t, map[string]string{"b-1": "", "b-0": "", "a": ""})
+	defer os.RemoveAll(dir)
+	files, err := InputFiles(filepath.Join(dir, "b-*") + ", " + filepath.Join(dir, "a"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{filepath.Join(dir, "b-0"), filepath.Join(dir, "b-1"), filepath.Join(dir, "a")}
+	if strings.Join(files, ",") != strings.Join(want, ",") {
+		t.Errorf("incorrect files: got %v want %v", files, want)
+	}
+	if _, err := InputFiles(filepath.Join(dir, "c-*")); err == nil {
+		t.Errorf("unexpected lack of error for pattern without match")
+	}
+	if _, err := InputFiles(","); err == nil {
+		t.Errorf("unexpected lack of error for empty list")
+	}
+}
+
+// lineDataSource returns the lines of its reader, reusing the scanner's buffer
+type lineDataSource struct {
+	scanner *bufio.Scanner
+}
+
+func (d *lineDataSource) NextItem() data.LoadedPoint {
+	if !d.scanner.Scan() {
+		return data.LoadedPoint{}
+	}
+	return data.NewLoadedPoint(d.scanner.Bytes())
+}
+
+func (d *lineDataSource) Headers() *common.GeneratedDataHeaders { return nil }
+
+func TestGetDataSourceParallel(t *testing.T) {
+	files := map[string]string{}
+	var want []string
+	for _, name := range []string{"x-0", "x-1.gz", "x-2.zst"} {
+		var content strings.Builder
+		// more items than a chunk, so that files are interleaved
+		for i := 0; i < parallelChunkSize+10; i++ {
+			line := name + "-" + strings.Repeat("i", i%7)
+			content.WriteString(line + "\n")
+			want = append(want, line)
+		}
+		files[name] = content.String()
+	}
+	dir := writeInputs(t, files)
+	defer os.RemoveAll(dir)
+
+	ds := GetDataSource(filepath.Join(dir, "x-*"), func(br *bufio.Reader) targets.DataSource {
+		return &lineDataSource{scanner: bufio.NewScanner(br)}
+	})
+	var got []string
+	for {
+		item := ds.NextItem()
+		if item.Data == nil {
+			break
+		}
+		got = append(got, string(item.Data.([]byte)))
+	}
+	sort.Strings(got)
+	sort.Strings(want)
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("incorrect items: got %d items want %d", len(got), len(want))
+	}
+}
+
+func TestParallelDataSourceClose(t *testing.T) {
+	files := map[string]string{}
+	// more chunks than the scanners may read ahead, so that they block
+	line := strings.Repeat("i", 10) + "\n"
+	for _, name := range []string{"y-0", "y-1"} {
+		files[name] = strings.Repeat(line, (parallelChunksPerFile+2)*parallelChunkSize)
+	}
+	dir := writeInputs(t, files)
+	defer os.RemoveAll(dir)
+
+	ds := GetDataSource(filepath.Join(dir, "y-*"), func(br *bufio.Reader) targets.DataSource {
+		return &lineDataSource{scanner: bufio.NewScanner(br)}
+	})
+	if item := ds.NextItem(); item.Data == nil {
+		t.Fatalf("no item read")
+	}
+
+	// the scan stops early, as with --limit
+	closed := make(chan struct{})
+	go func() {
+		ds.(targets.DataSourceCloser).Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("scanners of the files did not stop on close")
+	}
+	// only the chunks handed over before the close are left
+	left := 0
+	for ds.NextItem().Data != nil {
+		left++
+	}
+	if max := (len(files)*parallelChunksPerFile + 1) * parallelChunkSize; left > max {
+		t.Errorf("too many items read after close: got %d want at most %d", left, max)
+	}
+}
+
+func TestClosingReader(t *testing.T) {
+	closes := 0
+	cr := newClosingReader(strings.NewReader("abc"), func() { closes++ })
+	if _, err := ioutil.ReadAll(cr); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if closes != 1 {
+		t.Errorf("incorrect closes at the end of the input: got %d want 1", closes)
+	}
+	cr.close()
+	if closes != 1 {
+		t.Errorf("incorrect closes after a second close: got %d want 1", closes)
+	}
+}
+
+func TestGetDataSourceSingleFileClose(t *testing.T) {
+	dir := writeInputs(t, map[string]string{"z.zst": strings.Repeat("line\n", 10)})
+	defer os.RemoveAll(dir)
+
+	ds := GetDataSource(filepath.Join(dir, "z.zst"), func(br *bufio.Reader) targets.DataSource {
+		return &lineDataSource{scanner: bufio.NewScanner(br)}
+	})
+	if item := ds.NextItem(); item.Data == nil {
+		t.Fatalf("no item read")
+	}
+	c, ok := ds.(targets.DataSourceCloser)
+	if !ok {
+		t.Fatalf("data source of a single file cannot be closed")
+	}
+	c.Close()
+	// closing again, as the loader does after the scan, is harmless
+	c.Close()
+}
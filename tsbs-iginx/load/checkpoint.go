@@ -0,0 +1,233 @@
+package load
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math"
+	"os"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	defaultCheckpointInterval = 10 * time.Second
+
+	// noItem marks a channel without a batch being filled
+	noItem = math.MaxUint64
+)
+
+// AddCheckpointFlags adds the flags of resumable loads to the flag set, for
+// loaders that do not use AddToFlagSet.
+func AddCheckpointFlags(fs *pflag.FlagSet, flagPrefix string) {
+	fs.String(flagPrefix+"checkpoint-file", "", "Periodically save the number of items inserted to this file, so that the load can be resumed")
+	fs.Duration(flagPrefix+"checkpoint-interval", defaultCheckpointInterval, "How often to save the checkpoint")
+	fs.Bool(flagPrefix+"resume-from-checkpoint", false, "Skip the items already inserted according to --checkpoint-file, without creating the database")
+}
+
+// Checkpoint is the progress of a load saved to the checkpoint file
+type Checkpoint struct {
+	// File is the input file, if the data was read from one
+	File string `json:"file"`
+	// Items is the number of input items, counted from the start of the
+	// input, that were all inserted
+	Items uint64 `json:"items"`
+	// Complete is set once all input items were inserted. A load that
+	// skipped batches after errors is never complete.
+	Complete bool      `json:"complete"`
+	Time     time.Time `json:"time"`
+}
+
+// ReadCheckpoint reads a checkpoint file
+func ReadCheckpoint(path string) (*Checkpoint, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c Checkpoint
+	if err = json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint file %s: %v", path, err)
+	}
+	return &c, nil
+}
+
+// checkpointer tracks the batches the scanner sent until the workers
+// acknowledge them, to know how many leading input items were all inserted.
+// Batches are acknowledged out of order and, with hashed workers, hold items
+// that are not contiguous, so all items before the first item of the oldest
+// unacknowledged or unsent batch were inserted. A batch skipped after an error
+// holds the checkpoint before its first item for the rest of the load, so that
+// a resumed load sends it again. It is used by the scanner only; a nil
+// checkpointer tracks nothing.
+type checkpointer struct {
+	path       string
+	interval   time.Duration
+	file       string
+	resumeFrom uint64
+
+	nextSeq uint64
+	// index of the first item of the batches sent but not acknowledged
+	pending map[uint64]uint64
+	// index of the first item of the batch being filled for each channel
+	filling []uint64
+	// index of the first item of the earliest batch skipped after an error
+	skippedFrom uint64
+
+	lastWrite time.Time
+	written   uint64
+	nowFn     func() time.Time
+}
+
+func newCheckpointer(path string, interval time.Duration, file string, numChannels int, resumeFrom uint64) *checkpointer {
+	c := &checkpointer{
+		path:        path,
+		interval:    interval,
+		file:        file,
+		resumeFrom:  resumeFrom,
+		pending:     make(map[uint64]uint64),
+		filling:     make([]uint64, numChannels),
+		skippedFrom: noItem,
+		written:     resumeFrom,
+		nowFn:       time.Now,
+	}
+	for i := range c.filling {
+		c.filling[i] = noItem
+	}
+	c.lastWrite = c.nowFn()
+	return c
+}
+
+// skip returns the number of items to skip before scanning
+func (c *checkpointer) skip() uint64 {
+	if c == nil {
+		return 0
+	}
+	return c.resumeFrom
+}
+
+// startBatch records the index of the first item appended to the empty batch
+// of a channel
+func (c *checkpointer) startBatch(channel uint, item uint64) {
+	if c == nil {
+		return
+	}
+	c.filling[channel] = item
+}
+
+// sendBatch records that the batch of a channel is sent and returns its
+// sequence number
+func (c *checkpointer) sendBatch(channel uint) uint64 {
+	if c == nil {
+		return 0
+	}
+	seq := c.nextSeq
+	c.nextSeq++
+	c.pending[seq] = c.filling[channel]
+	c.filling[channel] = noItem
+	return seq
+}
+
+// ack records that the batch with the given sequence number was inserted or
+// skipped and saves the checkpoint if the interval passed
+func (c *checkpointer) ack(seq uint64, skipped bool, itemsRead uint64) {
+	if c == nil {
+		return
+	}
+	if first := c.pending[seq]; skipped && first < c.skippedFrom {
+		c.skippedFrom = first
+	}
+	delete(c.pending, seq)
+	now := c.nowFn()
+	if now.Sub(c.lastWrite) < c.interval {
+		return
+	}
+	c.lastWrite = now
+	if items := c.acknowledged(itemsRead); items > c.written {
+		c.save(items, false)
+	}
+}
+
+// acknowledged returns the number of leading items that were all inserted
+func (c *checkpointer) acknowledged(itemsRead uint64) uint64 {
+	items := itemsRead
+	if c.skippedFrom < items {
+		items = c.skippedFrom
+	}
+	for _, first := range c.pending {
+		if first < items {
+			items = first
+		}
+	}
+	for _, first := range c.filling {
+		if first < items {
+			items = first
+		}
+	}
+	return items
+}
+
+// finish saves the final checkpoint once all sent batches were acknowledged
+func (c *checkpointer) finish(itemsRead uint64, complete bool) {
+	if c == nil {
+		return
+	}
+	if c.skippedFrom != noItem {
+		log.Printf("batches were skipped after errors, the checkpoint stays before item %d", c.skippedFrom)
+		complete = false
+	}
+	c.save(c.acknowledged(itemsRead), complete)
+}
+
+// save writes the checkpoint file, replacing the previous one atomically.
+// Failures are logged only, the load goes on.
+func (c *checkpointer) save(items uint64, complete bool) {
+	b, err := json.MarshalIndent(Checkpoint{File: c.file, Items: items, Complete: complete, Time: c.nowFn()}, "", " ")
+	if err == nil {
+		tmp := c.path + ".tmp"
+		if err = ioutil.WriteFile(tmp, b, 0644); err == nil {
+			err = os.Rename(tmp, c.path)
+		}
+	}
+	if err != nil {
+		log.Printf("could not save checkpoint to %s: %v", c.path, err)
+		return
+	}
+	c.written = items
+}
+
+// resumeFrom returns the number of items to skip according to the checkpoint
+// file, if the load is resumed
+func (l *CommonBenchmarkRunner) resumeFrom() uint64 {
+	if !l.ResumeFromCheckpoint {
+		return 0
+	}
+	cp, err := ReadCheckpoint(l.CheckpointFile)
+	if err != nil {
+		fatal("cannot resume from checkpoint: %v", err)
+		return 0
+	}
+	if cp.File != "" && l.FileName != "" && cp.File != l.FileName {
+		log.Printf("warning: checkpoint was saved while loading %s, not %s", cp.File, l.FileName)
+	}
+	if cp.Complete {
+		printFn("checkpoint %s marks the load as complete, skipping all %d items\n", l.CheckpointFile, cp.Items)
+	} else {
+		printFn("resuming from checkpoint %s, skipping %d items\n", l.CheckpointFile, cp.Items)
+	}
+	return cp.Items
+}
+
+// newCheckpointer returns the checkpointer of the run, or nil without
+// --checkpoint-file
+func (l *CommonBenchmarkRunner) newCheckpointer(numChannels uint) *checkpointer {
+	if l.CheckpointFile == "" {
+		return nil
+	}
+	interval := l.CheckpointInterval
+	if interval <= 0 {
+		interval = defaultCheckpointInterval
+	}
+	return newCheckpointer(l.CheckpointFile, interval, l.FileName, int(numChannels), l.resumeFrom())
+}
@@ -0,0 +1,199 @@
+package load
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/targets"
+)
+
+func TestCheckpointerAcknowledged(t *testing.T) {
+	cp := newCheckpointer("", time.Hour, "", 2, 0)
+	// channel 0 gets items 0, 2, 4, channel 1 items 1, 3
+	cp.startBatch(0, 0)
+	cp.startBatch(1, 1)
+	seq0 := cp.sendBatch(0)
+	cp.startBatch(0, 4)
+	seq1 := cp.sendBatch(1)
+	if got := cp.acknowledged(5); got != 0 {
+		t.Errorf("incorrect acknowledged items with nothing acked: got %d want 0", got)
+	}
+	cp.ack(seq1, false, 5)
+	if got := cp.acknowledged(5); got != 0 {
+		t.Errorf("incorrect acknowledged items with later batch acked: got %d want 0", got)
+	}
+	cp.ack(seq0, false, 5)
+	if got := cp.acknowledged(5); got != 4 {
+		t.Errorf("incorrect acknowledged items with filling batch: got %d want 4", got)
+	}
+	seq2 := cp.sendBatch(0)
+	cp.ack(seq2, false, 5)
+	if got := cp.acknowledged(5); got != 5 {
+		t.Errorf("incorrect acknowledged items with all acked: got %d want 5", got)
+	}
+}
+
+func TestCheckpointerSkippedBatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tsbs-checkpoint")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "checkpoint.json")
+
+	cp := newCheckpointer(path, time.Hour, "", 1, 0)
+	cp.startBatch(0, 0)
+	cp.ack(cp.sendBatch(0), false, 2)
+	cp.startBatch(0, 2)
+	cp.ack(cp.sendBatch(0), true, 4)
+	cp.startBatch(0, 4)
+	cp.ack(cp.sendBatch(0), false, 6)
+	if got := cp.acknowledged(6); got != 2 {
+		t.Errorf("incorrect acknowledged items after a skipped batch: got %d want 2", got)
+	}
+
+	cp.finish(6, true)
+	c, err := ReadCheckpoint(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.Items != 2 || c.Complete {
+		t.Errorf("incorrect checkpoint after a skipped batch: %+v", c)
+	}
+}
+
+func TestCheckpointerSavesAfterInterval(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tsbs-checkpoint")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "checkpoint.json")
+
+	now := time.Unix(1000, 0)
+	cp := newCheckpointer(path, time.Second, "data", 1, 0)
+	cp.nowFn = func() time.Time { return now }
+	cp.lastWrite = now
+	cp.startBatch(0, 0)
+	cp.ack(cp.sendBatch(0), false, 10)
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("checkpoint saved before the interval passed")
+	}
+	now = now.Add(time.Second)
+	cp.startBatch(0, 10)
+	cp.ack(cp.sendBatch(0), false, 20)
+	c, err := ReadCheckpoint(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.Items != 20 || c.Complete || c.File != "data" {
+		t.Errorf("incorrect checkpoint: %+v", c)
+	}
+}
+
+func TestScanWithCheckpoint(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tsbs-checkpoint")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "checkpoint.json")
+	testData := []byte{0x00, 0x01, 0x02, 0x03, 0x04}
+
+	closed := make(chan struct{})
+	close(closed)
+	cases := []struct {
+		desc         string
+		resumeFrom   uint64
+		done         chan struct{}
+		wantCalls    uint64
+		wantItems    uint64
+		wantComplete bool
+	}{
+		{
+			desc:         "full load",
+			wantCalls:    uint64(len(testData)),
+			wantItems:    uint64(len(testData)),
+			wantComplete: true,
+		},
+		{
+			desc:         "resumed load",
+			resumeFrom:   3,
+			wantCalls:    uint64(len(testData)),
+			wantItems:    uint64(len(testData)),
+			wantComplete: true,
+		},
+		{
+			desc:         "stopped load",
+			resumeFrom:   2,
+			done:         closed,
+			wantCalls:    2,
+			wantItems:    2,
+			wantComplete: false,
+		},
+	}
+	for _, c := range cases {
+		channels := []*duplexChannel{newDuplexChannel(1)}
+		ds := &testDataSource{br: bufio.NewReader(bytes.NewReader(testData))}
+		cp := newCheckpointer(path, time.Hour, "", len(channels), c.resumeFrom)
+		go _boringWorker(channels[0])
+		read := scanWithFlowControl(channels, &batchSizer{items: 2}, 0, c.done, cp, ds, &testFactory{}, &targets.ConstantIndexer{})
+		_checkScan(t, c.desc, ds.called, read, c.wantCalls)
+
+		got, err := ReadCheckpoint(path)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.desc, err)
+		}
+		if got.Items != c.wantItems {
+			t.Errorf("%s: incorrect checkpoint items: got %d want %d", c.desc, got.Items, c.wantItems)
+		}
+		if got.Complete != c.wantComplete {
+			t.Errorf("%s: incorrect checkpoint complete: got %v want %v", c.desc, got.Complete, c.wantComplete)
+		}
+	}
+}
+
+func TestGetBenchmarkRunnerResume(t *testing.T) {
+	c := BenchmarkRunnerConfig{
+		DoCreateDB:           true,
+		CheckpointFile:       "checkpoint.json",
+		ResumeFromCheckpoint: true,
+	}
+	r := GetBenchmarkRunner(c).(*CommonBenchmarkRunner)
+	if r.DoCreateDB {
+		t.Errorf("database is created when resuming")
+	}
+
+	func() {
+		defer func() {
+			if re := recover(); re == nil {
+				t.Errorf("did not panic when resuming without a checkpoint file")
+			}
+		}()
+		GetBenchmarkRunner(BenchmarkRunnerConfig{ResumeFromCheckpoint: true})
+	}()
+	func() {
+		defer func() {
+			if re := recover(); re == nil {
+				t.Errorf("did not panic when checkpointing without flow control")
+			}
+		}()
+		GetBenchmarkRunner(BenchmarkRunnerConfig{CheckpointFile: "checkpoint.json", NoFlowControl: true})
+	}()
+
+	dir := writeInputs(t, map[string]string{"part-0": "", "part-1": ""})
+	defer os.RemoveAll(dir)
+	func() {
+		defer func() {
+			if re := recover(); re == nil {
+				t.Errorf("did not panic when checkpointing several files")
+			}
+		}()
+		GetBenchmarkRunner(BenchmarkRunnerConfig{CheckpointFile: "checkpoint.json", FileName: filepath.Join(dir, "part-*")})
+	}()
+}
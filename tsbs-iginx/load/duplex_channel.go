@@ -7,26 +7,41 @@ import "github.com/timescale/tsbs/pkg/targets"
 // to process and the toScan channel allows the worker to acknowledge completion.
 // Using this we can accomplish better flow control between the scanner and workers.
 type duplexChannel struct {
-	toWorker  chan targets.Batch
-	toScanner chan bool
+	toWorker  chan sentBatch
+	toScanner chan batchAck
+}
+
+// sentBatch is a batch sent to a worker, along with the sequence number the
+// worker acknowledges it with
+type sentBatch struct {
+	batch targets.Batch
+	seq   uint64
+}
+
+// batchAck acknowledges the batch with the given sequence number, which the
+// worker either inserted or skipped after an error
+type batchAck struct {
+	seq     uint64
+	skipped bool
 }
 
 // newDuplexChannel returns a duplexChannel with specified buffer sizes
 func newDuplexChannel(queueLen int) *duplexChannel {
 	return &duplexChannel{
-		toWorker:  make(chan targets.Batch, queueLen),
-		toScanner: make(chan bool, queueLen),
+		toWorker:  make(chan sentBatch, queueLen),
+		toScanner: make(chan batchAck, queueLen),
 	}
 }
 
 // sendToWorker passes a batch of work on to the worker from the scanner
-func (dc *duplexChannel) sendToWorker(b targets.Batch) {
-	dc.toWorker <- b
+func (dc *duplexChannel) sendToWorker(b targets.Batch, seq uint64) {
+	dc.toWorker <- sentBatch{batch: b, seq: seq}
 }
 
-// sendToScanner passes an acknowledge to the scanner from the worker
-func (dc *duplexChannel) sendToScanner() {
-	dc.toScanner <- true
+// sendToScanner passes an acknowledge of the batch with the given sequence
+// number to the scanner from the worker
+func (dc *duplexChannel) sendToScanner(seq uint64, skipped bool) {
+	dc.toScanner <- batchAck{seq: seq, skipped: skipped}
 }
 
 // close closes down the duplexChannel
@@ -36,17 +36,17 @@ func TestNewDuplexChannel(t *testing.T) {
 
 func TestSendToWorker(t *testing.T) {
 	ch := newDuplexChannel(1)
-	ch.sendToWorker(&testBatch{})
-	if res, ok := <-ch.toWorker; !ok || res == nil {
+	ch.sendToWorker(&testBatch{}, 3)
+	if res, ok := <-ch.toWorker; !ok || res.batch == nil || res.seq != 3 {
 		t.Errorf("sendToWorker did not send item or sent nil")
 	}
 }
 
 func TestSendToScanner(t *testing.T) {
 	ch := newDuplexChannel(1)
-	ch.sendToScanner()
-	if res, ok := <-ch.toScanner; !res || !ok {
-		t.Errorf("sendToScanner did not send 'true', sent %v", res)
+	ch.sendToScanner(3, true)
+	if res, ok := <-ch.toScanner; res.seq != 3 || !res.skipped || !ok {
+		t.Errorf("sendToScanner did not send the sequence number 3, sent %v", res)
 	}
 }
 
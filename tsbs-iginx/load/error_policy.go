@@ -0,0 +1,175 @@
+package load
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/timescale/tsbs/pkg/targets"
+)
+
+// What to do when a batch cannot be inserted
+const (
+	// OnErrorAbort stops the load on the first failed batch
+	OnErrorAbort = "abort"
+	// OnErrorSkip counts and drops failed batches
+	OnErrorSkip = "skip"
+	// OnErrorRetry retries batches that failed with a retryable error and
+	// aborts on other errors or once the retries are exhausted
+	OnErrorRetry = "retry"
+
+	defaultMaxRetries   = 3
+	defaultRetryBackoff = time.Second
+	maxRetryBackoff     = time.Minute
+)
+
+// AddErrorPolicyFlags adds the flags of the batch error policy to the flag set,
+// for loaders that do not use AddToFlagSet.
+func AddErrorPolicyFlags(fs *pflag.FlagSet, flagPrefix string) {
+	fs.String(flagPrefix+"on-error", OnErrorAbort, "What to do when a batch fails: "+OnErrorAbort+" the load, "+OnErrorSkip+" the batch, or "+OnErrorRetry+" it")
+	fs.Uint(flagPrefix+"max-retries", defaultMaxRetries, "Number of times a failed batch is retried with --on-error=retry")
+	fs.Duration(flagPrefix+"retry-backoff", defaultRetryBackoff, "Time to wait before the first retry of a batch, doubled on every further retry")
+}
+
+func validateErrorPolicy(onError string) error {
+	switch onError {
+	case OnErrorAbort, OnErrorSkip, OnErrorRetry:
+		return nil
+	}
+	return fmt.Errorf("invalid --on-error '%s', valid: %s, %s, %s", onError, OnErrorAbort, OnErrorSkip, OnErrorRetry)
+}
+
+// errorCounter counts batch errors by type. It is safe for concurrent use; a
+// nil counter counts nothing.
+type errorCounter struct {
+	mu      sync.Mutex
+	byType  map[string]uint64
+	retries uint64
+	skipped uint64
+}
+
+func newErrorCounter() *errorCounter {
+	return &errorCounter{byType: make(map[string]uint64)}
+}
+
+// add counts an error and reports whether it is the first of its type
+func (c *errorCounter) add(errType string, retried, skipped bool) (first bool) {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	first = c.byType[errType] == 0
+	c.byType[errType]++
+	if retried {
+		c.retries++
+	}
+	if skipped {
+		c.skipped++
+	}
+	return first
+}
+
+// total returns the number of errors
+func (c *errorCounter) total() uint64 {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	total := uint64(0)
+	for _, n := range c.byType {
+		total += n
+	}
+	return total
+}
+
+// skippedBatches returns the number of batches skipped after errors
+func (c *errorCounter) skippedBatches() uint64 {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.skipped
+}
+
+// summary describes the errors in one line, e.g.
+// "3 errors (connection: 1, timeout: 2), 2 retries, 1 batches skipped"
+func (c *errorCounter) summary() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	types := make([]string, 0, len(c.byType))
+	total := uint64(0)
+	for t, n := range c.byType {
+		types = append(types, fmt.Sprintf("%s: %d", t, n))
+		total += n
+	}
+	sort.Strings(types)
+	return fmt.Sprintf("%d errors (%s), %d retries, %d batches skipped", total, strings.Join(types, ", "), c.retries, c.skipped)
+}
+
+// totals returns the error counts for the results file
+func (c *errorCounter) totals() map[string]interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	byType := make(map[string]uint64, len(c.byType))
+	for t, n := range c.byType {
+		byType[t] = n
+	}
+	return map[string]interface{}{
+		"byType":         byType,
+		"retries":        c.retries,
+		"skippedBatches": c.skipped,
+	}
+}
+
+// processBatch inserts a batch with proc, applying the error policy, and
+// reports whether the batch was skipped after an error. The counts of a batch
+// that is retried add up over the attempts, since a failed attempt reports
+// only what it inserted.
+func (l *CommonBenchmarkRunner) processBatch(proc targets.Processor, batch targets.Batch, workerNum uint) (metricCnt, rowCnt uint64, skipped bool) {
+	backoff := l.RetryBackoff
+	for attempt := uint(0); ; attempt++ {
+		m, r, err := proc.ProcessBatch(batch, l.DoLoad)
+		metricCnt += m
+		rowCnt += r
+		if err == nil {
+			return metricCnt, rowCnt, false
+		}
+
+		errType, retryable := targets.ClassifyError(err)
+		retry := l.OnError == OnErrorRetry && retryable && attempt < l.MaxRetries
+		skip := l.OnError == OnErrorSkip
+		first := l.errors.add(errType, retry, skip)
+		switch {
+		case retry:
+			if first {
+				log.Printf("worker %d: retrying batch after %s (further errors are only counted): %v", workerNum, errType, err)
+			}
+			time.Sleep(backoff)
+			backoff *= 2
+			if backoff > maxRetryBackoff {
+				backoff = maxRetryBackoff
+			}
+		case skip:
+			if first {
+				log.Printf("worker %d: skipping batch after %s (further errors are only counted): %v", workerNum, errType, err)
+			}
+			if d, ok := proc.(targets.ProcessorDropper); ok {
+				d.DropBatch(batch)
+			}
+			return metricCnt, rowCnt, true
+		case attempt == 0:
+			fatal("worker %d: batch failed: %v", workerNum, err)
+			return metricCnt, rowCnt, false
+		default:
+			fatal("worker %d: batch failed after %d retries: %v", workerNum, attempt, err)
+			return metricCnt, rowCnt, false
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package load
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/targets"
+)
+
+type batchResult struct {
+	metrics, rows uint64
+	err           error
+}
+
+// failingProcessor returns the given results in order, then succeeds
+type failingProcessor struct {
+	results []batchResult
+	calls   int
+	dropped int
+}
+
+func (p *failingProcessor) Init(int, bool, bool) {}
+
+func (p *failingProcessor) ProcessBatch(targets.Batch, bool) (uint64, uint64, error) {
+	p.calls++
+	if len(p.results) == 0 {
+		return 1, 1, nil
+	}
+	r := p.results[0]
+	p.results = p.results[1:]
+	return r.metrics, r.rows, r.err
+}
+
+func (p *failingProcessor) DropBatch(targets.Batch) {
+	p.dropped++
+}
+
+func TestValidateErrorPolicy(t *testing.T) {
+	for _, s := range []string{OnErrorAbort, OnErrorSkip, OnErrorRetry} {
+		if err := validateErrorPolicy(s); err != nil {
+			t.Errorf("unexpected error for %s: %v", s, err)
+		}
+	}
+	if err := validateErrorPolicy("ignore"); err == nil {
+		t.Errorf("unexpected lack of error for invalid policy")
+	}
+}
+
+func TestProcessBatchErrorPolicy(t *testing.T) {
+	retryable := targets.NewBatchError(targets.ErrorTypeTimeout, true, errors.New("timed out"))
+	rejected := targets.NewBatchError(targets.ErrorTypeRejected, false, errors.New("bad data"))
+	cases := []struct {
+		desc        string
+		onError     string
+		results     []batchResult
+		wantMetrics uint64
+		wantRows    uint64
+		wantCalls   int
+		wantSkipped bool
+		wantDropped int
+		wantFatal   string
+		wantSummary string
+	}{
+		{
+			desc:        "no error",
+			onError:     OnErrorAbort,
+			wantMetrics: 1,
+			wantRows:    1,
+			wantCalls:   1,
+		},
+		{
+			desc:        "abort",
+			onError:     OnErrorAbort,
+			results:     []batchResult{{err: retryable}},
+			wantCalls:   1,
+			wantFatal:   "worker 0: batch failed: timeout error: timed out",
+			wantSummary: "1 errors (timeout: 1), 0 retries, 0 batches skipped",
+		},
+		{
+			desc:        "skip",
+			onError:     OnErrorSkip,
+			results:     []batchResult{{metrics: 2, rows: 1, err: rejected}},
+			wantMetrics: 2,
+			wantRows:    1,
+			wantCalls:   1,
+			wantSkipped: true,
+			wantDropped: 1,
+			wantSummary: "1 errors (rejected: 1), 0 retries, 1 batches skipped",
+		},
+		{
+			desc:        "retry until success, counts add up",
+			onError:     OnErrorRetry,
+			results:     []batchResult{{metrics: 2, rows: 1, err: retryable}, {err: retryable}},
+			wantMetrics: 3,
+			wantRows:    2,
+			wantCalls:   3,
+			wantSummary: "2 errors (timeout: 2), 2 retries, 0 batches skipped",
+		},
+		{
+			desc:        "retry not retryable",
+			onError:     OnErrorRetry,
+			results:     []batchResult{{err: rejected}},
+			wantCalls:   1,
+			wantFatal:   "worker 0: batch failed: rejected error: bad data",
+			wantSummary: "1 errors (rejected: 1), 0 retries, 0 batches skipped",
+		},
+		{
+			desc:        "retries exhausted",
+			onError:     OnErrorRetry,
+			results:     []batchResult{{err: retryable}, {err: retryable}, {err: retryable}},
+			wantCalls:   3,
+			wantFatal:   "worker 0: batch failed after 2 retries: timeout error: timed out",
+			wantSummary: "3 errors (timeout: 3), 2 retries, 0 batches skipped",
+		},
+	}
+
+	oldFatal := fatal
+	defer func() { fatal = oldFatal }()
+	for _, c := range cases {
+		fatalMsg := ""
+		fatal = func(format string, args ...interface{}) { fatalMsg = fmt.Sprintf(format, args...) }
+
+		br := &CommonBenchmarkRunner{errors: newErrorCounter()}
+		br.OnError = c.onError
+		br.MaxRetries = 2
+		br.RetryBackoff = time.Nanosecond
+		p := &failingProcessor{results: c.results}
+		metrics, rows, skipped := br.processBatch(p, &testBatch{}, 0)
+		if metrics != c.wantMetrics || rows != c.wantRows {
+			t.Errorf("%s: incorrect counts: got %d/%d want %d/%d", c.desc, metrics, rows, c.wantMetrics, c.wantRows)
+		}
+		if p.calls != c.wantCalls {
+			t.Errorf("%s: incorrect number of attempts: got %d want %d", c.desc, p.calls, c.wantCalls)
+		}
+		if skipped != c.wantSkipped {
+			t.Errorf("%s: incorrect skipped: got %v want %v", c.desc, skipped, c.wantSkipped)
+		}
+		if p.dropped != c.wantDropped {
+			t.Errorf("%s: incorrect number of dropped batches: got %d want %d", c.desc, p.dropped, c.wantDropped)
+		}
+		if fatalMsg != c.wantFatal {
+			t.Errorf("%s: incorrect fatal: got %q want %q", c.desc, fatalMsg, c.wantFatal)
+		}
+		if c.wantSummary == "" {
+			if got := br.errors.total(); got != 0 {
+				t.Errorf("%s: unexpected errors counted: %d", c.desc, got)
+			}
+		} else if got := br.errors.summary(); got != c.wantSummary {
+			t.Errorf("%s: incorrect summary: got %q want %q", c.desc, got, c.wantSummary)
+		}
+	}
+}
+
+func TestSummaryErrors(t *testing.T) {
+	br := &CommonBenchmarkRunner{errors: newErrorCounter()}
+	br.errors.add(targets.ErrorTypeConnection, true, false)
+	br.errors.add(targets.ErrorTypeServer, false, true)
+
+	var out strings.Builder
+	oldPrintFn := printFn
+	defer func() { printFn = oldPrintFn }()
+	printFn = func(s string, args ...interface{}) (n int, err error) {
+		return fmt.Fprintf(&out, s, args...)
+	}
+	br.summary(time.Second)
+	want := "2 errors (connection: 1, server: 1), 1 retries, 1 batches skipped"
+	if !strings.Contains(out.String(), want) {
+		t.Errorf("summary does not contain %q: %s", want, out.String())
+	}
+	totals := br.errors.totals()
+	if got := totals["skippedBatches"]; got != uint64(1) {
+		t.Errorf("incorrect skipped batches in totals: got %v", got)
+	}
+}
@@ -0,0 +1,82 @@
+package insertstrategy
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RatePacer schedules the batch inserts of all workers so that together they
+// insert at a constant target rate. It works as a token bucket without burst
+// that never forgives lag: every reservation is scheduled right after the
+// previous one, so if the database cannot keep up, the intended send times
+// fall behind the wall clock rather than the schedule being reset.
+// It is safe for concurrent use.
+type RatePacer struct {
+	mu      sync.Mutex
+	perUnit float64 // seconds per unit (metric or row)
+	next    time.Time
+	maxLag  time.Duration
+	lastLag time.Duration
+	nowFn   nowProviderFn
+	sleepFn func(time.Duration)
+}
+
+// NewRatePacer returns a RatePacer for the given target rate in units
+// (metrics or rows) per second.
+func NewRatePacer(rate float64) (*RatePacer, error) {
+	if rate <= 0 {
+		return nil, fmt.Errorf("target rate must be positive, can't be %f", rate)
+	}
+	return &RatePacer{perUnit: 1 / rate, nowFn: time.Now, sleepFn: time.Sleep}, nil
+}
+
+// Wait reserves n units and blocks until their scheduled send time, which it
+// returns. Latencies should be measured from that intended time rather than
+// from when the insert actually started, so that a database falling behind
+// is not hidden by the workers waiting on it.
+func (p *RatePacer) Wait(n float64) time.Time {
+	p.mu.Lock()
+	now := p.nowFn()
+	if p.next.IsZero() {
+		p.next = now
+	}
+	sendAt := p.next
+	p.next = p.next.Add(p.duration(n))
+	lag := time.Duration(0)
+	if now.After(sendAt) {
+		lag = now.Sub(sendAt)
+	}
+	p.lastLag = lag
+	if lag > p.maxLag {
+		p.maxLag = lag
+	}
+	p.mu.Unlock()
+
+	if sendAt.After(now) {
+		p.sleepFn(sendAt.Sub(now))
+	}
+	return sendAt
+}
+
+// Adjust moves the schedule by n units, e.g. to correct a reservation whose
+// actual number of units was only known once the batch was inserted. n may be
+// negative.
+func (p *RatePacer) Adjust(n float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.next.IsZero() {
+		p.next = p.next.Add(p.duration(n))
+	}
+}
+
+// Lag returns how far behind schedule the most recent and the worst send were
+func (p *RatePacer) Lag() (last, max time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.lastLag, p.maxLag
+}
+
+func (p *RatePacer) duration(n float64) time.Duration {
+	return time.Duration(n * p.perUnit * float64(time.Second))
+}
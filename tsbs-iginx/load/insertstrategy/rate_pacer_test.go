@@ -0,0 +1,83 @@
+package insertstrategy
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewRatePacer(t *testing.T) {
+	if _, err := NewRatePacer(0); err == nil {
+		t.Errorf("expected error for zero rate")
+	}
+	if _, err := NewRatePacer(-1); err == nil {
+		t.Errorf("expected error for negative rate")
+	}
+	if _, err := NewRatePacer(100); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func newTestPacer(rate float64) (*RatePacer, *time.Time, *[]time.Duration) {
+	p, _ := NewRatePacer(rate)
+	now := time.Unix(1000, 0)
+	var slept []time.Duration
+	p.nowFn = func() time.Time { return now }
+	p.sleepFn = func(d time.Duration) { slept = append(slept, d) }
+	return p, &now, &slept
+}
+
+func TestRatePacerSchedule(t *testing.T) {
+	p, now, slept := newTestPacer(1000) // 1ms per unit
+	start := *now
+
+	if got := p.Wait(10); !got.Equal(start) {
+		t.Errorf("first batch should be sent immediately: got %v", got)
+	}
+	if len(*slept) != 0 {
+		t.Errorf("first batch should not sleep")
+	}
+	// the second batch is scheduled 10 units (10ms) after the first
+	if got := p.Wait(20); !got.Equal(start.Add(10 * time.Millisecond)) {
+		t.Errorf("incorrect send time: got %v", got.Sub(start))
+	}
+	if len(*slept) != 1 || (*slept)[0] != 10*time.Millisecond {
+		t.Errorf("incorrect sleep: got %v want [10ms]", *slept)
+	}
+
+	// correcting the second reservation from 20 to 25 units delays the next one
+	p.Adjust(5)
+	if got := p.Wait(1); !got.Equal(start.Add(35 * time.Millisecond)) {
+		t.Errorf("incorrect send time after adjust: got %v", got.Sub(start))
+	}
+	if last, max := p.Lag(); last != 0 || max != 0 {
+		t.Errorf("unexpected lag: %v, %v", last, max)
+	}
+}
+
+func TestRatePacerLag(t *testing.T) {
+	p, now, slept := newTestPacer(100) // 10ms per unit
+	start := *now
+	p.Wait(10) // next send due at +100ms
+
+	// the database took 300ms: the next batch is behind schedule and is sent
+	// immediately, keeping its intended send time
+	*now = start.Add(300 * time.Millisecond)
+	if got := p.Wait(10); !got.Equal(start.Add(100 * time.Millisecond)) {
+		t.Errorf("schedule should not be reset: got %v", got.Sub(start))
+	}
+	if len(*slept) != 0 {
+		t.Errorf("late batch should not sleep")
+	}
+	last, max := p.Lag()
+	if last != 200*time.Millisecond || max != 200*time.Millisecond {
+		t.Errorf("incorrect lag: got %v, %v want 200ms, 200ms", last, max)
+	}
+
+	// catching up reduces the current lag but not the maximum
+	*now = start.Add(250 * time.Millisecond)
+	p.Wait(10)
+	last, max = p.Lag()
+	if last != 50*time.Millisecond || max != 200*time.Millisecond {
+		t.Errorf("incorrect lag: got %v, %v want 50ms, 200ms", last, max)
+	}
+}
@@ -12,7 +12,7 @@ type noFlowBenchmarkRunner struct {
 }
 
 func (l *noFlowBenchmarkRunner) RunBenchmark(b targets.Benchmark) {
-	wg, start := l.preRun(b)
+	wg, start, done := l.preRun(b)
 
 	var numChannels uint
 	if l.HashWorkers {
@@ -21,17 +21,21 @@ func (l *noFlowBenchmarkRunner) RunBenchmark(b targets.Benchmark) {
 		numChannels = 1
 	}
 	channels := l.createChannels(numChannels, l.ChannelCapacity)
+	l.sizer = l.newBatchSizer(numChannels)
 
 	// Launch all worker processes in background
 	for i := uint(0); i < l.Workers; i++ {
-		go l.work(b, wg, channels[i%numChannels], i)
+		go l.work(b, wg, channels[workerChannel(i, numChannels)], i)
 	}
 	// Start scan process - actual data read process
-	scanWithoutFlowControl(b.GetDataSource(), b.GetPointIndexer(numChannels), b.GetBatchFactory(), channels, l.BatchSize, l.Limit)
+	src := b.GetDataSource()
+	ds := l.verifier.dataSource(src)
+	scanWithoutFlowControl(ds, b.GetPointIndexer(numChannels), b.GetBatchFactory(), channels, l.sizer, l.Limit, done)
+	closeDataSource(src)
 	for _, c := range channels {
 		close(c)
 	}
-	l.postRun(wg, start)
+	l.postRun(wg, start, done)
 }
 
 // createChannels create channels from which workers would receive tasks
@@ -53,10 +57,15 @@ func (l *noFlowBenchmarkRunner) work(b targets.Benchmark, wg *sync.WaitGroup, c
 
 	// Process batches coming from the incoming queue (c)
 	for batch := range c {
+		sendAt, reserved, items := l.pace(batch)
+		size := batch.Len()
 		startedWorkAt := time.Now()
-		metricCnt, rowCnt := proc.ProcessBatch(batch, l.DoLoad)
+		metricCnt, rowCnt, _ := l.processBatch(proc, batch, workerNum)
+		l.batchLatencies.record(time.Since(sendAt))
+		l.sizer.record(workerNum, size, time.Since(startedWorkAt))
 		atomic.AddUint64(&l.metricCnt, metricCnt)
 		atomic.AddUint64(&l.rowCnt, rowCnt)
+		l.paced(reserved, items, metricCnt)
 		l.timeToSleep(workerNum, startedWorkAt)
 	}
 
@@ -3,6 +3,7 @@ package load
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/HdrHistogram/hdrhistogram-go"
 	"github.com/timescale/tsbs/pkg/targets"
 	"io/ioutil"
 	"log"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/spf13/pflag"
 	"github.com/timescale/tsbs/load/insertstrategy"
+	"github.com/timescale/tsbs/pkg/resources"
 )
 
 const (
@@ -21,6 +23,11 @@ const (
 	DefaultChannelCapacityFlagVal   = 0
 	defaultChannelCapacityPerWorker = 5
 	errDBExistsFmt                  = "database \"%s\" exists: aborting."
+
+	// TargetRateUnitMetrics paces inserts by the number of metrics inserted
+	TargetRateUnitMetrics = "metrics"
+	// TargetRateUnitRows paces inserts by the number of rows (items) read
+	TargetRateUnitRows = "rows"
 )
 
 // change for more useful testing
@@ -35,6 +42,7 @@ type BenchmarkRunnerConfig struct {
 	BatchSize       uint          `yaml:"batch-size" mapstructure:"batch-size" json:"batch-size"`
 	Workers         uint          `yaml:"workers" mapstructure:"workers" json:"workers"`
 	Limit           uint64        `yaml:"limit" mapstructure:"limit" json:"limit"`
+	Duration        time.Duration `yaml:"duration" mapstructure:"duration" json:"duration"`
 	DoLoad          bool          `yaml:"do-load" mapstructure:"do-load" json:"do-load"`
 	DoCreateDB      bool          `yaml:"do-create-db" mapstructure:"do-create-db" json:"do-create-db"`
 	DoAbortOnExist  bool          `yaml:"do-abort-on-exist" mapstructure:"do-abort-on-exist" json:"do-abort-on-exist"`
@@ -44,6 +52,38 @@ type BenchmarkRunnerConfig struct {
 	ChannelCapacity uint          `yaml:"channel-capacity" mapstructure:"channel-capacity" json:"channel-capacity"`
 	InsertIntervals string        `yaml:"insert-intervals" mapstructure:"insert-intervals" json:"insert-intervals"`
 	ResultsFile     string        `yaml:"results-file" mapstructure:"results-file" json:"results-file"`
+	TargetRate      float64       `yaml:"target-rate" mapstructure:"target-rate" json:"target-rate"`
+	TargetRateUnit  string        `yaml:"target-rate-unit" mapstructure:"target-rate-unit" json:"target-rate-unit"`
+	OnError         string        `yaml:"on-error" mapstructure:"on-error" json:"on-error"`
+	MaxRetries      uint          `yaml:"max-retries" mapstructure:"max-retries" json:"max-retries"`
+	RetryBackoff    time.Duration `yaml:"retry-backoff" mapstructure:"retry-backoff" json:"retry-backoff"`
+	// CheckpointFile enables saving the progress of the load, to resume it
+	// with ResumeFromCheckpoint
+	CheckpointFile       string        `yaml:"checkpoint-file" mapstructure:"checkpoint-file" json:"checkpoint-file"`
+	CheckpointInterval   time.Duration `yaml:"checkpoint-interval" mapstructure:"checkpoint-interval" json:"checkpoint-interval"`
+	ResumeFromCheckpoint bool          `yaml:"resume-from-checkpoint" mapstructure:"resume-from-checkpoint" json:"resume-from-checkpoint"`
+	// BatchBytes also cuts batches by the size of their payload, for targets
+	// whose batches implement targets.SizedBatch
+	BatchBytes        uint64 `yaml:"batch-bytes" mapstructure:"batch-bytes" json:"batch-bytes"`
+	AdaptiveBatchSize bool   `yaml:"adaptive-batch-size" mapstructure:"adaptive-batch-size" json:"adaptive-batch-size"`
+	// ResourceSampleInterval enables sampling the resource usage of the
+	// client, and of the server process ServerPID if set
+	ResourceSampleInterval time.Duration `yaml:"resource-sample-interval" mapstructure:"resource-sample-interval" json:"resource-sample-interval"`
+	ServerPID              int32         `yaml:"server-pid" mapstructure:"server-pid" json:"server-pid"`
+	// Verify compares what the database stores with what was sent, for
+	// targets that implement targets.VerifiableBenchmark
+	Verify bool `yaml:"verify" mapstructure:"verify" json:"verify"`
+	// Manifest is checked against the input before the load, see
+	// AddManifestFlags
+	Manifest string `yaml:"manifest" mapstructure:"manifest" json:"manifest"`
+	// StreamUseCase streams simulated data at wall clock time instead of
+	// reading FileName, for loaders that use DataSource
+	StreamUseCase     string        `yaml:"stream-use-case" mapstructure:"stream-use-case" json:"stream-use-case"`
+	StreamSchema      string        `yaml:"stream-schema" mapstructure:"stream-schema" json:"stream-schema"`
+	StreamScale       uint64        `yaml:"stream-scale" mapstructure:"stream-scale" json:"stream-scale"`
+	StreamLogInterval time.Duration `yaml:"stream-log-interval" mapstructure:"stream-log-interval" json:"stream-log-interval"`
+	StreamSpan        time.Duration `yaml:"stream-span" mapstructure:"stream-span" json:"stream-span"`
+	StreamSpeedup     float64       `yaml:"stream-speedup" mapstructure:"stream-speedup" json:"stream-speedup"`
 	// deprecated, should not be used in other places other than tsbs_load_xx commands
 	FileName string `yaml:"file" mapstructure:"file" json:"file"`
 	Seed     int64  `yaml:"seed" mapstructure:"seed" json:"seed"`
@@ -55,15 +95,31 @@ func (c BenchmarkRunnerConfig) AddToFlagSet(fs *pflag.FlagSet) {
 	fs.Uint("batch-size", defaultBatchSize, "Number of items to batch together in a single insert")
 	fs.Uint("workers", 1, "Number of parallel clients inserting")
 	fs.Uint64("limit", 0, "Number of items to insert (0 = all of them).")
+	fs.Duration("duration", 0, "Stop reading items after this long and insert the ones read so far (0 = no time limit)")
 	fs.Bool("do-load", true, "Whether to write data. Set this flag to false to check input read speed.")
 	fs.Bool("do-create-db", true, "Whether to create the database. Disable on all but one client if running on a multi client setup.")
 	fs.Bool("do-abort-on-exist", false, "Whether to abort if a database with the given name already exists.")
 	fs.Duration("reporting-period", 10*time.Second, "Period to report write stats")
-	fs.String("file", "", "File name to read data from")
+	fs.String("file", "", "File name to read data from, or a comma separated list of files and glob patterns for the targets that read several files (gzip and zstd input is decompressed)")
 	fs.Int64("seed", 0, "PRNG seed (default: 0, which uses the current timestamp)")
 	fs.String("insert-intervals", "", "Time to wait between each insert, default '' => all workers insert ASAP. '1,2' = worker 1 waits 1s between inserts, worker 2 and others wait 2s")
 	fs.Bool("hash-workers", false, "Whether to consistently hash insert data to the same workers (i.e., the data for a particular host always goes to the same worker)")
 	fs.String("results-file", "", "Write the test results summary json to this file")
+	AddTargetRateFlags(fs, "")
+	AddErrorPolicyFlags(fs, "")
+	AddCheckpointFlags(fs, "")
+	AddBatchSizeFlags(fs, "")
+	resources.AddFlags(fs, "")
+	AddVerifyFlags(fs, "")
+	AddManifestFlags(fs, "")
+	AddStreamingFlags(fs, "")
+}
+
+// AddTargetRateFlags adds the flags of the constant throughput mode to the flag
+// set, for loaders that do not use AddToFlagSet.
+func AddTargetRateFlags(fs *pflag.FlagSet, flagPrefix string) {
+	fs.Float64(flagPrefix+"target-rate", 0, "Insert at this constant rate (per second, over all workers) and measure batch latency from the intended send time, 0 = as fast as possible")
+	fs.String(flagPrefix+"target-rate-unit", TargetRateUnitMetrics, "Unit of --target-rate: "+TargetRateUnitMetrics+" or "+TargetRateUnitRows)
 }
 
 type BenchmarkRunner interface {
@@ -79,6 +135,16 @@ type CommonBenchmarkRunner struct {
 	rowCnt         uint64
 	initialRand    *rand.Rand
 	sleepRegulator insertstrategy.SleepRegulator
+	batchLatencies *latencyRecorder
+	pacer          *insertstrategy.RatePacer
+	pacedItems     uint64
+	errors         *errorCounter
+	sizer          *batchSizer
+	resources      *resources.Sampler
+	verifier       *verifier
+	verification   map[string]interface{}
+	interrupted    int32
+	finishRun      func()
 }
 
 // GetBenchmarkRunnerWithBatchSize returns the singleton CommonBenchmarkRunner for use in a benchmark program
@@ -92,6 +158,14 @@ func GetBenchmarkRunner(c BenchmarkRunnerConfig) BenchmarkRunner {
 	}
 
 	loader.initialRand = rand.New(rand.NewSource(loader.Seed))
+	loader.batchLatencies = newLatencyRecorder()
+	loader.errors = newErrorCounter()
+	if loader.OnError == "" {
+		loader.OnError = OnErrorAbort
+	}
+	if err := validateErrorPolicy(loader.OnError); err != nil {
+		panic(fmt.Sprintf("could not initialize BenchmarkRunner: %v", err))
+	}
 
 	var err error
 	if c.InsertIntervals == "" {
@@ -102,9 +176,42 @@ func GetBenchmarkRunner(c BenchmarkRunnerConfig) BenchmarkRunner {
 			panic(fmt.Sprintf("could not initialize BenchmarkRunner: %v", err))
 		}
 	}
+	if c.TargetRate > 0 {
+		if c.InsertIntervals != "" {
+			panic("could not initialize BenchmarkRunner: --target-rate and --insert-intervals cannot be used together")
+		}
+		if c.TargetRateUnit == "" {
+			loader.TargetRateUnit = TargetRateUnitMetrics
+		} else if c.TargetRateUnit != TargetRateUnitMetrics && c.TargetRateUnit != TargetRateUnitRows {
+			panic(fmt.Sprintf("could not initialize BenchmarkRunner: invalid target rate unit '%s'", c.TargetRateUnit))
+		}
+		loader.pacer, err = insertstrategy.NewRatePacer(c.TargetRate)
+		if err != nil {
+			panic(fmt.Sprintf("could not initialize BenchmarkRunner: %v", err))
+		}
+	}
+	if c.ResumeFromCheckpoint {
+		if c.CheckpointFile == "" {
+			panic("could not initialize BenchmarkRunner: --resume-from-checkpoint requires --checkpoint-file")
+		}
+		// the database holds the items inserted before the checkpoint
+		loader.DoCreateDB = false
+	}
+	if c.CheckpointFile != "" && c.StreamUseCase != "" {
+		panic("could not initialize BenchmarkRunner: streamed data cannot be checkpointed")
+	}
+	if c.CheckpointFile != "" && c.FileName != "" {
+		// items of several files are interleaved in no particular order
+		if files, err := InputFiles(c.FileName); err == nil && len(files) > 1 {
+			panic("could not initialize BenchmarkRunner: checkpoints require a single input file")
+		}
+	}
 	if !c.NoFlowControl {
 		return &loader
 	}
+	if c.CheckpointFile != "" {
+		panic("could not initialize BenchmarkRunner: checkpoints require flow control")
+	}
 
 	if c.ChannelCapacity == DefaultChannelCapacityFlagVal {
 		if c.HashWorkers {
@@ -122,7 +229,11 @@ func (l *CommonBenchmarkRunner) DatabaseName() string {
 	return l.DBName
 }
 
-func (l *CommonBenchmarkRunner) preRun(b targets.Benchmark) (*sync.WaitGroup, *time.Time) {
+// preRun creates the database and starts the reporting. The returned done
+// channel is closed when reading the input should stop early.
+func (l *CommonBenchmarkRunner) preRun(b targets.Benchmark) (*sync.WaitGroup, *time.Time, <-chan struct{}) {
+	l.checkManifest()
+
 	// Create required DB
 	if b.GetDBCreator() != nil {
 		cleanupFn := l.useDBCreator(b.GetDBCreator())
@@ -132,31 +243,76 @@ func (l *CommonBenchmarkRunner) preRun(b targets.Benchmark) (*sync.WaitGroup, *t
 	if l.ReportingPeriod.Nanoseconds() > 0 {
 		go l.report(l.ReportingPeriod)
 	}
+	l.verifier = l.newVerifier(b)
 	wg := &sync.WaitGroup{}
 	wg.Add(int(l.Workers))
+	var err error
+	if l.resources, err = resources.NewSampler(l.ResourceSampleInterval, l.ServerPID); err != nil {
+		log.Printf("warning: %v", err)
+	}
+	l.resources.Start()
+	ctx, finish := l.runContext()
+	l.finishRun = finish
 	start := time.Now()
-	return wg, &start
+	return wg, &start, ctx.Done()
 }
 
-func (l *CommonBenchmarkRunner) postRun(wg *sync.WaitGroup, start *time.Time) {
+func (l *CommonBenchmarkRunner) postRun(wg *sync.WaitGroup, start *time.Time, done <-chan struct{}) {
 	// Wait for all workers to finish
 	wg.Wait()
 	end := time.Now()
+	l.resources.Stop()
 	took := end.Sub(*start)
+	interrupted := atomic.LoadInt32(&l.interrupted) == 1
+	if interrupted {
+		printFn("load interrupted: statistics below are partial\n")
+	} else if l.Duration > 0 && isDone(done) {
+		printFn("load stopped after --duration %v\n", l.Duration)
+	}
+	l.finishRun()
 	l.summary(took)
+	l.runVerification(interrupted)
 	if l.BenchmarkRunnerConfig.ResultsFile != "" {
 		metricRate := float64(l.metricCnt) / took.Seconds()
 		rowRate := float64(l.rowCnt) / took.Seconds()
-		l.saveTestResult(took, *start, end, metricRate, rowRate)
+		l.saveTestResult(took, *start, end, metricRate, rowRate, interrupted)
 	}
 }
 
-func (l *CommonBenchmarkRunner) saveTestResult(took time.Duration, start time.Time, end time.Time, metricRate, rowRate float64) {
+func (l *CommonBenchmarkRunner) saveTestResult(took time.Duration, start time.Time, end time.Time, metricRate, rowRate float64, interrupted bool) {
 	totals := make(map[string]interface{})
 	totals["metricRate"] = metricRate
 	if l.rowCnt > 0 {
 		totals["rowRate"] = rowRate
 	}
+	latencies := l.batchLatencies.overall()
+	totals["batches"] = latencies.TotalCount()
+	totals["batchLatencyQuantiles"] = latencyQuantiles(latencies)
+	encoded, err := latencies.Encode(hdrhistogram.V2CompressedEncodingCookieBase)
+	if err != nil {
+		log.Fatal(err)
+	}
+	totals["batchLatencyHistogram"] = string(encoded)
+	if l.errors.total() > 0 {
+		totals["errors"] = l.errors.totals()
+	}
+	if l.sizer != nil && l.sizer.adaptive != nil {
+		totals["batchSizes"] = l.sizer.finalSizes()
+	}
+	if l.resources != nil {
+		totals["resources"] = l.resources.Totals()
+	}
+	if l.verification != nil {
+		totals["verification"] = l.verification
+	}
+	if l.pacer != nil {
+		lastLag, maxLag := l.pacer.Lag()
+		totals["targetRate"] = l.TargetRate
+		totals["targetRateUnit"] = l.TargetRateUnit
+		totals["targetRateAchieved"] = l.achievedRate(took) / l.TargetRate
+		totals["lagMillis"] = lastLag.Milliseconds()
+		totals["maxLagMillis"] = maxLag.Milliseconds()
+	}
 
 	testResult := LoaderTestResult{
 		ResultFormatVersion: LoaderTestResultVersion,
@@ -164,6 +320,7 @@ func (l *CommonBenchmarkRunner) saveTestResult(took time.Duration, start time.Ti
 		StartTime:           start.Unix(),
 		EndTime:             end.Unix(),
 		DurationMillis:      took.Milliseconds(),
+		Interrupted:         interrupted,
 		Totals:              totals,
 	}
 
@@ -181,7 +338,7 @@ func (l *CommonBenchmarkRunner) saveTestResult(took time.Duration, start time.Ti
 
 // RunBenchmark takes in a Benchmark b and uses it to run the load benchmark
 func (l *CommonBenchmarkRunner) RunBenchmark(b targets.Benchmark) {
-	wg, start := l.preRun(b)
+	wg, start, done := l.preRun(b)
 	var numChannels, capacity uint
 	if l.HashWorkers {
 		numChannels = l.Workers
@@ -192,14 +349,20 @@ func (l *CommonBenchmarkRunner) RunBenchmark(b targets.Benchmark) {
 	}
 
 	channels := l.createChannels(numChannels, capacity)
+	cp := l.newCheckpointer(numChannels)
+	l.sizer = l.newBatchSizer(numChannels)
 
 	// Launch all worker processes in background
 	for i := uint(0); i < l.Workers; i++ {
-		go l.work(b, wg, channels[i%numChannels], i)
+		go l.work(b, wg, channels[workerChannel(i, numChannels)], i)
 	}
 
 	// Start scan process - actual data read process
-	scanWithFlowControl(channels, l.BatchSize, l.Limit, b.GetDataSource(), b.GetBatchFactory(), b.GetPointIndexer(uint(len(channels))))
+	src := b.GetDataSource()
+	ds := l.verifier.dataSource(src)
+	scanWithFlowControl(channels, l.sizer, l.Limit, done, cp, ds, b.GetBatchFactory(), b.GetPointIndexer(uint(len(channels))))
+	// the scan may stop before the data source was read to the end
+	closeDataSource(src)
 	// After scan process completed (no more data to come) - begin shutdown process
 
 	// Close all communication channels to/from workers
@@ -207,7 +370,15 @@ func (l *CommonBenchmarkRunner) RunBenchmark(b targets.Benchmark) {
 		c.close()
 	}
 
-	l.postRun(wg, start)
+	l.postRun(wg, start, done)
+}
+
+// closeDataSource releases the resources of a data source once the scan
+// stopped, whether or not all items were read
+func closeDataSource(ds targets.DataSource) {
+	if c, ok := ds.(targets.DataSourceCloser); ok {
+		c.Close()
+	}
 }
 
 // useDBCreator handles a DBCreator by running it according to flags set by the
@@ -280,12 +451,18 @@ func (l *CommonBenchmarkRunner) work(b targets.Benchmark, wg *sync.WaitGroup, c
 
 	// Process batches coming from duplexChannel.toWorker queue
 	// and send ACKs into duplexChannel.toScanner queue
-	for batch := range c.toWorker {
+	for sent := range c.toWorker {
+		batch := sent.batch
+		sendAt, reserved, items := l.pace(batch)
+		size := batch.Len()
 		startedWorkAt := time.Now()
-		metricCnt, rowCnt := proc.ProcessBatch(batch, l.DoLoad)
+		metricCnt, rowCnt, skipped := l.processBatch(proc, batch, workerNum)
+		l.batchLatencies.record(time.Since(sendAt))
+		l.sizer.record(workerNum, size, time.Since(startedWorkAt))
 		atomic.AddUint64(&l.metricCnt, metricCnt)
 		atomic.AddUint64(&l.rowCnt, rowCnt)
-		c.sendToScanner()
+		l.paced(reserved, items, metricCnt)
+		c.sendToScanner(sent.seq, skipped)
 		l.timeToSleep(workerNum, startedWorkAt)
 	}
 
@@ -298,6 +475,45 @@ func (l *CommonBenchmarkRunner) work(b targets.Benchmark, wg *sync.WaitGroup, c
 	wg.Done()
 }
 
+// pace blocks until batch may be sent at the target rate. It returns the time
+// the batch was meant to be sent at, from which its latency is measured, and
+// the units reserved for it. Without a target rate it returns immediately.
+func (l *CommonBenchmarkRunner) pace(batch targets.Batch) (sendAt time.Time, reserved float64, items uint) {
+	if l.pacer == nil {
+		return time.Now(), 0, 0
+	}
+	items = batch.Len()
+	reserved = float64(items)
+	if l.TargetRateUnit == TargetRateUnitMetrics {
+		// The number of metrics is only known once the batch is processed,
+		// estimate it from the batches processed so far
+		if pacedItems := atomic.LoadUint64(&l.pacedItems); pacedItems > 0 {
+			reserved *= float64(atomic.LoadUint64(&l.metricCnt)) / float64(pacedItems)
+		}
+	}
+	return l.pacer.Wait(reserved), reserved, items
+}
+
+// paced corrects the schedule once the actual number of metrics of a paced
+// batch is known
+func (l *CommonBenchmarkRunner) paced(reserved float64, items uint, metricCnt uint64) {
+	if l.pacer == nil {
+		return
+	}
+	atomic.AddUint64(&l.pacedItems, uint64(items))
+	if l.TargetRateUnit == TargetRateUnitMetrics {
+		l.pacer.Adjust(float64(metricCnt) - reserved)
+	}
+}
+
+// achievedRate is the insert rate in the unit of the target rate
+func (l *CommonBenchmarkRunner) achievedRate(took time.Duration) float64 {
+	if l.TargetRateUnit == TargetRateUnitRows {
+		return float64(atomic.LoadUint64(&l.pacedItems)) / took.Seconds()
+	}
+	return float64(atomic.LoadUint64(&l.metricCnt)) / took.Seconds()
+}
+
 func (l *CommonBenchmarkRunner) timeToSleep(workerNum uint, startedWorkAt time.Time) {
 	if l.sleepRegulator != nil {
 		l.sleepRegulator.Sleep(int(workerNum), startedWorkAt)
@@ -313,6 +529,25 @@ func (l *CommonBenchmarkRunner) summary(took time.Duration) {
 		rowRate := float64(l.rowCnt) / float64(took.Seconds())
 		printFn("loaded %d rows in %0.3fsec with %d workers (mean rate %0.2f rows/sec)\n", l.rowCnt, took.Seconds(), l.Workers, rowRate)
 	}
+	if latencies := l.batchLatencies.overall(); latencies.TotalCount() > 0 {
+		printFn("batch latency over %d batches: %s\n", latencies.TotalCount(), latencySummary(latencies))
+	}
+	if l.errors.total() > 0 {
+		printFn("%s\n", l.errors.summary())
+	}
+	if s := l.sizer.summary(); s != "" {
+		printFn("%s\n", s)
+	}
+	if l.resources != nil {
+		printFn("%s\n", l.resources.Summary())
+	}
+	if l.pacer != nil {
+		lastLag, maxLag := l.pacer.Lag()
+		achieved := l.achievedRate(took)
+		printFn("target rate %0.2f %s/sec, achieved %0.2f %s/sec (%0.1f%%), lag behind schedule %v at the end, %v at most\n",
+			l.TargetRate, l.TargetRateUnit, achieved, l.TargetRateUnit, 100*achieved/l.TargetRate,
+			lastLag.Round(time.Millisecond), maxLag.Round(time.Millisecond))
+	}
 }
 
 // report handles periodic reporting of loading stats
@@ -322,10 +557,14 @@ func (l *CommonBenchmarkRunner) report(period time.Duration) {
 	prevColCount := uint64(0)
 	prevRowCount := uint64(0)
 
-	printFn("time,per. metric/s,metric total,overall metric/s,per. row/s,row total,overall row/s\n")
+	printFn("time,per. metric/s,metric total,overall metric/s,per. row/s,row total,overall row/s,per. batch p50 ms,per. batch p99 ms,per. batch max ms\n")
 	for now := range time.NewTicker(period).C {
 		cCount := atomic.LoadUint64(&l.metricCnt)
 		rCount := atomic.LoadUint64(&l.rowCnt)
+		latencies := "-,-,-"
+		if h := l.batchLatencies.takeInterval(); h.TotalCount() > 0 {
+			latencies = fmt.Sprintf("%0.2f,%0.2f,%0.2f", latencyMillis(h, 50), latencyMillis(h, 99), latencyMillis(h, 100))
+		}
 
 		sinceStart := now.Sub(start)
 		took := now.Sub(prevTime)
@@ -334,9 +573,9 @@ func (l *CommonBenchmarkRunner) report(period time.Duration) {
 		if rCount > 0 {
 			rowrate := float64(rCount-prevRowCount) / float64(took.Seconds())
 			overallRowRate := float64(rCount) / float64(sinceStart.Seconds())
-			printFn("%d,%0.2f,%E,%0.2f,%0.2f,%E,%0.2f\n", now.Unix(), colrate, float64(cCount), overallColRate, rowrate, float64(rCount), overallRowRate)
+			printFn("%d,%0.2f,%E,%0.2f,%0.2f,%E,%0.2f,%s\n", now.Unix(), colrate, float64(cCount), overallColRate, rowrate, float64(rCount), overallRowRate, latencies)
 		} else {
-			printFn("%d,%0.2f,%E,%0.2f,-,-,-\n", now.Unix(), colrate, float64(cCount), overallColRate)
+			printFn("%d,%0.2f,%E,%0.2f,-,-,-,%s\n", now.Unix(), colrate, float64(cCount), overallColRate, latencies)
 		}
 
 		prevColCount = cCount
@@ -2,8 +2,14 @@ package load
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
+	"github.com/timescale/tsbs/load/insertstrategy"
+	"github.com/timescale/tsbs/pkg/resources"
 	"github.com/timescale/tsbs/pkg/targets"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -20,8 +26,8 @@ func (p *testProcessor) Init(workerNum int, _, _ bool) {
 	p.worker = workerNum
 }
 
-func (p *testProcessor) ProcessBatch(targets.Batch, bool) (metricCount, rowCount uint64) {
-	return 1, 0
+func (p *testProcessor) ProcessBatch(targets.Batch, bool) (metricCount, rowCount uint64, err error) {
+	return 1, 0, nil
 }
 
 func (p *testProcessor) Close(_ bool) {
@@ -306,7 +312,7 @@ func TestCreateChannelsAndPartitions(t *testing.T) {
 }
 
 func TestWork(t *testing.T) {
-	br := &CommonBenchmarkRunner{}
+	br := &CommonBenchmarkRunner{batchLatencies: newLatencyRecorder()}
 	b := &testBenchmark{}
 	for i := 0; i < 2; i++ {
 		b.processors = append(b.processors, &testProcessor{})
@@ -314,8 +320,8 @@ func TestWork(t *testing.T) {
 	var wg sync.WaitGroup
 	wg.Add(2)
 	c := newDuplexChannel(2)
-	c.sendToWorker(&testBatch{})
-	c.sendToWorker(&testBatch{})
+	c.sendToWorker(&testBatch{}, 0)
+	c.sendToWorker(&testBatch{}, 0)
 	go br.work(b, &wg, c, 0)
 	time.Sleep(100 * time.Millisecond)
 	go br.work(b, &wg, c, 1)
@@ -343,6 +349,10 @@ func TestWork(t *testing.T) {
 	if !b.processors[1].closed {
 		t.Errorf("TestWork: processor 1 not closed")
 	}
+
+	if got := br.batchLatencies.overall().TotalCount(); got != 2 {
+		t.Errorf("TestWork: invalid batch latency count: got %d want %d", got, 2)
+	}
 }
 
 func TestWorkWithSleep(t *testing.T) {
@@ -354,7 +364,7 @@ func TestWorkWithSleep(t *testing.T) {
 	var wg sync.WaitGroup
 	wg.Add(1)
 	c := newDuplexChannel(1)
-	c.sendToWorker(&testBatch{})
+	c.sendToWorker(&testBatch{}, 0)
 	go br.work(b, &wg, c, 0)
 	<-c.toScanner
 	c.close()
@@ -380,11 +390,12 @@ func TestWorkWithSleep(t *testing.T) {
 
 func TestSummary(t *testing.T) {
 	cases := []struct {
-		desc    string
-		metrics uint64
-		rows    uint64
-		took    time.Duration
-		want    string
+		desc      string
+		metrics   uint64
+		rows      uint64
+		took      time.Duration
+		latencies []time.Duration
+		want      string
 	}{
 		{
 			desc:    "10 metrics, 0 rows, 1 second",
@@ -414,10 +425,21 @@ func TestSummary(t *testing.T) {
 			took:    time.Second,
 			want:    "\nSummary:\nloaded 10 metrics in 1.000sec with 0 workers (mean rate 10.00 metrics/sec)\nloaded 1 rows in 1.000sec with 0 workers (mean rate 1.00 rows/sec)\n",
 		},
+		{
+			desc:      "include batch latencies: 10 metrics, 0 rows, 1 second",
+			metrics:   10,
+			rows:      0,
+			took:      time.Second,
+			latencies: []time.Duration{time.Millisecond, 2 * time.Millisecond},
+			want:      "\nSummary:\nloaded 10 metrics in 1.000sec with 0 workers (mean rate 10.00 metrics/sec)\nbatch latency over 2 batches: min 1.00ms, p50 1.00ms, p95 2.00ms, p99 2.00ms, p99.9 2.00ms, max 2.00ms\n",
+		},
 	}
 
 	for _, c := range cases {
-		br := &CommonBenchmarkRunner{}
+		br := &CommonBenchmarkRunner{batchLatencies: newLatencyRecorder()}
+		for _, l := range c.latencies {
+			br.batchLatencies.record(l)
+		}
 		br.metricCnt = c.metrics
 		br.rowCnt = c.rows
 		var b bytes.Buffer
@@ -441,7 +463,7 @@ func TestReport(t *testing.T) {
 		defer m.Unlock()
 		return fmt.Fprintf(&b, s, args...)
 	}
-	br := &CommonBenchmarkRunner{}
+	br := &CommonBenchmarkRunner{batchLatencies: newLatencyRecorder()}
 	duration := 200 * time.Millisecond
 	go br.report(duration)
 
@@ -460,22 +482,167 @@ func TestReport(t *testing.T) {
 		t.Errorf("TestReport: counter check incorrect (2): got %d want %d", got, 3)
 	}
 	m.Lock()
-	end := strings.TrimSpace(string(b.Bytes()))
+	end := lastReportFields(b.String())
 	m.Unlock()
-	if end[len(end)-1:len(end)] != "-" {
-		t.Errorf("TestReport: non-row report does not end in -")
+	if end[4] != "-" {
+		t.Errorf("TestReport: non-row report has a row rate")
+	}
+	if end[len(end)-1] != "-" {
+		t.Errorf("TestReport: report without batches has a batch latency")
 	}
 
-	// update row count so line is different
+	// update row count and latencies so line is different
 	atomic.StoreUint64(&br.rowCnt, 1)
+	br.batchLatencies.record(5 * time.Millisecond)
 	time.Sleep(duration)
 	if got := atomic.LoadInt64(&counter); got != 4 {
 		t.Errorf("TestReport: counter check incorrect (1): got %d want %d", got, 4)
 	}
 	m.Lock()
-	end = strings.TrimSpace(string(b.Bytes()))
+	end = lastReportFields(b.String())
 	m.Unlock()
-	if end[len(end)-1:len(end)] == "-" {
-		t.Errorf("TestReport: row report ends in -")
+	if end[4] == "-" {
+		t.Errorf("TestReport: row report has no row rate")
+	}
+	if got := end[len(end)-1]; got != "5.00" {
+		t.Errorf("TestReport: incorrect max batch latency: got %s want 5.00", got)
+	}
+}
+
+// lastReportFields returns the columns of the last line of the report
+func lastReportFields(report string) []string {
+	lines := strings.Split(strings.TrimSpace(report), "\n")
+	return strings.Split(lines[len(lines)-1], ",")
+}
+
+func TestGetBenchmarkRunnerTargetRate(t *testing.T) {
+	cases := []struct {
+		desc      string
+		conf      BenchmarkRunnerConfig
+		wantPanic bool
+		wantUnit  string
+	}{
+		{
+			desc:     "default unit",
+			conf:     BenchmarkRunnerConfig{Workers: 1, TargetRate: 100},
+			wantUnit: TargetRateUnitMetrics,
+		},
+		{
+			desc:     "rows",
+			conf:     BenchmarkRunnerConfig{Workers: 1, TargetRate: 100, TargetRateUnit: TargetRateUnitRows, NoFlowControl: true},
+			wantUnit: TargetRateUnitRows,
+		},
+		{
+			desc:      "invalid unit",
+			conf:      BenchmarkRunnerConfig{Workers: 1, TargetRate: 100, TargetRateUnit: "bytes"},
+			wantPanic: true,
+		},
+		{
+			desc:      "with insert intervals",
+			conf:      BenchmarkRunnerConfig{Workers: 1, TargetRate: 100, InsertIntervals: "1"},
+			wantPanic: true,
+		},
+	}
+	for _, c := range cases {
+		func() {
+			defer func() {
+				if r := recover(); (r != nil) != c.wantPanic {
+					t.Errorf("%s: unexpected panic state: %v", c.desc, r)
+				}
+			}()
+			var br *CommonBenchmarkRunner
+			switch r := GetBenchmarkRunner(c.conf).(type) {
+			case *CommonBenchmarkRunner:
+				br = r
+			case *noFlowBenchmarkRunner:
+				br = &r.CommonBenchmarkRunner
+			}
+			if br.pacer == nil {
+				t.Errorf("%s: no pacer", c.desc)
+			}
+			if br.TargetRateUnit != c.wantUnit {
+				t.Errorf("%s: incorrect unit: got %s want %s", c.desc, br.TargetRateUnit, c.wantUnit)
+			}
+		}()
+	}
+}
+
+func TestWorkWithTargetRate(t *testing.T) {
+	pacer, err := insertstrategy.NewRatePacer(1e6)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	br := &CommonBenchmarkRunner{
+		batchLatencies: newLatencyRecorder(),
+		pacer:          pacer,
+	}
+	br.TargetRate = 1e6
+	br.TargetRateUnit = TargetRateUnitMetrics
+	b := &testBenchmark{}
+	b.processors = append(b.processors, &testProcessor{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	c := newDuplexChannel(2)
+	c.sendToWorker(&testBatch{len: 10}, 0)
+	c.sendToWorker(&testBatch{len: 10}, 0)
+	go br.work(b, &wg, c, 0)
+	<-c.toScanner
+	<-c.toScanner
+	c.close()
+	wg.Wait()
+
+	if got := br.pacedItems; got != 20 {
+		t.Errorf("incorrect paced items: got %d want 20", got)
+	}
+	if got := br.batchLatencies.overall().TotalCount(); got != 2 {
+		t.Errorf("incorrect batch latency count: got %d want 2", got)
+	}
+
+	var out bytes.Buffer
+	oldPrintFn := printFn
+	defer func() { printFn = oldPrintFn }()
+	printFn = func(s string, args ...interface{}) (n int, err error) {
+		return fmt.Fprintf(&out, s, args...)
+	}
+	br.summary(time.Second)
+	if !strings.Contains(out.String(), "target rate 1000000.00 metrics/sec, achieved 2.00 metrics/sec (0.0%)") {
+		t.Errorf("incorrect summary: %s", out.String())
+	}
+}
+
+func TestSaveTestResultResources(t *testing.T) {
+	dir, err := ioutil.TempDir("", "load-results")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	br := &CommonBenchmarkRunner{batchLatencies: newLatencyRecorder()}
+	br.ResultsFile = filepath.Join(dir, "results.json")
+	if br.resources, err = resources.NewSampler(time.Hour, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	br.resources.Start()
+	br.resources.Stop()
+	oldPrintFn := printFn
+	printFn = func(string, ...interface{}) (int, error) { return 0, nil }
+	defer func() { printFn = oldPrintFn }()
+	now := time.Now()
+	br.saveTestResult(time.Second, now, now.Add(time.Second), 1, 0, false)
+
+	b, err := ioutil.ReadFile(br.ResultsFile)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var result LoaderTestResult
+	if err = json.Unmarshal(b, &result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	totals, ok := result.Totals["resources"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("resources missing from totals: %v", result.Totals)
+	}
+	if got := totals["summary"].(map[string]interface{})["samples"]; got != float64(1) {
+		t.Errorf("incorrect number of samples: got %v want 1", got)
 	}
 }
@@ -11,9 +11,12 @@ type LoaderTestResult struct {
 	RunnerConfig BenchmarkRunnerConfig `json:"RunnerConfig"`
 
 	// Run info
-	StartTime      int64 `json:"StartTime`
+	StartTime      int64 `json:"StartTime"`
 	EndTime        int64 `json:"EndTime"`
 	DurationMillis int64 `json:"DurationMillis"`
+	// Interrupted is set if the load was stopped by a signal before all
+	// items were read
+	Interrupted bool `json:"Interrupted"`
 
 	// Totals
 	Totals map[string]interface{} `json:"Totals"`
@@ -0,0 +1,41 @@
+package load
+
+import (
+	"github.com/spf13/pflag"
+	"github.com/timescale/tsbs/pkg/data/manifest"
+)
+
+// AddManifestFlags adds the flag of the manifest check to the flag set, for
+// loaders that do not use AddToFlagSet.
+func AddManifestFlags(fs *pflag.FlagSet, flagPrefix string) {
+	fs.String(flagPrefix+"manifest", "", "Before the load, check that the input has the size and SHA-256 of this manifest written by tsbs_generate_data")
+}
+
+// checkManifest reads the whole input to check it against the manifest, if
+// one is set, and exits if it does not match.
+func (c *BenchmarkRunnerConfig) checkManifest() {
+	if c.Manifest == "" {
+		return
+	}
+	if c.FileName == "" || c.StreamUseCase != "" {
+		fatal("--manifest requires an input file")
+		return
+	}
+	m, err := manifest.Read(c.Manifest)
+	if err != nil {
+		fatal("%v", err)
+		return
+	}
+	br, close := OpenBufferedReader(c.FileName)
+	if br == nil {
+		return
+	}
+	err = m.Verify(br)
+	close()
+	if err != nil {
+		fatal("input does not match manifest %s: %v", c.Manifest, err)
+		return
+	}
+	printFn("input matches manifest %s: %d points, %d metrics, %v to %v\n",
+		c.Manifest, m.Points, m.Metrics, m.Start, m.End)
+}
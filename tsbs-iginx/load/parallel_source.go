@@ -0,0 +1,149 @@
+package load
+
+import (
+	"bufio"
+	"sync"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+	"github.com/timescale/tsbs/pkg/targets"
+)
+
+const (
+	// parallelChunkSize is the number of items a file scanner hands over at once
+	parallelChunkSize = 1000
+	// parallelChunksPerFile is the number of chunks each file scanner may read
+	// ahead
+	parallelChunksPerFile = 4
+)
+
+// GetDataSource returns the data source that reads the value of a --file
+// flag, see InputFiles, with the data sources returned by newDataSource.
+// Several input files are scanned concurrently, each by its own goroutine
+// and data source, and their items are interleaved: within a file items keep
+// their order. Only formats without headers can be read from several files.
+func GetDataSource(fileName string, newDataSource func(*bufio.Reader) targets.DataSource) targets.DataSource {
+	if fileName == "" {
+		return newDataSource(GetBufferedReader(fileName))
+	}
+	files, err := InputFiles(fileName)
+	if err != nil {
+		fatal("cannot open file for read %s: %v", fileName, err)
+		return nil
+	}
+	if len(files) == 1 {
+		br, close := openInput(files[0])
+		if br == nil {
+			return nil
+		}
+		return &fileDataSource{DataSource: newDataSource(br), close: close}
+	}
+	return newParallelDataSource(files, newDataSource)
+}
+
+// fileDataSource is the data source of a single input file, which Close
+// closes along with its decompressor
+type fileDataSource struct {
+	targets.DataSource
+	close     func()
+	closeOnce sync.Once
+}
+
+// Close implements targets.DataSourceCloser
+func (d *fileDataSource) Close() {
+	d.closeOnce.Do(d.close)
+}
+
+// parallelDataSource reads the items that the scanners of several files
+// hand over in chunks. Closing done stops the scanners, which close their
+// files, when the items are not all read.
+type parallelDataSource struct {
+	chunks  chan []data.LoadedPoint
+	current []data.LoadedPoint
+
+	done      chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+func newParallelDataSource(files []string, newDataSource func(*bufio.Reader) targets.DataSource) *parallelDataSource {
+	d := &parallelDataSource{
+		chunks: make(chan []data.LoadedPoint, len(files)*parallelChunksPerFile),
+		done:   make(chan struct{}),
+	}
+	d.wg.Add(len(files))
+	for _, file := range files {
+		go func(file string) {
+			defer d.wg.Done()
+			br, close := openInput(file)
+			defer close()
+			d.scan(newDataSource(br))
+		}(file)
+	}
+	go func() {
+		d.wg.Wait()
+		close(d.chunks)
+	}()
+	return d
+}
+
+// scan reads all items of a data source, until the data source is closed.
+// Byte slices are copied, since data sources may reuse their buffer for the
+// next item.
+func (d *parallelDataSource) scan(ds targets.DataSource) {
+	chunk := make([]data.LoadedPoint, 0, parallelChunkSize)
+	for {
+		item := ds.NextItem()
+		if item.Data == nil {
+			break
+		}
+		if b, ok := item.Data.([]byte); ok {
+			item.Data = append([]byte(nil), b...)
+		}
+		chunk = append(chunk, item)
+		if len(chunk) == parallelChunkSize {
+			if !d.send(chunk) {
+				return
+			}
+			chunk = make([]data.LoadedPoint, 0, parallelChunkSize)
+		}
+	}
+	if len(chunk) > 0 {
+		d.send(chunk)
+	}
+}
+
+// send hands a chunk over, and reports false if the data source was closed
+// instead
+func (d *parallelDataSource) send(chunk []data.LoadedPoint) bool {
+	select {
+	case d.chunks <- chunk:
+		return true
+	case <-d.done:
+		return false
+	}
+}
+
+// NextItem returns the next item of any file, or an empty item once all
+// files were read
+func (d *parallelDataSource) NextItem() data.LoadedPoint {
+	for len(d.current) == 0 {
+		chunk, ok := <-d.chunks
+		if !ok {
+			return data.LoadedPoint{}
+		}
+		d.current = chunk
+	}
+	item := d.current[0]
+	d.current = d.current[1:]
+	return item
+}
+
+func (d *parallelDataSource) Headers() *common.GeneratedDataHeaders { return nil }
+
+// Close stops the scanners of the files, for scans that stop before all items
+// were read, and returns once they closed their files
+func (d *parallelDataSource) Close() {
+	d.closeOnce.Do(func() { close(d.done) })
+	d.wg.Wait()
+}
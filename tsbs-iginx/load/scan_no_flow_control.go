@@ -4,17 +4,17 @@ import (
 	"github.com/timescale/tsbs/pkg/targets"
 )
 
-// scanWithoutFlowControl reads data from the DataSource ds until a limit is reached (if -1, all items are read).
-// Data is then placed into appropriate batches, using the supplied PointIndexer,
-// which are then dispatched to workers (channel idx chosen by PointIndexer).
+// scanWithoutFlowControl reads data from the DataSource ds until a limit is reached (if -1, all items are read)
+// or done is closed. Data is then placed into appropriate batches, using the supplied PointIndexer,
+// which are then dispatched to workers (channel idx chosen by PointIndexer) once the sizer considers them full.
 // readDs does no flow control, if the capacity of a channel is reached, scanning stops for all
 // workers. (should only happen if channel-capacity is low and one worker is unreasonable slower than the rest)
 // in that case just set hash-workers to false and use 1 channel for all workers.
 func scanWithoutFlowControl(
 	ds targets.DataSource, indexer targets.PointIndexer, factory targets.BatchFactory, channels []chan targets.Batch,
-	batchSize uint, limit uint64,
+	sizer *batchSizer, limit uint64, done <-chan struct{},
 ) uint64 {
-	if batchSize == 0 {
+	if sizer.items == 0 {
 		panic("batch size can't be 0")
 	}
 	numChannels := len(channels)
@@ -27,6 +27,9 @@ func scanWithoutFlowControl(
 		if limit > 0 && itemsRead >= limit {
 			break
 		}
+		if isDone(done) {
+			break
+		}
 		item := ds.NextItem()
 		if item.Data == nil {
 			// Nothing to scan any more - input is empty or failed
@@ -36,9 +39,13 @@ func scanWithoutFlowControl(
 		itemsRead++
 
 		idx := indexer.GetIndex(item)
+		if sizer.overflows(batches[idx], item) {
+			channels[idx] <- batches[idx]
+			batches[idx] = factory.New()
+		}
 		batches[idx].Append(item)
 
-		if batches[idx].Len() >= batchSize {
+		if sizer.full(idx, batches[idx]) {
 			channels[idx] <- batches[idx]
 			batches[idx] = factory.New()
 		}
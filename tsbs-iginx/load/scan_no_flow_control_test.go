@@ -19,6 +19,7 @@ func TestScanWithoutFlowControl(t *testing.T) {
 		batchSize        uint
 		numChannels      uint
 		limit            uint64
+		stopped          bool
 		wantCalls        uint64
 		wantChannelCalls []int
 		shouldPanic      bool
@@ -63,6 +64,13 @@ func TestScanWithoutFlowControl(t *testing.T) {
 			numChannels:      2,
 			wantChannelCalls: []int{1, 1},
 			wantCalls:        uint64(len(testData)),
+		}, {
+			desc:             "scan w/ done closed",
+			batchSize:        1,
+			numChannels:      1,
+			stopped:          true,
+			wantChannelCalls: []int{0},
+			wantCalls:        0,
 		}, {
 			desc:        "batchSize = 0 is panic",
 			batchSize:   0,
@@ -80,6 +88,11 @@ func TestScanWithoutFlowControl(t *testing.T) {
 			}
 			testDataSource := &testDataSource{called: 0, br: br}
 			indexer := &modIndexer{mod: c.numChannels}
+			var done chan struct{}
+			if c.stopped {
+				done = make(chan struct{})
+				close(done)
+			}
 			if c.shouldPanic {
 				func() {
 					defer func() {
@@ -87,7 +100,7 @@ func TestScanWithoutFlowControl(t *testing.T) {
 							t.Errorf("%s: did not panic when should", c.desc)
 						}
 					}()
-					scanWithoutFlowControl(testDataSource, indexer, &testFactory{}, channels, c.batchSize, c.limit)
+					scanWithoutFlowControl(testDataSource, indexer, &testFactory{}, channels, &batchSizer{items: c.batchSize}, c.limit, nil)
 				}()
 				return
 			} else {
@@ -96,7 +109,7 @@ func TestScanWithoutFlowControl(t *testing.T) {
 				for i := uint(0); i < c.numChannels; i++ {
 					go _boringWorkerSingleChannel(channels[i], &channelCalls[i], wg)
 				}
-				read := scanWithoutFlowControl(testDataSource, indexer, &testFactory{}, channels, c.batchSize, c.limit)
+				read := scanWithoutFlowControl(testDataSource, indexer, &testFactory{}, channels, &batchSizer{items: c.batchSize}, c.limit, done)
 				for i := uint(0); i < c.numChannels; i++ {
 					close(channels[i])
 				}
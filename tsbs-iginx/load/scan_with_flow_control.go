@@ -9,11 +9,11 @@ import (
 // ackAndMaybeSend adjust the unsent batches count
 // and sends one batch (if any available) to the worker via ch.
 // Returns the updated state of unsent
-func ackAndMaybeSend(ch *duplexChannel, count *int, unsent []targets.Batch) []targets.Batch {
+func ackAndMaybeSend(ch *duplexChannel, count *int, unsent []sentBatch) []sentBatch {
 	*count--
 	// If there are still batches waiting, send the next
 	if len(unsent) > 0 {
-		ch.sendToWorker(unsent[0])
+		ch.sendToWorker(unsent[0].batch, unsent[0].seq)
 		if len(unsent) > 1 {
 			return unsent[1:]
 		}
@@ -25,31 +25,34 @@ func ackAndMaybeSend(ch *duplexChannel, count *int, unsent []targets.Batch) []ta
 // sendOrQueueBatch attempts to send a Batch of data on a duplexChannel.
 // If it would block or there is other work to be sent first, the Batch is stored on a queue.
 // The count of outstanding work is adjusted upwards
-func sendOrQueueBatch(ch *duplexChannel, count *int, batch targets.Batch, unsent []targets.Batch) []targets.Batch {
+func sendOrQueueBatch(ch *duplexChannel, count *int, batch sentBatch, unsent []sentBatch) []sentBatch {
 	// In case there are no outstanding batches yet and there are empty positions in toWorker queue
 	// we can send/put batch into toWorker queue
 	*count++
 	if len(unsent) == 0 && len(ch.toWorker) < cap(ch.toWorker) {
-		ch.sendToWorker(batch)
+		ch.sendToWorker(batch.batch, batch.seq)
 	} else {
 		return append(unsent, batch)
 	}
 	return unsent
 }
 
-// scanWithFlowControl reads data from the DataSource ds until a limit is reached (if -1, all items are read).
-// Data is then placed into appropriate batches, using the supplied PointIndexer,
+// scanWithFlowControl reads data from the DataSource ds until a limit is reached (if -1, all items are read)
+// or done is closed. Data is then placed into appropriate batches, using the supplied PointIndexer,
 // which are then dispatched to workers (duplexChannel chosen by PointIndexer).
+// A batch is sent once the sizer considers it full.
+// The items a checkpoint was saved for are skipped first, and acknowledged batches are reported
+// to the checkpointer cp, which may be nil.
 // Scan does flow control to make sure workers are not left idle for too long
 // and also that the scanning process does not starve them of CPU.
 func scanWithFlowControl(
-	channels []*duplexChannel, batchSize uint, limit uint64,
+	channels []*duplexChannel, sizer *batchSizer, limit uint64, done <-chan struct{}, cp *checkpointer,
 	ds targets.DataSource, factory targets.BatchFactory, indexer targets.PointIndexer,
 ) uint64 {
 	var itemsRead uint64
 	numChannels := len(channels)
 
-	if batchSize < 1 {
+	if sizer.items < 1 {
 		panic("--batch-size cannot be less than 1")
 	}
 
@@ -66,9 +69,9 @@ func scanWithFlowControl(
 	}
 
 	// Batches that are ready to be set when space on a channel opens
-	unsentBatches := make([][]targets.Batch, numChannels)
+	unsentBatches := make([][]sentBatch, numChannels)
 	for i := range unsentBatches {
-		unsentBatches[i] = []targets.Batch{}
+		unsentBatches[i] = []sentBatch{}
 	}
 
 	// We use Select via reflection to either select an acknowledged channel so
@@ -92,11 +95,24 @@ func scanWithFlowControl(
 	// so we don't go over a limit (olimit), in order to slow down the scanner so it doesn't starve the workers
 	ocnt := 0
 	olimit := numChannels * cap(channels[0].toWorker) * 3
+
+	// Skip the items inserted before the checkpoint
+	for skip := cp.skip(); itemsRead < skip; itemsRead++ {
+		if ds.NextItem().Data == nil {
+			break
+		}
+	}
+
+	complete := true
 	for {
 
 		// Check whether incoming items limit reached.
 		// We do not want to process more items than specified.
-		if limit > 0 && itemsRead == limit {
+		if limit > 0 && itemsRead >= limit {
+			break
+		}
+		if isDone(done) {
+			complete = false
 			break
 		}
 
@@ -107,8 +123,10 @@ func scanWithFlowControl(
 		}
 
 		// Only receive an 'ok' when it's from a channel, default does not return 'ok'
-		chosen, _, ok := reflect.Select(cases[:caseLimit])
+		chosen, ack, ok := reflect.Select(cases[:caseLimit])
 		if ok {
+			a := ack.Interface().(batchAck)
+			cp.ack(a.seq, a.skipped, itemsRead)
 			unsentBatches[chosen] = ackAndMaybeSend(channels[chosen], &ocnt, unsentBatches[chosen])
 		}
 
@@ -119,16 +137,25 @@ func scanWithFlowControl(
 			// Time to exit
 			break
 		}
-		itemsRead++
-
 		// Append new item to batch
 		idx := indexer.GetIndex(item)
+		if sizer.overflows(fillingBatches[idx], item) {
+			// The item would take the batch over batchBytes - send the batch without it
+			batch := sentBatch{batch: fillingBatches[idx], seq: cp.sendBatch(idx)}
+			unsentBatches[idx] = sendOrQueueBatch(channels[idx], &ocnt, batch, unsentBatches[idx])
+			fillingBatches[idx] = factory.New()
+		}
+		if cp != nil && fillingBatches[idx].Len() == 0 {
+			cp.startBatch(idx, itemsRead)
+		}
+		itemsRead++
 		fillingBatches[idx].Append(item)
 
-		if fillingBatches[idx].Len() >= batchSize {
-			// Batch is full (contains at least batchSize items) - ready to be sent to worker,
+		if sizer.full(idx, fillingBatches[idx]) {
+			// Batch is full (contains at least batchSize items or batchBytes bytes) - ready to be sent to worker,
 			// or moved to outstanding, in case no workers available atm.
-			unsentBatches[idx] = sendOrQueueBatch(channels[idx], &ocnt, fillingBatches[idx], unsentBatches[idx])
+			batch := sentBatch{batch: fillingBatches[idx], seq: cp.sendBatch(idx)}
+			unsentBatches[idx] = sendOrQueueBatch(channels[idx], &ocnt, batch, unsentBatches[idx])
 			// Place new empty batch
 			fillingBatches[idx] = factory.New()
 		}
@@ -139,7 +166,8 @@ func scanWithFlowControl(
 	for idx, b := range fillingBatches {
 		// Do not enqueue empty batches (with 0 items)
 		if b.Len() > 0 {
-			unsentBatches[idx] = sendOrQueueBatch(channels[idx], &ocnt, fillingBatches[idx], unsentBatches[idx])
+			batch := sentBatch{batch: b, seq: cp.sendBatch(uint(idx))}
+			unsentBatches[idx] = sendOrQueueBatch(channels[idx], &ocnt, batch, unsentBatches[idx])
 		}
 	}
 
@@ -152,11 +180,15 @@ func scanWithFlowControl(
 		}
 
 		// Try to send batches to workers
-		chosen, _, ok := reflect.Select(cases[:len(cases)-1])
+		chosen, ack, ok := reflect.Select(cases[:len(cases)-1])
 		if ok {
+			a := ack.Interface().(batchAck)
+			cp.ack(a.seq, a.skipped, itemsRead)
 			unsentBatches[chosen] = ackAndMaybeSend(channels[chosen], &ocnt, unsentBatches[chosen])
 		}
 	}
 
+	cp.finish(itemsRead, complete)
+
 	return itemsRead
 }
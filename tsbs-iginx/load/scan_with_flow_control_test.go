@@ -26,7 +26,7 @@ func (b *testBatch) Append(p data.LoadedPoint) {
 func TestAckAndMaybeSend(t *testing.T) {
 	cases := []struct {
 		desc         string
-		unsent       []targets.Batch
+		unsent       []sentBatch
 		count        int
 		afterCount   int
 		afterLen     int
@@ -41,21 +41,21 @@ func TestAckAndMaybeSend(t *testing.T) {
 		},
 		{
 			desc:       "unsent has 0 elements",
-			unsent:     []targets.Batch{},
+			unsent:     []sentBatch{},
 			count:      0,
 			afterCount: -1,
 			afterLen:   0,
 		},
 		{
 			desc:       "unsent has 1 element",
-			unsent:     []targets.Batch{&testBatch{1, 1}},
+			unsent:     []sentBatch{{batch: &testBatch{1, 1}}},
 			count:      1,
 			afterCount: 0,
 			afterLen:   0,
 		},
 		{
 			desc:         "unsent has 2 elements",
-			unsent:       []targets.Batch{&testBatch{1, 1}, &testBatch{2, 1}},
+			unsent:       []sentBatch{{batch: &testBatch{1, 1}}, {batch: &testBatch{2, 1}}},
 			count:        2,
 			afterCount:   1,
 			afterLen:     1,
@@ -72,7 +72,7 @@ func TestAckAndMaybeSend(t *testing.T) {
 			t.Errorf("%s: len incorrect: want %d got %d", c.desc, c.afterLen, len(c.unsent))
 		}
 		if len(c.unsent) > 0 {
-			if got := c.unsent[0].(*testBatch); c.afterFirstID != got.id {
+			if got := c.unsent[0].batch.(*testBatch); c.afterFirstID != got.id {
 				t.Errorf("%s: first element incorrect: want %d got %d", c.desc, c.afterFirstID, got.id)
 			}
 		}
@@ -82,8 +82,8 @@ func TestAckAndMaybeSend(t *testing.T) {
 func TestSendOrQueueBatch(t *testing.T) {
 	cases := []struct {
 		desc           string
-		unsent         []targets.Batch
-		toSend         []targets.Batch
+		unsent         []sentBatch
+		toSend         []sentBatch
 		queueSize      int
 		count          int
 		afterCount     int
@@ -92,8 +92,8 @@ func TestSendOrQueueBatch(t *testing.T) {
 	}{
 		{
 			desc:           "unsent is empty, queue does not fill up",
-			unsent:         []targets.Batch{},
-			toSend:         []targets.Batch{&testBatch{1, 1}},
+			unsent:         []sentBatch{},
+			toSend:         []sentBatch{{batch: &testBatch{1, 1}}},
 			queueSize:      1,
 			count:          0,
 			afterCount:     1,
@@ -102,8 +102,8 @@ func TestSendOrQueueBatch(t *testing.T) {
 		},
 		{
 			desc:           "unsent is empty, queue fills up",
-			unsent:         []targets.Batch{},
-			toSend:         []targets.Batch{&testBatch{1, 1}, &testBatch{2, 1}},
+			unsent:         []sentBatch{},
+			toSend:         []sentBatch{{batch: &testBatch{1, 1}}, {batch: &testBatch{2, 1}}},
 			queueSize:      1,
 			count:          0,
 			afterCount:     2,
@@ -112,8 +112,8 @@ func TestSendOrQueueBatch(t *testing.T) {
 		},
 		{
 			desc:           "unsent is non-empty, queue fills up",
-			unsent:         []targets.Batch{&testBatch{1, 1}},
-			toSend:         []targets.Batch{&testBatch{2, 1}, &testBatch{3, 1}},
+			unsent:         []sentBatch{{batch: &testBatch{1, 1}}},
+			toSend:         []sentBatch{{batch: &testBatch{2, 1}}, {batch: &testBatch{3, 1}}},
 			queueSize:      2,
 			count:          1,
 			afterCount:     3,
@@ -203,8 +203,8 @@ func _checkScan(t *testing.T, desc string, called, read, want uint64) {
 }
 
 func _boringWorker(c *duplexChannel) {
-	for range c.toWorker {
-		c.sendToScanner()
+	for b := range c.toWorker {
+		c.sendToScanner(b.seq, false)
 	}
 }
 
@@ -215,6 +215,7 @@ func TestScanWithIndexer(t *testing.T) {
 		desc        string
 		batchSize   uint
 		limit       uint64
+		stopped     bool
 		wantCalls   uint64
 		shouldPanic bool
 	}{
@@ -243,6 +244,12 @@ func TestScanWithIndexer(t *testing.T) {
 			limit:     4,
 			wantCalls: uint64(len(testData)),
 		},
+		{
+			desc:      "scan w/ done closed",
+			batchSize: 1,
+			stopped:   true,
+			wantCalls: 0,
+		},
 		{
 			desc:        "batchSize = 0 is panic",
 			batchSize:   0,
@@ -251,6 +258,11 @@ func TestScanWithIndexer(t *testing.T) {
 		},
 	}
 	for _, c := range cases {
+		var done chan struct{}
+		if c.stopped {
+			done = make(chan struct{})
+			close(done)
+		}
 		br := bufio.NewReader(bytes.NewReader(testData))
 		channels := []*duplexChannel{newDuplexChannel(1)}
 		testDataSource := &testDataSource{called: 0, br: br}
@@ -262,12 +274,12 @@ func TestScanWithIndexer(t *testing.T) {
 						t.Errorf("%s: did not panic when should", c.desc)
 					}
 				}()
-				scanWithFlowControl(channels, c.batchSize, c.limit, testDataSource, &testFactory{}, indexer)
+				scanWithFlowControl(channels, &batchSizer{items: c.batchSize}, c.limit, nil, nil, testDataSource, &testFactory{}, indexer)
 			}()
 			continue
 		} else {
 			go _boringWorker(channels[0])
-			read := scanWithFlowControl(channels, c.batchSize, c.limit, testDataSource, &testFactory{}, indexer)
+			read := scanWithFlowControl(channels, &batchSizer{items: c.batchSize}, c.limit, done, nil, testDataSource, &testFactory{}, indexer)
 			_checkScan(t, c.desc, testDataSource.called, read, c.wantCalls)
 		}
 	}
@@ -0,0 +1,71 @@
+package load
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// exitFn is called on a second interrupt; change for more useful testing
+var exitFn = os.Exit
+
+// isDone reports whether done is closed. A nil done is never closed.
+func isDone(done <-chan struct{}) bool {
+	select {
+	case <-done:
+		return true
+	default:
+		return false
+	}
+}
+
+// runContext returns the context that stops reading the input: it is done
+// once the --duration budget is spent, on interrupt, or when finish is called.
+// finish must be called once all workers are done.
+func (l *CommonBenchmarkRunner) runContext() (ctx context.Context, finish func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if l.Duration > 0 {
+		ctx, cancel = withTimeout(ctx, cancel, l.Duration)
+	}
+	finished := make(chan struct{})
+	go l.stopOnInterrupt(ctx, cancel, finished)
+	return ctx, func() {
+		cancel()
+		close(finished)
+	}
+}
+
+func withTimeout(parent context.Context, cancelParent context.CancelFunc, d time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(parent, d)
+	return ctx, func() {
+		cancel()
+		cancelParent()
+	}
+}
+
+// stopOnInterrupt calls cancel when the process receives SIGINT or SIGTERM
+// before ctx is done, so that the batches read so far are inserted and the
+// results reported. Another signal before the run is finished exits
+// immediately.
+func (l *CommonBenchmarkRunner) stopOnInterrupt(ctx context.Context, cancel context.CancelFunc, finished <-chan struct{}) {
+	sigs := make(chan os.Signal, 2)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigs)
+	select {
+	case <-sigs:
+		_, _ = fmt.Fprintln(os.Stderr, "interrupt received, inserting the batches already read (interrupt again to exit immediately)")
+		atomic.StoreInt32(&l.interrupted, 1)
+		cancel()
+	case <-ctx.Done():
+	}
+	select {
+	case <-sigs:
+		_, _ = fmt.Fprintln(os.Stderr, "interrupt received while finishing, exiting")
+		exitFn(1)
+	case <-finished:
+	}
+}
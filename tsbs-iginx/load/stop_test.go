@@ -0,0 +1,110 @@
+package load
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRunContextDuration(t *testing.T) {
+	br := &CommonBenchmarkRunner{}
+	br.Duration = 10 * time.Millisecond
+	ctx, finish := br.runContext()
+	defer finish()
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatalf("run context not done after --duration")
+	}
+}
+
+func TestRunContextFinish(t *testing.T) {
+	br := &CommonBenchmarkRunner{}
+	ctx, finish := br.runContext()
+	if isDone(ctx.Done()) {
+		t.Fatalf("run context done before finish without --duration")
+	}
+	finish()
+	if !isDone(ctx.Done()) {
+		t.Errorf("run context not done after finish")
+	}
+}
+
+func TestPostRunStopped(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tsbs-load-stop")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	closed := make(chan struct{})
+	close(closed)
+	cases := []struct {
+		desc            string
+		duration        time.Duration
+		interrupted     bool
+		done            chan struct{}
+		wantOutput      string
+		wantInterrupted bool
+	}{
+		{
+			desc:       "input exhausted",
+			wantOutput: "",
+		},
+		{
+			desc:       "stopped after duration",
+			duration:   time.Minute,
+			done:       closed,
+			wantOutput: "load stopped after --duration 1m0s",
+		},
+		{
+			desc:            "interrupted",
+			interrupted:     true,
+			done:            closed,
+			wantOutput:      "load interrupted: statistics below are partial",
+			wantInterrupted: true,
+		},
+	}
+
+	oldPrintFn := printFn
+	defer func() { printFn = oldPrintFn }()
+	for _, c := range cases {
+		var out strings.Builder
+		printFn = func(s string, args ...interface{}) (n int, err error) {
+			return fmt.Fprintf(&out, s, args...)
+		}
+		br := &CommonBenchmarkRunner{batchLatencies: newLatencyRecorder(), finishRun: func() {}}
+		br.Duration = c.duration
+		br.ResultsFile = filepath.Join(dir, "results.json")
+		if c.interrupted {
+			br.interrupted = 1
+		}
+		start := time.Now()
+		br.postRun(&sync.WaitGroup{}, &start, c.done)
+
+		firstLine := strings.SplitN(out.String(), "\n", 2)[0]
+		if c.wantOutput != "" && firstLine != c.wantOutput {
+			t.Errorf("%s: incorrect output: got %q want %q", c.desc, firstLine, c.wantOutput)
+		}
+		if c.wantOutput == "" && firstLine != "" {
+			t.Errorf("%s: unexpected output: %q", c.desc, firstLine)
+		}
+		b, err := ioutil.ReadFile(br.ResultsFile)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var result LoaderTestResult
+		if err = json.Unmarshal(b, &result); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Interrupted != c.wantInterrupted {
+			t.Errorf("%s: incorrect interrupted: got %v want %v", c.desc, result.Interrupted, c.wantInterrupted)
+		}
+	}
+}
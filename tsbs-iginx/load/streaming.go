@@ -0,0 +1,89 @@
+package load
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/timescale/tsbs/pkg/data/serialize"
+	"github.com/timescale/tsbs/pkg/data/source"
+	"github.com/timescale/tsbs/pkg/data/usecases"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+	"github.com/timescale/tsbs/pkg/targets"
+)
+
+const (
+	// streamMaxMetricCount is the number of metrics of a streamed
+	// devops-generic host
+	streamMaxMetricCount = 100
+)
+
+// streamStart is the simulated time streams start from, it is rewritten
+var streamStart = time.Date(2016, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// AddStreamingFlags adds the flags of streaming simulated data to the flag
+// set, for loaders that do not use AddToFlagSet.
+func AddStreamingFlags(fs *pflag.FlagSet, flagPrefix string) {
+	fs.String(flagPrefix+"stream-use-case", "", fmt.Sprintf("Instead of reading --file, stream data simulated for this use case at wall clock time, with timestamps rewritten to now (choices: %s)", strings.Join(common.UseCaseChoices, ", ")))
+	fs.String(flagPrefix+"stream-schema", "", "YAML file declaring the streamed data of the custom use case")
+	fs.Uint64(flagPrefix+"stream-scale", 1, "Scaling value of the streamed use case (e.g., hosts in 'devops', trucks in 'iot')")
+	fs.Duration(flagPrefix+"stream-log-interval", 10*time.Second, "Duration between the streamed points of a series")
+	fs.Duration(flagPrefix+"stream-span", 365*24*time.Hour, "Simulated time to stream, use --duration to stop earlier")
+	fs.Float64(flagPrefix+"stream-speedup", 1, "How many times faster than real time the streamed simulated time passes")
+}
+
+// DataSource returns the data source of the run for loaders that read the
+// format of a serializer: the data streamed for StreamUseCase serialized with
+// serializer, else the data of FileName, see GetDataSource. In both cases
+// newDataSource reads the items.
+func (c BenchmarkRunnerConfig) DataSource(serializer serialize.PointSerializer, newDataSource func(*bufio.Reader) targets.DataSource) targets.DataSource {
+	if c.StreamUseCase == "" {
+		return GetDataSource(c.FileName, newDataSource)
+	}
+	sim, err := c.newStreamingSimulator()
+	if err != nil {
+		fatal("cannot stream %s data: %v", c.StreamUseCase, err)
+		return nil
+	}
+	return newDataSource(bufio.NewReaderSize(source.NewSimulatorReader(sim, serializer, nil), defaultReadSize))
+}
+
+// newStreamingSimulator returns the simulator of StreamUseCase, paced at
+// wall clock time
+func (c BenchmarkRunnerConfig) newStreamingSimulator() (common.Simulator, error) {
+	streaming := &source.StreamingConfig{Speedup: c.StreamSpeedup}
+	if err := streaming.Validate(); err != nil {
+		return nil, err
+	}
+	if c.StreamScale == 0 {
+		return nil, fmt.Errorf(common.ErrScaleIsZero)
+	}
+	if c.StreamLogInterval <= 0 {
+		return nil, fmt.Errorf("stream log interval must be positive, got %v", c.StreamLogInterval)
+	}
+	if c.StreamSpan < c.StreamLogInterval {
+		return nil, fmt.Errorf("stream span %v is shorter than the log interval %v", c.StreamSpan, c.StreamLogInterval)
+	}
+	dgc := &common.DataGeneratorConfig{
+		BaseConfig: common.BaseConfig{
+			Use:       c.StreamUseCase,
+			Scale:     c.StreamScale,
+			TimeStart: streamStart.Format(time.RFC3339),
+			TimeEnd:   streamStart.Add(c.StreamSpan).Format(time.RFC3339),
+			Seed:      c.Seed,
+		},
+		Schema:                c.StreamSchema,
+		InitialScale:          c.StreamScale,
+		LogInterval:           c.StreamLogInterval,
+		MaxMetricCountPerHost: streamMaxMetricCount,
+		InterleavedNumGroups:  1,
+	}
+	common.Seed(c.Seed)
+	scfg, err := usecases.GetSimulatorConfig(dgc)
+	if err != nil {
+		return nil, err
+	}
+	return source.NewStreamingSimulator(scfg.NewSimulator(common.Rand(), dgc.LogInterval, dgc.Limit), streaming.Speedup), nil
+}
@@ -0,0 +1,92 @@
+package load
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/targets"
+)
+
+// timestampSerializer writes the measurement name and timestamp of a point
+type timestampSerializer struct{}
+
+func (timestampSerializer) Serialize(p *data.Point, w io.Writer) error {
+	_, err := fmt.Fprintf(w, "%s %d\n", p.MeasurementName(), p.Timestamp().UnixNano())
+	return err
+}
+
+func TestConfigDataSourceStreaming(t *testing.T) {
+	c := BenchmarkRunnerConfig{
+		StreamUseCase:     "cpu-only",
+		StreamScale:       2,
+		StreamLogInterval: 10 * time.Second,
+		StreamSpan:        time.Minute,
+		StreamSpeedup:     1e6,
+	}
+	start := time.Now()
+	ds := c.DataSource(timestampSerializer{}, func(br *bufio.Reader) targets.DataSource {
+		return &lineDataSource{scanner: bufio.NewScanner(br)}
+	})
+	items := 0
+	for item := ds.NextItem(); item.Data != nil; item = ds.NextItem() {
+		var measurement string
+		var ns int64
+		if _, err := fmt.Sscanf(string(item.Data.([]byte)), "%s %d", &measurement, &ns); err != nil {
+			t.Fatalf("cannot parse item %q: %v", item.Data, err)
+		}
+		if ts := time.Unix(0, ns); ts.Before(start.Add(-time.Second)) || ts.After(time.Now().Add(time.Second)) {
+			t.Errorf("timestamp not rewritten to now: %v", ts)
+		}
+		items++
+	}
+	// 6 intervals of 2 hosts
+	if items != 12 {
+		t.Errorf("incorrect number of items: got %d want 12", items)
+	}
+}
+
+func TestNewStreamingSimulatorErrors(t *testing.T) {
+	valid := BenchmarkRunnerConfig{
+		StreamUseCase:     "devops",
+		StreamScale:       1,
+		StreamLogInterval: 10 * time.Second,
+		StreamSpan:        time.Hour,
+		StreamSpeedup:     1,
+	}
+	if _, err := valid.newStreamingSimulator(); err != nil {
+		t.Fatalf("unexpected error for valid config: %v", err)
+	}
+	cases := []struct {
+		desc   string
+		modify func(c *BenchmarkRunnerConfig)
+	}{
+		{desc: "unknown use case", modify: func(c *BenchmarkRunnerConfig) { c.StreamUseCase = "weather" }},
+		{desc: "zero scale", modify: func(c *BenchmarkRunnerConfig) { c.StreamScale = 0 }},
+		{desc: "zero speedup", modify: func(c *BenchmarkRunnerConfig) { c.StreamSpeedup = 0 }},
+		{desc: "zero interval", modify: func(c *BenchmarkRunnerConfig) { c.StreamLogInterval = 0 }},
+		{desc: "short span", modify: func(c *BenchmarkRunnerConfig) { c.StreamSpan = time.Second }},
+	}
+	for _, tc := range cases {
+		c := valid
+		tc.modify(&c)
+		if _, err := c.newStreamingSimulator(); err == nil {
+			t.Errorf("%s: unexpected lack of error", tc.desc)
+		}
+	}
+}
+
+func TestConfigDataSourceFile(t *testing.T) {
+	c := BenchmarkRunnerConfig{}
+	called := false
+	c.DataSource(timestampSerializer{}, func(br *bufio.Reader) targets.DataSource {
+		called = true
+		return nil
+	})
+	if !called {
+		t.Errorf("file data source not created without stream use case")
+	}
+}
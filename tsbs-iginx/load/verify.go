@@ -0,0 +1,251 @@
+package load
+
+import (
+	"bytes"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/targets"
+)
+
+const (
+	verifyOK         = "ok"
+	verifyMissing    = "missing"
+	verifyDuplicated = "duplicated"
+	verifyTimestamps = "timestamps differ"
+)
+
+// AddVerifyFlags adds the flags of load verification to the flag set, for
+// loaders that do not use AddToFlagSet.
+func AddVerifyFlags(fs *pflag.FlagSet, flagPrefix string) {
+	fs.Bool(flagPrefix+"verify", false, "After the load, compare the number of points and the first and last timestamps of every measurement stored with the data sent")
+}
+
+// measurementVerification compares what was sent for a measurement with what
+// the database stores
+type measurementVerification struct {
+	Measurement    string    `json:"measurement"`
+	Status         string    `json:"status"`
+	ExpectedPoints uint64    `json:"expectedPoints"`
+	StoredPoints   uint64    `json:"storedPoints"`
+	ExpectedFirst  time.Time `json:"expectedFirst"`
+	StoredFirst    time.Time `json:"storedFirst"`
+	ExpectedLast   time.Time `json:"expectedLast"`
+	StoredLast     time.Time `json:"storedLast"`
+}
+
+// verifier tracks the points of every measurement read from the input, and
+// compares them with the ones stored once the load is done. An item equal to
+// the one before it, such as the duplicates of --duplicate-chance, overwrites
+// the same series and timestamp in the database and is not counted again;
+// other duplicates of a series and timestamp show as missing points. It is
+// used by the scanner only, until the workers are done; a nil verifier
+// tracks nothing.
+type verifier struct {
+	target   targets.Verifier
+	expected map[string]*targets.MeasurementStats
+	fields   map[string]map[string]struct{}
+	invalid  uint64
+	// previous is the last item read, repeated counts the items equal to
+	// the one before them
+	previous []byte
+	repeated uint64
+
+	results []measurementVerification
+}
+
+// newVerifier returns the verifier of the run, or nil without --verify or if
+// the target cannot verify loads
+func (l *CommonBenchmarkRunner) newVerifier(b targets.Benchmark) *verifier {
+	if !l.Verify || !l.DoLoad {
+		return nil
+	}
+	switch vb := b.(type) {
+	case targets.VerifiableBenchmark:
+		return &verifier{
+			target:   vb.GetVerifier(),
+			expected: make(map[string]*targets.MeasurementStats),
+			fields:   make(map[string]map[string]struct{}),
+		}
+	default:
+		log.Printf("warning: --verify is ignored, this target does not support verification")
+		return nil
+	}
+}
+
+// dataSource returns the data source of the benchmark, which the verifier
+// inspects the items of
+func (v *verifier) dataSource(ds targets.DataSource) targets.DataSource {
+	if v == nil {
+		return ds
+	}
+	return &verifyingDataSource{DataSource: ds, v: v}
+}
+
+// verifyingDataSource passes every item read on to the verifier
+type verifyingDataSource struct {
+	targets.DataSource
+	v *verifier
+}
+
+func (d *verifyingDataSource) NextItem() data.LoadedPoint {
+	item := d.DataSource.NextItem()
+	if item.Data != nil {
+		d.v.add(item)
+	}
+	return item
+}
+
+func (v *verifier) add(item data.LoadedPoint) {
+	if b, ok := item.Data.([]byte); ok {
+		if v.previous != nil && bytes.Equal(b, v.previous) {
+			v.repeated++
+			return
+		}
+		v.previous = append(v.previous[:0], b...)
+	}
+	measurement, fields, ts, err := v.target.Inspect(item)
+	if err != nil {
+		if v.invalid == 0 {
+			log.Printf("warning: cannot verify item: %v", err)
+		}
+		v.invalid++
+		return
+	}
+	stats, ok := v.expected[measurement]
+	if !ok {
+		stats = &targets.MeasurementStats{First: ts, Last: ts}
+		v.expected[measurement] = stats
+		v.fields[measurement] = make(map[string]struct{})
+	}
+	stats.Points += uint64(len(fields))
+	if ts.Before(stats.First) {
+		stats.First = ts
+	}
+	if ts.After(stats.Last) {
+		stats.Last = ts
+	}
+	for _, f := range fields {
+		v.fields[measurement][f] = struct{}{}
+	}
+}
+
+// verify queries what the database stores and compares it with what was
+// sent, returning whether all measurements match
+func (v *verifier) verify(dbName string) (bool, error) {
+	fields := make(map[string][]string, len(v.fields))
+	for measurement, set := range v.fields {
+		for f := range set {
+			fields[measurement] = append(fields[measurement], f)
+		}
+		sort.Strings(fields[measurement])
+	}
+	stored, err := v.target.Stored(dbName, fields)
+	if err != nil {
+		return false, err
+	}
+
+	measurements := make([]string, 0, len(v.expected))
+	for measurement := range v.expected {
+		measurements = append(measurements, measurement)
+	}
+	sort.Strings(measurements)
+	ok := true
+	v.results = v.results[:0]
+	for _, measurement := range measurements {
+		r := compareMeasurement(measurement, *v.expected[measurement], stored[measurement])
+		ok = ok && r.Status == verifyOK
+		v.results = append(v.results, r)
+	}
+	return ok, nil
+}
+
+func compareMeasurement(measurement string, expected, stored targets.MeasurementStats) measurementVerification {
+	r := measurementVerification{
+		Measurement:    measurement,
+		Status:         verifyOK,
+		ExpectedPoints: expected.Points,
+		StoredPoints:   stored.Points,
+		ExpectedFirst:  expected.First,
+		StoredFirst:    stored.First,
+		ExpectedLast:   expected.Last,
+		StoredLast:     stored.Last,
+	}
+	switch {
+	case stored.Points < expected.Points:
+		r.Status = verifyMissing
+	case stored.Points > expected.Points:
+		r.Status = verifyDuplicated
+	case !stored.First.Equal(expected.First) || !stored.Last.Equal(expected.Last):
+		r.Status = verifyTimestamps
+	}
+	return r
+}
+
+// report prints the result of every measurement, and a summary line
+func (v *verifier) report(ok bool, skippedBatches uint64) {
+	var failed int
+	var missing, duplicated uint64
+	for _, r := range v.results {
+		printFn("verify %s: %d points sent, %d stored, first %s/%s, last %s/%s: %s\n",
+			r.Measurement, r.ExpectedPoints, r.StoredPoints,
+			r.ExpectedFirst.UTC().Format(time.RFC3339Nano), r.StoredFirst.UTC().Format(time.RFC3339Nano),
+			r.ExpectedLast.UTC().Format(time.RFC3339Nano), r.StoredLast.UTC().Format(time.RFC3339Nano), r.Status)
+		if r.Status == verifyOK {
+			continue
+		}
+		failed++
+		if r.StoredPoints < r.ExpectedPoints {
+			missing += r.ExpectedPoints - r.StoredPoints
+		} else {
+			duplicated += r.StoredPoints - r.ExpectedPoints
+		}
+	}
+	if v.invalid > 0 {
+		printFn("verify: %d items could not be inspected and are not counted\n", v.invalid)
+	}
+	if v.repeated > 0 {
+		printFn("verify: %d items repeat the item before them and are counted once\n", v.repeated)
+	}
+	if ok {
+		printFn("verification passed: %d measurements\n", len(v.results))
+		return
+	}
+	printFn("verification failed: %d of %d measurements differ, %d points missing, %d duplicated\n",
+		failed, len(v.results), missing, duplicated)
+	if skippedBatches > 0 {
+		printFn("verify: %d batches were skipped after errors, their points are missing\n", skippedBatches)
+	}
+}
+
+// totals returns the results to save in the results file
+func (v *verifier) totals(ok bool) map[string]interface{} {
+	return map[string]interface{}{
+		"passed":       ok,
+		"measurements": v.results,
+		"invalidItems": v.invalid,
+	}
+}
+
+// runVerification verifies the load once all workers are done. An
+// interrupted load is not verified, since points read may not have been sent.
+func (l *CommonBenchmarkRunner) runVerification(interrupted bool) {
+	if l.verifier == nil {
+		return
+	}
+	if interrupted {
+		printFn("verification skipped: the load was interrupted\n")
+		return
+	}
+	ok, err := l.verifier.verify(l.DBName)
+	if err != nil {
+		printFn("verification failed: cannot query the database: %v\n", err)
+		l.verification = map[string]interface{}{"passed": false, "error": err.Error()}
+		return
+	}
+	l.verifier.report(ok, l.errors.skippedBatches())
+	l.verification = l.verifier.totals(ok)
+}
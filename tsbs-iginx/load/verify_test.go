@@ -0,0 +1,188 @@
+package load
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/targets"
+)
+
+// testVerifier inspects lines of "measurement field,field seconds", and
+// returns the given stored stats
+type testVerifier struct {
+	stored map[string]targets.MeasurementStats
+	err    error
+	fields map[string][]string
+}
+
+func (v *testVerifier) Inspect(item data.LoadedPoint) (string, []string, time.Time, error) {
+	parts := strings.Split(string(item.Data.([]byte)), " ")
+	if len(parts) != 3 {
+		return "", nil, time.Time{}, errors.New("invalid line")
+	}
+	secs, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return "", nil, time.Time{}, err
+	}
+	return parts[0], strings.Split(parts[1], ","), time.Unix(secs, 0), nil
+}
+
+func (v *testVerifier) Stored(_ string, fields map[string][]string) (map[string]targets.MeasurementStats, error) {
+	v.fields = fields
+	return v.stored, v.err
+}
+
+type verifiableBenchmark struct {
+	targets.Benchmark
+	v *testVerifier
+}
+
+func (b *verifiableBenchmark) GetVerifier() targets.Verifier { return b.v }
+
+func TestNewVerifier(t *testing.T) {
+	vb := &verifiableBenchmark{v: &testVerifier{}}
+	cases := []struct {
+		desc   string
+		verify bool
+		doLoad bool
+		b      targets.Benchmark
+		want   bool
+	}{
+		{desc: "verify", verify: true, doLoad: true, b: vb, want: true},
+		{desc: "no verify", verify: false, doLoad: true, b: vb},
+		{desc: "no load", verify: true, doLoad: false, b: vb},
+		{desc: "not verifiable", verify: true, doLoad: true, b: &testBenchmark{}},
+	}
+	for _, c := range cases {
+		l := &CommonBenchmarkRunner{BenchmarkRunnerConfig: BenchmarkRunnerConfig{Verify: c.verify, DoLoad: c.doLoad}}
+		if got := l.newVerifier(c.b) != nil; got != c.want {
+			t.Errorf("%s: incorrect verifier: got %v want %v", c.desc, got, c.want)
+		}
+	}
+}
+
+func TestVerifyDataSource(t *testing.T) {
+	tv := &testVerifier{}
+	v := &verifier{
+		target:   tv,
+		expected: make(map[string]*targets.MeasurementStats),
+		fields:   make(map[string]map[string]struct{}),
+	}
+	lines := "cpu a,b 20\ncpu a 10\nmem c 15\ninvalid\ncpu b,c 30\n"
+	ds := v.dataSource(&lineDataSource{scanner: bufio.NewScanner(strings.NewReader(lines))})
+	items := 0
+	for item := ds.NextItem(); item.Data != nil; item = ds.NextItem() {
+		items++
+	}
+	if items != 5 {
+		t.Errorf("incorrect number of items: got %d want 5", items)
+	}
+	if v.invalid != 1 {
+		t.Errorf("incorrect invalid items: got %d want 1", v.invalid)
+	}
+	want := targets.MeasurementStats{Points: 5, First: time.Unix(10, 0), Last: time.Unix(30, 0)}
+	if got := *v.expected["cpu"]; got != want {
+		t.Errorf("incorrect cpu stats: got %+v want %+v", got, want)
+	}
+
+	tv.stored = map[string]targets.MeasurementStats{
+		"cpu": want,
+		"mem": {Points: 1, First: time.Unix(15, 0), Last: time.Unix(15, 0)},
+	}
+	ok, err := v.verify("db")
+	if err != nil || !ok {
+		t.Fatalf("unexpected verification failure: %v, %v", ok, err)
+	}
+	if got := strings.Join(tv.fields["cpu"], ","); got != "a,b,c" {
+		t.Errorf("incorrect fields queried: got %s want a,b,c", got)
+	}
+
+	tv.err = errors.New("unreachable")
+	if _, err := v.verify("db"); err == nil {
+		t.Errorf("unexpected lack of error")
+	}
+}
+
+func TestVerifyRepeatedItems(t *testing.T) {
+	v := &verifier{
+		target:   &testVerifier{},
+		expected: make(map[string]*targets.MeasurementStats),
+		fields:   make(map[string]map[string]struct{}),
+	}
+	// the second line is a duplicate, the fourth repeats a series and
+	// timestamp, but not in a row
+	lines := "cpu a,b 20\ncpu a,b 20\ncpu a,b 30\ncpu a,b 20\n"
+	ds := v.dataSource(&lineDataSource{scanner: bufio.NewScanner(strings.NewReader(lines))})
+	for ds.NextItem().Data != nil {
+	}
+	if v.repeated != 1 {
+		t.Errorf("incorrect repeated items: got %d want 1", v.repeated)
+	}
+	if got := v.expected["cpu"].Points; got != 6 {
+		t.Errorf("incorrect cpu points: got %d want 6", got)
+	}
+}
+
+func TestCompareMeasurement(t *testing.T) {
+	expected := targets.MeasurementStats{Points: 10, First: time.Unix(10, 0), Last: time.Unix(20, 0)}
+	cases := []struct {
+		desc   string
+		stored targets.MeasurementStats
+		want   string
+	}{
+		{desc: "ok", stored: expected, want: verifyOK},
+		{desc: "missing", stored: targets.MeasurementStats{Points: 8, First: expected.First, Last: expected.Last}, want: verifyMissing},
+		{desc: "not stored", want: verifyMissing},
+		{desc: "duplicated", stored: targets.MeasurementStats{Points: 12, First: expected.First, Last: expected.Last}, want: verifyDuplicated},
+		{desc: "timestamps", stored: targets.MeasurementStats{Points: 10, First: expected.First, Last: time.Unix(19, 0)}, want: verifyTimestamps},
+	}
+	for _, c := range cases {
+		if got := compareMeasurement("cpu", expected, c.stored).Status; got != c.want {
+			t.Errorf("%s: incorrect status: got %s want %s", c.desc, got, c.want)
+		}
+	}
+}
+
+func TestRunVerificationReport(t *testing.T) {
+	tv := &testVerifier{stored: map[string]targets.MeasurementStats{
+		"cpu": {Points: 1, First: time.Unix(10, 0), Last: time.Unix(10, 0)},
+	}}
+	l := &CommonBenchmarkRunner{errors: newErrorCounter()}
+	l.errors.add(targets.ErrorTypeRejected, false, true)
+	l.verifier = &verifier{
+		target:   tv,
+		expected: map[string]*targets.MeasurementStats{"cpu": {Points: 3, First: time.Unix(10, 0), Last: time.Unix(20, 0)}},
+		fields:   map[string]map[string]struct{}{"cpu": {"a": {}}},
+	}
+
+	var out strings.Builder
+	oldPrintFn := printFn
+	defer func() { printFn = oldPrintFn }()
+	printFn = func(s string, args ...interface{}) (n int, err error) {
+		return fmt.Fprintf(&out, s, args...)
+	}
+
+	l.runVerification(true)
+	if l.verification != nil || !strings.Contains(out.String(), "verification skipped") {
+		t.Errorf("interrupted load was verified: %s", out.String())
+	}
+
+	l.runVerification(false)
+	for _, want := range []string{
+		"verification failed: 1 of 1 measurements differ, 2 points missing, 0 duplicated",
+		"1 batches were skipped after errors",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("report does not contain %q: %s", want, out.String())
+		}
+	}
+	if got := l.verification["passed"]; got != false {
+		t.Errorf("incorrect passed in totals: got %v", got)
+	}
+}
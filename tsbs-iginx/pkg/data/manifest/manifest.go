@@ -0,0 +1,163 @@
+// Package manifest describes generated data in a sidecar file, so that the
+// data can be reproduced, and loaders and query generators can check that
+// they use the data they expect.
+package manifest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
+// Suffix is appended to the name of a data file to name its manifest
+const Suffix = ".manifest.json"
+
+// Manifest describes the generated data: the config and seed that reproduce
+// it, what it contains, and the hash of its content.
+type Manifest struct {
+	// Config is the config of the generator, with the seed it used
+	Config common.DataGeneratorConfig `json:"config"`
+	// Start and End are the first and last timestamps of the points
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	// Points and Metrics are the number of points and of non-null field
+	// values, in total and per measurement
+	Points       uint64                  `json:"points"`
+	Metrics      uint64                  `json:"metrics"`
+	Measurements map[string]*Measurement `json:"measurements"`
+	// Bytes and SHA256 are the size and hash of the data, uncompressed
+	Bytes  int64  `json:"bytes"`
+	SHA256 string `json:"sha256"`
+}
+
+// Measurement counts the points and metrics of a measurement
+type Measurement struct {
+	Points  uint64 `json:"points"`
+	Metrics uint64 `json:"metrics"`
+}
+
+// Read reads the manifest of a JSON file
+func Read(fileName string) (*Manifest, error) {
+	b, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read manifest: %v", err)
+	}
+	var m Manifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("cannot parse manifest %s: %v", fileName, err)
+	}
+	return &m, nil
+}
+
+// Write writes the manifest to a JSON file
+func (m *Manifest) Write(fileName string) error {
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fileName, append(b, '\n'), 0644)
+}
+
+// Verify checks that the data read from r has the size and hash of the
+// manifest.
+func (m *Manifest) Verify(r io.Reader) error {
+	h := sha256.New()
+	n, err := io.Copy(h, r)
+	if err != nil {
+		return fmt.Errorf("cannot read data: %v", err)
+	}
+	if n != m.Bytes {
+		return fmt.Errorf("data has %d bytes, the manifest %d", n, m.Bytes)
+	}
+	if sum := hex.EncodeToString(h.Sum(nil)); sum != m.SHA256 {
+		return fmt.Errorf("data has SHA-256 %s, the manifest %s", sum, m.SHA256)
+	}
+	return nil
+}
+
+// VerifyConfig checks that queries generated with the use case, scale and
+// start time of a config query the data of the manifest. The seed of the
+// queries is free, it does not depend on the data.
+func (m *Manifest) VerifyConfig(c *common.BaseConfig) error {
+	d := &m.Config
+	if c.Use != d.Use {
+		return fmt.Errorf("use case %s differs from the data's %s", c.Use, d.Use)
+	}
+	if c.Scale != d.Scale {
+		return fmt.Errorf("scale %d differs from the data's %d", c.Scale, d.Scale)
+	}
+	if c.TimeStart != d.TimeStart {
+		return fmt.Errorf("start time %s differs from the data's %s", c.TimeStart, d.TimeStart)
+	}
+	return nil
+}
+
+// Recorder builds the manifest of the data a generator writes: it counts the
+// points the generator records, and hashes the bytes written to it.
+type Recorder struct {
+	m    Manifest
+	hash hash.Hash
+}
+
+// NewRecorder returns a Recorder for data generated with the config.
+func NewRecorder(config *common.DataGeneratorConfig) *Recorder {
+	return &Recorder{
+		m: Manifest{
+			Config:       *config,
+			Measurements: make(map[string]*Measurement),
+		},
+		hash: sha256.New(),
+	}
+}
+
+// Write hashes the data.
+func (r *Recorder) Write(p []byte) (int, error) {
+	r.m.Bytes += int64(len(p))
+	return r.hash.Write(p)
+}
+
+// Record counts a written point.
+func (r *Recorder) Record(p *data.Point) {
+	name := string(p.MeasurementName())
+	count, ok := r.m.Measurements[name]
+	if !ok {
+		count = &Measurement{}
+		r.m.Measurements[name] = count
+	}
+	metrics := uint64(0)
+	for _, v := range p.FieldValues() {
+		if v != nil {
+			metrics++
+		}
+	}
+	count.Points++
+	count.Metrics += metrics
+	r.m.Points++
+	r.m.Metrics += metrics
+
+	if p.Timestamp() == nil {
+		return
+	}
+	ts := *p.Timestamp()
+	if r.m.Start.IsZero() || ts.Before(r.m.Start) {
+		r.m.Start = ts
+	}
+	if ts.After(r.m.End) {
+		r.m.End = ts
+	}
+}
+
+// Manifest returns the manifest of the data written so far.
+func (r *Recorder) Manifest() *Manifest {
+	m := r.m
+	m.SHA256 = hex.EncodeToString(r.hash.Sum(nil))
+	return &m
+}
@@ -0,0 +1,121 @@
+package manifest
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
+func testConfig() *common.DataGeneratorConfig {
+	return &common.DataGeneratorConfig{
+		BaseConfig: common.BaseConfig{
+			Use:       common.UseCaseDevops,
+			Scale:     10,
+			Seed:      123,
+			TimeStart: "2016-01-01T00:00:00Z",
+			TimeEnd:   "2016-01-02T00:00:00Z",
+		},
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	start := time.Date(2016, time.January, 1, 0, 0, 0, 0, time.UTC)
+	r := NewRecorder(testConfig())
+	points := []struct {
+		measurement string
+		offset      time.Duration
+		fields      []interface{}
+	}{
+		{"cpu", time.Minute, []interface{}{1, 2}},
+		{"cpu", 0, []interface{}{1, nil}},
+		{"mem", 2 * time.Minute, []interface{}{1.5}},
+	}
+	for _, tp := range points {
+		p := data.NewPoint()
+		p.SetMeasurementName([]byte(tp.measurement))
+		ts := start.Add(tp.offset)
+		p.SetTimestamp(&ts)
+		for i, v := range tp.fields {
+			p.AppendField([]byte{byte('a' + i)}, v)
+		}
+		r.Record(p)
+	}
+	content := []byte("some data\n")
+	r.Write(content)
+
+	m := r.Manifest()
+	if m.Points != 3 || m.Metrics != 4 {
+		t.Errorf("incorrect counts: got %d points, %d metrics", m.Points, m.Metrics)
+	}
+	if got := m.Measurements["cpu"]; got == nil || got.Points != 2 || got.Metrics != 3 {
+		t.Errorf("incorrect cpu counts: got %v", got)
+	}
+	if !m.Start.Equal(start) || !m.End.Equal(start.Add(2*time.Minute)) {
+		t.Errorf("incorrect time range: got %v to %v", m.Start, m.End)
+	}
+	if err := m.Verify(bytes.NewReader(content)); err != nil {
+		t.Errorf("unexpected error verifying the recorded data: %v", err)
+	}
+	if err := m.Verify(bytes.NewReader([]byte("some date\n"))); err == nil {
+		t.Errorf("unexpected lack of error verifying other data")
+	}
+	if err := m.Verify(bytes.NewReader(content[1:])); err == nil {
+		t.Errorf("unexpected lack of error verifying shorter data")
+	}
+}
+
+func TestWriteRead(t *testing.T) {
+	dir, err := ioutil.TempDir("", "manifest")
+	if err != nil {
+		t.Fatalf("could not create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	r := NewRecorder(testConfig())
+	r.Write([]byte("data"))
+	want := r.Manifest()
+	fileName := filepath.Join(dir, "data"+Suffix)
+	if err := want.Write(fileName); err != nil {
+		t.Fatalf("unexpected error writing: %v", err)
+	}
+	got, err := Read(fileName)
+	if err != nil {
+		t.Fatalf("unexpected error reading: %v", err)
+	}
+	if got.SHA256 != want.SHA256 || got.Bytes != want.Bytes || got.Config.Seed != 123 || got.Config.Use != common.UseCaseDevops {
+		t.Errorf("incorrect manifest read: got %+v want %+v", got, want)
+	}
+	if _, err := Read(filepath.Join(dir, "missing")); err == nil {
+		t.Errorf("unexpected lack of error reading a missing manifest")
+	}
+}
+
+func TestVerifyConfig(t *testing.T) {
+	m := &Manifest{Config: *testConfig()}
+	c := testConfig().BaseConfig
+	c.Seed = 321
+	if err := m.VerifyConfig(&c); err != nil {
+		t.Errorf("unexpected error for matching config: %v", err)
+	}
+	cases := []struct {
+		desc   string
+		change func(c *common.BaseConfig)
+	}{
+		{"use case", func(c *common.BaseConfig) { c.Use = common.UseCaseIoT }},
+		{"scale", func(c *common.BaseConfig) { c.Scale = 100 }},
+		{"start", func(c *common.BaseConfig) { c.TimeStart = "2016-01-02T00:00:00Z" }},
+	}
+	for _, tc := range cases {
+		c := testConfig().BaseConfig
+		tc.change(&c)
+		if err := m.VerifyConfig(&c); err == nil {
+			t.Errorf("%s: unexpected lack of error", tc.desc)
+		}
+	}
+}
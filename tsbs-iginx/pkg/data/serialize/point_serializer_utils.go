@@ -16,12 +16,16 @@ var (
 	TestColFloat    = []byte("usage_guest_nice")
 	TestColInt      = []byte("usage_guest")
 	TestColInt64    = []byte("big_usage_guest")
+	TestColString   = []byte("log_level")
+	TestColBool     = []byte("throttled")
 )
 
 const (
 	TestFloat             = float64(38.24311829)
 	TestInt               = 38
 	TestInt64             = int64(5000000000)
+	TestString            = "WARN"
+	TestBool              = true
 	ErrWriterAlwaysErr    = "bad write: I always error"
 	ErrWriterSometimesErr = "bad write: I sometimes error"
 )
@@ -86,6 +90,11 @@ func TestPointWithNilField() *data.Point {
 		[][]byte{TestColInt64, TestColFloat}, []interface{}{nil, TestFloat})
 }
 
+func TestPointNonNumeric() *data.Point {
+	return generateTestPoint(TestMeasurement, TestTagKeys, TestTagVals, &TestNow,
+		[][]byte{TestColString, TestColBool, TestColFloat}, []interface{}{TestString, TestBool, TestFloat})
+}
+
 type SerializeCase struct {
 	Desc       string
 	InputPoint *data.Point
@@ -3,8 +3,12 @@ package serialize
 import (
 	"fmt"
 	"strconv"
+
+	"github.com/timescale/tsbs/pkg/data"
 )
 
+const errNonNumericFieldFmt = "%s serializer does not support the %T value of field %s"
+
 // Utility function for appending various data types to a byte string
 func FastFormatAppend(v interface{}, buf []byte) []byte {
 	switch v.(type) {
@@ -34,3 +38,30 @@ func FastFormatAppend(v interface{}, buf []byte) []byte {
 		panic(fmt.Sprintf("unknown field type for %#v", v))
 	}
 }
+
+// AppendQuoted appends a string field value to a byte string in double
+// quotes, escaping the quotes and backslashes it contains, as the InfluxDB
+// line protocol requires.
+func AppendQuoted(s string, buf []byte) []byte {
+	buf = append(buf, '"')
+	for i := 0; i < len(s); i++ {
+		if s[i] == '"' || s[i] == '\\' {
+			buf = append(buf, '\\')
+		}
+		buf = append(buf, s[i])
+	}
+	return append(buf, '"')
+}
+
+// CheckNumericFields returns an error if the point has a string or boolean
+// field, for the serializers of formats that only store numbers.
+func CheckNumericFields(format string, p *data.Point) error {
+	fieldValues := p.FieldValues()
+	for i, v := range fieldValues {
+		switch v.(type) {
+		case string, bool:
+			return fmt.Errorf(errNonNumericFieldFmt, format, v, p.FieldKeys()[i])
+		}
+	}
+	return nil
+}
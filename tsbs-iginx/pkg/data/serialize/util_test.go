@@ -90,3 +90,27 @@ func TestFastFormatAppend(t *testing.T) {
 		}
 	}
 }
+
+func TestAppendQuoted(t *testing.T) {
+	got := string(AppendQuoted(`say "hi" \o/`, []byte("v=")))
+	want := `v="say \"hi\" \\o/"`
+	if got != want {
+		t.Errorf("incorrect quoting: got %s want %s", got, want)
+	}
+}
+
+func TestCheckNumericFields(t *testing.T) {
+	if err := CheckNumericFields("test", TestPointMultiField()); err != nil {
+		t.Errorf("unexpected error for numeric fields: %v", err)
+	}
+	if err := CheckNumericFields("test", TestPointWithNilField()); err != nil {
+		t.Errorf("unexpected error for a nil field: %v", err)
+	}
+	err := CheckNumericFields("test", TestPointNonNumeric())
+	if err == nil {
+		t.Fatalf("unexpected lack of error for a string field")
+	}
+	if want := "test serializer does not support the string value of field log_level"; err.Error() != want {
+		t.Errorf("incorrect error: got %s want %s", err, want)
+	}
+}
@@ -17,4 +17,6 @@ type DataSourceConfig struct {
 	Type      string                      `yaml:"type"`
 	File      *FileDataSourceConfig       `yaml:"file,omitempty"`
 	Simulator *common.DataGeneratorConfig `yaml:"simulator,omitempty"`
+	// Streaming paces the simulator at wall clock time, see Paced
+	Streaming *StreamingConfig `yaml:"streaming,omitempty"`
 }
@@ -70,6 +70,124 @@ func TestDataGeneratorConfigValidate(t *testing.T) {
 	}
 	c.LogInterval = time.Second
 
+	// Test custom use case validation
+	c.Use = common.UseCaseCustom
+	err = c.Validate()
+	if err == nil {
+		t.Errorf("unexpected lack of error for custom use case without schema")
+	}
+	c.Schema = "schema.yaml"
+	err = c.Validate()
+	if err != nil {
+		t.Errorf("unexpected error for custom use case with schema: %v", err)
+	}
+	c.Use = common.UseCaseDevops
+
+	// Test source validation
+	c.Source = "bogus"
+	err = c.Validate()
+	if err == nil {
+		t.Errorf("unexpected lack of error for bad source")
+	}
+	c.Source = common.SourceCSV
+	c.Use = ""
+	err = c.Validate()
+	if err == nil {
+		t.Errorf("unexpected lack of error for csv source without file and mapping")
+	}
+	c.SourceFile = "plant.csv"
+	c.Mapping = "mapping.yaml"
+	err = c.Validate()
+	if err != nil {
+		t.Errorf("unexpected error for csv source without use case: %v", err)
+	}
+	c.Source = common.SourceSimulator
+	err = c.Validate()
+	if err == nil {
+		t.Errorf("unexpected lack of error for simulator source without use case")
+	}
+	c.Use = common.UseCaseDevops
+
+	// Test disorder validation
+	c.OutOfOrderChance = 0.1
+	err = c.Validate()
+	if err == nil {
+		t.Errorf("unexpected lack of error for out-of-order chance without max lateness")
+	}
+	c.MaxLateness = time.Minute
+	err = c.Validate()
+	if err != nil {
+		t.Errorf("unexpected error for out-of-order chance with max lateness: %v", err)
+	}
+	c.OutOfOrderChance = 0
+
+	// Test non-numeric fields validation
+	c.NonNumericFields = true
+	err = c.Validate()
+	if err != nil {
+		t.Errorf("unexpected error for non-numeric fields in devops: %v", err)
+	}
+	c.Use = common.UseCaseDevopsGeneric
+	err = c.Validate()
+	if err == nil {
+		t.Errorf("unexpected lack of error for non-numeric fields in devops-generic")
+	}
+	c.Use = common.UseCaseDevops
+	c.NonNumericFields = false
+
+	// Test churn validation
+	c.ChurnRate = 0.1
+	err = c.Validate()
+	if err != nil {
+		t.Errorf("unexpected error for churn in devops: %v", err)
+	}
+	c.Use = common.UseCaseDevopsGeneric
+	err = c.Validate()
+	if err == nil {
+		t.Errorf("unexpected lack of error for churn in devops-generic")
+	}
+	c.Use = common.UseCaseDevops
+	c.ChurnRate = 1.5
+	err = c.Validate()
+	if err == nil {
+		t.Errorf("unexpected lack of error for churn rate above 1")
+	}
+	c.ChurnRate = 0
+
+	// Test irregular timestamps validation
+	c.TimestampJitter = 500 * time.Millisecond
+	err = c.Validate()
+	if err == nil {
+		t.Errorf("unexpected lack of error for timestamp jitter in devops")
+	}
+	c.Use = common.UseCaseIoT
+	err = c.Validate()
+	if err != nil {
+		t.Errorf("unexpected error for timestamp jitter in iot: %v", err)
+	}
+	c.SeriesIntervals = []time.Duration{c.LogInterval + 500*time.Millisecond}
+	err = c.Validate()
+	if err == nil {
+		t.Errorf("unexpected lack of error for series interval not a multiple of the log interval")
+	}
+	c.Use = common.UseCaseDevops
+	c.TimestampJitter = 0
+	c.SeriesIntervals = nil
+
+	// Test truck routes validation
+	c.TruckRoutes = true
+	err = c.Validate()
+	if err == nil {
+		t.Errorf("unexpected lack of error for truck routes in devops")
+	}
+	c.Use = common.UseCaseIoT
+	err = c.Validate()
+	if err != nil {
+		t.Errorf("unexpected error for truck routes in iot: %v", err)
+	}
+	c.Use = common.UseCaseDevops
+	c.TruckRoutes = false
+
 	// Test groups validation
 	c.InterleavedNumGroups = 0
 	err = c.Validate()
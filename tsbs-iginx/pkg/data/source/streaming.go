@@ -0,0 +1,122 @@
+package source
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/serialize"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
+// StreamingConfig paces a simulator at wall clock time, see NewStreamingSimulator
+type StreamingConfig struct {
+	// Speedup is how many times faster than real time the simulated time
+	// passes, 1 streams in real time
+	Speedup float64 `yaml:"speedup" mapstructure:"speedup"`
+}
+
+// Validate checks that the values of the StreamingConfig are reasonable.
+func (c *StreamingConfig) Validate() error {
+	if c.Speedup <= 0 {
+		return fmt.Errorf("streaming speedup must be positive, got %v", c.Speedup)
+	}
+	return nil
+}
+
+// Paced returns the simulator paced at wall clock time when the data source
+// streams, otherwise the simulator itself
+func (c *DataSourceConfig) Paced(sim common.Simulator) common.Simulator {
+	if c.Streaming == nil {
+		return sim
+	}
+	return NewStreamingSimulator(sim, c.Streaming.Speedup)
+}
+
+// streamingSimulator holds back every point of a simulator until its time
+// has come, and rewrites its timestamp to that time
+type streamingSimulator struct {
+	common.Simulator
+	speedup float64
+
+	started   bool
+	simStart  time.Time
+	wallStart time.Time
+
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+// NewStreamingSimulator returns a simulator that returns the points of sim at
+// the pace of the wall clock, speedup times faster, with their timestamps
+// rewritten to the time they are due. The first point is due immediately.
+// A point is never returned before it is due, but a reader that falls behind
+// gets it late: its timestamp keeps the time it was due, so that every series
+// keeps its interval and the lag of the reader shows in the data.
+func NewStreamingSimulator(sim common.Simulator, speedup float64) common.Simulator {
+	if speedup <= 0 {
+		speedup = 1
+	}
+	return &streamingSimulator{
+		Simulator: sim,
+		speedup:   speedup,
+		now:       time.Now,
+		sleep:     time.Sleep,
+	}
+}
+
+func (s *streamingSimulator) Next(p *data.Point) bool {
+	write := s.Simulator.Next(p)
+	ts := p.Timestamp()
+	if !write || ts == nil {
+		return write
+	}
+	if !s.started {
+		s.started = true
+		s.simStart = *ts
+		s.wallStart = s.now()
+	}
+	due := s.wallStart.Add(time.Duration(float64(ts.Sub(s.simStart)) / s.speedup))
+	if wait := due.Sub(s.now()); wait > 0 {
+		s.sleep(wait)
+	}
+	p.SetTimestamp(&due)
+	return write
+}
+
+// simulatorReader reads the points of a simulator in the format of a
+// serializer, as tsbs_generate_data would write them
+type simulatorReader struct {
+	sim        common.Simulator
+	serializer serialize.PointSerializer
+	point      *data.Point
+	buf        bytes.Buffer
+}
+
+// NewSimulatorReader returns a reader of the points of sim, serialized with
+// serializer after header. With a streaming simulator a point can be read as
+// soon as it is due, so loaders that read files can load streamed data.
+func NewSimulatorReader(sim common.Simulator, serializer serialize.PointSerializer, header []byte) io.Reader {
+	r := &simulatorReader{sim: sim, serializer: serializer, point: data.NewPoint()}
+	r.buf.Write(header)
+	return r
+}
+
+// Read returns the points serialized so far, or waits for the next one
+func (r *simulatorReader) Read(p []byte) (int, error) {
+	for r.buf.Len() == 0 {
+		if r.sim.Finished() {
+			return 0, io.EOF
+		}
+		write := r.sim.Next(r.point)
+		if write {
+			if err := r.serializer.Serialize(r.point, &r.buf); err != nil {
+				return 0, fmt.Errorf("can not serialize point: %s", err)
+			}
+		}
+		r.point.Reset()
+	}
+	return r.buf.Read(p)
+}
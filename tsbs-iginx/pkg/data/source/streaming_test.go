@@ -0,0 +1,137 @@
+package source
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
+// testSimulator returns a point every interval, starting at start
+type testSimulator struct {
+	common.Simulator
+	start    time.Time
+	interval time.Duration
+	made     int
+	points   int
+}
+
+func (s *testSimulator) Finished() bool { return s.made >= s.points }
+
+func (s *testSimulator) Next(p *data.Point) bool {
+	ts := s.start.Add(time.Duration(s.made) * s.interval)
+	p.SetMeasurementName([]byte("cpu"))
+	p.SetTimestamp(&ts)
+	p.AppendField([]byte("usage"), float64(s.made))
+	s.made++
+	return true
+}
+
+// testSerializer writes the measurement name and timestamp of a point
+type testSerializer struct{}
+
+func (testSerializer) Serialize(p *data.Point, w io.Writer) error {
+	_, err := fmt.Fprintf(w, "%s %d\n", p.MeasurementName(), p.Timestamp().UnixNano())
+	return err
+}
+
+func TestStreamingSimulator(t *testing.T) {
+	simStart := time.Date(2016, time.January, 1, 0, 0, 0, 0, time.UTC)
+	wallStart := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		desc      string
+		speedup   float64
+		wantDue   []time.Duration
+		wantSleep []time.Duration
+	}{
+		{
+			desc:      "real time",
+			speedup:   1,
+			wantDue:   []time.Duration{0, 10 * time.Second, 20 * time.Second},
+			wantSleep: []time.Duration{10 * time.Second, 10 * time.Second},
+		},
+		{
+			desc:      "speedup",
+			speedup:   10,
+			wantDue:   []time.Duration{0, time.Second, 2 * time.Second},
+			wantSleep: []time.Duration{time.Second, time.Second},
+		},
+	}
+	for _, c := range cases {
+		now := wallStart
+		var slept []time.Duration
+		sim := NewStreamingSimulator(&testSimulator{start: simStart, interval: 10 * time.Second, points: 3}, c.speedup).(*streamingSimulator)
+		sim.now = func() time.Time { return now }
+		sim.sleep = func(d time.Duration) {
+			slept = append(slept, d)
+			now = now.Add(d)
+		}
+		for i, due := range c.wantDue {
+			p := data.NewPoint()
+			if !sim.Next(p) {
+				t.Fatalf("%s: point %d not written", c.desc, i)
+			}
+			if got, want := *p.Timestamp(), wallStart.Add(due); !got.Equal(want) {
+				t.Errorf("%s: incorrect timestamp of point %d: got %v want %v", c.desc, i, got, want)
+			}
+		}
+		if fmt.Sprint(slept) != fmt.Sprint(c.wantSleep) {
+			t.Errorf("%s: incorrect sleeps: got %v want %v", c.desc, slept, c.wantSleep)
+		}
+	}
+}
+
+func TestStreamingSimulatorBehind(t *testing.T) {
+	simStart := time.Date(2016, time.January, 1, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	wallStart := now
+	sim := NewStreamingSimulator(&testSimulator{start: simStart, interval: time.Second, points: 2}, 1).(*streamingSimulator)
+	sim.now = func() time.Time { return now }
+	sim.sleep = func(d time.Duration) { t.Errorf("unexpected sleep of %v", d) }
+
+	sim.Next(data.NewPoint())
+	// the reader falls behind, the point keeps the time it was due
+	now = now.Add(5 * time.Second)
+	p := data.NewPoint()
+	sim.Next(p)
+	if got, want := *p.Timestamp(), wallStart.Add(time.Second); !got.Equal(want) {
+		t.Errorf("incorrect timestamp: got %v want %v", got, want)
+	}
+}
+
+func TestSimulatorReader(t *testing.T) {
+	start := time.Unix(100, 0)
+	r := NewSimulatorReader(&testSimulator{start: start, interval: time.Second, points: 3}, testSerializer{}, []byte("header\n"))
+	got, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var want bytes.Buffer
+	want.WriteString("header\n")
+	for i := 0; i < 3; i++ {
+		fmt.Fprintf(&want, "cpu %d\n", start.Add(time.Duration(i)*time.Second).UnixNano())
+	}
+	if string(got) != want.String() {
+		t.Errorf("incorrect output: got\n%s\nwant\n%s", got, want.String())
+	}
+}
+
+func TestDataSourceConfigPaced(t *testing.T) {
+	sim := &testSimulator{}
+	c := &DataSourceConfig{Type: SimulatorDataSourceType}
+	if c.Paced(sim) != common.Simulator(sim) {
+		t.Errorf("simulator paced without streaming config")
+	}
+	c.Streaming = &StreamingConfig{Speedup: 2}
+	if got, ok := c.Paced(sim).(*streamingSimulator); !ok || got.speedup != 2 {
+		t.Errorf("simulator not paced with streaming config: %v", got)
+	}
+	if err := (&StreamingConfig{}).Validate(); err == nil {
+		t.Errorf("unexpected lack of error for speedup 0")
+	}
+}
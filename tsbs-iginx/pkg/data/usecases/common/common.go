@@ -3,18 +3,18 @@ package common
 import "math/rand"
 
 // RandomStringSliceChoice returns a random string from the provided slice of string slices.
-func RandomStringSliceChoice(s []string) string {
-	return s[rand.Intn(len(s))]
+func RandomStringSliceChoice(r *rand.Rand, s []string) string {
+	return s[r.Intn(len(s))]
 }
 
 // RandomByteStringSliceChoice returns a random byte string slice from the provided slice of byte string slices.
-func RandomByteStringSliceChoice(s [][]byte) []byte {
-	return s[rand.Intn(len(s))]
+func RandomByteStringSliceChoice(r *rand.Rand, s [][]byte) []byte {
+	return s[r.Intn(len(s))]
 }
 
 // RandomInt64SliceChoice returns a random int64 from an int64 slice.
-func RandomInt64SliceChoice(s []int64) int64 {
-	return s[rand.Intn(len(s))]
+func RandomInt64SliceChoice(r *rand.Rand, s []int64) int64 {
+	return s[r.Intn(len(s))]
 }
 
 const (
@@ -24,6 +24,8 @@ const (
 	UseCaseDevops        = "devops"
 	UseCaseIoT           = "iot"
 	UseCaseDevopsGeneric = "devops-generic"
+	UseCaseCustom        = "custom"
+	UseCaseIndustrial    = "industrial"
 )
 
 var UseCaseChoices = []string{
@@ -32,4 +34,44 @@ var UseCaseChoices = []string{
 	UseCaseDevops,
 	UseCaseIoT,
 	UseCaseDevopsGeneric,
+	UseCaseCustom,
+	UseCaseIndustrial,
+}
+
+// nonNumericUseCases are the use cases that simulate string and boolean
+// fields with NonNumericFields
+var nonNumericUseCases = []string{
+	UseCaseCPUOnly,
+	UseCaseCPUSingle,
+	UseCaseDevops,
+	UseCaseIoT,
+}
+
+// churnUseCases are the use cases whose hosts or trucks are replaced with
+// ChurnRate
+var churnUseCases = []string{
+	UseCaseCPUOnly,
+	UseCaseCPUSingle,
+	UseCaseDevops,
+	UseCaseIoT,
+}
+
+// timingUseCases are the use cases whose timestamps are made irregular with
+// a TimingConfig
+var timingUseCases = []string{
+	UseCaseIoT,
+	UseCaseIndustrial,
+}
+
+const (
+	// Data source choices: simulate a use case, or import a dataset
+	SourceSimulator = "simulator"
+	SourceCSV       = "csv"
+	SourceParquet   = "parquet"
+)
+
+var SourceChoices = []string{
+	SourceSimulator,
+	SourceCSV,
+	SourceParquet,
 }
@@ -22,7 +22,7 @@ func TestRandomByteSliceChoice(t *testing.T) {
 	}
 	// One million attempts ought to catch it?
 	for i := 0; i < 1000000; i++ {
-		choice := RandomByteStringSliceChoice(arr)
+		choice := RandomByteStringSliceChoice(Rand(), arr)
 		testIfInByteStringSlice(t, arr, choice)
 	}
 }
@@ -40,7 +40,7 @@ func TestRandomInt64Choice(t *testing.T) {
 	arr := []int64{0, 10000, 9999}
 	// One million attempts ought to catch it?
 	for i := 0; i < 1000000; i++ {
-		choice := RandomInt64SliceChoice(arr)
+		choice := RandomInt64SliceChoice(Rand(), arr)
 		testIfInInt64Slice(t, arr, choice)
 	}
 }
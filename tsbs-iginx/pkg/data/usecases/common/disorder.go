@@ -0,0 +1,163 @@
+package common
+
+import (
+	"container/heap"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+)
+
+// DisorderConfig declares the imperfections of the data of a Simulator: the
+// chances that a point arrives late, goes missing or is duplicated.
+type DisorderConfig struct {
+	// OutOfOrderChance is the chance that a point is delayed by up to
+	// MaxLateness of simulated time, so it arrives after newer points
+	OutOfOrderChance float64
+	MaxLateness      time.Duration
+	// MissingChance is the chance that a point is dropped
+	MissingChance float64
+	// DuplicateChance is the chance that a point is emitted twice in a row
+	DuplicateChance float64
+}
+
+// Enabled tells whether the config changes the data of a Simulator.
+func (c DisorderConfig) Enabled() bool {
+	return c.OutOfOrderChance > 0 || c.MissingChance > 0 || c.DuplicateChance > 0
+}
+
+// Validate checks that the chances are probabilities, and that delayed
+// points have a lateness.
+func (c DisorderConfig) Validate() error {
+	chances := []struct {
+		name  string
+		value float64
+	}{
+		{"out-of-order", c.OutOfOrderChance},
+		{"missing", c.MissingChance},
+		{"duplicate", c.DuplicateChance},
+	}
+	for _, ch := range chances {
+		if ch.value < 0 || ch.value > 1 {
+			return fmt.Errorf("%s chance must be between 0 and 1, got %v", ch.name, ch.value)
+		}
+	}
+	if c.OutOfOrderChance > 0 && c.MaxLateness <= 0 {
+		return fmt.Errorf("out-of-order points require a positive max lateness, got %v", c.MaxLateness)
+	}
+	return nil
+}
+
+// DisorderSimulatorConfig creates the Simulators of a SimulatorConfig with
+// the imperfections of a DisorderConfig.
+type DisorderSimulatorConfig struct {
+	SimulatorConfig
+	Disorder DisorderConfig
+}
+
+// NewSimulator produces a Simulator of the wrapped config, with disorder.
+func (c *DisorderSimulatorConfig) NewSimulator(r *rand.Rand, interval time.Duration, limit uint64) Simulator {
+	return NewDisorderSimulator(r, c.SimulatorConfig.NewSimulator(r, interval, limit), c.Disorder)
+}
+
+// NewDisorderSimulator wraps a Simulator to drop, delay and duplicate its
+// points at the chances of the config, drawn from r.
+func NewDisorderSimulator(r *rand.Rand, sim Simulator, config DisorderConfig) Simulator {
+	return &disorderSimulator{Simulator: sim, rng: r, config: config}
+}
+
+// disorderSimulator holds the points of the wrapped Simulator until they are
+// due: on time points at once, delayed points once the wrapped Simulator
+// reaches their timestamp plus the delay.
+type disorderSimulator struct {
+	Simulator
+	rng       *rand.Rand
+	config    DisorderConfig
+	pending   pendingPoints
+	seq       uint64
+	now       time.Time
+	duplicate *data.Point
+}
+
+// Finished tells whether the wrapped Simulator is finished and all the
+// delayed points are emitted.
+func (s *disorderSimulator) Finished() bool {
+	return s.Simulator.Finished() && len(s.pending) == 0 && s.duplicate == nil
+}
+
+// Next populates the point with the next due point, if any.
+func (s *disorderSimulator) Next(p *data.Point) bool {
+	if s.duplicate != nil {
+		p.Copy(s.duplicate)
+		s.duplicate = nil
+		return true
+	}
+	for {
+		if len(s.pending) > 0 && (s.Simulator.Finished() || !s.pending[0].due.After(s.now)) {
+			next := heap.Pop(&s.pending).(*pendingPoint).point
+			p.Copy(next)
+			if s.rng.Float64() < s.config.DuplicateChance {
+				s.duplicate = next
+			}
+			return true
+		}
+		if s.Simulator.Finished() {
+			return false
+		}
+
+		q := data.NewPoint()
+		if !s.Simulator.Next(q) {
+			return false
+		}
+		s.now = *q.Timestamp()
+		if s.rng.Float64() < s.config.MissingChance {
+			return false
+		}
+		due := s.now
+		if s.rng.Float64() < s.config.OutOfOrderChance {
+			due = due.Add(time.Duration(1 + s.rng.Int63n(int64(s.config.MaxLateness))))
+		}
+		heap.Push(&s.pending, &pendingPoint{point: q, due: due, seq: s.seq})
+		s.seq++
+	}
+}
+
+// Err returns the error of the wrapped Simulator, if it reports one.
+func (s *disorderSimulator) Err() error {
+	switch sim := s.Simulator.(type) {
+	case interface{ Err() error }:
+		return sim.Err()
+	}
+	return nil
+}
+
+type pendingPoint struct {
+	point *data.Point
+	due   time.Time
+	seq   uint64
+}
+
+// pendingPoints is a heap of points ordered by due time, then by arrival.
+type pendingPoints []*pendingPoint
+
+func (h pendingPoints) Len() int { return len(h) }
+
+func (h pendingPoints) Less(i, j int) bool {
+	if h[i].due.Equal(h[j].due) {
+		return h[i].seq < h[j].seq
+	}
+	return h[i].due.Before(h[j].due)
+}
+
+func (h pendingPoints) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *pendingPoints) Push(x interface{}) { *h = append(*h, x.(*pendingPoint)) }
+
+func (h *pendingPoints) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return x
+}
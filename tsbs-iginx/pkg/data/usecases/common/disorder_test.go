@@ -0,0 +1,119 @@
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+)
+
+var testDisorderStart = time.Date(2016, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// sequenceSimulator emits n points a second apart, the field i numbering them
+type sequenceSimulator struct {
+	BaseSimulator
+	n    int
+	made int
+}
+
+func (s *sequenceSimulator) Finished() bool {
+	return s.made >= s.n
+}
+
+func (s *sequenceSimulator) Next(p *data.Point) bool {
+	ts := testDisorderStart.Add(time.Duration(s.made) * time.Second)
+	p.SetMeasurementName(dummyMeasurementName)
+	p.SetTimestamp(&ts)
+	p.AppendField([]byte("i"), s.made)
+	s.made++
+	return true
+}
+
+// runDisorder returns the numbers of the points a disordered sequence emits
+func runDisorder(config DisorderConfig, n int) []int {
+	Seed(123)
+	sim := NewDisorderSimulator(Rand(), &sequenceSimulator{n: n}, config)
+	var got []int
+	p := data.NewPoint()
+	for !sim.Finished() {
+		if sim.Next(p) {
+			got = append(got, p.GetFieldValue([]byte("i")).(int))
+		}
+		p.Reset()
+	}
+	return got
+}
+
+func TestDisorderSimulator(t *testing.T) {
+	const n = 1000
+	got := runDisorder(DisorderConfig{}, n)
+	if len(got) != n {
+		t.Fatalf("incorrect number of points without disorder: got %d want %d", len(got), n)
+	}
+	for i, v := range got {
+		if v != i {
+			t.Fatalf("point %d out of order without disorder: got %d", i, v)
+		}
+	}
+
+	if got := runDisorder(DisorderConfig{MissingChance: 1}, n); len(got) != 0 {
+		t.Errorf("points emitted with a missing chance of 1: %d", len(got))
+	}
+	got = runDisorder(DisorderConfig{DuplicateChance: 1}, n)
+	if len(got) != 2*n || got[0] != 0 || got[1] != 0 || got[2] != 1 {
+		t.Errorf("points not duplicated with a duplicate chance of 1: %d points, %v", len(got), got[:3])
+	}
+	got = runDisorder(DisorderConfig{MissingChance: 0.2}, n)
+	if len(got) < n*7/10 || len(got) > n*9/10 {
+		t.Errorf("incorrect number of points with a missing chance of 0.2: %d", len(got))
+	}
+
+	maxLateness := 5 * time.Second
+	got = runDisorder(DisorderConfig{OutOfOrderChance: 0.3, MaxLateness: maxLateness}, n)
+	if len(got) != n {
+		t.Fatalf("points lost with out-of-order points: got %d want %d", len(got), n)
+	}
+	seen := make(map[int]bool)
+	outOfOrder := 0
+	newest := -1
+	for _, v := range got {
+		seen[v] = true
+		if v < newest {
+			outOfOrder++
+			if time.Duration(newest-v)*time.Second > maxLateness {
+				t.Errorf("point %d later than max lateness: after point %d", v, newest)
+			}
+		} else {
+			newest = v
+		}
+	}
+	if len(seen) != n {
+		t.Errorf("incorrect number of distinct points: got %d want %d", len(seen), n)
+	}
+	if outOfOrder < n/10 || outOfOrder > n/2 {
+		t.Errorf("incorrect number of out of order points with a chance of 0.3: %d", outOfOrder)
+	}
+}
+
+func TestDisorderConfigValidate(t *testing.T) {
+	valid := DisorderConfig{OutOfOrderChance: 0.1, MaxLateness: time.Minute, MissingChance: 0.01, DuplicateChance: 1}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("unexpected error for valid config: %v", err)
+	}
+	if !valid.Enabled() || (DisorderConfig{MaxLateness: time.Minute}).Enabled() {
+		t.Errorf("incorrect enabled state")
+	}
+	cases := []struct {
+		desc   string
+		config DisorderConfig
+	}{
+		{"negative chance", DisorderConfig{MissingChance: -0.1}},
+		{"chance above 1", DisorderConfig{DuplicateChance: 1.5}},
+		{"out of order without lateness", DisorderConfig{OutOfOrderChance: 0.1}},
+	}
+	for _, c := range cases {
+		if err := c.config.Validate(); err == nil {
+			t.Errorf("%s: unexpected lack of error", c.desc)
+		}
+	}
+}
@@ -16,21 +16,24 @@ type NormalDistribution struct {
 	Mean   float64
 	StdDev float64
 
+	rng   *rand.Rand
 	value float64
 }
 
-// ND creates a new normal distribution with the given mean/stddev
-func ND(mean, stddev float64) *NormalDistribution {
+// ND creates a new normal distribution with the given mean/stddev, drawing
+// from r
+func ND(r *rand.Rand, mean, stddev float64) *NormalDistribution {
 	return &NormalDistribution{
 		Mean:   mean,
 		StdDev: stddev,
+		rng:    r,
 	}
 }
 
 // Advance advances this distribution. Since the distribution is
 // stateless, this just overwrites the internal cache value.
 func (d *NormalDistribution) Advance() {
-	d.value = rand.NormFloat64()*d.StdDev + d.Mean
+	d.value = d.rng.NormFloat64()*d.StdDev + d.Mean
 }
 
 // Get returns the last computed value for this distribution.
@@ -43,21 +46,23 @@ type UniformDistribution struct {
 	Low  float64
 	High float64
 
+	rng   *rand.Rand
 	value float64
 }
 
-// UD creates a new uniform distribution with the given range
-func UD(low, high float64) *UniformDistribution {
+// UD creates a new uniform distribution with the given range, drawing from r
+func UD(r *rand.Rand, low, high float64) *UniformDistribution {
 	return &UniformDistribution{
 		Low:  low,
 		High: high,
+		rng:  r,
 	}
 }
 
 // Advance advances this distribution. Since the distribution is
 // stateless, this just overwrites the internal cache value.
 func (d *UniformDistribution) Advance() {
-	x := rand.Float64() // uniform
+	x := d.rng.Float64() // uniform
 	x *= d.High - d.Low
 	x += d.Low
 	d.value = x
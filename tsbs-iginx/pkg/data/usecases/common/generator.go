@@ -25,7 +25,18 @@ type Tag struct {
 const (
 	errMaxMetricCountValue = "max metric count per host has to be greater than 0"
 	errLogIntervalZero     = "cannot have log interval of 0"
+	errSchemaMissing       = "custom use case requires a schema"
+	errBadSourceFmt        = "invalid source specified: '%v'"
+	errImportMissing       = "%s source requires a source file and a mapping"
+	errParallelFormatFmt   = "format %s keeps state across points and cannot be serialized in parallel"
+	errNonNumericUseFmt    = "string and boolean fields are not simulated for use case '%s'"
+	errChurnUseFmt         = "churn is not simulated for use case '%s'"
+	errChurnRateFmt        = "churn rate must be between 0 and 1, got %v"
+	errTimingUseFmt        = "irregular timestamps are not simulated for use case '%s'"
+	errRoutesUseFmt        = "truck routes are not simulated for use case '%s'"
+	errParallelFiles       = "parallel files require a parallelism above 1 and an output file, and write a manifest next to each file"
 	defaultLogInterval     = 10 * time.Second
+	defaultMaxLateness     = time.Minute
 )
 
 // DataGeneratorConfig is the GeneratorConfig that should be used with a
@@ -40,15 +51,114 @@ type DataGeneratorConfig struct {
 	InterleavedGroupID    uint          `yaml:"interleaved-generation-group-id" mapstructure:"interleaved-generation-group-id"`
 	InterleavedNumGroups  uint          `yaml:"interleaved-generation-groups" mapstructure:"interleaved-generation-groups"`
 	MaxMetricCountPerHost uint64        `yaml:"max-metric-count" mapstructure:"max-metric-count"`
+	// Schema is the YAML file that declares the custom use case
+	Schema string `yaml:"schema,omitempty" mapstructure:"schema,omitempty"`
+
+	// SensorsPerDevice and SensorsPerBlock are the sensors of the devices of
+	// the industrial use case, and how many of them are written together
+	SensorsPerDevice uint `yaml:"sensors-per-device,omitempty" mapstructure:"sensors-per-device,omitempty"`
+	SensorsPerBlock  uint `yaml:"sensors-per-block,omitempty" mapstructure:"sensors-per-block,omitempty"`
+
+	// Source is where the data comes from: the simulator of the use case, or
+	// a CSV or Parquet dataset whose columns Mapping maps to points
+	Source          string `yaml:"source,omitempty" mapstructure:"source,omitempty"`
+	SourceFile      string `yaml:"source-file,omitempty" mapstructure:"source-file,omitempty"`
+	Mapping         string `yaml:"mapping,omitempty" mapstructure:"mapping,omitempty"`
+	SourceTimeShift bool   `yaml:"source-time-shift,omitempty" mapstructure:"source-time-shift,omitempty"`
+
+	// Disorder of the generated data, see DisorderConfig
+	OutOfOrderChance float64       `yaml:"out-of-order-chance,omitempty" mapstructure:"out-of-order-chance,omitempty"`
+	MaxLateness      time.Duration `yaml:"max-lateness,omitempty" mapstructure:"max-lateness,omitempty"`
+	MissingChance    float64       `yaml:"missing-chance,omitempty" mapstructure:"missing-chance,omitempty"`
+	DuplicateChance  float64       `yaml:"duplicate-chance,omitempty" mapstructure:"duplicate-chance,omitempty"`
+	// DisorderSet tells whether any of the disorder chances was set, even to
+	// 0. They then replace the batch disorder of the IoT use case
+	DisorderSet bool `yaml:"-" mapstructure:"-"`
+
+	// NonNumericFields adds string and boolean fields to the devops and IoT
+	// measurements
+	NonNumericFields bool `yaml:"non-numeric-fields,omitempty" mapstructure:"non-numeric-fields,omitempty"`
+
+	// ChurnRate is the chance at each log interval that a host or truck is
+	// retired and replaced by one with a new name
+	ChurnRate float64 `yaml:"churn-rate,omitempty" mapstructure:"churn-rate,omitempty"`
+
+	// Irregular timestamps of the series, see TimingConfig
+	TimestampJitter time.Duration   `yaml:"timestamp-jitter,omitempty" mapstructure:"timestamp-jitter,omitempty"`
+	SeriesIntervals []time.Duration `yaml:"series-intervals,omitempty" mapstructure:"series-intervals,omitempty"`
+	BurstChance     float64         `yaml:"burst-chance,omitempty" mapstructure:"burst-chance,omitempty"`
+	BurstPoints     uint            `yaml:"burst-points,omitempty" mapstructure:"burst-points,omitempty"`
+
+	// TruckRoutes makes the IoT trucks drive routes on a grid of roads,
+	// rather than wander at random
+	TruckRoutes bool `yaml:"truck-routes,omitempty" mapstructure:"truck-routes,omitempty"`
+
+	// Manifest is where the manifest of the generated data is written
+	Manifest string `yaml:"manifest,omitempty" mapstructure:"manifest,omitempty"`
+
+	// Parallelism is the number of simulator shards generating the points on
+	// their own goroutine, each keeping an interleaved group. With
+	// ParallelFiles, they write a file each, else one stream merged by time
+	Parallelism   uint `yaml:"parallelism,omitempty" mapstructure:"parallelism,omitempty"`
+	ParallelFiles bool `yaml:"parallel-files,omitempty" mapstructure:"parallel-files,omitempty"`
+}
+
+// Disorder returns the disorder to apply to the generated data.
+func (c *DataGeneratorConfig) Disorder() DisorderConfig {
+	return DisorderConfig{
+		OutOfOrderChance: c.OutOfOrderChance,
+		MaxLateness:      c.MaxLateness,
+		MissingChance:    c.MissingChance,
+		DuplicateChance:  c.DuplicateChance,
+	}
+}
+
+// disorderChanceKeys are the settings of the disorder chances.
+var disorderChanceKeys = []string{"out-of-order-chance", "missing-chance", "duplicate-chance"}
+
+// DisorderChanceSet tells whether any of the disorder chances is set, given
+// whether the setting of a key is set.
+func DisorderChanceSet(isSet func(key string) bool) bool {
+	for _, key := range disorderChanceKeys {
+		if isSet(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// Timing returns the irregularities of the timestamps of the series.
+func (c *DataGeneratorConfig) Timing() TimingConfig {
+	return TimingConfig{
+		Jitter:          c.TimestampJitter,
+		SeriesIntervals: c.SeriesIntervals,
+		BurstChance:     c.BurstChance,
+		BurstPoints:     c.BurstPoints,
+	}
+}
+
+// Imported tells whether the data is imported from a dataset rather than
+// simulated.
+func (c *DataGeneratorConfig) Imported() bool {
+	return c.Source != "" && c.Source != SourceSimulator
 }
 
 // Validate checks that the values of the DataGeneratorConfig are reasonable.
 func (c *DataGeneratorConfig) Validate() error {
-	err := c.BaseConfig.Validate()
+	if !utils.IsIn(c.Source, append(SourceChoices, "")) {
+		return fmt.Errorf(errBadSourceFmt, c.Source)
+	}
+
+	// an imported dataset has no use case
+	err := c.BaseConfig.validate(!c.Imported())
 	if err != nil {
 		return err
 	}
 
+	if c.Imported() && (c.SourceFile == "" || c.Mapping == "") {
+		return fmt.Errorf(errImportMissing, c.Source)
+	}
+
 	if c.InitialScale == 0 {
 		c.InitialScale = c.BaseConfig.Scale
 	}
@@ -63,6 +173,45 @@ func (c *DataGeneratorConfig) Validate() error {
 		return fmt.Errorf(errMaxMetricCountValue)
 	}
 
+	if c.Use == UseCaseCustom && c.Schema == "" {
+		return fmt.Errorf(errSchemaMissing)
+	}
+
+	if err := c.Disorder().Validate(); err != nil {
+		return err
+	}
+
+	if c.NonNumericFields && (c.Imported() || !utils.IsIn(c.Use, nonNumericUseCases)) {
+		return fmt.Errorf(errNonNumericUseFmt, c.Use)
+	}
+
+	if c.ChurnRate < 0 || c.ChurnRate > 1 {
+		return fmt.Errorf(errChurnRateFmt, c.ChurnRate)
+	}
+	if c.ChurnRate > 0 && (c.Imported() || !utils.IsIn(c.Use, churnUseCases)) {
+		return fmt.Errorf(errChurnUseFmt, c.Use)
+	}
+
+	if timing := c.Timing(); timing.Enabled() {
+		if c.Imported() || !utils.IsIn(c.Use, timingUseCases) {
+			return fmt.Errorf(errTimingUseFmt, c.Use)
+		}
+		if err := timing.Validate(c.LogInterval); err != nil {
+			return err
+		}
+	}
+
+	if c.TruckRoutes && (c.Imported() || c.Use != UseCaseIoT) {
+		return fmt.Errorf(errRoutesUseFmt, c.Use)
+	}
+
+	if c.Parallelism > 1 && (c.Format == constants.FormatAkumuli || c.Format == constants.FormatPrometheus) {
+		return fmt.Errorf(errParallelFormatFmt, c.Format)
+	}
+	if c.ParallelFiles && (c.Parallelism < 2 || c.File == "" || c.Manifest != "") {
+		return fmt.Errorf(errParallelFiles)
+	}
+
 	return err
 }
 
@@ -77,6 +226,35 @@ func (c *DataGeneratorConfig) AddToFlagSet(fs *pflag.FlagSet) {
 	fs.Uint("interleaved-generation-groups", 1,
 		"The number of round-robin serialization groups. Use this to scale up data generation to multiple processes.")
 	fs.Uint64("max-metric-count", 100, "Max number of metric fields to generate per host. Used only in devops-generic use-case")
+	fs.String("schema", "", "YAML file declaring the measurements, tags and fields to generate. Used only in custom use-case")
+	fs.Uint("sensors-per-device", 1000, "Number of sensors of each device, up to 10000. Used only in industrial use-case")
+	fs.Uint("sensors-per-block", 100, "Number of sensors written together, as the fields of one point. Used only in industrial use-case")
+
+	fs.String("source", SourceSimulator, fmt.Sprintf("Source of the data. (choices: %s)", strings.Join(SourceChoices, ", ")))
+	fs.String("source-file", "", "Dataset to import. Used only with the csv and parquet sources")
+	fs.String("mapping", "", "YAML file mapping the dataset columns to the timestamp, tags and fields. Used only with the csv and parquet sources")
+	fs.Bool("source-time-shift", false, "Shift the imported dataset to start at timestamp-start, and drop its rows from timestamp-end")
+
+	fs.Float64("out-of-order-chance", 0, "Chance that a point is delayed by up to max-lateness, so it arrives after newer points")
+	fs.Duration("max-lateness", defaultMaxLateness, "Maximum delay, in simulated time, of an out-of-order point")
+	fs.Float64("missing-chance", 0, "Chance that a point is dropped")
+	fs.Float64("duplicate-chance", 0, "Chance that a point is emitted twice")
+
+	fs.Bool("non-numeric-fields", false, fmt.Sprintf("Add string and boolean fields to the measurements. Used only in the %s use cases", strings.Join(nonNumericUseCases, ", ")))
+	fs.Float64("churn-rate", 0, fmt.Sprintf("Chance at each log interval that a host or truck is retired and replaced by one with a new name. Used only in the %s use cases", strings.Join(churnUseCases, ", ")))
+
+	timingUse := fmt.Sprintf("Used only in the %s use cases", strings.Join(timingUseCases, ", "))
+	fs.Duration("timestamp-jitter", 0, "Each point is late on its schedule by a random duration below this, which must be below log-interval. "+timingUse)
+	fs.StringSlice("series-intervals", nil, "Intervals at which the series report, given to the trucks in turn, each a multiple of log-interval (default: log-interval). "+timingUse)
+	fs.Float64("burst-chance", 0, "Chance that a point starts a burst of burst-points extra points, spread until the next point of the series. "+timingUse)
+	fs.Uint("burst-points", 10, "Number of extra points of a burst")
+
+	fs.Bool("truck-routes", false, "Drive the trucks on a grid of roads, with their velocity and stops matching their moves. Used only in iot use-case")
+
+	fs.Uint("parallelism", 1, "Number of simulator shards generating the points, each on a goroutine with its own source of randomness and keeping an interleaved group. Their points are merged by time into one stream")
+	fs.Bool("parallel-files", false, "Write the points of each of the parallelism shards to a file named after --file with a .<shard> suffix, as separate interleaved generation groups would")
+
+	fs.String("manifest", "", "Write the manifest of the data to this path (default: the output file with a .manifest.json suffix, none when writing to stdout)")
 }
 
 const defaultTimeStart = "2016-01-01T00:00:00Z"
@@ -130,6 +308,11 @@ func (c *BaseConfig) AddToFlagSet(fs *pflag.FlagSet) {
 }
 
 func (c *BaseConfig) Validate() error {
+	return c.validate(true)
+}
+
+// validate checks the config, and the use case if checkUse is set.
+func (c *BaseConfig) validate(checkUse bool) error {
 	if c.Scale == 0 {
 		return fmt.Errorf(ErrScaleIsZero)
 	}
@@ -142,7 +325,7 @@ func (c *BaseConfig) Validate() error {
 		return fmt.Errorf(errBadFormatFmt, c.Format)
 	}
 
-	if !utils.IsIn(c.Use, UseCaseChoices) {
+	if checkUse && !utils.IsIn(c.Use, UseCaseChoices) {
 		return fmt.Errorf(errBadUseFmt, c.Use)
 	}
 
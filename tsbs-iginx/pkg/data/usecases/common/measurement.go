@@ -2,6 +2,7 @@ package common
 
 import (
 	"github.com/timescale/tsbs/pkg/data"
+	"math/rand"
 	"time"
 )
 
@@ -20,11 +21,11 @@ func NewSubsystemMeasurement(start time.Time, numDistributions int) *SubsystemMe
 }
 
 // NewSubsystemMeasurementWithDistributionMakers creates a new SubsystemMeasurement with start time and distribution makers
-// which are used to create the necessary distributions.
-func NewSubsystemMeasurementWithDistributionMakers(start time.Time, makers []LabeledDistributionMaker) *SubsystemMeasurement {
+// which are used to create the necessary distributions, drawing from r.
+func NewSubsystemMeasurementWithDistributionMakers(r *rand.Rand, start time.Time, makers []LabeledDistributionMaker) *SubsystemMeasurement {
 	m := NewSubsystemMeasurement(start, len(makers))
 	for i := 0; i < len(makers); i++ {
-		m.Distributions[i] = makers[i].DistributionMaker()
+		m.Distributions[i] = makers[i].DistributionMaker(r)
 	}
 	return m
 }
@@ -60,8 +61,9 @@ func (m *SubsystemMeasurement) ToPointAllInt64(p *data.Point, measurementName []
 	}
 }
 
-// LabeledDistributionMaker combines a distribution maker with a label.
+// LabeledDistributionMaker combines a distribution maker with a label. The
+// maker creates a distribution drawing from the given source of randomness.
 type LabeledDistributionMaker struct {
 	Label             []byte
-	DistributionMaker func() Distribution
+	DistributionMaker func(r *rand.Rand) Distribution
 }
@@ -3,6 +3,7 @@ package common
 import (
 	"github.com/timescale/tsbs/pkg/data"
 	"math"
+	"math/rand"
 	"testing"
 	"time"
 )
@@ -62,11 +63,11 @@ func TestNewSubsystemMeasurement(t *testing.T) {
 
 func TestNewSubsystemMeasurementWithDistributionMakers(t *testing.T) {
 	makers := []LabeledDistributionMaker{
-		{[]byte("foo"), func() Distribution { return &monotonicDistribution{state: 0.0} }},
-		{[]byte("bar"), func() Distribution { return &monotonicDistribution{state: 1.0} }},
+		{[]byte("foo"), func(*rand.Rand) Distribution { return &monotonicDistribution{state: 0.0} }},
+		{[]byte("bar"), func(*rand.Rand) Distribution { return &monotonicDistribution{state: 1.0} }},
 	}
 	now := time.Now()
-	m := NewSubsystemMeasurementWithDistributionMakers(now, makers)
+	m := NewSubsystemMeasurementWithDistributionMakers(Rand(), now, makers)
 	if !m.Timestamp.Equal(now) {
 		t.Errorf("incorrect timestamp set: got %v want %v", m.Timestamp, now)
 	}
@@ -125,9 +126,9 @@ func TestToPointAllInt64(t *testing.T) {
 
 func setupToPoint(start time.Time) (*SubsystemMeasurement, []LabeledDistributionMaker) {
 	makers := []LabeledDistributionMaker{
-		{[]byte(toPointFieldLabel), func() Distribution { return &monotonicDistribution{state: toPointState} }},
+		{[]byte(toPointFieldLabel), func(*rand.Rand) Distribution { return &monotonicDistribution{state: toPointState} }},
 	}
-	m := NewSubsystemMeasurementWithDistributionMakers(start, makers)
+	m := NewSubsystemMeasurementWithDistributionMakers(Rand(), start, makers)
 	m.Tick(time.Nanosecond)
 	return m, makers
 }
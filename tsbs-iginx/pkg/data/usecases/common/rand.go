@@ -0,0 +1,21 @@
+package common
+
+import "math/rand"
+
+// rng is the source of randomness of data generation by a single simulator.
+// It is seeded with Seed and used by nothing else, so the generated data
+// depends only on the seed and the config, not on other users of the global
+// math/rand source. Like the simulators, it is not safe for concurrent use:
+// simulators running at once are each given their own source.
+var rng = rand.New(rand.NewSource(1))
+
+// Seed seeds the source of randomness of data generation.
+func Seed(seed int64) {
+	rng.Seed(seed)
+}
+
+// Rand returns the source of randomness of data generation, to give to the
+// SimulatorConfig of a use case.
+func Rand() *rand.Rand {
+	return rng
+}
@@ -2,13 +2,16 @@ package common
 
 import (
 	"github.com/timescale/tsbs/pkg/data"
+	"math/rand"
 	"reflect"
 	"time"
 )
 
 // SimulatorConfig is an interface to create a Simulator from a time.Duration.
+// The Simulator draws all its random values from the given source, so
+// Simulators with their own sources can run at once.
 type SimulatorConfig interface {
-	NewSimulator(time.Duration, uint64) Simulator
+	NewSimulator(*rand.Rand, time.Duration, uint64) Simulator
 }
 
 // BaseSimulatorConfig is used to create a BaseSimulator.
@@ -21,8 +24,13 @@ type BaseSimulatorConfig struct {
 	InitGeneratorScale uint64
 	// GeneratorScale is the total number of Generators to have in the last reporting period
 	GeneratorScale uint64
-	// GeneratorConstructor is the function used to create a new Generator given an id number and start time
-	GeneratorConstructor func(i int, start time.Time) Generator
+	// GeneratorConstructor is the function used to create a new Generator given a source of randomness, an id number and start time
+	GeneratorConstructor func(r *rand.Rand, i int, start time.Time) Generator
+	// ChurnRate is the chance at each epoch that a reporting Generator is
+	// retired and replaced by a new one, with the next unused id number
+	ChurnRate float64
+	// Timing makes the timestamps of the Generators irregular
+	Timing TimingConfig
 }
 
 func calculateEpochs(duration time.Duration, interval time.Duration) uint64 {
@@ -30,10 +38,10 @@ func calculateEpochs(duration time.Duration, interval time.Duration) uint64 {
 }
 
 // NewSimulator produces a Simulator that conforms to the given config over the specified interval.
-func (sc *BaseSimulatorConfig) NewSimulator(interval time.Duration, limit uint64) Simulator {
+func (sc *BaseSimulatorConfig) NewSimulator(r *rand.Rand, interval time.Duration, limit uint64) Simulator {
 	generators := make([]Generator, sc.GeneratorScale)
 	for i := 0; i < len(generators); i++ {
-		generators[i] = sc.GeneratorConstructor(i, sc.Start)
+		generators[i] = sc.GeneratorConstructor(r, i, sc.Start)
 	}
 
 	epochs := calculateEpochs(sc.End.Sub(sc.Start), interval)
@@ -43,6 +51,7 @@ func (sc *BaseSimulatorConfig) NewSimulator(interval time.Duration, limit uint64
 		maxPoints = limit
 	}
 	sim := &BaseSimulator{
+		rng:        r,
 		madePoints: 0,
 		maxPoints:  maxPoints,
 
@@ -58,6 +67,12 @@ func (sc *BaseSimulatorConfig) NewSimulator(interval time.Duration, limit uint64
 		interval:        interval,
 
 		simulatedMeasurementIndex: 0,
+
+		churnRate:            sc.ChurnRate,
+		nextID:               len(generators),
+		generatorConstructor: sc.GeneratorConstructor,
+
+		timing: sc.Timing,
 	}
 
 	return sim
@@ -81,6 +96,7 @@ type Simulator interface {
 
 // BaseSimulator generates data similar to truck readings.
 type BaseSimulator struct {
+	rng        *rand.Rand
 	madePoints uint64
 	maxPoints  uint64
 
@@ -97,28 +113,37 @@ type BaseSimulator struct {
 	interval       time.Duration
 
 	simulatedMeasurementIndex int
+
+	churnRate            float64
+	nextID               int
+	generatorConstructor func(r *rand.Rand, i int, start time.Time) Generator
+
+	timing    TimingConfig
+	burst     *data.Point
+	burstLeft uint
+	burstStep time.Duration
 }
 
 // Finished tells whether we have simulated all the necessary points.
 func (s *BaseSimulator) Finished() bool {
-	return s.madePoints >= s.maxPoints
+	return s.madePoints >= s.maxPoints && s.burstLeft == 0
 }
 
 // Next advances a Point to the next state in the generator.
 func (s *BaseSimulator) Next(p *data.Point) bool {
-	if s.generatorIndex == uint64(len(s.generators)) {
-		s.generatorIndex = 0
-		s.simulatedMeasurementIndex++
+	if s.burstLeft > 0 {
+		return s.nextBurstPoint(p)
 	}
 
-	if s.simulatedMeasurementIndex == len(s.generators[0].Measurements()) {
-		s.simulatedMeasurementIndex = 0
-
-		for i := 0; i < len(s.generators); i++ {
-			s.generators[i].TickAll(s.interval)
+	s.advance()
+	// skip the generators that do not report at this epoch
+	for !s.reports(s.generatorIndex) {
+		s.madePoints++
+		s.generatorIndex++
+		if s.Finished() {
+			return false
 		}
-
-		s.adjustNumHostsForEpoch()
+		s.advance()
 	}
 
 	generator := s.generators[s.generatorIndex]
@@ -132,11 +157,35 @@ func (s *BaseSimulator) Next(p *data.Point) bool {
 	generator.Measurements()[s.simulatedMeasurementIndex].ToPoint(p)
 
 	ret := s.generatorIndex < s.epochGenerators
+	if ret && s.timing.Enabled() {
+		s.retime(p, s.generatorIndex)
+	}
 	s.madePoints++
 	s.generatorIndex++
 	return ret
 }
 
+// advance moves to the next measurement once all the generators went
+// through the current one, and to the next epoch once all the measurements
+// are simulated.
+func (s *BaseSimulator) advance() {
+	if s.generatorIndex == uint64(len(s.generators)) {
+		s.generatorIndex = 0
+		s.simulatedMeasurementIndex++
+	}
+
+	if s.simulatedMeasurementIndex == len(s.generators[0].Measurements()) {
+		s.simulatedMeasurementIndex = 0
+
+		for i := 0; i < len(s.generators); i++ {
+			s.generators[i].TickAll(s.interval)
+		}
+
+		s.adjustNumHostsForEpoch()
+		s.churn()
+	}
+}
+
 // Fields returns all the simulated measurements for the device.
 func (s *BaseSimulator) Fields() map[string][]string {
 	if len(s.generators) <= 0 {
@@ -211,6 +260,22 @@ func (s *BaseSimulator) adjustNumHostsForEpoch() {
 	s.epochGenerators = s.initGenerators + uint64(missingScale*float64(s.epoch)/float64(s.epochs-1))
 }
 
+// churn retires each reporting Generator with the chance of the churn rate,
+// and puts in its place a Generator with a new id number, so its series end
+// and new ones start.
+func (s *BaseSimulator) churn() {
+	if s.churnRate == 0 {
+		return
+	}
+	now := s.timestampStart.Add(time.Duration(s.epoch) * s.interval)
+	for i := uint64(0); i < s.epochGenerators && i < uint64(len(s.generators)); i++ {
+		if s.rng.Float64() < s.churnRate {
+			s.generators[i] = s.generatorConstructor(s.rng, s.nextID, now)
+			s.nextID++
+		}
+	}
+}
+
 // SimulatedMeasurement simulates one measurement (e.g. Redis for DevOps).
 type SimulatedMeasurement interface {
 	Tick(time.Duration)
@@ -3,6 +3,7 @@ package common
 import (
 	"fmt"
 	"github.com/timescale/tsbs/pkg/data"
+	"math/rand"
 	"testing"
 	"time"
 )
@@ -59,12 +60,12 @@ func (d dummyGenerator) Tags() []Tag {
 func (d dummyGenerator) TickAll(duration time.Duration) {
 }
 
-func dummyGeneratorConstructor(i int, start time.Time) Generator {
+func dummyGeneratorConstructor(_ *rand.Rand, i int, start time.Time) Generator {
 	return &dummyGenerator{}
 }
 
 func TestBaseSimulatorNext(t *testing.T) {
-	s := testBaseConf.NewSimulator(time.Second, 0).(*BaseSimulator)
+	s := testBaseConf.NewSimulator(Rand(), time.Second, 0).(*BaseSimulator)
 	// There are two epochs for the test configuration, and a difference of 90
 	// from init to final, so each epoch should add 45 devices to be written.
 	writtenIdx := []int{10, 55, 100}
@@ -99,7 +100,7 @@ func TestBaseSimulatorNext(t *testing.T) {
 }
 
 func TestBaseSimulatorTagKeys(t *testing.T) {
-	s := testBaseConf.NewSimulator(time.Second, 0).(*BaseSimulator)
+	s := testBaseConf.NewSimulator(Rand(), time.Second, 0).(*BaseSimulator)
 
 	tagKeys := s.TagKeys()
 
@@ -127,7 +128,7 @@ func TestBaseSimulatorTagKeysPanic(t *testing.T) {
 }
 
 func TestBaseSimulatorTagTypes(t *testing.T) {
-	s := testBaseConf.NewSimulator(time.Second, 0).(*BaseSimulator)
+	s := testBaseConf.NewSimulator(Rand(), time.Second, 0).(*BaseSimulator)
 
 	tagTypes := s.TagTypes()
 
@@ -155,7 +156,7 @@ func TestBaseSimulatorTagTypesPanic(t *testing.T) {
 }
 
 func TestBaseSimulatorFields(t *testing.T) {
-	s := testBaseConf.NewSimulator(time.Second, 0).(*BaseSimulator)
+	s := testBaseConf.NewSimulator(Rand(), time.Second, 0).(*BaseSimulator)
 
 	fields := s.Fields()
 
@@ -209,7 +210,7 @@ func TestBaseSimulatorConfigNewSimulator(t *testing.T) {
 
 	for _, limit := range cases {
 		t.Run(fmt.Sprintf("limit %d", limit), func(t *testing.T) {
-			sim := conf.NewSimulator(duration, limit).(*BaseSimulator)
+			sim := conf.NewSimulator(Rand(), duration, limit).(*BaseSimulator)
 			if got := sim.madePoints; got != 0 {
 				t.Errorf("incorrect initial points: got %d want %d", got, 0)
 			}
@@ -249,3 +250,43 @@ func TestBaseSimulatorConfigNewSimulator(t *testing.T) {
 	}
 
 }
+
+func TestBaseSimulatorChurn(t *testing.T) {
+	type construction struct {
+		id    int
+		start time.Time
+	}
+	var constructed []construction
+	start := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	conf := &BaseSimulatorConfig{
+		Start:              start,
+		End:                start.Add(3 * time.Second),
+		InitGeneratorScale: 10,
+		GeneratorScale:     10,
+		GeneratorConstructor: func(_ *rand.Rand, i int, start time.Time) Generator {
+			constructed = append(constructed, construction{i, start})
+			return &dummyGenerator{}
+		},
+		ChurnRate: 1,
+	}
+	sim := conf.NewSimulator(Rand(), time.Second, 0).(*BaseSimulator)
+	p := data.NewPoint()
+	// two epochs of points, then the first point of the third one
+	for i := 0; i < 2*10*dummyGeneratorMeasurementCount+1; i++ {
+		sim.Next(p)
+		p.Reset()
+	}
+
+	if got, want := len(constructed), 30; got != want {
+		t.Fatalf("incorrect number of generators constructed: got %d want %d", got, want)
+	}
+	for i, c := range constructed {
+		if c.id != i {
+			t.Errorf("incorrect id of generator %d: got %d", i, c.id)
+		}
+		want := start.Add(time.Duration(i/10) * time.Second)
+		if !c.start.Equal(want) {
+			t.Errorf("incorrect start of generator %d: got %v want %v", i, c.start, want)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package common
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+)
+
+// TimingConfig declares the irregularities of the timestamps of the series
+// of a BaseSimulator: jitter, series reporting less often than every log
+// interval, and bursts of extra points.
+type TimingConfig struct {
+	// Jitter is the most a point is late on its schedule, each point being
+	// late by a random duration below it
+	Jitter time.Duration
+	// SeriesIntervals are the intervals at which the series report, given to
+	// the generators in turn. Each is a multiple of the log interval
+	SeriesIntervals []time.Duration
+	// BurstChance is the chance that a point starts a burst of BurstPoints
+	// extra points, spread until the next point of the series
+	BurstChance float64
+	BurstPoints uint
+}
+
+// Enabled tells whether the config changes the timestamps of a Simulator.
+func (c TimingConfig) Enabled() bool {
+	return c.Jitter > 0 || len(c.SeriesIntervals) > 0 || c.BurstChance > 0
+}
+
+// Validate checks the config against the log interval: the jitter keeps the
+// points of a series in order, and the intervals are whole numbers of log
+// intervals.
+func (c TimingConfig) Validate(logInterval time.Duration) error {
+	if c.Jitter < 0 || c.Jitter >= logInterval {
+		return fmt.Errorf("timestamp jitter must be between 0 and the log interval %v, got %v", logInterval, c.Jitter)
+	}
+	for _, interval := range c.SeriesIntervals {
+		if interval < logInterval || interval%logInterval != 0 {
+			return fmt.Errorf("series interval %v is not a multiple of the log interval %v", interval, logInterval)
+		}
+	}
+	if c.BurstChance < 0 || c.BurstChance > 1 {
+		return fmt.Errorf("burst chance must be between 0 and 1, got %v", c.BurstChance)
+	}
+	if c.BurstChance > 0 && c.BurstPoints == 0 {
+		return fmt.Errorf("bursts require at least 1 burst point")
+	}
+	return nil
+}
+
+// reports tells whether the i-th generator reports at the epoch. A generator
+// whose interval spans k epochs reports every k-th epoch, the generators
+// being spread over the k epochs.
+func (s *BaseSimulator) reports(i uint64) bool {
+	if len(s.timing.SeriesIntervals) == 0 {
+		return true
+	}
+	k := uint64(s.timing.SeriesIntervals[i%uint64(len(s.timing.SeriesIntervals))] / s.interval)
+	return s.epoch%k == i%k
+}
+
+// seriesInterval returns the interval of the i-th generator.
+func (s *BaseSimulator) seriesInterval(i uint64) time.Duration {
+	if len(s.timing.SeriesIntervals) == 0 {
+		return s.interval
+	}
+	return s.timing.SeriesIntervals[i%uint64(len(s.timing.SeriesIntervals))]
+}
+
+// retime delays the point of the i-th generator by its jitter, and may
+// start a burst of copies of it, spread between it and the next point of
+// the series.
+func (s *BaseSimulator) retime(p *data.Point, i uint64) {
+	scheduled := *p.Timestamp()
+	ts := scheduled
+	if s.timing.Jitter > 0 {
+		ts = ts.Add(time.Duration(s.rng.Int63n(int64(s.timing.Jitter))))
+		p.SetTimestamp(&ts)
+	}
+	if s.timing.BurstChance == 0 || s.rng.Float64() >= s.timing.BurstChance {
+		return
+	}
+
+	s.burst = data.NewPoint()
+	s.burst.SetMeasurementName(p.MeasurementName())
+	for j, key := range p.TagKeys() {
+		s.burst.AppendTag(key, p.TagValues()[j])
+	}
+	for j, key := range p.FieldKeys() {
+		s.burst.AppendField(key, p.FieldValues()[j])
+	}
+	s.burst.SetTimestamp(&ts)
+	s.burstLeft = s.timing.BurstPoints
+	s.burstStep = scheduled.Add(s.seriesInterval(i)).Sub(ts) / time.Duration(s.timing.BurstPoints+1)
+}
+
+// nextBurstPoint populates the point with the next point of the burst.
+func (s *BaseSimulator) nextBurstPoint(p *data.Point) bool {
+	p.Copy(s.burst)
+	ts := p.Timestamp().Add(s.burstStep * time.Duration(s.timing.BurstPoints-s.burstLeft+1))
+	p.SetTimestamp(&ts)
+	s.burstLeft--
+	return true
+}
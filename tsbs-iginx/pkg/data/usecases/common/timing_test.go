@@ -0,0 +1,148 @@
+package common
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+)
+
+func TestTimingConfigValidate(t *testing.T) {
+	cases := []struct {
+		desc      string
+		config    TimingConfig
+		shouldErr bool
+	}{
+		{desc: "disabled", config: TimingConfig{}},
+		{desc: "jitter below the log interval", config: TimingConfig{Jitter: 500 * time.Millisecond}},
+		{desc: "jitter of the log interval", config: TimingConfig{Jitter: time.Second}, shouldErr: true},
+		{desc: "negative jitter", config: TimingConfig{Jitter: -time.Millisecond}, shouldErr: true},
+		{desc: "multiple intervals", config: TimingConfig{SeriesIntervals: []time.Duration{time.Second, time.Minute}}},
+		{desc: "interval not a multiple", config: TimingConfig{SeriesIntervals: []time.Duration{1500 * time.Millisecond}}, shouldErr: true},
+		{desc: "interval below the log interval", config: TimingConfig{SeriesIntervals: []time.Duration{0}}, shouldErr: true},
+		{desc: "bursts", config: TimingConfig{BurstChance: 0.1, BurstPoints: 5}},
+		{desc: "burst chance above 1", config: TimingConfig{BurstChance: 1.1, BurstPoints: 5}, shouldErr: true},
+		{desc: "bursts without points", config: TimingConfig{BurstChance: 0.1}, shouldErr: true},
+	}
+	for _, c := range cases {
+		err := c.config.Validate(time.Second)
+		if c.shouldErr && err == nil {
+			t.Errorf("%s: unexpected lack of error", c.desc)
+		} else if !c.shouldErr && err != nil {
+			t.Errorf("%s: unexpected error: %v", c.desc, err)
+		}
+	}
+}
+
+// simulateTimestamps returns the timestamps of the written points of the
+// simulator, by generator id.
+func simulateTimestamps(timing TimingConfig, epochs int) map[int][]time.Time {
+	start := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	conf := &BaseSimulatorConfig{
+		Start:              start,
+		End:                start.Add(time.Duration(epochs) * time.Second),
+		InitGeneratorScale: 4,
+		GeneratorScale:     4,
+		GeneratorConstructor: func(_ *rand.Rand, i int, start time.Time) Generator {
+			return &timedGenerator{id: i, measurement: &SubsystemMeasurement{Timestamp: start}}
+		},
+		Timing: timing,
+	}
+	sim := conf.NewSimulator(Rand(), time.Second, 0)
+	timestamps := make(map[int][]time.Time)
+	p := data.NewPoint()
+	for !sim.Finished() {
+		if sim.Next(p) {
+			id := p.GetTagValue([]byte("id")).(int)
+			timestamps[id] = append(timestamps[id], *p.Timestamp())
+		}
+		p.Reset()
+	}
+	return timestamps
+}
+
+type timedGenerator struct {
+	id          int
+	measurement *SubsystemMeasurement
+}
+
+func (g *timedGenerator) Measurements() []SimulatedMeasurement {
+	return []SimulatedMeasurement{g}
+}
+
+func (g *timedGenerator) Tags() []Tag {
+	return []Tag{{Key: []byte("id"), Value: g.id}}
+}
+
+func (g *timedGenerator) TickAll(d time.Duration) {
+	g.measurement.Tick(d)
+}
+
+func (g *timedGenerator) Tick(d time.Duration) {
+	g.measurement.Tick(d)
+}
+
+func (g *timedGenerator) ToPoint(p *data.Point) {
+	p.SetMeasurementName(dummyMeasurementName)
+	p.SetTimestamp(&g.measurement.Timestamp)
+	p.AppendField(dummyFieldLabel, 1.0)
+}
+
+func TestBaseSimulatorSeriesIntervals(t *testing.T) {
+	timestamps := simulateTimestamps(TimingConfig{SeriesIntervals: []time.Duration{time.Second, 3 * time.Second}}, 6)
+	start := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	// generators 0 and 2 report every second, 1 and 3 every 3 seconds, the
+	// epochs of which they are spread over by their id
+	want := map[int][]int{
+		0: {0, 1, 2, 3, 4, 5},
+		1: {1, 4},
+		2: {0, 1, 2, 3, 4, 5},
+		3: {0, 3},
+	}
+	for id, seconds := range want {
+		if got := len(timestamps[id]); got != len(seconds) {
+			t.Fatalf("generator %d: incorrect number of points: got %d want %d", id, got, len(seconds))
+		}
+		for i, s := range seconds {
+			if got := timestamps[id][i]; !got.Equal(start.Add(time.Duration(s) * time.Second)) {
+				t.Errorf("generator %d: incorrect timestamp %d: got %v want second %d", id, i, got, s)
+			}
+		}
+	}
+}
+
+func TestBaseSimulatorJitterAndBursts(t *testing.T) {
+	Seed(123)
+	timing := TimingConfig{Jitter: 300 * time.Millisecond, BurstChance: 0.5, BurstPoints: 3}
+	timestamps := simulateTimestamps(timing, 10)
+	start := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	bursts := 0
+	for id, ts := range timestamps {
+		scheduled := 0
+		for i, t0 := range ts {
+			if i > 0 && !t0.After(ts[i-1]) {
+				t.Errorf("generator %d: timestamp %d not after the previous one: %v", id, i, t0)
+			}
+			// a point on schedule is jittered by less than the jitter, other
+			// points are burst points before the next scheduled point
+			offset := t0.Sub(start.Add(time.Duration(scheduled) * time.Second))
+			if offset >= time.Second {
+				scheduled++
+				offset -= time.Second
+			} else if i > 0 {
+				bursts++
+				continue
+			}
+			if offset < 0 || offset >= timing.Jitter {
+				t.Errorf("generator %d: incorrect jitter of point %d: %v", id, i, offset)
+			}
+		}
+		if scheduled != 9 {
+			t.Errorf("generator %d: incorrect number of scheduled points: got %d want 10", id, scheduled+1)
+		}
+	}
+	if bursts == 0 || bursts%int(timing.BurstPoints) != 0 {
+		t.Errorf("incorrect number of burst points: %d", bursts)
+	}
+}
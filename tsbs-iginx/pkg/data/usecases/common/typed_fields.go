@@ -0,0 +1,142 @@
+package common
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+)
+
+// TypedField is a string or boolean field that a TypedFieldsSimulatorConfig
+// adds to the points of a measurement. Each series of the measurement keeps
+// the value of the field until it draws a new one.
+type TypedField struct {
+	Label []byte
+	// Values are the values of a string field, drawn uniformly, so repeating
+	// a value makes it more likely. A boolean field has none.
+	Values []string
+	// TrueChance is the chance that a boolean field draws true
+	TrueChance float64
+	// ChangeChance is the chance at each point that the field draws a new
+	// value, 1 drawing one for every point
+	ChangeChance float64
+}
+
+func (f *TypedField) draw(r *rand.Rand) interface{} {
+	if len(f.Values) == 0 {
+		return r.Float64() < f.TrueChance
+	}
+	return f.Values[r.Intn(len(f.Values))]
+}
+
+// TypedFieldsSimulatorConfig creates the Simulators of a SimulatorConfig with
+// string and boolean fields added to their measurements.
+type TypedFieldsSimulatorConfig struct {
+	SimulatorConfig
+	// Fields are the fields to add, by measurement name
+	Fields map[string][]TypedField
+}
+
+// NewSimulator produces a Simulator of the wrapped config, with the fields.
+func (c *TypedFieldsSimulatorConfig) NewSimulator(r *rand.Rand, interval time.Duration, limit uint64) Simulator {
+	return NewTypedFieldsSimulator(r, c.SimulatorConfig.NewSimulator(r, interval, limit), c.Fields)
+}
+
+// NewTypedFieldsSimulator wraps a Simulator to add the fields, by measurement
+// name, to its points, drawing their values from r.
+func NewTypedFieldsSimulator(r *rand.Rand, sim Simulator, fields map[string][]TypedField) Simulator {
+	return &typedFieldsSimulator{
+		Simulator: sim,
+		rng:       r,
+		fields:    fields,
+		series:    make(map[string][]interface{}),
+	}
+}
+
+type typedFieldsSimulator struct {
+	Simulator
+	rng    *rand.Rand
+	fields map[string][]TypedField
+	// series holds the values of the fields of each series, by measurement
+	// name and tag values
+	series map[string][]interface{}
+	key    []byte
+}
+
+// Next populates the point with the next point of the wrapped Simulator, and
+// the fields of its measurement.
+func (s *typedFieldsSimulator) Next(p *data.Point) bool {
+	if !s.Simulator.Next(p) {
+		return false
+	}
+	fields, ok := s.fields[string(p.MeasurementName())]
+	if !ok {
+		return true
+	}
+
+	s.key = append(s.key[:0], p.MeasurementName()...)
+	for _, v := range p.TagValues() {
+		s.key = append(s.key, ',')
+		switch v := v.(type) {
+		case string:
+			s.key = append(s.key, v...)
+		case []byte:
+			s.key = append(s.key, v...)
+		case nil:
+		default:
+			s.key = append(s.key, fmt.Sprint(v)...)
+		}
+	}
+	values, ok := s.series[string(s.key)]
+	if !ok {
+		values = make([]interface{}, len(fields))
+		for i := range fields {
+			values[i] = fields[i].draw(s.rng)
+		}
+		s.series[string(s.key)] = values
+	} else {
+		for i := range fields {
+			if s.rng.Float64() < fields[i].ChangeChance {
+				values[i] = fields[i].draw(s.rng)
+			}
+		}
+	}
+
+	for i := range fields {
+		p.AppendField(fields[i].Label, values[i])
+	}
+	return true
+}
+
+// Fields returns the fields of the wrapped Simulator, with the added fields.
+func (s *typedFieldsSimulator) Fields() map[string][]string {
+	fields := make(map[string][]string)
+	for measurement, keys := range s.Simulator.Fields() {
+		keys = append([]string{}, keys...)
+		for _, f := range s.fields[measurement] {
+			keys = append(keys, string(f.Label))
+		}
+		fields[measurement] = keys
+	}
+	return fields
+}
+
+// Headers returns the headers of the wrapped Simulator, with the added fields.
+func (s *typedFieldsSimulator) Headers() *GeneratedDataHeaders {
+	headers := s.Simulator.Headers()
+	return &GeneratedDataHeaders{
+		TagTypes:  headers.TagTypes,
+		TagKeys:   headers.TagKeys,
+		FieldKeys: s.Fields(),
+	}
+}
+
+// Err returns the error of the wrapped Simulator, if it reports one.
+func (s *typedFieldsSimulator) Err() error {
+	switch sim := s.Simulator.(type) {
+	case interface{ Err() error }:
+		return sim.Err()
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/timescale/tsbs/pkg/data"
+)
+
+var (
+	testStringField = TypedField{Label: []byte("level"), Values: []string{"INFO", "WARN"}, ChangeChance: 1}
+	testFixedField  = TypedField{Label: []byte("version"), Values: []string{"1.0", "2.0", "3.0"}}
+	testBoolField   = TypedField{Label: []byte("alarm"), TrueChance: 1, ChangeChance: 1}
+)
+
+// seriesSimulator emits n points of two series, the tag series numbering them
+type seriesSimulator struct {
+	sequenceSimulator
+}
+
+func (s *seriesSimulator) Next(p *data.Point) bool {
+	p.AppendTag([]byte("series"), string(rune('a'+s.made%2)))
+	return s.sequenceSimulator.Next(p)
+}
+
+func TestTypedFieldsSimulator(t *testing.T) {
+	Seed(123)
+	fields := map[string][]TypedField{
+		string(dummyMeasurementName): {testStringField, testFixedField, testBoolField},
+	}
+	sim := NewTypedFieldsSimulator(Rand(), &seriesSimulator{sequenceSimulator{n: 100}}, fields)
+	versions := make(map[string]string)
+	levels := make(map[interface{}]bool)
+	p := data.NewPoint()
+	for !sim.Finished() {
+		if !sim.Next(p) {
+			t.Fatalf("point not written")
+		}
+		keys := p.FieldKeys()
+		if len(keys) != 4 || string(keys[0]) != "i" || string(keys[1]) != "level" || string(keys[3]) != "alarm" {
+			t.Fatalf("incorrect fields: %q", keys)
+		}
+		series := p.GetTagValue([]byte("series")).(string)
+		version := p.GetFieldValue([]byte("version")).(string)
+		if v, ok := versions[series]; ok && v != version {
+			t.Errorf("series %s: version changed without a change chance: %s then %s", series, v, version)
+		}
+		versions[series] = version
+		levels[p.GetFieldValue([]byte("level"))] = true
+		if alarm := p.GetFieldValue([]byte("alarm")); alarm != true {
+			t.Errorf("incorrect boolean with a true chance of 1: %v", alarm)
+		}
+		p.Reset()
+	}
+	if len(versions) != 2 {
+		t.Errorf("incorrect number of series: %d", len(versions))
+	}
+	if !levels["INFO"] || !levels["WARN"] || len(levels) != 2 {
+		t.Errorf("incorrect string values drawn: %v", levels)
+	}
+}
@@ -0,0 +1,254 @@
+package custom
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+	"gopkg.in/yaml.v2"
+)
+
+// Distribution types of a DistributionConfig
+const (
+	DistributionND    = "ND"
+	DistributionUD    = "UD"
+	DistributionWD    = "WD"
+	DistributionCWD   = "CWD"
+	DistributionMWD   = "MWD"
+	DistributionLD    = "LD"
+	DistributionConst = "CONST"
+)
+
+// Field types of a FieldConfig
+const (
+	FieldTypeFloat = "float"
+	FieldTypeInt   = "int"
+)
+
+// Schema declares a use case: the tags of every simulated device, and the
+// measurements it reports.
+type Schema struct {
+	Tags         []TagConfig         `yaml:"tags"`
+	Measurements []MeasurementConfig `yaml:"measurements"`
+}
+
+// TagConfig declares a tag of the devices. A device takes a random value of
+// Values, or of the Cardinality values Format makes of 0 to Cardinality-1.
+// Without either, the value is Format of the device number, which tells
+// devices apart. Format defaults to "<name>_%d".
+type TagConfig struct {
+	Name        string   `yaml:"name"`
+	Values      []string `yaml:"values"`
+	Cardinality int      `yaml:"cardinality"`
+	Format      string   `yaml:"format"`
+}
+
+// MeasurementConfig declares a measurement and its fields. A measurement
+// with an Interval reports once per Interval, which must be a multiple of the
+// log interval; otherwise it reports every log interval.
+type MeasurementConfig struct {
+	Name     string        `yaml:"name"`
+	Interval time.Duration `yaml:"interval"`
+	Fields   []FieldConfig `yaml:"fields"`
+}
+
+// FieldConfig declares a field of a measurement, whose values follow the
+// distribution. Precision, if set, is the number of decimals of a float
+// field.
+type FieldConfig struct {
+	Name         string             `yaml:"name"`
+	Type         string             `yaml:"type"`
+	Precision    *int               `yaml:"precision"`
+	Distribution DistributionConfig `yaml:"distribution"`
+}
+
+// DistributionConfig declares a common.Distribution. Each type uses its own
+// parameters:
+//
+//	ND: mean, stddev
+//	UD: low, high
+//	WD: step, state
+//	CWD: step, min, max, state (random between min and max if not set)
+//	MWD: step, state
+//	LD: motive, step, threshold
+//	CONST: state
+type DistributionConfig struct {
+	Type      string              `yaml:"type"`
+	Mean      float64             `yaml:"mean"`
+	StdDev    float64             `yaml:"stddev"`
+	Low       float64             `yaml:"low"`
+	High      float64             `yaml:"high"`
+	Min       float64             `yaml:"min"`
+	Max       float64             `yaml:"max"`
+	Threshold float64             `yaml:"threshold"`
+	State     *float64            `yaml:"state"`
+	Step      *DistributionConfig `yaml:"step"`
+	Motive    *DistributionConfig `yaml:"motive"`
+}
+
+// LoadSchema reads and validates the schema of a YAML file
+func LoadSchema(fileName string) (*Schema, error) {
+	b, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read schema: %v", err)
+	}
+	var s Schema
+	if err := yaml.UnmarshalStrict(b, &s); err != nil {
+		return nil, fmt.Errorf("cannot parse schema %s: %v", fileName, err)
+	}
+	if err := s.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid schema %s: %v", fileName, err)
+	}
+	return &s, nil
+}
+
+// Validate checks that the schema declares at least one measurement, and
+// that names, tags and distributions are valid.
+func (s *Schema) Validate() error {
+	if len(s.Measurements) == 0 {
+		return fmt.Errorf("no measurements")
+	}
+	tagNames := make(map[string]bool)
+	for i := range s.Tags {
+		t := &s.Tags[i]
+		if t.Name == "" {
+			return fmt.Errorf("tag %d has no name", i)
+		}
+		if tagNames[t.Name] {
+			return fmt.Errorf("duplicate tag %s", t.Name)
+		}
+		tagNames[t.Name] = true
+		if t.Cardinality < 0 {
+			return fmt.Errorf("tag %s: negative cardinality", t.Name)
+		}
+		if len(t.Values) > 0 && (t.Cardinality > 0 || t.Format != "") {
+			return fmt.Errorf("tag %s: values cannot be combined with cardinality or format", t.Name)
+		}
+		if t.Format != "" && strings.Count(t.Format, "%d") != 1 {
+			return fmt.Errorf("tag %s: format must contain %%d once", t.Name)
+		}
+	}
+
+	names := make(map[string]bool)
+	for i := range s.Measurements {
+		m := &s.Measurements[i]
+		if m.Name == "" {
+			return fmt.Errorf("measurement %d has no name", i)
+		}
+		if names[m.Name] {
+			return fmt.Errorf("duplicate measurement %s", m.Name)
+		}
+		names[m.Name] = true
+		if m.Interval < 0 {
+			return fmt.Errorf("measurement %s: negative interval", m.Name)
+		}
+		if len(m.Fields) == 0 {
+			return fmt.Errorf("measurement %s has no fields", m.Name)
+		}
+		fieldNames := make(map[string]bool)
+		for _, f := range m.Fields {
+			if f.Name == "" {
+				return fmt.Errorf("measurement %s: field with no name", m.Name)
+			}
+			if fieldNames[f.Name] {
+				return fmt.Errorf("measurement %s: duplicate field %s", m.Name, f.Name)
+			}
+			fieldNames[f.Name] = true
+			switch f.Type {
+			case "", FieldTypeFloat, FieldTypeInt:
+			default:
+				return fmt.Errorf("measurement %s, field %s: unknown type '%s'", m.Name, f.Name, f.Type)
+			}
+			if f.Precision != nil && *f.Precision < 0 {
+				return fmt.Errorf("measurement %s, field %s: negative precision", m.Name, f.Name)
+			}
+			if err := f.Distribution.validate(); err != nil {
+				return fmt.Errorf("measurement %s, field %s: %v", m.Name, f.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// CheckInterval checks that the interval of every measurement is a multiple
+// of the log interval
+func (s *Schema) CheckInterval(logInterval time.Duration) error {
+	for _, m := range s.Measurements {
+		if m.Interval > 0 && m.Interval%logInterval != 0 {
+			return fmt.Errorf("interval %v of measurement %s is not a multiple of the log interval %v", m.Interval, m.Name, logInterval)
+		}
+	}
+	return nil
+}
+
+func (c *DistributionConfig) validate() error {
+	requireStep := func() error {
+		if c.Step == nil {
+			return fmt.Errorf("%s distribution requires a step distribution", c.Type)
+		}
+		return c.Step.validate()
+	}
+	switch strings.ToUpper(c.Type) {
+	case DistributionND:
+		if c.StdDev < 0 {
+			return fmt.Errorf("ND distribution with negative stddev")
+		}
+	case DistributionUD:
+		if c.Low > c.High {
+			return fmt.Errorf("UD distribution with low > high")
+		}
+	case DistributionWD, DistributionMWD:
+		return requireStep()
+	case DistributionCWD:
+		if c.Min > c.Max {
+			return fmt.Errorf("CWD distribution with min > max")
+		}
+		return requireStep()
+	case DistributionLD:
+		if c.Motive == nil {
+			return fmt.Errorf("LD distribution requires a motive distribution")
+		}
+		if err := c.Motive.validate(); err != nil {
+			return err
+		}
+		return requireStep()
+	case DistributionConst:
+	case "":
+		return fmt.Errorf("distribution has no type")
+	default:
+		return fmt.Errorf("unknown distribution type '%s'", c.Type)
+	}
+	return nil
+}
+
+// state returns the initial state of a stateful distribution, or def
+func (c *DistributionConfig) state(def float64) float64 {
+	if c.State != nil {
+		return *c.State
+	}
+	return def
+}
+
+// newDistribution creates the distribution of a device, drawing from r, the
+// config is valid
+func (c *DistributionConfig) newDistribution(r *rand.Rand) common.Distribution {
+	switch strings.ToUpper(c.Type) {
+	case DistributionND:
+		return common.ND(r, c.Mean, c.StdDev)
+	case DistributionUD:
+		return common.UD(r, c.Low, c.High)
+	case DistributionWD:
+		return common.WD(c.Step.newDistribution(r), c.state(0))
+	case DistributionCWD:
+		return common.CWD(c.Step.newDistribution(r), c.Min, c.Max, c.state(c.Min+r.Float64()*(c.Max-c.Min)))
+	case DistributionMWD:
+		return common.MWD(c.Step.newDistribution(r), c.state(0))
+	case DistributionLD:
+		return common.LD(c.Motive.newDistribution(r), c.Step.newDistribution(r), c.Threshold)
+	default:
+		return &common.ConstantDistribution{State: c.state(0)}
+	}
+}
@@ -0,0 +1,201 @@
+package custom
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
+const testSchema = `
+tags:
+  - name: sensor
+  - name: plant
+    values: [berlin, shanghai]
+  - name: line
+    cardinality: 3
+    format: line-%d
+measurements:
+  - name: environment
+    fields:
+      - name: temperature
+        precision: 1
+        distribution:
+          type: CWD
+          step: {type: ND, mean: 0, stddev: 1}
+          min: 10
+          max: 20
+      - name: state
+        type: int
+        distribution: {type: const, state: 3}
+  - name: counter
+    interval: 30s
+    fields:
+      - name: parts
+        type: int
+        distribution:
+          type: MWD
+          step: {type: UD, low: 1, high: 2}
+          state: 100
+`
+
+func writeSchema(t *testing.T, content string) (string, func()) {
+	dir, err := ioutil.TempDir("", "schema")
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := filepath.Join(dir, "schema.yaml")
+	if err := ioutil.WriteFile(name, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return name, func() { os.RemoveAll(dir) }
+}
+
+func TestLoadSchema(t *testing.T) {
+	name, cleanup := writeSchema(t, testSchema)
+	defer cleanup()
+	s, err := LoadSchema(name)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(s.Tags) != 3 || len(s.Measurements) != 2 {
+		t.Fatalf("incorrect schema: %+v", s)
+	}
+	if got := s.Measurements[1].Interval; got != 30*time.Second {
+		t.Errorf("incorrect interval: got %v want 30s", got)
+	}
+	if err := s.CheckInterval(10 * time.Second); err != nil {
+		t.Errorf("unexpected error for interval multiple: %v", err)
+	}
+	if err := s.CheckInterval(20 * time.Second); err == nil {
+		t.Errorf("unexpected lack of error for interval not a multiple")
+	}
+
+	if _, err := LoadSchema(name + ".missing"); err == nil {
+		t.Errorf("unexpected lack of error for missing file")
+	}
+	unknown, cleanup := writeSchema(t, "measurements:\n  - name: m\n    unit: celsius\n")
+	defer cleanup()
+	if _, err := LoadSchema(unknown); err == nil || !strings.Contains(err.Error(), "unit") {
+		t.Errorf("unexpected lack of error for unknown key: %v", err)
+	}
+}
+
+func TestSchemaValidate(t *testing.T) {
+	nd := DistributionConfig{Type: "ND", StdDev: 1}
+	field := FieldConfig{Name: "f", Distribution: nd}
+	valid := func() *Schema {
+		return &Schema{
+			Tags:         []TagConfig{{Name: "id"}},
+			Measurements: []MeasurementConfig{{Name: "m", Fields: []FieldConfig{field}}},
+		}
+	}
+	if err := valid().Validate(); err != nil {
+		t.Fatalf("unexpected error for valid schema: %v", err)
+	}
+	cases := []struct {
+		desc   string
+		modify func(s *Schema)
+	}{
+		{"no measurements", func(s *Schema) { s.Measurements = nil }},
+		{"tag without name", func(s *Schema) { s.Tags[0].Name = "" }},
+		{"duplicate tag", func(s *Schema) { s.Tags = append(s.Tags, TagConfig{Name: "id"}) }},
+		{"values and cardinality", func(s *Schema) { s.Tags[0].Values = []string{"a"}; s.Tags[0].Cardinality = 2 }},
+		{"format without verb", func(s *Schema) { s.Tags[0].Format = "id" }},
+		{"duplicate measurement", func(s *Schema) { s.Measurements = append(s.Measurements, s.Measurements[0]) }},
+		{"no fields", func(s *Schema) { s.Measurements[0].Fields = nil }},
+		{"duplicate field", func(s *Schema) { s.Measurements[0].Fields = []FieldConfig{field, field} }},
+		{"unknown field type", func(s *Schema) { s.Measurements[0].Fields[0].Type = "bool" }},
+		{"no distribution", func(s *Schema) { s.Measurements[0].Fields[0].Distribution = DistributionConfig{} }},
+		{"unknown distribution", func(s *Schema) { s.Measurements[0].Fields[0].Distribution.Type = "poisson" }},
+		{"walk without step", func(s *Schema) { s.Measurements[0].Fields[0].Distribution = DistributionConfig{Type: "CWD"} }},
+		{"invalid step", func(s *Schema) {
+			s.Measurements[0].Fields[0].Distribution = DistributionConfig{Type: "WD", Step: &DistributionConfig{Type: "UD", Low: 2, High: 1}}
+		}},
+		{"lazy without motive", func(s *Schema) {
+			s.Measurements[0].Fields[0].Distribution = DistributionConfig{Type: "LD", Step: &nd}
+		}},
+	}
+	for _, c := range cases {
+		s := valid()
+		c.modify(s)
+		if err := s.Validate(); err == nil {
+			t.Errorf("%s: unexpected lack of error", c.desc)
+		}
+	}
+}
+
+func TestCustomSimulator(t *testing.T) {
+	name, cleanup := writeSchema(t, testSchema)
+	defer cleanup()
+	s, err := LoadSchema(name)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	start := time.Date(2016, time.January, 1, 0, 0, 0, 0, time.UTC)
+	c := &SimulatorConfig{Schema: s, Start: start, End: start.Add(time.Minute), InitGeneratorScale: 2, GeneratorScale: 2}
+	sim := c.NewSimulator(common.Rand(), 10*time.Second, 0)
+
+	counts := map[string]int{}
+	p := data.NewPoint()
+	for !sim.Finished() {
+		if sim.Next(p) {
+			m := string(p.MeasurementName())
+			counts[m]++
+			tags := p.TagValues()
+			if len(tags) != 3 {
+				t.Fatalf("incorrect number of tags: %v", tags)
+			}
+			if plant := tags[1].(string); plant != "berlin" && plant != "shanghai" {
+				t.Errorf("tag value not of the pool: %s", plant)
+			}
+			if line := tags[2].(string); !strings.HasPrefix(line, "line-") {
+				t.Errorf("tag value not formatted: %s", line)
+			}
+			fields := p.FieldValues()
+			switch m {
+			case "environment":
+				if v := fields[0].(float64); v < 10 || v > 20 {
+					t.Errorf("clamped value out of range: %v", v)
+				}
+				if v := fields[1].(int64); v != 3 {
+					t.Errorf("incorrect constant: %v", v)
+				}
+			case "counter":
+				if v := fields[0].(int64); v < 100 {
+					t.Errorf("monotonic value below its state: %v", v)
+				}
+			}
+		}
+		p.Reset()
+	}
+	// 6 epochs of 2 devices, the counter every third epoch
+	if counts["environment"] != 12 || counts["counter"] != 4 {
+		t.Errorf("incorrect points per measurement: %v", counts)
+	}
+
+	headers := sim.Headers()
+	if strings.Join(headers.TagKeys, ",") != "sensor,plant,line" {
+		t.Errorf("incorrect tag keys: %v", headers.TagKeys)
+	}
+	if strings.Join(headers.FieldKeys["environment"], ",") != "temperature,state" {
+		t.Errorf("incorrect field keys: %v", headers.FieldKeys)
+	}
+}
+
+func TestTagValue(t *testing.T) {
+	if got := (&TagConfig{Name: "sensor"}).value(common.Rand(), 7); got != "sensor_7" {
+		t.Errorf("incorrect default tag value: got %s want sensor_7", got)
+	}
+	if got := (&TagConfig{Name: "id", Format: "dev-%d"}).value(common.Rand(), 7); got != "dev-7" {
+		t.Errorf("incorrect formatted tag value: got %s want dev-7", got)
+	}
+	if got := (&TagConfig{Name: "zone", Cardinality: 1}).value(common.Rand(), 7); got != "zone_0" {
+		t.Errorf("incorrect tag value with cardinality: got %s want zone_0", got)
+	}
+}
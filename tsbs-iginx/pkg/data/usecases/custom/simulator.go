@@ -0,0 +1,155 @@
+package custom
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
+// SimulatorConfig is used to create a simulator of the devices of a Schema.
+type SimulatorConfig struct {
+	Schema *Schema
+	// Start is the beginning time for the Simulator
+	Start time.Time
+	// End is the ending time for the Simulator
+	End time.Time
+	// InitGeneratorScale is the number of devices to start with in the first reporting period
+	InitGeneratorScale uint64
+	// GeneratorScale is the total number of devices to have in the last reporting period
+	GeneratorScale uint64
+}
+
+// NewSimulator produces a Simulator that conforms to the given config over the specified interval.
+func (c *SimulatorConfig) NewSimulator(r *rand.Rand, interval time.Duration, limit uint64) common.Simulator {
+	base := &common.BaseSimulatorConfig{
+		Start:                c.Start,
+		End:                  c.End,
+		InitGeneratorScale:   c.InitGeneratorScale,
+		GeneratorScale:       c.GeneratorScale,
+		GeneratorConstructor: c.Schema.NewDevice,
+	}
+	sim := base.NewSimulator(r, interval, limit)
+
+	intervals := make(map[string]time.Duration)
+	for _, m := range c.Schema.Measurements {
+		if m.Interval > interval {
+			intervals[m.Name] = m.Interval
+		}
+	}
+	if len(intervals) == 0 {
+		return sim
+	}
+	return &simulator{Simulator: sim, start: c.Start, intervals: intervals}
+}
+
+// simulator skips the points of measurements that report less often than
+// every log interval. Their distributions still advance every log interval.
+type simulator struct {
+	common.Simulator
+	start     time.Time
+	intervals map[string]time.Duration
+}
+
+func (s *simulator) Next(p *data.Point) bool {
+	write := s.Simulator.Next(p)
+	if interval, ok := s.intervals[string(p.MeasurementName())]; ok && p.Timestamp().Sub(s.start)%interval != 0 {
+		return false
+	}
+	return write
+}
+
+// device is a simulated entity with the tags and measurements of a Schema
+type device struct {
+	simulatedMeasurements []common.SimulatedMeasurement
+	tags                  []common.Tag
+}
+
+// NewDevice creates the i-th device of the schema
+func (s *Schema) NewDevice(r *rand.Rand, i int, start time.Time) common.Generator {
+	d := &device{
+		simulatedMeasurements: make([]common.SimulatedMeasurement, len(s.Measurements)),
+		tags:                  make([]common.Tag, len(s.Tags)),
+	}
+	for j, t := range s.Tags {
+		d.tags[j] = common.Tag{Key: []byte(t.Name), Value: t.value(r, i)}
+	}
+	for j := range s.Measurements {
+		d.simulatedMeasurements[j] = newMeasurement(r, &s.Measurements[j], start)
+	}
+	return d
+}
+
+// value returns the tag value of the i-th device, drawing from r
+func (t *TagConfig) value(r *rand.Rand, i int) string {
+	if len(t.Values) > 0 {
+		return common.RandomStringSliceChoice(r, t.Values)
+	}
+	format := t.Format
+	if format == "" {
+		format = strings.Replace(t.Name, "%", "%%", -1) + "_%d"
+	}
+	if t.Cardinality > 0 {
+		return fmt.Sprintf(format, r.Intn(t.Cardinality))
+	}
+	return fmt.Sprintf(format, i)
+}
+
+// TickAll advances all Distributions of a device.
+func (d *device) TickAll(dur time.Duration) {
+	for i := range d.simulatedMeasurements {
+		d.simulatedMeasurements[i].Tick(dur)
+	}
+}
+
+// Measurements returns the device measurements.
+func (d *device) Measurements() []common.SimulatedMeasurement {
+	return d.simulatedMeasurements
+}
+
+// Tags returns the device tags.
+func (d *device) Tags() []common.Tag {
+	return d.tags
+}
+
+// measurement simulates a measurement declared in a schema
+type measurement struct {
+	*common.SubsystemMeasurement
+	name   []byte
+	labels [][]byte
+	ints   []bool
+}
+
+func newMeasurement(r *rand.Rand, c *MeasurementConfig, start time.Time) *measurement {
+	m := &measurement{
+		SubsystemMeasurement: common.NewSubsystemMeasurement(start, len(c.Fields)),
+		name:                 []byte(c.Name),
+		labels:               make([][]byte, len(c.Fields)),
+		ints:                 make([]bool, len(c.Fields)),
+	}
+	for i, f := range c.Fields {
+		m.labels[i] = []byte(f.Name)
+		m.ints[i] = f.Type == FieldTypeInt
+		dist := f.Distribution.newDistribution(r)
+		if f.Precision != nil && !m.ints[i] {
+			dist = common.FP(dist, *f.Precision)
+		}
+		m.Distributions[i] = dist
+	}
+	return m
+}
+
+func (m *measurement) ToPoint(p *data.Point) {
+	p.SetMeasurementName(m.name)
+	p.SetTimestamp(&m.Timestamp)
+	for i, d := range m.Distributions {
+		if m.ints[i] {
+			p.AppendField(m.labels[i], int64(d.Get()))
+		} else {
+			p.AppendField(m.labels[i], d.Get())
+		}
+	}
+}
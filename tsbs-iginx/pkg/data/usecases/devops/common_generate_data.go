@@ -3,11 +3,14 @@ package devops
 import (
 	"github.com/timescale/tsbs/pkg/data"
 	"github.com/timescale/tsbs/pkg/data/usecases/common"
+	"math/rand"
 	"time"
 )
 
 // HostContext contains information needed to create a new host
 type HostContext struct {
+	// rng is the source of randomness of the simulator of the host
+	rng   *rand.Rand
 	id    int
 	start time.Time
 	// used for devops-generic use-case
@@ -28,14 +31,17 @@ type commonDevopsSimulatorConfig struct {
 	HostConstructor func(ctx *HostContext) Host
 	// MaxMetricCount is the max number of metrics per host to create when using generic-devops use-case
 	MaxMetricCount uint64
+	// ChurnRate is the chance at each epoch that a reporting host is
+	// decommissioned and replaced by a host with a new name
+	ChurnRate float64
 }
 
-func NewHostCtx(id int, start time.Time) *HostContext {
-	return &HostContext{id, start, 0, 0}
+func NewHostCtx(r *rand.Rand, id int, start time.Time) *HostContext {
+	return &HostContext{r, id, start, 0, 0}
 }
 
-func NewHostCtxTime(start time.Time) *HostContext {
-	return &HostContext{0, start, 0, 0}
+func NewHostCtxTime(r *rand.Rand, start time.Time) *HostContext {
+	return &HostContext{r, 0, start, 0, 0}
 }
 
 func calculateEpochs(c commonDevopsSimulatorConfig, interval time.Duration) uint64 {
@@ -43,6 +49,7 @@ func calculateEpochs(c commonDevopsSimulatorConfig, interval time.Duration) uint
 }
 
 type commonDevopsSimulator struct {
+	rng        *rand.Rand
 	madePoints uint64
 	maxPoints  uint64
 
@@ -57,6 +64,10 @@ type commonDevopsSimulator struct {
 	timestampStart time.Time
 	timestampEnd   time.Time
 	interval       time.Duration
+
+	churnRate       float64
+	nextHostID      int
+	hostConstructor func(ctx *HostContext) Host
 }
 
 // Finished tells whether we have simulated all the necessary points
@@ -147,4 +158,21 @@ func (s *commonDevopsSimulator) adjustNumHostsForEpoch() {
 	s.epoch++
 	missingScale := float64(uint64(len(s.hosts)) - s.initHosts)
 	s.epochHosts = s.initHosts + uint64(missingScale*float64(s.epoch)/float64(s.epochs-1))
+	s.churn()
+}
+
+// churn decommissions each reporting host with the chance of the churn rate,
+// and puts in its place a host with the next unused name, as container
+// workloads come and go.
+func (s *commonDevopsSimulator) churn() {
+	if s.churnRate == 0 {
+		return
+	}
+	now := s.timestampStart.Add(time.Duration(s.epoch) * s.interval)
+	for i := uint64(0); i < s.epochHosts && i < uint64(len(s.hosts)); i++ {
+		if s.rng.Float64() < s.churnRate {
+			s.hosts[i] = s.hostConstructor(NewHostCtx(s.rng, s.nextHostID, now))
+			s.nextHostID++
+		}
+	}
 }
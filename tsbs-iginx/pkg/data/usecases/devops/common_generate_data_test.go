@@ -126,7 +126,7 @@ func TestCommonDevopsSimulatorFinished(t *testing.T) {
 func TestCommonDevopsSimulatorFields(t *testing.T) {
 	s := &commonDevopsSimulator{}
 	host := Host{}
-	host.SimulatedMeasurements = []common.SimulatedMeasurement{NewCPUMeasurement(time.Now())}
+	host.SimulatedMeasurements = []common.SimulatedMeasurement{NewCPUMeasurement(common.Rand(), time.Now())}
 	s.hosts = append(s.hosts, host)
 	fields := s.Fields()
 	if got := len(fields); got != 1 {
@@ -144,7 +144,7 @@ func TestCommonDevopsSimulatorFields(t *testing.T) {
 	// because we assume each Host has the same set of simulated measurements.
 	// TODO - Examine whether this assumption should be refined.
 	host = Host{}
-	host.SimulatedMeasurements = []common.SimulatedMeasurement{NewMemMeasurement(time.Now())}
+	host.SimulatedMeasurements = []common.SimulatedMeasurement{NewMemMeasurement(common.Rand(), time.Now())}
 	s.hosts = append(s.hosts, host)
 	fields = s.Fields()
 	if got := len(fields); got != 1 {
@@ -153,7 +153,7 @@ func TestCommonDevopsSimulatorFields(t *testing.T) {
 
 	// Add new measurement, this should change the result.
 	host = s.hosts[0]
-	host.SimulatedMeasurements = append(host.SimulatedMeasurements, NewMemMeasurement(time.Now()))
+	host.SimulatedMeasurements = append(host.SimulatedMeasurements, NewMemMeasurement(common.Rand(), time.Now()))
 	s.hosts[0] = host
 	fields = s.Fields()
 	if got := len(fields); got != 2 {
@@ -194,7 +194,7 @@ func TestCommonDevopsSimulatorPopulatePoint(t *testing.T) {
 			ServiceVersion:     sprintf("%s%d", prefix[8], i),
 			ServiceEnvironment: sprintf("%s%d", prefix[9], i),
 		}
-		host.SimulatedMeasurements = []common.SimulatedMeasurement{NewCPUMeasurement(time.Now())}
+		host.SimulatedMeasurements = []common.SimulatedMeasurement{NewCPUMeasurement(common.Rand(), time.Now())}
 		s.hosts = append(s.hosts, host)
 	}
 	s.hostIndex = 0
@@ -10,38 +10,38 @@ import (
 var (
 	labelCPU  = []byte("cpu") // heap optimization
 	cpuFields = []common.LabeledDistributionMaker{
-		{Label: []byte("usage_user"), DistributionMaker: func() common.Distribution { return common.CWD(cpuND, 0.0, 100.0, rand.Float64()*100.0) }},
-		{Label: []byte("usage_system"), DistributionMaker: func() common.Distribution { return common.CWD(cpuND, 0.0, 100.0, rand.Float64()*100.0) }},
-		{Label: []byte("usage_idle"), DistributionMaker: func() common.Distribution { return common.CWD(cpuND, 0.0, 100.0, rand.Float64()*100.0) }},
-		{Label: []byte("usage_nice"), DistributionMaker: func() common.Distribution { return common.CWD(cpuND, 0.0, 100.0, rand.Float64()*100.0) }},
-		{Label: []byte("usage_iowait"), DistributionMaker: func() common.Distribution { return common.CWD(cpuND, 0.0, 100.0, rand.Float64()*100.0) }},
-		{Label: []byte("usage_irq"), DistributionMaker: func() common.Distribution { return common.CWD(cpuND, 0.0, 100.0, rand.Float64()*100.0) }},
-		{Label: []byte("usage_softirq"), DistributionMaker: func() common.Distribution { return common.CWD(cpuND, 0.0, 100.0, rand.Float64()*100.0) }},
-		{Label: []byte("usage_steal"), DistributionMaker: func() common.Distribution { return common.CWD(cpuND, 0.0, 100.0, rand.Float64()*100.0) }},
-		{Label: []byte("usage_guest"), DistributionMaker: func() common.Distribution { return common.CWD(cpuND, 0.0, 100.0, rand.Float64()*100.0) }},
-		{Label: []byte("usage_guest_nice"), DistributionMaker: func() common.Distribution { return common.CWD(cpuND, 0.0, 100.0, rand.Float64()*100.0) }},
+		{Label: []byte("usage_user"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.CWD(cpuND(r), 0.0, 100.0, r.Float64()*100.0) }},
+		{Label: []byte("usage_system"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.CWD(cpuND(r), 0.0, 100.0, r.Float64()*100.0) }},
+		{Label: []byte("usage_idle"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.CWD(cpuND(r), 0.0, 100.0, r.Float64()*100.0) }},
+		{Label: []byte("usage_nice"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.CWD(cpuND(r), 0.0, 100.0, r.Float64()*100.0) }},
+		{Label: []byte("usage_iowait"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.CWD(cpuND(r), 0.0, 100.0, r.Float64()*100.0) }},
+		{Label: []byte("usage_irq"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.CWD(cpuND(r), 0.0, 100.0, r.Float64()*100.0) }},
+		{Label: []byte("usage_softirq"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.CWD(cpuND(r), 0.0, 100.0, r.Float64()*100.0) }},
+		{Label: []byte("usage_steal"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.CWD(cpuND(r), 0.0, 100.0, r.Float64()*100.0) }},
+		{Label: []byte("usage_guest"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.CWD(cpuND(r), 0.0, 100.0, r.Float64()*100.0) }},
+		{Label: []byte("usage_guest_nice"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.CWD(cpuND(r), 0.0, 100.0, r.Float64()*100.0) }},
 	}
 )
 
-// Reuse NormalDistributions as arguments to other distributions. This is
-// safe to do because the higher-level distribution advances the ND and
-// immediately uses its value and saves the state
-var cpuND = common.ND(0.0, 1.0)
+// Each distribution steps with an ND of its own, drawing from the source of
+// randomness of its simulator. The higher-level distribution advances the ND
+// and immediately uses its value.
+func cpuND(r *rand.Rand) common.Distribution { return common.ND(r, 0.0, 1.0) }
 
 type CPUMeasurement struct {
 	*common.SubsystemMeasurement
 }
 
-func NewCPUMeasurement(start time.Time) *CPUMeasurement {
-	return newCPUMeasurementNumDistributions(start, len(cpuFields))
+func NewCPUMeasurement(r *rand.Rand, start time.Time) *CPUMeasurement {
+	return newCPUMeasurementNumDistributions(r, start, len(cpuFields))
 }
 
-func newSingleCPUMeasurement(start time.Time) *CPUMeasurement {
-	return newCPUMeasurementNumDistributions(start, 1)
+func newSingleCPUMeasurement(r *rand.Rand, start time.Time) *CPUMeasurement {
+	return newCPUMeasurementNumDistributions(r, start, 1)
 }
 
-func newCPUMeasurementNumDistributions(start time.Time, numDistributions int) *CPUMeasurement {
-	sub := common.NewSubsystemMeasurementWithDistributionMakers(start, cpuFields[:numDistributions])
+func newCPUMeasurementNumDistributions(r *rand.Rand, start time.Time, numDistributions int) *CPUMeasurement {
+	sub := common.NewSubsystemMeasurementWithDistributionMakers(r, start, cpuFields[:numDistributions])
 	return &CPUMeasurement{sub}
 }
 
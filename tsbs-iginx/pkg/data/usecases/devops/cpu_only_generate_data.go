@@ -3,6 +3,7 @@ package devops
 import (
 	"github.com/timescale/tsbs/pkg/data"
 	"github.com/timescale/tsbs/pkg/data/usecases/common"
+	"math/rand"
 	"time"
 )
 
@@ -45,10 +46,10 @@ func (d *CPUOnlySimulator) Next(p *data.Point) bool {
 type CPUOnlySimulatorConfig commonDevopsSimulatorConfig
 
 // NewSimulator produces a Simulator that conforms to the given SimulatorConfig over the specified interval
-func (c *CPUOnlySimulatorConfig) NewSimulator(interval time.Duration, limit uint64) common.Simulator {
+func (c *CPUOnlySimulatorConfig) NewSimulator(r *rand.Rand, interval time.Duration, limit uint64) common.Simulator {
 	hostInfos := make([]Host, c.HostCount)
 	for i := 0; i < len(hostInfos); i++ {
-		hostInfos[i] = c.HostConstructor(NewHostCtx(i, c.Start))
+		hostInfos[i] = c.HostConstructor(NewHostCtx(r, i, c.Start))
 	}
 
 	epochs := calculateEpochs(commonDevopsSimulatorConfig(*c), interval)
@@ -58,6 +59,7 @@ func (c *CPUOnlySimulatorConfig) NewSimulator(interval time.Duration, limit uint
 		maxPoints = limit
 	}
 	sim := &CPUOnlySimulator{&commonDevopsSimulator{
+		rng:        r,
 		madePoints: 0,
 		maxPoints:  maxPoints,
 
@@ -71,6 +73,10 @@ func (c *CPUOnlySimulatorConfig) NewSimulator(interval time.Duration, limit uint
 		timestampStart: c.Start,
 		timestampEnd:   c.End,
 		interval:       interval,
+
+		churnRate:       c.ChurnRate,
+		nextHostID:      len(hostInfos),
+		hostConstructor: c.HostConstructor,
 	}}
 
 	return sim
@@ -1,7 +1,9 @@
 package devops
 
 import (
+	"fmt"
 	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
 	"testing"
 	"time"
 )
@@ -18,7 +20,7 @@ var (
 )
 
 func TestCPUOnlySimulatorFields(t *testing.T) {
-	s := testCPUOnlyConf.NewSimulator(time.Second, 0).(*CPUOnlySimulator)
+	s := testCPUOnlyConf.NewSimulator(common.Rand(), time.Second, 0).(*CPUOnlySimulator)
 	fields := s.Fields()
 	if got := len(fields); got != 1 {
 		t.Errorf("fields length does not equal 1: got %d", got)
@@ -44,7 +46,7 @@ func TestCPUOnlySimulatorFields(t *testing.T) {
 }
 
 func TestCPUOnlySimulatorNext(t *testing.T) {
-	s := testCPUOnlyConf.NewSimulator(time.Second, 0).(*CPUOnlySimulator)
+	s := testCPUOnlyConf.NewSimulator(common.Rand(), time.Second, 0).(*CPUOnlySimulator)
 	// There are two epochs for the test configuration, and a difference of 90
 	// from init to final, so each epoch should add 45 devices to be written.
 	writtenIdx := []int{10, 55, 100}
@@ -89,7 +91,7 @@ func TestCPUOnlySimulatorConfigNewSimulator(t *testing.T) {
 		HostCount:       numHosts,
 		HostConstructor: NewHostCPUOnly,
 	}
-	sim := conf.NewSimulator(duration, 0).(*CPUOnlySimulator)
+	sim := conf.NewSimulator(common.Rand(), duration, 0).(*CPUOnlySimulator)
 	if got := sim.madePoints; got != 0 {
 		t.Errorf("incorrect initial points: got %d want %d", got, 0)
 	}
@@ -120,3 +122,33 @@ func TestCPUOnlySimulatorConfigNewSimulator(t *testing.T) {
 		t.Errorf("incorrect max points: got %d want %d", got, wantMaxPoints)
 	}
 }
+
+func TestCPUOnlySimulatorChurn(t *testing.T) {
+	start := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	conf := &CPUOnlySimulatorConfig{
+		Start:           start,
+		End:             start.Add(3 * time.Second),
+		InitHostCount:   10,
+		HostCount:       10,
+		HostConstructor: NewHostCPUOnly,
+		ChurnRate:       1,
+	}
+	s := conf.NewSimulator(common.Rand(), time.Second, 0).(*CPUOnlySimulator)
+	p := data.NewPoint()
+	for epoch := 0; epoch < 3; epoch++ {
+		for i := 0; i < 10; i++ {
+			if !s.Next(p) {
+				t.Fatalf("epoch %d: point of host %d not written", epoch, i)
+			}
+			// every host is replaced at each epoch, by the next unused name
+			want := fmt.Sprintf(hostFmt, epoch*10+i)
+			if got := p.GetTagValue(MachineTagKeys[0]); got != want {
+				t.Errorf("epoch %d: incorrect host name: got %v want %s", epoch, got, want)
+			}
+			if got, want := *p.Timestamp(), start.Add(time.Duration(epoch)*time.Second); !got.Equal(want) {
+				t.Errorf("epoch %d: incorrect timestamp: got %v want %v", epoch, got, want)
+			}
+			p.Reset()
+		}
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"github.com/timescale/tsbs/pkg/data"
 	"github.com/timescale/tsbs/pkg/data/usecases/common"
-	"math/rand"
 	"testing"
 	"time"
 )
@@ -33,7 +32,7 @@ func testDistributionsAreDifferent(oldVals map[string]float64, m *common.Subsyst
 
 func TestCPUMeasurementTick(t *testing.T) {
 	now := time.Now()
-	m := NewCPUMeasurement(now)
+	m := NewCPUMeasurement(common.Rand(), now)
 	duration := time.Second
 	oldVals := map[string]float64{}
 	fields := ldmToFieldLabels(cpuFields)
@@ -41,7 +40,7 @@ func TestCPUMeasurementTick(t *testing.T) {
 		oldVals[string(ldm.Label)] = m.Distributions[i].Get()
 	}
 
-	rand.Seed(123)
+	common.Seed(123)
 	m.Tick(duration)
 	err := testDistributionsAreDifferent(oldVals, m.SubsystemMeasurement, fields)
 	if err != nil {
@@ -56,7 +55,7 @@ func TestCPUMeasurementTick(t *testing.T) {
 
 func TestCPUMeasurementToPoint(t *testing.T) {
 	now := time.Now()
-	m := NewCPUMeasurement(now)
+	m := NewCPUMeasurement(common.Rand(), now)
 	duration := time.Second
 	m.Tick(duration)
 
@@ -75,7 +74,7 @@ func TestCPUMeasurementToPoint(t *testing.T) {
 
 func TestSingleCPUMeasurementTick(t *testing.T) {
 	now := time.Now()
-	m := newSingleCPUMeasurement(now)
+	m := newSingleCPUMeasurement(common.Rand(), now)
 	duration := time.Second
 	oldVals := map[string]float64{}
 	fields := ldmToFieldLabels(cpuFields[:1]) // only the first field in this use case
@@ -86,7 +85,7 @@ func TestSingleCPUMeasurementTick(t *testing.T) {
 		oldVals[string(f)] = m.Distributions[i].Get()
 	}
 
-	rand.Seed(123)
+	common.Seed(123)
 	m.Tick(duration)
 	err := testDistributionsAreDifferent(oldVals, m.SubsystemMeasurement, fields)
 	if err != nil {
@@ -101,7 +100,7 @@ func TestSingleCPUMeasurementTick(t *testing.T) {
 
 func TestSingleCPUMeasurementToPoint(t *testing.T) {
 	now := time.Now()
-	m := newSingleCPUMeasurement(now)
+	m := newSingleCPUMeasurement(common.Rand(), now)
 	duration := time.Second
 	fields := cpuFields[:1] // only the first field in this use case
 	m.Tick(duration)
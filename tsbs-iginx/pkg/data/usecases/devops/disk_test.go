@@ -3,7 +3,7 @@ package devops
 import (
 	"bytes"
 	"github.com/timescale/tsbs/pkg/data"
-	"math/rand"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
 	"testing"
 	"time"
 )
@@ -28,7 +28,7 @@ func testIfInStringSlice(t *testing.T, arr []string, choice string) {
 
 func TestDiskMeasurementTick(t *testing.T) {
 	now := time.Now()
-	m := NewDiskMeasurement(now)
+	m := NewDiskMeasurement(common.Rand(), now)
 	origPath := string(m.path)
 	origFS := string(m.fsType)
 	duration := time.Second
@@ -38,7 +38,7 @@ func TestDiskMeasurementTick(t *testing.T) {
 		oldVals[string(f)] = m.Distributions[i].Get()
 	}
 
-	rand.Seed(123)
+	common.Seed(123)
 	m.Tick(duration)
 	err := testDistributionsAreDifferent(oldVals, m.SubsystemMeasurement, fields)
 	if err != nil {
@@ -66,7 +66,7 @@ func TestDiskMeasurementTick(t *testing.T) {
 
 func TestDiskMeasurementToPoint(t *testing.T) {
 	now := time.Now()
-	m := NewDiskMeasurement(now)
+	m := NewDiskMeasurement(common.Rand(), now)
 	origPath := m.path
 	origFS := m.fsType
 	testIfInStringSlice(t, diskFSTypeChoices, m.fsType)
@@ -16,32 +16,32 @@ var (
 	labelDiskIO       = []byte("diskio") // heap optimization
 	labelDiskIOSerial = []byte("serial")
 
-	// Reuse NormalDistributions as arguments to other distributions. This is
-	// safe to do because the higher-level distribution advances the ND and
-	// immediately uses its value and saves the state
-	opsND   = common.ND(50, 1)
-	bytesND = common.ND(100, 1)
-	timeND  = common.ND(5, 1)
-
 	diskIOFields = []common.LabeledDistributionMaker{
-		{Label: []byte("reads"), DistributionMaker: func() common.Distribution { return common.MWD(opsND, 0) }},
-		{Label: []byte("writes"), DistributionMaker: func() common.Distribution { return common.MWD(opsND, 0) }},
-		{Label: []byte("read_bytes"), DistributionMaker: func() common.Distribution { return common.MWD(bytesND, 0) }},
-		{Label: []byte("write_bytes"), DistributionMaker: func() common.Distribution { return common.MWD(bytesND, 0) }},
-		{Label: []byte("read_time"), DistributionMaker: func() common.Distribution { return common.MWD(timeND, 0) }},
-		{Label: []byte("write_time"), DistributionMaker: func() common.Distribution { return common.MWD(timeND, 0) }},
-		{Label: []byte("io_time"), DistributionMaker: func() common.Distribution { return common.MWD(timeND, 0) }},
+		{Label: []byte("reads"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.MWD(opsND(r), 0) }},
+		{Label: []byte("writes"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.MWD(opsND(r), 0) }},
+		{Label: []byte("read_bytes"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.MWD(bytesND(r), 0) }},
+		{Label: []byte("write_bytes"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.MWD(bytesND(r), 0) }},
+		{Label: []byte("read_time"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.MWD(timeND(r), 0) }},
+		{Label: []byte("write_time"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.MWD(timeND(r), 0) }},
+		{Label: []byte("io_time"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.MWD(timeND(r), 0) }},
 	}
 )
 
+// Each distribution steps with an ND of its own, drawing from the source of
+// randomness of its simulator. The higher-level distribution advances the ND
+// and immediately uses its value.
+func opsND(r *rand.Rand) common.Distribution   { return common.ND(r, 50, 1) }
+func bytesND(r *rand.Rand) common.Distribution { return common.ND(r, 100, 1) }
+func timeND(r *rand.Rand) common.Distribution  { return common.ND(r, 5, 1) }
+
 type DiskIOMeasurement struct {
 	*common.SubsystemMeasurement
 	serial string
 }
 
-func NewDiskIOMeasurement(start time.Time) *DiskIOMeasurement {
-	sub := common.NewSubsystemMeasurementWithDistributionMakers(start, diskIOFields)
-	serial := fmt.Sprintf(diskSerialFmt, rand.Intn(1000), rand.Intn(1000), rand.Intn(1000))
+func NewDiskIOMeasurement(r *rand.Rand, start time.Time) *DiskIOMeasurement {
+	sub := common.NewSubsystemMeasurementWithDistributionMakers(r, start, diskIOFields)
+	serial := fmt.Sprintf(diskSerialFmt, r.Intn(1000), r.Intn(1000), r.Intn(1000))
 	return &DiskIOMeasurement{
 		SubsystemMeasurement: sub,
 		serial:               serial,
@@ -3,6 +3,7 @@ package devops
 import (
 	"github.com/timescale/tsbs/pkg/data"
 	"github.com/timescale/tsbs/pkg/data/usecases/common"
+	"math/rand"
 	"time"
 )
 
@@ -62,10 +63,10 @@ func (d *DevopsSimulator) Headers() *common.GeneratedDataHeaders {
 type DevopsSimulatorConfig commonDevopsSimulatorConfig
 
 // NewSimulator produces a Simulator that conforms to the given SimulatorConfig over the specified interval
-func (d *DevopsSimulatorConfig) NewSimulator(interval time.Duration, limit uint64) common.Simulator {
+func (d *DevopsSimulatorConfig) NewSimulator(r *rand.Rand, interval time.Duration, limit uint64) common.Simulator {
 	hostInfos := make([]Host, d.HostCount)
 	for i := 0; i < len(hostInfos); i++ {
-		hostInfos[i] = d.HostConstructor(NewHostCtx(i, d.Start))
+		hostInfos[i] = d.HostConstructor(NewHostCtx(r, i, d.Start))
 	}
 
 	epochs := calculateEpochs(commonDevopsSimulatorConfig(*d), interval)
@@ -76,6 +77,7 @@ func (d *DevopsSimulatorConfig) NewSimulator(interval time.Duration, limit uint6
 	}
 	dg := &DevopsSimulator{
 		commonDevopsSimulator: &commonDevopsSimulator{
+			rng:        r,
 			madePoints: 0,
 			maxPoints:  maxPoints,
 
@@ -89,6 +91,10 @@ func (d *DevopsSimulatorConfig) NewSimulator(interval time.Duration, limit uint6
 			timestampStart: d.Start,
 			timestampEnd:   d.End,
 			interval:       interval,
+
+			churnRate:       d.ChurnRate,
+			nextHostID:      len(hostInfos),
+			hostConstructor: d.HostConstructor,
 		},
 		simulatedMeasurementIndex: 0,
 	}
@@ -12,11 +12,15 @@ import (
 var (
 	labelGenericMetrics                                   = []byte("generic_metrics")
 	genericMetricFields []common.LabeledDistributionMaker = nil
-	metricND                                              = common.ND(0.0, 1.0)
 	zipfRandSeed                                          = int64(1234)
 )
 
 // GenericMeasurements represents measurements generated for generic metric fields
+// Each distribution steps with an ND of its own, drawing from the source of
+// randomness of its simulator. The higher-level distribution advances the ND
+// and immediately uses its value.
+func metricND(r *rand.Rand) common.Distribution { return common.ND(r, 0.0, 1.0) }
+
 type GenericMeasurements struct {
 	*common.SubsystemMeasurement
 }
@@ -25,13 +29,13 @@ func initGenericMetricFields(size uint64) {
 	if genericMetricFields == nil {
 		genericMetricFields = make([]common.LabeledDistributionMaker, size)
 		for i := range genericMetricFields {
-			genericMetricFields[i] = common.LabeledDistributionMaker{Label: []byte(fmt.Sprintf("metric_%d", i)), DistributionMaker: func() common.Distribution { return common.CWD(metricND, 0.0, 1000, rand.Float64()*1000) }}
+			genericMetricFields[i] = common.LabeledDistributionMaker{Label: []byte(fmt.Sprintf("metric_%d", i)), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.CWD(metricND(r), 0.0, 1000, r.Float64()*1000) }}
 		}
 	}
 }
 
-func NewGenericMeasurements(start time.Time, count uint64) *GenericMeasurements {
-	sub := common.NewSubsystemMeasurementWithDistributionMakers(start, genericMetricFields[:count])
+func NewGenericMeasurements(r *rand.Rand, start time.Time, count uint64) *GenericMeasurements {
+	sub := common.NewSubsystemMeasurementWithDistributionMakers(r, start, genericMetricFields[:count])
 	return &GenericMeasurements{sub}
 }
 
@@ -4,6 +4,7 @@ import (
 	"github.com/timescale/tsbs/pkg/data"
 	"github.com/timescale/tsbs/pkg/data/usecases/common"
 	"math"
+	"math/rand"
 	"time"
 )
 
@@ -20,7 +21,7 @@ type GenericMetricsSimulator struct {
 
 // NewSimulator creates GenericMetricsSimulator for generic-devops use-case. Number of metrics assigned to each host follow zipf distribution.
 // 50% of hosts is long lived and 50% has a liftspan that follows zipf distribution.
-func (c *GenericMetricsSimulatorConfig) NewSimulator(interval time.Duration, limit uint64) common.Simulator {
+func (c *GenericMetricsSimulatorConfig) NewSimulator(r *rand.Rand, interval time.Duration, limit uint64) common.Simulator {
 	hostInfos := make([]Host, c.HostCount)
 	// initialize all generic metric fields at once so they can be reused for different hosts
 	initGenericMetricFields(c.MaxMetricCount)
@@ -28,7 +29,7 @@ func (c *GenericMetricsSimulatorConfig) NewSimulator(interval time.Duration, lim
 	epochs := calculateEpochs(commonDevopsSimulatorConfig(*c.DevopsSimulatorConfig), interval)
 	epochsToLive := generateHostEpochsToLive(c.HostCount, epochs)
 	for i := 0; i < len(hostInfos); i++ {
-		hostInfos[i] = c.HostConstructor(&HostContext{i, c.Start, hostMetricCount[i], epochsToLive[i]})
+		hostInfos[i] = c.HostConstructor(&HostContext{r, i, c.Start, hostMetricCount[i], epochsToLive[i]})
 	}
 
 	// This is not an optimal upper limit as it doesn't take into account host liveness but should be good enough
@@ -38,6 +39,7 @@ func (c *GenericMetricsSimulatorConfig) NewSimulator(interval time.Duration, lim
 	}
 	dg := &GenericMetricsSimulator{
 		commonDevopsSimulator: &commonDevopsSimulator{
+			rng:        r,
 			madePoints: 0,
 			maxPoints:  maxPoints,
 
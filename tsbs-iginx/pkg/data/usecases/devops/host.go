@@ -161,32 +161,32 @@ type generator func(ctx *HostContext) []common.SimulatedMeasurement
 
 func newHostMeasurements(ctx *HostContext) []common.SimulatedMeasurement {
 	return []common.SimulatedMeasurement{
-		NewCPUMeasurement(ctx.start),
-		NewDiskIOMeasurement(ctx.start),
-		NewDiskMeasurement(ctx.start),
-		NewKernelMeasurement(ctx.start),
-		NewMemMeasurement(ctx.start),
-		NewNetMeasurement(ctx.start),
-		NewNginxMeasurement(ctx.start),
-		NewPostgresqlMeasurement(ctx.start),
-		NewRedisMeasurement(ctx.start),
+		NewCPUMeasurement(ctx.rng, ctx.start),
+		NewDiskIOMeasurement(ctx.rng, ctx.start),
+		NewDiskMeasurement(ctx.rng, ctx.start),
+		NewKernelMeasurement(ctx.rng, ctx.start),
+		NewMemMeasurement(ctx.rng, ctx.start),
+		NewNetMeasurement(ctx.rng, ctx.start),
+		NewNginxMeasurement(ctx.rng, ctx.start),
+		NewPostgresqlMeasurement(ctx.rng, ctx.start),
+		NewRedisMeasurement(ctx.rng, ctx.start),
 	}
 }
 
 func newCPUOnlyHostMeasurements(ctx *HostContext) []common.SimulatedMeasurement {
 	return []common.SimulatedMeasurement{
-		NewCPUMeasurement(ctx.start),
+		NewCPUMeasurement(ctx.rng, ctx.start),
 	}
 }
 
 func newCPUSingleHostMeasurements(ctx *HostContext) []common.SimulatedMeasurement {
 	return []common.SimulatedMeasurement{
-		newSingleCPUMeasurement(ctx.start),
+		newSingleCPUMeasurement(ctx.rng, ctx.start),
 	}
 }
 
 func newGenericHostMeasurements(ctx *HostContext) []common.SimulatedMeasurement {
-	return []common.SimulatedMeasurement{NewGenericMeasurements(ctx.start, ctx.metricCount)}
+	return []common.SimulatedMeasurement{NewGenericMeasurements(ctx.rng, ctx.start, ctx.metricCount)}
 }
 
 // NewHost creates a new host in a simulated devops use case
@@ -215,20 +215,20 @@ func NewHostGenericMetrics(ctx *HostContext) Host {
 func newHostWithMeasurementGenerator(gen generator, ctx *HostContext) Host {
 	sm := gen(ctx)
 
-	region := randomRegionSliceChoice(regions)
+	region := randomRegionSliceChoice(ctx.rng, regions)
 
 	h := Host{
 		// Tag Values that are static throughout the life of a Host:
 		Name:               fmt.Sprintf(hostFmt, ctx.id),
 		Region:             region.Name,
-		Datacenter:         common.RandomStringSliceChoice(region.Datacenters),
-		Rack:               getStringRandomInt(machineRackChoicesPerDatacenter),
-		Arch:               common.RandomStringSliceChoice(MachineArchChoices),
-		OS:                 common.RandomStringSliceChoice(MachineOSChoices),
-		Service:            getStringRandomInt(machineServiceChoices),
-		ServiceVersion:     getStringRandomInt(machineServiceVersionChoices),
-		ServiceEnvironment: common.RandomStringSliceChoice(MachineServiceEnvironmentChoices),
-		Team:               common.RandomStringSliceChoice(MachineTeamChoices),
+		Datacenter:         common.RandomStringSliceChoice(ctx.rng, region.Datacenters),
+		Rack:               getStringRandomInt(ctx.rng, machineRackChoicesPerDatacenter),
+		Arch:               common.RandomStringSliceChoice(ctx.rng, MachineArchChoices),
+		OS:                 common.RandomStringSliceChoice(ctx.rng, MachineOSChoices),
+		Service:            getStringRandomInt(ctx.rng, machineServiceChoices),
+		ServiceVersion:     getStringRandomInt(ctx.rng, machineServiceVersionChoices),
+		ServiceEnvironment: common.RandomStringSliceChoice(ctx.rng, MachineServiceEnvironmentChoices),
+		Team:               common.RandomStringSliceChoice(ctx.rng, MachineTeamChoices),
 
 		SimulatedMeasurements: sm,
 		GenericMetricCount:    ctx.metricCount,
@@ -246,10 +246,10 @@ func (h *Host) TickAll(d time.Duration) {
 	}
 }
 
-func getStringRandomInt(limit int64) string {
-	return strconv.FormatInt(rand.Int63n(limit), 10)
+func getStringRandomInt(r *rand.Rand, limit int64) string {
+	return strconv.FormatInt(r.Int63n(limit), 10)
 }
 
-func randomRegionSliceChoice(s []region) *region {
-	return &s[rand.Intn(len(s))]
+func randomRegionSliceChoice(r *rand.Rand, s []region) *region {
+	return &s[r.Intn(len(s))]
 }
@@ -11,7 +11,7 @@ import (
 
 func TestNewHostMeasurements(t *testing.T) {
 	start := time.Now()
-	measurements := newHostMeasurements(NewHostCtxTime(start))
+	measurements := newHostMeasurements(NewHostCtxTime(common.Rand(), start))
 	if got := len(measurements); got != 9 {
 		t.Errorf("incorrect number of measurements: got %d want %d", got, 9)
 	}
@@ -59,7 +59,7 @@ func TestNewHostMeasurements(t *testing.T) {
 
 func TestNewCPUOnlyHostMeasurements(t *testing.T) {
 	start := time.Now()
-	measurements := newCPUOnlyHostMeasurements(NewHostCtxTime(start))
+	measurements := newCPUOnlyHostMeasurements(NewHostCtxTime(common.Rand(), start))
 	if got := len(measurements); got != 1 {
 		t.Errorf("incorrect number of measurements: got %d want %d", got, 9)
 	}
@@ -75,7 +75,7 @@ func TestNewCPUOnlyHostMeasurements(t *testing.T) {
 
 func TestNewCPUSingleHostMeasurements(t *testing.T) {
 	start := time.Now()
-	measurements := newCPUSingleHostMeasurements(NewHostCtxTime(start))
+	measurements := newCPUSingleHostMeasurements(NewHostCtxTime(common.Rand(), start))
 	if got := len(measurements); got != 1 {
 		t.Errorf("incorrect number of measurements: got %d want %d", got, 9)
 	}
@@ -93,7 +93,7 @@ func TestNewHost(t *testing.T) {
 	now := time.Now()
 	// test 1000 times to get diversity of results
 	for i := 0; i < 1000; i++ {
-		h := NewHost(NewHostCtx(i, now))
+		h := NewHost(NewHostCtx(common.Rand(), i, now))
 		if got := len(h.SimulatedMeasurements); got != 9 {
 			t.Errorf("incorrect number of measurements: got %d want %d", got, 9)
 		}
@@ -116,7 +116,7 @@ func TestNewHostCPUOnly(t *testing.T) {
 	now := time.Now()
 	// test 1000 times to get diversity of results
 	for i := 0; i < 1000; i++ {
-		h := NewHostCPUOnly(NewHostCtx(i, now))
+		h := NewHostCPUOnly(NewHostCtx(common.Rand(), i, now))
 		if got := len(h.SimulatedMeasurements); got != 1 {
 			t.Errorf("incorrect number of measurements: got %d want %d", got, 9)
 		}
@@ -139,7 +139,7 @@ func TestNewHostCPUSingle(t *testing.T) {
 	now := time.Now()
 	// test 1000 times to get diversity of results
 	for i := 0; i < 1000; i++ {
-		h := NewHostCPUSingle(NewHostCtx(i, now))
+		h := NewHostCPUSingle(NewHostCtx(common.Rand(), i, now))
 		if got := len(h.SimulatedMeasurements); got != 1 {
 			t.Errorf("incorrect number of measurements: got %d want %d", got, 9)
 		}
@@ -165,7 +165,7 @@ func TestNewHostGenericMeasurments(t *testing.T) {
 	initGenericMetricFields(metricCount)
 	// test 1000 times to get diversity of results
 	for i := 0; i < 1000; i++ {
-		h := NewHostGenericMetrics(&HostContext{i, now, metricCount, 0})
+		h := NewHostGenericMetrics(&HostContext{common.Rand(), i, now, metricCount, 0})
 		if got := len(h.SimulatedMeasurements); got != 1 {
 			t.Errorf("incorrect number of measurements: got %d want %d", got, 1)
 		}
@@ -213,7 +213,7 @@ func TestNewHostWithMeasurementGenerator(t *testing.T) {
 	now := time.Now()
 	// test 1000 times to get diversity of results
 	for i := 0; i < 1000; i++ {
-		h := newHostWithMeasurementGenerator(testGenerator, NewHostCtx(i, now))
+		h := newHostWithMeasurementGenerator(testGenerator, NewHostCtx(common.Rand(), i, now))
 		wantName := fmt.Sprintf(hostFmt, i)
 		if got := string(h.Name); got != wantName {
 			t.Errorf("incorrect host name format: got %s want %s", got, wantName)
@@ -243,7 +243,7 @@ func (m *testMeasurement) ToPoint(_ *data.Point) {}
 
 func TestHostTickAll(t *testing.T) {
 	now := time.Now()
-	h := newHostWithMeasurementGenerator(testGenerator, NewHostCtxTime(now))
+	h := newHostWithMeasurementGenerator(testGenerator, NewHostCtxTime(common.Rand(), now))
 	if got := h.SimulatedMeasurements[0].(*testMeasurement).ticks; got != 0 {
 		t.Errorf("ticks not equal to 0 to start: got %d", got)
 	}
@@ -264,7 +264,7 @@ func TestHostTickAll(t *testing.T) {
 func TestGetStringRandomInt(t *testing.T) {
 	limit := int64(100)
 	for i := 0; i < 1000000; i++ {
-		s := getStringRandomInt(limit)
+		s := getStringRandomInt(common.Rand(), limit)
 		testStringNumberIsValid(t, limit, s)
 	}
 }
@@ -280,7 +280,7 @@ func testIfInRegionSlice(t *testing.T, arr []region, choice *region) {
 
 func TestRandomRegionSliceChoice(t *testing.T) {
 	for i := 0; i < 1000000; i++ {
-		r := randomRegionSliceChoice(regions)
+		r := randomRegionSliceChoice(common.Rand(), regions)
 		testIfInRegionSlice(t, regions, r)
 	}
 }
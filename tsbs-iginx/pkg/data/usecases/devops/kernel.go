@@ -11,28 +11,28 @@ var (
 	labelKernel         = []byte("kernel") // heap optimization
 	labelKernelBootTime = []byte("boot_time")
 
-	// Reuse NormalDistributions as arguments to other distributions. This is
-	// safe to do because the higher-level distribution advances the ND and
-	// immediately uses its value and saves the state
-	kernelND = common.ND(5, 1)
-
 	kernelFields = []common.LabeledDistributionMaker{
-		{Label: []byte("interrupts"), DistributionMaker: func() common.Distribution { return common.MWD(kernelND, 0) }},
-		{Label: []byte("context_switches"), DistributionMaker: func() common.Distribution { return common.MWD(kernelND, 0) }},
-		{Label: []byte("processes_forked"), DistributionMaker: func() common.Distribution { return common.MWD(kernelND, 0) }},
-		{Label: []byte("disk_pages_in"), DistributionMaker: func() common.Distribution { return common.MWD(kernelND, 0) }},
-		{Label: []byte("disk_pages_out"), DistributionMaker: func() common.Distribution { return common.MWD(kernelND, 0) }},
+		{Label: []byte("interrupts"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.MWD(kernelND(r), 0) }},
+		{Label: []byte("context_switches"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.MWD(kernelND(r), 0) }},
+		{Label: []byte("processes_forked"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.MWD(kernelND(r), 0) }},
+		{Label: []byte("disk_pages_in"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.MWD(kernelND(r), 0) }},
+		{Label: []byte("disk_pages_out"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.MWD(kernelND(r), 0) }},
 	}
 )
 
+// Each distribution steps with an ND of its own, drawing from the source of
+// randomness of its simulator. The higher-level distribution advances the ND
+// and immediately uses its value.
+func kernelND(r *rand.Rand) common.Distribution { return common.ND(r, 5, 1) }
+
 type KernelMeasurement struct {
 	*common.SubsystemMeasurement
 	bootTime int64
 }
 
-func NewKernelMeasurement(start time.Time) *KernelMeasurement {
-	sub := common.NewSubsystemMeasurementWithDistributionMakers(start, kernelFields)
-	bootTime := rand.Int63n(240)
+func NewKernelMeasurement(r *rand.Rand, start time.Time) *KernelMeasurement {
+	sub := common.NewSubsystemMeasurementWithDistributionMakers(r, start, kernelFields)
+	bootTime := r.Int63n(240)
 	return &KernelMeasurement{
 		SubsystemMeasurement: sub,
 		bootTime:             bootTime,
@@ -2,14 +2,14 @@ package devops
 
 import (
 	"github.com/timescale/tsbs/pkg/data"
-	"math/rand"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
 	"testing"
 	"time"
 )
 
 func TestKernelMeasurementTick(t *testing.T) {
 	now := time.Now()
-	m := NewKernelMeasurement(now)
+	m := NewKernelMeasurement(common.Rand(), now)
 	duration := time.Second
 	bootTime := m.bootTime
 	oldVals := map[string]float64{}
@@ -18,7 +18,7 @@ func TestKernelMeasurementTick(t *testing.T) {
 		oldVals[string(ldm.Label)] = m.Distributions[i].Get()
 	}
 
-	rand.Seed(123)
+	common.Seed(123)
 	m.Tick(duration)
 	err := testDistributionsAreDifferent(oldVals, m.SubsystemMeasurement, fields)
 	if err != nil {
@@ -39,7 +39,7 @@ func TestKernelMeasurementTick(t *testing.T) {
 
 func TestKernelMeasurementToPoint(t *testing.T) {
 	now := time.Now()
-	m := NewKernelMeasurement(now)
+	m := NewKernelMeasurement(common.Rand(), now)
 	duration := time.Second
 	bootTime := m.bootTime
 	m.Tick(duration)
@@ -31,21 +31,21 @@ type MemMeasurement struct {
 	bytesTotal int64 // this doesn't change
 }
 
-func NewMemMeasurement(start time.Time) *MemMeasurement {
+func NewMemMeasurement(r *rand.Rand, start time.Time) *MemMeasurement {
 	sub := common.NewSubsystemMeasurement(start, 3)
-	bytesTotal := common.RandomInt64SliceChoice(memoryTotalChoices)
+	bytesTotal := common.RandomInt64SliceChoice(r, memoryTotalChoices)
 
 	// Reuse NormalDistributions as arguments to other distributions. This is
 	// safe to do because the higher-level distribution advances the ND and
 	// immediately uses its value and saves the state
-	nd := common.ND(0.0, float64(bytesTotal)/64)
+	nd := common.ND(r, 0.0, float64(bytesTotal)/64)
 
 	// used bytes
-	sub.Distributions[0] = common.CWD(nd, 0.0, float64(bytesTotal), rand.Float64()*float64(bytesTotal))
+	sub.Distributions[0] = common.CWD(nd, 0.0, float64(bytesTotal), r.Float64()*float64(bytesTotal))
 	// cached bytes
-	sub.Distributions[1] = common.CWD(nd, 0.0, float64(bytesTotal), rand.Float64()*float64(bytesTotal))
+	sub.Distributions[1] = common.CWD(nd, 0.0, float64(bytesTotal), r.Float64()*float64(bytesTotal))
 	// buffered bytes
-	sub.Distributions[2] = common.CWD(nd, 0.0, float64(bytesTotal), rand.Float64()*float64(bytesTotal))
+	sub.Distributions[2] = common.CWD(nd, 0.0, float64(bytesTotal), r.Float64()*float64(bytesTotal))
 	return &MemMeasurement{
 		SubsystemMeasurement: sub,
 		bytesTotal:           bytesTotal,
@@ -2,7 +2,7 @@ package devops
 
 import (
 	"github.com/timescale/tsbs/pkg/data"
-	"math/rand"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
 	"testing"
 	"time"
 )
@@ -18,7 +18,7 @@ func testIfInInt64Slice(t *testing.T, arr []int64, choice int64) {
 
 func TestMemMeasurementTick(t *testing.T) {
 	now := time.Now()
-	m := NewMemMeasurement(now)
+	m := NewMemMeasurement(common.Rand(), now)
 	duration := time.Second
 	oldVals := map[string]float64{}
 	oldTotal := m.bytesTotal
@@ -28,7 +28,7 @@ func TestMemMeasurementTick(t *testing.T) {
 		oldVals[string(f)] = m.Distributions[i].Get()
 	}
 
-	rand.Seed(123)
+	common.Seed(123)
 	m.Tick(duration)
 	err := testDistributionsAreDifferent(oldVals, m.SubsystemMeasurement, fields)
 	if err != nil {
@@ -49,7 +49,7 @@ func TestMemMeasurementTick(t *testing.T) {
 
 func TestMemMeasurementToPoint(t *testing.T) {
 	now := time.Now()
-	m := NewMemMeasurement(now)
+	m := NewMemMeasurement(common.Rand(), now)
 	duration := time.Second
 	m.Tick(duration)
 
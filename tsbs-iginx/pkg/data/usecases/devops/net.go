@@ -12,32 +12,32 @@ var (
 	labelNet             = []byte("net") // heap optimization
 	labelNetTagInterface = []byte("interface")
 
-	// Reuse NormalDistributions as arguments to other distributions. This is
-	// safe to do because the higher-level distribution advances the ND and
-	// immediately uses its value and saves the state
-	highND = common.ND(50, 1)
-	lowND  = common.ND(5, 1)
-
 	netFields = []common.LabeledDistributionMaker{
-		{Label: []byte("bytes_sent"), DistributionMaker: func() common.Distribution { return common.MWD(highND, 0) }},
-		{Label: []byte("bytes_recv"), DistributionMaker: func() common.Distribution { return common.MWD(highND, 0) }},
-		{Label: []byte("packets_sent"), DistributionMaker: func() common.Distribution { return common.MWD(highND, 0) }},
-		{Label: []byte("packets_recv"), DistributionMaker: func() common.Distribution { return common.MWD(highND, 0) }},
-		{Label: []byte("err_in"), DistributionMaker: func() common.Distribution { return common.MWD(lowND, 0) }},
-		{Label: []byte("err_out"), DistributionMaker: func() common.Distribution { return common.MWD(lowND, 0) }},
-		{Label: []byte("drop_in"), DistributionMaker: func() common.Distribution { return common.MWD(lowND, 0) }},
-		{Label: []byte("drop_out"), DistributionMaker: func() common.Distribution { return common.MWD(lowND, 0) }},
+		{Label: []byte("bytes_sent"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.MWD(highND(r), 0) }},
+		{Label: []byte("bytes_recv"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.MWD(highND(r), 0) }},
+		{Label: []byte("packets_sent"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.MWD(highND(r), 0) }},
+		{Label: []byte("packets_recv"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.MWD(highND(r), 0) }},
+		{Label: []byte("err_in"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.MWD(lowND(r), 0) }},
+		{Label: []byte("err_out"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.MWD(lowND(r), 0) }},
+		{Label: []byte("drop_in"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.MWD(lowND(r), 0) }},
+		{Label: []byte("drop_out"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.MWD(lowND(r), 0) }},
 	}
 )
 
+// Each distribution steps with an ND of its own, drawing from the source of
+// randomness of its simulator. The higher-level distribution advances the ND
+// and immediately uses its value.
+func highND(r *rand.Rand) common.Distribution { return common.ND(r, 50, 1) }
+func lowND(r *rand.Rand) common.Distribution  { return common.ND(r, 5, 1) }
+
 type NetMeasurement struct {
 	*common.SubsystemMeasurement
 	interfaceName string
 }
 
-func NewNetMeasurement(start time.Time) *NetMeasurement {
-	sub := common.NewSubsystemMeasurementWithDistributionMakers(start, netFields)
-	interfaceName := fmt.Sprintf("eth%d", rand.Intn(4))
+func NewNetMeasurement(r *rand.Rand, start time.Time) *NetMeasurement {
+	sub := common.NewSubsystemMeasurementWithDistributionMakers(r, start, netFields)
+	interfaceName := fmt.Sprintf("eth%d", r.Intn(4))
 	return &NetMeasurement{
 		SubsystemMeasurement: sub,
 		interfaceName:        interfaceName,
@@ -2,14 +2,14 @@ package devops
 
 import (
 	"github.com/timescale/tsbs/pkg/data"
-	"math/rand"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
 	"testing"
 	"time"
 )
 
 func TestNetMeasurementTick(t *testing.T) {
 	now := time.Now()
-	m := NewNetMeasurement(now)
+	m := NewNetMeasurement(common.Rand(), now)
 	origName := string(m.interfaceName)
 	duration := time.Second
 	oldVals := map[string]float64{}
@@ -18,7 +18,7 @@ func TestNetMeasurementTick(t *testing.T) {
 		oldVals[string(ldm.Label)] = m.Distributions[i].Get()
 	}
 
-	rand.Seed(123)
+	common.Seed(123)
 	m.Tick(duration)
 	err := testDistributionsAreDifferent(oldVals, m.SubsystemMeasurement, fields)
 	if err != nil {
@@ -39,7 +39,7 @@ func TestNetMeasurementTick(t *testing.T) {
 
 func TestNetMeasurementToPoint(t *testing.T) {
 	now := time.Now()
-	m := NewNetMeasurement(now)
+	m := NewNetMeasurement(common.Rand(), now)
 	origName := m.interfaceName
 	duration := time.Second
 	m.Tick(duration)
@@ -14,31 +14,31 @@ var (
 	labelNginxTagPort   = []byte("port")
 	labelNginxTagServer = []byte("server")
 
-	// Reuse NormalDistributions as arguments to other distributions. This is
-	// safe to do because the higher-level distribution advances the ND and
-	// immediately uses its value and saves the state
-	nginxND = common.ND(5, 1)
-
 	nginxFields = []common.LabeledDistributionMaker{
-		{Label: []byte("accepts"), DistributionMaker: func() common.Distribution { return common.MWD(nginxND, 0) }},
-		{Label: []byte("active"), DistributionMaker: func() common.Distribution { return common.CWD(nginxND, 0, 100, 0) }},
-		{Label: []byte("handled"), DistributionMaker: func() common.Distribution { return common.MWD(nginxND, 0) }},
-		{Label: []byte("reading"), DistributionMaker: func() common.Distribution { return common.CWD(nginxND, 0, 100, 0) }},
-		{Label: []byte("requests"), DistributionMaker: func() common.Distribution { return common.MWD(nginxND, 0) }},
-		{Label: []byte("waiting"), DistributionMaker: func() common.Distribution { return common.CWD(nginxND, 0, 100, 0) }},
-		{Label: []byte("writing"), DistributionMaker: func() common.Distribution { return common.CWD(nginxND, 0, 100, 0) }},
+		{Label: []byte("accepts"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.MWD(nginxND(r), 0) }},
+		{Label: []byte("active"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.CWD(nginxND(r), 0, 100, 0) }},
+		{Label: []byte("handled"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.MWD(nginxND(r), 0) }},
+		{Label: []byte("reading"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.CWD(nginxND(r), 0, 100, 0) }},
+		{Label: []byte("requests"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.MWD(nginxND(r), 0) }},
+		{Label: []byte("waiting"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.CWD(nginxND(r), 0, 100, 0) }},
+		{Label: []byte("writing"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.CWD(nginxND(r), 0, 100, 0) }},
 	}
 )
 
+// Each distribution steps with an ND of its own, drawing from the source of
+// randomness of its simulator. The higher-level distribution advances the ND
+// and immediately uses its value.
+func nginxND(r *rand.Rand) common.Distribution { return common.ND(r, 5, 1) }
+
 type NginxMeasurement struct {
 	*common.SubsystemMeasurement
 	port, serverName string
 }
 
-func NewNginxMeasurement(start time.Time) *NginxMeasurement {
-	sub := common.NewSubsystemMeasurementWithDistributionMakers(start, nginxFields)
-	serverName := fmt.Sprintf("nginx_%d", rand.Intn(100000))
-	port := strconv.FormatInt(rand.Int63n(20000)+1024, 10)
+func NewNginxMeasurement(r *rand.Rand, start time.Time) *NginxMeasurement {
+	sub := common.NewSubsystemMeasurementWithDistributionMakers(r, start, nginxFields)
+	serverName := fmt.Sprintf("nginx_%d", r.Intn(100000))
+	port := strconv.FormatInt(r.Int63n(20000)+1024, 10)
 	return &NginxMeasurement{
 		SubsystemMeasurement: sub,
 		port:                 port,
@@ -2,14 +2,14 @@ package devops
 
 import (
 	"github.com/timescale/tsbs/pkg/data"
-	"math/rand"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
 	"testing"
 	"time"
 )
 
 func TestNginxMeasurementTick(t *testing.T) {
 	now := time.Now()
-	m := NewNginxMeasurement(now)
+	m := NewNginxMeasurement(common.Rand(), now)
 	origName := string(m.serverName)
 	origPort := string(m.port)
 	duration := time.Second
@@ -19,7 +19,7 @@ func TestNginxMeasurementTick(t *testing.T) {
 		oldVals[string(ldm.Label)] = m.Distributions[i].Get()
 	}
 
-	rand.Seed(123)
+	common.Seed(123)
 	m.Tick(duration)
 	err := testDistributionsAreDifferent(oldVals, m.SubsystemMeasurement, fields)
 	if err != nil {
@@ -46,7 +46,7 @@ func TestNginxMeasurementTick(t *testing.T) {
 
 func TestNginxMeasurementToPoint(t *testing.T) {
 	now := time.Now()
-	m := NewNginxMeasurement(now)
+	m := NewNginxMeasurement(common.Rand(), now)
 	origName := m.serverName
 	origPort := m.port
 	duration := time.Second
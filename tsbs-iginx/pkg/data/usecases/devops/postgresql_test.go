@@ -2,14 +2,14 @@ package devops
 
 import (
 	"github.com/timescale/tsbs/pkg/data"
-	"math/rand"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
 	"testing"
 	"time"
 )
 
 func TestPostgresqlMeasurementTick(t *testing.T) {
 	now := time.Now()
-	m := NewPostgresqlMeasurement(now)
+	m := NewPostgresqlMeasurement(common.Rand(), now)
 	duration := time.Second
 	oldVals := map[string]float64{}
 	fields := ldmToFieldLabels(postgresqlFields)
@@ -17,7 +17,7 @@ func TestPostgresqlMeasurementTick(t *testing.T) {
 		oldVals[string(ldm.Label)] = m.Distributions[i].Get()
 	}
 
-	rand.Seed(123)
+	common.Seed(123)
 	m.Tick(duration)
 	err := testDistributionsAreDifferent(oldVals, m.SubsystemMeasurement, fields)
 	if err != nil {
@@ -32,7 +32,7 @@ func TestPostgresqlMeasurementTick(t *testing.T) {
 
 func TestPostgresqlMeasurementToPoint(t *testing.T) {
 	now := time.Now()
-	m := NewPostgresqlMeasurement(now)
+	m := NewPostgresqlMeasurement(common.Rand(), now)
 	duration := time.Second
 	m.Tick(duration)
 
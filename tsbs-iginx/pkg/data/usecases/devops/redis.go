@@ -17,48 +17,48 @@ var (
 
 	sixteenGB = float64(16 * 1024 * 1024 * 1024)
 
-	// Reuse NormalDistributions as arguments to other distributions. This is
-	// safe to do because the higher-level distribution advances the ND and
-	// immediately uses its value and saves the state
-	redisLowND  = common.ND(5, 1)
-	redisHighND = common.ND(50, 1)
-
 	redisFields = []common.LabeledDistributionMaker{
-		{Label: []byte("total_connections_received"), DistributionMaker: func() common.Distribution { return common.MWD(redisLowND, 0) }},
-		{Label: []byte("expired_keys"), DistributionMaker: func() common.Distribution { return common.MWD(redisHighND, 0) }},
-		{Label: []byte("evicted_keys"), DistributionMaker: func() common.Distribution { return common.MWD(redisHighND, 0) }},
-		{Label: []byte("keyspace_hits"), DistributionMaker: func() common.Distribution { return common.MWD(redisHighND, 0) }},
-		{Label: []byte("keyspace_misses"), DistributionMaker: func() common.Distribution { return common.MWD(redisHighND, 0) }},
+		{Label: []byte("total_connections_received"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.MWD(redisLowND(r), 0) }},
+		{Label: []byte("expired_keys"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.MWD(redisHighND(r), 0) }},
+		{Label: []byte("evicted_keys"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.MWD(redisHighND(r), 0) }},
+		{Label: []byte("keyspace_hits"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.MWD(redisHighND(r), 0) }},
+		{Label: []byte("keyspace_misses"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.MWD(redisHighND(r), 0) }},
 
-		{Label: []byte("instantaneous_ops_per_sec"), DistributionMaker: func() common.Distribution { return common.WD(common.ND(1, 1), 0) }},
-		{Label: []byte("instantaneous_input_kbps"), DistributionMaker: func() common.Distribution { return common.WD(common.ND(1, 1), 0) }},
-		{Label: []byte("instantaneous_output_kbps"), DistributionMaker: func() common.Distribution { return common.WD(common.ND(1, 1), 0) }},
-		{Label: []byte("connected_clients"), DistributionMaker: func() common.Distribution { return common.CWD(redisHighND, 0, 10000, 0) }},
-		{Label: []byte("used_memory"), DistributionMaker: func() common.Distribution { return common.CWD(redisHighND, 0, sixteenGB, sixteenGB/2) }},
-		{Label: []byte("used_memory_rss"), DistributionMaker: func() common.Distribution { return common.CWD(redisHighND, 0, sixteenGB, sixteenGB/2) }},
-		{Label: []byte("used_memory_peak"), DistributionMaker: func() common.Distribution { return common.CWD(redisHighND, 0, sixteenGB, sixteenGB/2) }},
-		{Label: []byte("used_memory_lua"), DistributionMaker: func() common.Distribution { return common.CWD(redisHighND, 0, sixteenGB, sixteenGB/2) }},
-		{Label: []byte("rdb_changes_since_last_save"), DistributionMaker: func() common.Distribution { return common.CWD(redisHighND, 0, 10000, 0) }},
+		{Label: []byte("instantaneous_ops_per_sec"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.WD(common.ND(r, 1, 1), 0) }},
+		{Label: []byte("instantaneous_input_kbps"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.WD(common.ND(r, 1, 1), 0) }},
+		{Label: []byte("instantaneous_output_kbps"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.WD(common.ND(r, 1, 1), 0) }},
+		{Label: []byte("connected_clients"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.CWD(redisHighND(r), 0, 10000, 0) }},
+		{Label: []byte("used_memory"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.CWD(redisHighND(r), 0, sixteenGB, sixteenGB/2) }},
+		{Label: []byte("used_memory_rss"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.CWD(redisHighND(r), 0, sixteenGB, sixteenGB/2) }},
+		{Label: []byte("used_memory_peak"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.CWD(redisHighND(r), 0, sixteenGB, sixteenGB/2) }},
+		{Label: []byte("used_memory_lua"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.CWD(redisHighND(r), 0, sixteenGB, sixteenGB/2) }},
+		{Label: []byte("rdb_changes_since_last_save"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.CWD(redisHighND(r), 0, 10000, 0) }},
 
-		{Label: []byte("sync_full"), DistributionMaker: func() common.Distribution { return common.CWD(redisLowND, 0, 1000, 0) }},
-		{Label: []byte("sync_partial_ok"), DistributionMaker: func() common.Distribution { return common.CWD(redisLowND, 0, 1000, 0) }},
-		{Label: []byte("sync_partial_err"), DistributionMaker: func() common.Distribution { return common.CWD(redisLowND, 0, 1000, 0) }},
-		{Label: []byte("pubsub_channels"), DistributionMaker: func() common.Distribution { return common.CWD(redisLowND, 0, 1000, 0) }},
-		{Label: []byte("pubsub_patterns"), DistributionMaker: func() common.Distribution { return common.CWD(redisLowND, 0, 1000, 0) }},
-		{Label: []byte("latest_fork_usec"), DistributionMaker: func() common.Distribution { return common.CWD(redisLowND, 0, 1000, 0) }},
-		{Label: []byte("connected_slaves"), DistributionMaker: func() common.Distribution { return common.CWD(redisLowND, 0, 1000, 0) }},
-		{Label: []byte("master_repl_offset"), DistributionMaker: func() common.Distribution { return common.CWD(redisLowND, 0, 1000, 0) }},
-		{Label: []byte("repl_backlog_active"), DistributionMaker: func() common.Distribution { return common.CWD(redisLowND, 0, 1000, 0) }},
-		{Label: []byte("repl_backlog_size"), DistributionMaker: func() common.Distribution { return common.CWD(redisLowND, 0, 1000, 0) }},
-		{Label: []byte("repl_backlog_histlen"), DistributionMaker: func() common.Distribution { return common.CWD(redisLowND, 0, 1000, 0) }},
-		{Label: []byte("mem_fragmentation_ratio"), DistributionMaker: func() common.Distribution { return common.CWD(redisLowND, 0, 100, 0) }},
-		{Label: []byte("used_cpu_sys"), DistributionMaker: func() common.Distribution { return common.CWD(redisLowND, 0, 1000, 0) }},
-		{Label: []byte("used_cpu_user"), DistributionMaker: func() common.Distribution { return common.CWD(redisLowND, 0, 1000, 0) }},
-		{Label: []byte("used_cpu_sys_children"), DistributionMaker: func() common.Distribution { return common.CWD(redisLowND, 0, 1000, 0) }},
-		{Label: []byte("used_cpu_user_children"), DistributionMaker: func() common.Distribution { return common.CWD(redisLowND, 0, 1000, 0) }},
+		{Label: []byte("sync_full"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.CWD(redisLowND(r), 0, 1000, 0) }},
+		{Label: []byte("sync_partial_ok"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.CWD(redisLowND(r), 0, 1000, 0) }},
+		{Label: []byte("sync_partial_err"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.CWD(redisLowND(r), 0, 1000, 0) }},
+		{Label: []byte("pubsub_channels"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.CWD(redisLowND(r), 0, 1000, 0) }},
+		{Label: []byte("pubsub_patterns"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.CWD(redisLowND(r), 0, 1000, 0) }},
+		{Label: []byte("latest_fork_usec"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.CWD(redisLowND(r), 0, 1000, 0) }},
+		{Label: []byte("connected_slaves"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.CWD(redisLowND(r), 0, 1000, 0) }},
+		{Label: []byte("master_repl_offset"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.CWD(redisLowND(r), 0, 1000, 0) }},
+		{Label: []byte("repl_backlog_active"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.CWD(redisLowND(r), 0, 1000, 0) }},
+		{Label: []byte("repl_backlog_size"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.CWD(redisLowND(r), 0, 1000, 0) }},
+		{Label: []byte("repl_backlog_histlen"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.CWD(redisLowND(r), 0, 1000, 0) }},
+		{Label: []byte("mem_fragmentation_ratio"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.CWD(redisLowND(r), 0, 100, 0) }},
+		{Label: []byte("used_cpu_sys"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.CWD(redisLowND(r), 0, 1000, 0) }},
+		{Label: []byte("used_cpu_user"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.CWD(redisLowND(r), 0, 1000, 0) }},
+		{Label: []byte("used_cpu_sys_children"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.CWD(redisLowND(r), 0, 1000, 0) }},
+		{Label: []byte("used_cpu_user_children"), DistributionMaker: func(r *rand.Rand) common.Distribution { return common.CWD(redisLowND(r), 0, 1000, 0) }},
 	}
 )
 
+// Each distribution steps with an ND of its own, drawing from the source of
+// randomness of its simulator. The higher-level distribution advances the ND
+// and immediately uses its value.
+func redisLowND(r *rand.Rand) common.Distribution  { return common.ND(r, 5, 1) }
+func redisHighND(r *rand.Rand) common.Distribution { return common.ND(r, 50, 1) }
+
 type RedisMeasurement struct {
 	*common.SubsystemMeasurement
 
@@ -66,10 +66,10 @@ type RedisMeasurement struct {
 	uptime           time.Duration
 }
 
-func NewRedisMeasurement(start time.Time) *RedisMeasurement {
-	sub := common.NewSubsystemMeasurementWithDistributionMakers(start, redisFields)
-	serverName := fmt.Sprintf("redis_%d", rand.Intn(100000))
-	port := strconv.FormatInt(rand.Int63n(20000)+1024, 10)
+func NewRedisMeasurement(r *rand.Rand, start time.Time) *RedisMeasurement {
+	sub := common.NewSubsystemMeasurementWithDistributionMakers(r, start, redisFields)
+	serverName := fmt.Sprintf("redis_%d", r.Intn(100000))
+	port := strconv.FormatInt(r.Int63n(20000)+1024, 10)
 	return &RedisMeasurement{
 		SubsystemMeasurement: sub,
 		port:                 port,
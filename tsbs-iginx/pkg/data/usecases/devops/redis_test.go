@@ -2,14 +2,14 @@ package devops
 
 import (
 	"github.com/timescale/tsbs/pkg/data"
-	"math/rand"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
 	"testing"
 	"time"
 )
 
 func TestRedisMeasurementTick(t *testing.T) {
 	now := time.Now()
-	m := NewRedisMeasurement(now)
+	m := NewRedisMeasurement(common.Rand(), now)
 	origName := string(m.serverName)
 	origPort := string(m.port)
 	duration := time.Second
@@ -19,7 +19,7 @@ func TestRedisMeasurementTick(t *testing.T) {
 		oldVals[string(ldm.Label)] = m.Distributions[i].Get()
 	}
 
-	rand.Seed(123)
+	common.Seed(123)
 	m.Tick(duration)
 	err := testDistributionsAreDifferent(oldVals, m.SubsystemMeasurement, fields)
 	if err != nil {
@@ -52,7 +52,7 @@ func TestRedisMeasurementTick(t *testing.T) {
 
 func TestRedisMeasurementToPoint(t *testing.T) {
 	now := time.Now()
-	m := NewRedisMeasurement(now)
+	m := NewRedisMeasurement(common.Rand(), now)
 	origName := m.serverName
 	origPort := m.port
 	duration := time.Second
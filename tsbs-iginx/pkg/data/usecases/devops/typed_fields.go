@@ -0,0 +1,17 @@
+package devops
+
+import "github.com/timescale/tsbs/pkg/data/usecases/common"
+
+// TypedFields are the string and boolean fields added to the devops
+// measurements with --non-numeric-fields.
+var TypedFields = map[string][]common.TypedField{
+	string(labelCPU): {
+		// mostly INFO, drawn again at every point
+		{Label: []byte("log_level"), Values: []string{"DEBUG", "INFO", "INFO", "INFO", "INFO", "WARN", "ERROR"}, ChangeChance: 1},
+		// an alarm that stays raised for a while
+		{Label: []byte("throttled"), TrueChance: 0.1, ChangeChance: 0.05},
+	},
+	string(labelNginx): {
+		{Label: []byte("last_status"), Values: []string{"200", "200", "200", "200", "301", "304", "404", "500", "503"}, ChangeChance: 1},
+	},
+}
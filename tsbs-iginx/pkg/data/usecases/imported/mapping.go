@@ -0,0 +1,184 @@
+package imported
+
+import (
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Timestamp formats of a TimestampConfig, any other format is a Go time layout
+const (
+	TimestampRFC3339 = "rfc3339"
+	TimestampUnix    = "unix"
+	TimestampUnixMs  = "unix-ms"
+	TimestampUnixUs  = "unix-us"
+	TimestampUnixNs  = "unix-ns"
+)
+
+// Field types of a FieldConfig
+const (
+	FieldTypeFloat = "float"
+	FieldTypeInt   = "int"
+)
+
+const defaultReplicaTag = "replica"
+
+// Mapping declares how the columns of a dataset map to the measurement,
+// timestamp, tags and fields of the points.
+type Mapping struct {
+	Measurement string          `yaml:"measurement"`
+	Timestamp   TimestampConfig `yaml:"timestamp"`
+	Tags        []ColumnConfig  `yaml:"tags"`
+	Fields      []FieldConfig   `yaml:"fields"`
+	// IDTag is the tag whose values are suffixed with the copy number when
+	// the dataset is replicated. Without it, a "replica" tag tells copies apart.
+	IDTag string `yaml:"id-tag"`
+	// Delimiter is the field delimiter of a CSV dataset, "," by default
+	Delimiter string `yaml:"delimiter"`
+}
+
+// TimestampConfig declares the column of the timestamps and its format:
+// rfc3339 (the default), unix, unix-ms, unix-us, unix-ns or a Go time layout.
+type TimestampConfig struct {
+	Column string `yaml:"column"`
+	Format string `yaml:"format"`
+}
+
+// ColumnConfig maps a column to a tag. Name defaults to the column name.
+type ColumnConfig struct {
+	Column string `yaml:"column"`
+	Name   string `yaml:"name"`
+}
+
+// FieldConfig maps a column to a float (the default) or int field.
+type FieldConfig struct {
+	ColumnConfig `yaml:",inline"`
+	Type         string `yaml:"type"`
+}
+
+// LoadMapping reads and validates the mapping of a YAML file
+func LoadMapping(fileName string) (*Mapping, error) {
+	b, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read mapping: %v", err)
+	}
+	var m Mapping
+	if err := yaml.UnmarshalStrict(b, &m); err != nil {
+		return nil, fmt.Errorf("cannot parse mapping %s: %v", fileName, err)
+	}
+	if err := m.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid mapping %s: %v", fileName, err)
+	}
+	return &m, nil
+}
+
+// Validate checks that the mapping names a measurement, a timestamp column
+// and at least one field, and that tag and field names are unique.
+func (m *Mapping) Validate() error {
+	if m.Measurement == "" {
+		return fmt.Errorf("no measurement")
+	}
+	if m.Timestamp.Column == "" {
+		return fmt.Errorf("no timestamp column")
+	}
+	if len(m.Fields) == 0 {
+		return fmt.Errorf("no fields")
+	}
+	if len([]rune(m.delimiter())) != 1 {
+		return fmt.Errorf("delimiter must be a single character")
+	}
+
+	tags := make(map[string]bool)
+	for i, t := range m.Tags {
+		if t.Column == "" {
+			return fmt.Errorf("tag %d has no column", i)
+		}
+		if tags[t.name()] {
+			return fmt.Errorf("duplicate tag %s", t.name())
+		}
+		tags[t.name()] = true
+	}
+	if m.IDTag != "" && !tags[m.IDTag] {
+		return fmt.Errorf("id tag %s is not a tag", m.IDTag)
+	}
+	if m.IDTag == "" && tags[defaultReplicaTag] {
+		return fmt.Errorf("tag %s requires an id tag to replicate the dataset", defaultReplicaTag)
+	}
+
+	fields := make(map[string]bool)
+	for i, f := range m.Fields {
+		if f.Column == "" {
+			return fmt.Errorf("field %d has no column", i)
+		}
+		if fields[f.name()] {
+			return fmt.Errorf("duplicate field %s", f.name())
+		}
+		fields[f.name()] = true
+		switch f.Type {
+		case "", FieldTypeFloat, FieldTypeInt:
+		default:
+			return fmt.Errorf("field %s: unknown type '%s'", f.name(), f.Type)
+		}
+	}
+	return nil
+}
+
+// Columns returns the columns the mapping reads: the timestamp, the tags and
+// the fields, in that order.
+func (m *Mapping) Columns() []string {
+	columns := []string{m.Timestamp.Column}
+	for _, t := range m.Tags {
+		columns = append(columns, t.Column)
+	}
+	for _, f := range m.Fields {
+		columns = append(columns, f.Column)
+	}
+	return columns
+}
+
+func (m *Mapping) delimiter() string {
+	if m.Delimiter == "" {
+		return ","
+	}
+	return m.Delimiter
+}
+
+func (c *ColumnConfig) name() string {
+	if c.Name == "" {
+		return c.Column
+	}
+	return c.Name
+}
+
+// parseTimestamp converts the value of the timestamp column, a string of a
+// CSV dataset or a string or number of a Parquet dataset.
+func (c *TimestampConfig) parseTimestamp(v interface{}) (time.Time, error) {
+	switch c.Format {
+	case "", TimestampRFC3339:
+		s, ok := v.(string)
+		if !ok {
+			return time.Time{}, fmt.Errorf("timestamp %v is not a string", v)
+		}
+		return time.Parse(time.RFC3339Nano, s)
+	case TimestampUnix, TimestampUnixMs, TimestampUnixUs, TimestampUnixNs:
+		n, err := toInt(v)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("timestamp %v: %v", v, err)
+		}
+		unit := map[string]time.Duration{
+			TimestampUnix:   time.Second,
+			TimestampUnixMs: time.Millisecond,
+			TimestampUnixUs: time.Microsecond,
+			TimestampUnixNs: time.Nanosecond,
+		}[c.Format]
+		return time.Unix(0, n*int64(unit)).UTC(), nil
+	default:
+		s, ok := v.(string)
+		if !ok {
+			return time.Time{}, fmt.Errorf("timestamp %v is not a string", v)
+		}
+		return time.Parse(c.Format, s)
+	}
+}
@@ -0,0 +1,130 @@
+package imported
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testMapping = `
+measurement: plant
+timestamp: {column: ts, format: unix-ms}
+tags:
+  - column: machine
+    name: device
+  - column: site
+fields:
+  - column: temp
+    name: temperature
+  - column: parts
+    type: int
+id-tag: device
+`
+
+func writeFile(t *testing.T, name, content string) (string, func()) {
+	dir, err := ioutil.TempDir("", "imported")
+	if err != nil {
+		t.Fatal(err)
+	}
+	name = filepath.Join(dir, name)
+	if err := ioutil.WriteFile(name, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return name, func() { os.RemoveAll(dir) }
+}
+
+func TestLoadMapping(t *testing.T) {
+	name, cleanup := writeFile(t, "mapping.yaml", testMapping)
+	defer cleanup()
+	m, err := LoadMapping(name)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.Join(m.Columns(), ","); got != "ts,machine,site,temp,parts" {
+		t.Errorf("incorrect columns: got %s", got)
+	}
+	if got := m.Tags[1].name(); got != "site" {
+		t.Errorf("incorrect default tag name: got %s want site", got)
+	}
+
+	if _, err := LoadMapping(name + ".missing"); err == nil {
+		t.Errorf("unexpected lack of error for missing file")
+	}
+	unknown, cleanup := writeFile(t, "unknown.yaml", testMapping+"unit: celsius\n")
+	defer cleanup()
+	if _, err := LoadMapping(unknown); err == nil || !strings.Contains(err.Error(), "unit") {
+		t.Errorf("unexpected lack of error for unknown key: %v", err)
+	}
+}
+
+func TestMappingValidate(t *testing.T) {
+	valid := func() *Mapping {
+		return &Mapping{
+			Measurement: "m",
+			Timestamp:   TimestampConfig{Column: "ts"},
+			Tags:        []ColumnConfig{{Column: "id"}},
+			Fields:      []FieldConfig{{ColumnConfig: ColumnConfig{Column: "f"}}},
+		}
+	}
+	if err := valid().Validate(); err != nil {
+		t.Fatalf("unexpected error for valid mapping: %v", err)
+	}
+	cases := []struct {
+		desc   string
+		modify func(m *Mapping)
+	}{
+		{"no measurement", func(m *Mapping) { m.Measurement = "" }},
+		{"no timestamp", func(m *Mapping) { m.Timestamp.Column = "" }},
+		{"no fields", func(m *Mapping) { m.Fields = nil }},
+		{"long delimiter", func(m *Mapping) { m.Delimiter = ";;" }},
+		{"tag without column", func(m *Mapping) { m.Tags[0].Column = "" }},
+		{"duplicate tag", func(m *Mapping) { m.Tags = append(m.Tags, ColumnConfig{Column: "other", Name: "id"}) }},
+		{"unknown id tag", func(m *Mapping) { m.IDTag = "host" }},
+		{"replica tag without id tag", func(m *Mapping) { m.Tags[0].Name = defaultReplicaTag }},
+		{"field without column", func(m *Mapping) { m.Fields[0].Column = "" }},
+		{"duplicate field", func(m *Mapping) { m.Fields = append(m.Fields, m.Fields[0]) }},
+		{"unknown field type", func(m *Mapping) { m.Fields[0].Type = "bool" }},
+	}
+	for _, c := range cases {
+		m := valid()
+		c.modify(m)
+		if err := m.Validate(); err == nil {
+			t.Errorf("%s: unexpected lack of error", c.desc)
+		}
+	}
+}
+
+func TestParseTimestamp(t *testing.T) {
+	want := time.Date(2021, time.March, 1, 8, 0, 30, 0, time.UTC)
+	cases := []struct {
+		format string
+		value  interface{}
+	}{
+		{"", "2021-03-01T08:00:30Z"},
+		{TimestampRFC3339, "2021-03-01T09:00:30+01:00"},
+		{TimestampUnix, "1614585630"},
+		{TimestampUnixMs, int64(1614585630000)},
+		{TimestampUnixUs, "1614585630000000"},
+		{TimestampUnixNs, int64(1614585630000000000)},
+		{"2006-01-02 15:04:05", "2021-03-01 08:00:30"},
+	}
+	for _, c := range cases {
+		tc := &TimestampConfig{Column: "ts", Format: c.format}
+		got, err := tc.parseTimestamp(c.value)
+		if err != nil {
+			t.Errorf("format '%s': unexpected error: %v", c.format, err)
+		} else if !got.Equal(want) {
+			t.Errorf("format '%s': incorrect timestamp: got %v want %v", c.format, got, want)
+		}
+	}
+
+	if _, err := (&TimestampConfig{Format: TimestampUnix}).parseTimestamp("soon"); err == nil {
+		t.Errorf("unexpected lack of error for invalid unix timestamp")
+	}
+	if _, err := (&TimestampConfig{}).parseTimestamp(int64(1)); err == nil {
+		t.Errorf("unexpected lack of error for number with rfc3339 format")
+	}
+}
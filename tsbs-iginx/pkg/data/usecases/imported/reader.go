@@ -0,0 +1,226 @@
+package imported
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/xitongsys/parquet-go/reader"
+	"github.com/xitongsys/parquet-go/source"
+)
+
+// Dataset formats
+const (
+	FormatCSV     = "csv"
+	FormatParquet = "parquet"
+)
+
+// parquetBatchSize is the number of rows read from every Parquet column at once
+const parquetBatchSize = 1024
+
+// rowReader reads the values of the columns of a dataset row by row. A nil
+// value is an empty cell or a null.
+type rowReader interface {
+	// Read returns the values of the next row, in the order of the columns it
+	// was opened with, or io.EOF at the end of the dataset.
+	Read() ([]interface{}, error)
+	Close() error
+}
+
+// openReader opens a dataset of the format to read the columns.
+func openReader(format, fileName string, columns []string, delimiter rune) (rowReader, error) {
+	switch format {
+	case FormatCSV:
+		return openCSV(fileName, columns, delimiter)
+	case FormatParquet:
+		return openParquet(fileName, columns)
+	default:
+		return nil, fmt.Errorf("unknown dataset format '%s'", format)
+	}
+}
+
+// csvReader reads a CSV dataset whose first row names the columns
+type csvReader struct {
+	file    *os.File
+	r       *csv.Reader
+	indexes []int
+}
+
+func openCSV(fileName string, columns []string, delimiter rune) (*csvReader, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open dataset: %v", err)
+	}
+	r := csv.NewReader(f)
+	r.Comma = delimiter
+	r.ReuseRecord = true
+	header, err := r.Read()
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("cannot read header of %s: %v", fileName, err)
+	}
+	positions := make(map[string]int, len(header))
+	for i, name := range header {
+		positions[name] = i
+	}
+	indexes := make([]int, len(columns))
+	for i, c := range columns {
+		pos, ok := positions[c]
+		if !ok {
+			f.Close()
+			return nil, fmt.Errorf("column %s not in the header of %s", c, fileName)
+		}
+		indexes[i] = pos
+	}
+	return &csvReader{file: f, r: r, indexes: indexes}, nil
+}
+
+func (c *csvReader) Read() ([]interface{}, error) {
+	record, err := c.r.Read()
+	if err != nil {
+		return nil, err
+	}
+	values := make([]interface{}, len(c.indexes))
+	for i, pos := range c.indexes {
+		if record[pos] != "" {
+			values[i] = record[pos]
+		}
+	}
+	return values, nil
+}
+
+func (c *csvReader) Close() error {
+	return c.file.Close()
+}
+
+// parquetFile is a source.ParquetFile of the local file system, which
+// reopens the same file for every column it reads.
+type parquetFile struct {
+	*os.File
+	name string
+}
+
+func (f *parquetFile) Open(name string) (source.ParquetFile, error) {
+	if name == "" {
+		name = f.name
+	}
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &parquetFile{File: file, name: name}, nil
+}
+
+func (f *parquetFile) Create(string) (source.ParquetFile, error) {
+	return nil, fmt.Errorf("cannot create a parquet file when importing")
+}
+
+// parquetReader reads the columns of a Parquet dataset in batches of rows.
+type parquetReader struct {
+	file    *parquetFile
+	r       *reader.ParquetReader
+	paths   []string
+	rows    int64
+	read    int64
+	batch   [][]interface{}
+	current int
+}
+
+func openParquet(fileName string, columns []string) (*parquetReader, error) {
+	pf, err := (&parquetFile{}).Open(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open dataset: %v", err)
+	}
+	r, err := reader.NewParquetColumnReader(pf, 1)
+	if err != nil {
+		pf.Close()
+		return nil, fmt.Errorf("cannot read %s: %v", fileName, err)
+	}
+	root := r.SchemaHandler.GetRootExName()
+	paths := make([]string, len(columns))
+	for i, c := range columns {
+		paths[i] = root + "." + c
+		if _, err := r.SchemaHandler.ConvertToInPathStr(paths[i]); err != nil {
+			pf.Close()
+			return nil, fmt.Errorf("column %s not in the schema of %s", c, fileName)
+		}
+	}
+	return &parquetReader{file: pf.(*parquetFile), r: r, paths: paths, rows: r.GetNumRows()}, nil
+}
+
+func (p *parquetReader) Read() ([]interface{}, error) {
+	if p.batch == nil || p.current == len(p.batch[0]) {
+		if p.read == p.rows {
+			return nil, io.EOF
+		}
+		if err := p.readBatch(); err != nil {
+			return nil, err
+		}
+	}
+	values := make([]interface{}, len(p.paths))
+	for i := range p.paths {
+		values[i] = p.batch[i][p.current]
+	}
+	p.current++
+	return values, nil
+}
+
+func (p *parquetReader) readBatch() error {
+	n := p.rows - p.read
+	if n > parquetBatchSize {
+		n = parquetBatchSize
+	}
+	p.batch = make([][]interface{}, len(p.paths))
+	for i, path := range p.paths {
+		values, _, _, err := p.r.ReadColumnByPath(path, n)
+		if err != nil {
+			return fmt.Errorf("cannot read column %s: %v", path, err)
+		}
+		if int64(len(values)) != n {
+			return fmt.Errorf("column %s is not a flat column", path)
+		}
+		p.batch[i] = values
+	}
+	p.read += n
+	p.current = 0
+	return nil
+}
+
+func (p *parquetReader) Close() error {
+	p.r.ReadStop()
+	return p.file.Close()
+}
+
+// toFloat converts a CSV string or a Parquet number to a float
+func toFloat(v interface{}) (float64, error) {
+	switch x := v.(type) {
+	case string:
+		return strconv.ParseFloat(x, 64)
+	case float64:
+		return x, nil
+	case float32:
+		return float64(x), nil
+	case int64:
+		return float64(x), nil
+	case int32:
+		return float64(x), nil
+	default:
+		return 0, fmt.Errorf("%v is not a number", v)
+	}
+}
+
+// toInt converts a CSV string or a Parquet integer to an int
+func toInt(v interface{}) (int64, error) {
+	switch x := v.(type) {
+	case string:
+		return strconv.ParseInt(x, 10, 64)
+	case int64:
+		return x, nil
+	case int32:
+		return int64(x), nil
+	default:
+		return 0, fmt.Errorf("%v is not an integer", v)
+	}
+}
@@ -0,0 +1,267 @@
+package imported
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
+// SimulatorConfig is used to create a Simulator that replays a dataset.
+type SimulatorConfig struct {
+	Mapping *Mapping
+	// Format is the format of the dataset, csv or parquet
+	Format string
+	// File is the dataset
+	File string
+	// Start is the time of the first row when TimeShift is set
+	Start time.Time
+	// End is the time rows are dropped from when TimeShift is set
+	End time.Time
+	// TimeShift shifts the timestamps of the dataset so that it starts at Start
+	TimeShift bool
+	// Scale is the number of copies of every row
+	Scale uint64
+
+	reader rowReader
+}
+
+// Open opens the dataset, so that a missing file or column is reported
+// before the Simulator is created.
+func (c *SimulatorConfig) Open() error {
+	if c.reader != nil {
+		return nil
+	}
+	r, err := openReader(c.Format, c.File, c.Mapping.Columns(), []rune(c.Mapping.delimiter())[0])
+	if err != nil {
+		return err
+	}
+	c.reader = r
+	return nil
+}
+
+// NewSimulator produces a Simulator that emits the rows of the dataset in
+// order, Scale times each. Neither the source of randomness nor the interval
+// are used: the values and the timestamps are the ones of the dataset.
+func (c *SimulatorConfig) NewSimulator(_ *rand.Rand, _ time.Duration, limit uint64) common.Simulator {
+	s := &Simulator{
+		mapping:     c.Mapping,
+		start:       c.Start,
+		end:         c.End,
+		timeShift:   c.TimeShift,
+		scale:       c.Scale,
+		limit:       limit,
+		measurement: []byte(c.Mapping.Measurement),
+	}
+	if s.scale == 0 {
+		s.scale = 1
+	}
+	for _, t := range c.Mapping.Tags {
+		s.tagKeys = append(s.tagKeys, []byte(t.name()))
+	}
+	if c.Mapping.IDTag == "" && s.scale > 1 {
+		s.tagKeys = append(s.tagKeys, []byte(defaultReplicaTag))
+	}
+	for _, f := range c.Mapping.Fields {
+		s.fieldKeys = append(s.fieldKeys, []byte(f.name()))
+	}
+
+	if err := c.Open(); err != nil {
+		s.fail(err)
+		return s
+	}
+	s.reader = c.reader
+	c.reader = nil
+	return s
+}
+
+// Simulator replays the rows of a dataset as points.
+type Simulator struct {
+	mapping     *Mapping
+	reader      rowReader
+	start       time.Time
+	end         time.Time
+	timeShift   bool
+	shift       time.Duration
+	scale       uint64
+	limit       uint64
+	measurement []byte
+	tagKeys     [][]byte
+	fieldKeys   [][]byte
+
+	// the current row and the next copy of it to emit
+	rows      uint64
+	timestamp time.Time
+	tags      []interface{}
+	fields    []interface{}
+	copy      uint64
+
+	madePoints uint64
+	finished   bool
+	err        error
+}
+
+// Finished tells whether the dataset is exhausted, the limit reached or a
+// row is invalid.
+func (s *Simulator) Finished() bool {
+	return s.finished
+}
+
+// Err returns the error that finished the Simulator early, if any.
+func (s *Simulator) Err() error {
+	return s.err
+}
+
+// Next populates the point with the next copy of the current row.
+func (s *Simulator) Next(p *data.Point) bool {
+	if s.tags == nil || s.copy == s.scale {
+		if !s.readRow() {
+			return false
+		}
+	}
+
+	p.SetMeasurementName(s.measurement)
+	ts := s.timestamp
+	p.SetTimestamp(&ts)
+	for i, t := range s.mapping.Tags {
+		v := s.tags[i]
+		if v != nil && s.copy > 0 && t.name() == s.mapping.IDTag {
+			v = fmt.Sprintf("%s_%d", v, s.copy)
+		}
+		p.AppendTag(s.tagKeys[i], v)
+	}
+	if len(s.tagKeys) > len(s.mapping.Tags) {
+		p.AppendTag(s.tagKeys[len(s.tagKeys)-1], strconv.FormatUint(s.copy, 10))
+	}
+	for i, v := range s.fields {
+		p.AppendField(s.fieldKeys[i], v)
+	}
+
+	s.copy++
+	s.madePoints++
+	if s.limit > 0 && s.madePoints >= s.limit {
+		s.finish()
+	}
+	return true
+}
+
+// readRow reads and converts the next row of the dataset, skipping the rows
+// shifted past the end. It returns false when the Simulator is finished.
+func (s *Simulator) readRow() bool {
+	for !s.finished {
+		values, err := s.reader.Read()
+		if err == io.EOF {
+			s.finish()
+			return false
+		}
+		s.rows++
+		if err != nil {
+			s.fail(fmt.Errorf("row %d: %v", s.rows, err))
+			return false
+		}
+		if err := s.convert(values); err != nil {
+			s.fail(fmt.Errorf("row %d: %v", s.rows, err))
+			return false
+		}
+		if s.timeShift && !s.timestamp.Before(s.end) {
+			continue
+		}
+		s.copy = 0
+		return true
+	}
+	return false
+}
+
+func (s *Simulator) convert(values []interface{}) error {
+	if values[0] == nil {
+		return fmt.Errorf("no timestamp")
+	}
+	ts, err := s.mapping.Timestamp.parseTimestamp(values[0])
+	if err != nil {
+		return err
+	}
+	if s.timeShift {
+		if s.rows == 1 {
+			s.shift = s.start.Sub(ts)
+		}
+		ts = ts.Add(s.shift)
+	}
+	s.timestamp = ts.UTC()
+
+	tags := values[1 : 1+len(s.mapping.Tags)]
+	s.tags = make([]interface{}, len(tags))
+	for i, v := range tags {
+		if v != nil {
+			s.tags[i] = fmt.Sprint(v)
+		}
+	}
+
+	s.fields = make([]interface{}, len(s.mapping.Fields))
+	for i, v := range values[1+len(s.mapping.Tags):] {
+		if v == nil {
+			continue
+		}
+		f := s.mapping.Fields[i]
+		if f.Type == FieldTypeInt {
+			s.fields[i], err = toInt(v)
+		} else {
+			s.fields[i], err = toFloat(v)
+		}
+		if err != nil {
+			return fmt.Errorf("column %s: %v", f.Column, err)
+		}
+	}
+	return nil
+}
+
+func (s *Simulator) finish() {
+	s.finished = true
+	if s.reader != nil {
+		s.reader.Close()
+		s.reader = nil
+	}
+}
+
+func (s *Simulator) fail(err error) {
+	s.err = err
+	s.finish()
+}
+
+// Fields returns the field keys of the measurement.
+func (s *Simulator) Fields() map[string][]string {
+	keys := make([]string, len(s.fieldKeys))
+	for i, k := range s.fieldKeys {
+		keys[i] = string(k)
+	}
+	return map[string][]string{string(s.measurement): keys}
+}
+
+// TagKeys returns the tag keys of the points.
+func (s *Simulator) TagKeys() []string {
+	keys := make([]string, len(s.tagKeys))
+	for i, k := range s.tagKeys {
+		keys[i] = string(k)
+	}
+	return keys
+}
+
+// TagTypes returns the type of every tag, the imported tags are strings.
+func (s *Simulator) TagTypes() []string {
+	types := make([]string, len(s.tagKeys))
+	for i := range types {
+		types[i] = "string"
+	}
+	return types
+}
+
+func (s *Simulator) Headers() *common.GeneratedDataHeaders {
+	return &common.GeneratedDataHeaders{
+		TagTypes:  s.TagTypes(),
+		TagKeys:   s.TagKeys(),
+		FieldKeys: s.Fields(),
+	}
+}
@@ -0,0 +1,206 @@
+package imported
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+const testCSV = `ts,machine,site,temp,parts,unused
+1614585600000,press-1,berlin,41.5,10,x
+1614585600000,press-2,berlin,,12,x
+1614585610000,press-1,berlin,41.7,11,x
+1614585620000,press-1,,41.9,13,x
+`
+
+type testRow struct {
+	TS      int64    `parquet:"name=ts, type=INT64"`
+	Machine string   `parquet:"name=machine, type=UTF8"`
+	Site    *string  `parquet:"name=site, type=UTF8, repetitiontype=OPTIONAL"`
+	Temp    *float64 `parquet:"name=temp, type=DOUBLE, repetitiontype=OPTIONAL"`
+	Parts   int32    `parquet:"name=parts, type=INT32"`
+}
+
+// writeParquet writes the rows of testCSV to a Parquet file of dir
+func writeParquet(t *testing.T, dir string) string {
+	name := filepath.Join(dir, "plant.parquet")
+	f, err := os.Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	pw, err := writer.NewParquetWriterFromWriter(f, new(testRow), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	berlin := "berlin"
+	temps := []float64{41.5, 41.7, 41.9}
+	rows := []testRow{
+		{1614585600000, "press-1", &berlin, &temps[0], 10},
+		{1614585600000, "press-2", &berlin, nil, 12},
+		{1614585610000, "press-1", &berlin, &temps[1], 11},
+		{1614585620000, "press-1", nil, &temps[2], 13},
+	}
+	for _, r := range rows {
+		if err := pw.Write(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := pw.WriteStop(); err != nil {
+		t.Fatal(err)
+	}
+	return name
+}
+
+func loadTestMapping(t *testing.T) *Mapping {
+	name, cleanup := writeFile(t, "mapping.yaml", testMapping)
+	defer cleanup()
+	m, err := LoadMapping(name)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return m
+}
+
+// collect runs the simulator and returns the points it emits
+func collect(t *testing.T, c *SimulatorConfig, limit uint64) ([]*data.Point, *Simulator) {
+	sim := c.NewSimulator(common.Rand(), time.Second, limit).(*Simulator)
+	var points []*data.Point
+	for !sim.Finished() {
+		p := data.NewPoint()
+		if sim.Next(p) {
+			points = append(points, p)
+		}
+	}
+	return points, sim
+}
+
+func TestSimulatorFormats(t *testing.T) {
+	csvName, cleanup := writeFile(t, "plant.csv", testCSV)
+	defer cleanup()
+	parquetName := writeParquet(t, filepath.Dir(csvName))
+
+	for _, c := range []struct {
+		format string
+		file   string
+	}{{FormatCSV, csvName}, {FormatParquet, parquetName}} {
+		points, sim := collect(t, &SimulatorConfig{Mapping: loadTestMapping(t), Format: c.format, File: c.file}, 0)
+		if sim.Err() != nil {
+			t.Fatalf("%s: unexpected error: %v", c.format, sim.Err())
+		}
+		if len(points) != 4 {
+			t.Fatalf("%s: incorrect number of points: got %d want 4", c.format, len(points))
+		}
+		p := points[0]
+		if string(p.MeasurementName()) != "plant" {
+			t.Errorf("%s: incorrect measurement: %s", c.format, p.MeasurementName())
+		}
+		if got := p.Timestamp().UnixNano(); got != 1614585600000*int64(time.Millisecond) {
+			t.Errorf("%s: incorrect timestamp: %d", c.format, got)
+		}
+		if got := p.GetTagValue([]byte("device")); got != "press-1" {
+			t.Errorf("%s: incorrect device: %v", c.format, got)
+		}
+		if got := p.GetFieldValue([]byte("temperature")); got != 41.5 {
+			t.Errorf("%s: incorrect temperature: %v", c.format, got)
+		}
+		if got := p.GetFieldValue([]byte("parts")); got != int64(10) {
+			t.Errorf("%s: incorrect parts: %v", c.format, got)
+		}
+		if got := points[1].GetFieldValue([]byte("temperature")); got != nil {
+			t.Errorf("%s: null value not nil: %v", c.format, got)
+		}
+		if got := points[3].GetTagValue([]byte("site")); got != nil {
+			t.Errorf("%s: null tag not nil: %v", c.format, got)
+		}
+	}
+}
+
+func TestSimulatorReplicateAndShift(t *testing.T) {
+	name, cleanup := writeFile(t, "plant.csv", testCSV)
+	defer cleanup()
+	start := time.Date(2016, time.January, 1, 0, 0, 0, 0, time.UTC)
+	c := &SimulatorConfig{
+		Mapping:   loadTestMapping(t),
+		Format:    FormatCSV,
+		File:      name,
+		Start:     start,
+		End:       start.Add(20 * time.Second),
+		TimeShift: true,
+		Scale:     3,
+	}
+	points, sim := collect(t, c, 0)
+	if sim.Err() != nil {
+		t.Fatalf("unexpected error: %v", sim.Err())
+	}
+	// the last row is shifted to the end and dropped
+	if len(points) != 9 {
+		t.Fatalf("incorrect number of points: got %d want 9", len(points))
+	}
+	devices := make([]string, 3)
+	for i := range devices {
+		devices[i] = points[i].GetTagValue([]byte("device")).(string)
+		if !points[i].Timestamp().Equal(start) {
+			t.Errorf("timestamp not shifted: got %v want %v", points[i].Timestamp(), start)
+		}
+	}
+	if got := strings.Join(devices, ","); got != "press-1,press-1_1,press-1_2" {
+		t.Errorf("incorrect copies: got %s", got)
+	}
+	if got := points[6].Timestamp(); !got.Equal(start.Add(10 * time.Second)) {
+		t.Errorf("incorrect shifted timestamp: got %v", got)
+	}
+
+	// without an id tag, a replica tag tells copies apart
+	c.Mapping.IDTag = ""
+	c.Scale = 2
+	c.TimeShift = false
+	points, _ = collect(t, c, 3)
+	if len(points) != 3 {
+		t.Fatalf("limit not respected: got %d points want 3", len(points))
+	}
+	if got := points[1].GetTagValue([]byte(defaultReplicaTag)); got != "1" {
+		t.Errorf("incorrect replica tag: got %v want 1", got)
+	}
+	if got := strings.Join(sim.TagKeys(), ","); got != "device,site" {
+		t.Errorf("incorrect tag keys: got %s", got)
+	}
+	headers := c.NewSimulator(common.Rand(), time.Second, 0).Headers()
+	if got := strings.Join(headers.TagKeys, ","); got != "device,site,replica" {
+		t.Errorf("incorrect tag keys with replica: got %s", got)
+	}
+	if got := strings.Join(headers.FieldKeys["plant"], ","); got != "temperature,parts" {
+		t.Errorf("incorrect field keys: got %s", got)
+	}
+}
+
+func TestSimulatorErrors(t *testing.T) {
+	m := loadTestMapping(t)
+	name, cleanup := writeFile(t, "plant.csv", testCSV+"1614585630000,press-1,berlin,hot,14,x\n")
+	defer cleanup()
+	points, sim := collect(t, &SimulatorConfig{Mapping: m, Format: FormatCSV, File: name}, 0)
+	if len(points) != 4 {
+		t.Errorf("incorrect number of points before the invalid row: %d", len(points))
+	}
+	if err := sim.Err(); err == nil || !strings.Contains(err.Error(), "row 5") {
+		t.Errorf("incorrect error for invalid value: %v", err)
+	}
+
+	missing, cleanup := writeFile(t, "missing.csv", "ts,machine,site,temp\n")
+	defer cleanup()
+	if err := (&SimulatorConfig{Mapping: m, Format: FormatCSV, File: missing}).Open(); err == nil || !strings.Contains(err.Error(), "parts") {
+		t.Errorf("incorrect error for missing column: %v", err)
+	}
+	if err := (&SimulatorConfig{Mapping: m, Format: FormatParquet, File: name}).Open(); err == nil {
+		t.Errorf("unexpected lack of error for CSV file read as Parquet")
+	}
+	if err := (&SimulatorConfig{Mapping: m, Format: "orc", File: name}).Open(); err == nil {
+		t.Errorf("unexpected lack of error for unknown format")
+	}
+}
@@ -0,0 +1,144 @@
+package industrial
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
+const deviceFmt = "device_%d"
+
+var (
+	// PlantChoices contains all the plant values of the industrial use case
+	PlantChoices = []string{
+		"Bremen",
+		"Lyon",
+		"Monterrey",
+		"Osaka",
+		"Pune",
+	}
+
+	lineChoices = []string{
+		"line_1",
+		"line_2",
+		"line_3",
+		"line_4",
+		"line_5",
+		"line_6",
+	}
+
+	modelChoices = []string{
+		"CNC-500",
+		"PRESS-80",
+		"ROBOT-6X",
+		"EXTRUDER-2",
+	}
+
+	// sensorKinds are the kinds of sensors, given to the sensors of a device
+	// in turn: temperature, pressure, vibration, flow and motor current.
+	sensorKinds = []struct {
+		min, max  float64
+		step      func(r *rand.Rand) common.Distribution
+		precision int
+	}{
+		{min: 0, max: 150, step: udStep(-0.5, 0.5), precision: 2},
+		{min: 0, max: 16, step: udStep(-0.05, 0.05), precision: 3},
+		{min: 0, max: 50, step: ndStep(0, 1), precision: 2},
+		{min: 0, max: 500, step: udStep(-2, 2), precision: 1},
+		{min: 0, max: 100, step: ndStep(0, 0.5), precision: 2},
+	}
+)
+
+// udStep and ndStep make the step of a sensor, a distribution of its own
+// drawing from the source of randomness of its simulator.
+func udStep(low, high float64) func(r *rand.Rand) common.Distribution {
+	return func(r *rand.Rand) common.Distribution { return common.UD(r, low, high) }
+}
+
+func ndStep(mean, stddev float64) func(r *rand.Rand) common.Distribution {
+	return func(r *rand.Rand) common.Distribution { return common.ND(r, mean, stddev) }
+}
+
+// sensorField returns the field of the i-th sensor, a clamped random walk
+// over the range of its kind.
+func sensorField(sensor int) common.LabeledDistributionMaker {
+	kind := sensorKinds[sensor%len(sensorKinds)]
+	return common.LabeledDistributionMaker{
+		Label: []byte(SensorName(sensor)),
+		DistributionMaker: func(r *rand.Rand) common.Distribution {
+			start := kind.min + r.Float64()*(kind.max-kind.min)
+			return common.FP(common.CWD(kind.step(r), kind.min, kind.max, start), kind.precision)
+		},
+	}
+}
+
+// blockFields returns the fields of each block of the layout, shared by all
+// the devices.
+func blockFields(l Layout) [][]common.LabeledDistributionMaker {
+	blocks := make([][]common.LabeledDistributionMaker, l.Blocks())
+	for b := range blocks {
+		for _, sensor := range l.BlockSensors(b) {
+			blocks[b] = append(blocks[b], sensorField(sensor))
+		}
+	}
+	return blocks
+}
+
+// blockMeasurement simulates the sensors of a block of a device.
+type blockMeasurement struct {
+	*common.SubsystemMeasurement
+	name   []byte
+	fields []common.LabeledDistributionMaker
+}
+
+// ToPoint fills the point with the readings of all the sensors of the block.
+func (m *blockMeasurement) ToPoint(p *data.Point) {
+	m.SubsystemMeasurement.ToPoint(p, m.name, m.fields)
+}
+
+// Device models a machine whose sensors report together, block by block.
+type Device struct {
+	simulatedMeasurements []common.SimulatedMeasurement
+	tags                  []common.Tag
+}
+
+// TickAll advances all the sensors of a Device.
+func (d *Device) TickAll(duration time.Duration) {
+	for i := range d.simulatedMeasurements {
+		d.simulatedMeasurements[i].Tick(duration)
+	}
+}
+
+// Measurements returns the blocks of the device.
+func (d *Device) Measurements() []common.SimulatedMeasurement {
+	return d.simulatedMeasurements
+}
+
+// Tags returns the tags of the device.
+func (d *Device) Tags() []common.Tag {
+	return d.tags
+}
+
+// newDevice creates the i-th device, with a measurement for each block.
+func newDevice(r *rand.Rand, i int, start time.Time, blocks [][]common.LabeledDistributionMaker) *Device {
+	d := &Device{
+		tags: []common.Tag{
+			{Key: []byte("device"), Value: fmt.Sprintf(deviceFmt, i)},
+			{Key: []byte("plant"), Value: common.RandomStringSliceChoice(r, PlantChoices)},
+			{Key: []byte("line"), Value: common.RandomStringSliceChoice(r, lineChoices)},
+			{Key: []byte("model"), Value: common.RandomStringSliceChoice(r, modelChoices)},
+		},
+		simulatedMeasurements: make([]common.SimulatedMeasurement, len(blocks)),
+	}
+	for b, fields := range blocks {
+		d.simulatedMeasurements[b] = &blockMeasurement{
+			SubsystemMeasurement: common.NewSubsystemMeasurementWithDistributionMakers(r, start, fields),
+			name:                 []byte(BlockName(b)),
+			fields:               fields,
+		}
+	}
+	return d
+}
@@ -0,0 +1,69 @@
+package industrial
+
+import "fmt"
+
+const (
+	// MaxSensors is the most sensors a device can have.
+	MaxSensors = 10000
+	// MaxTimescaleDBSensorsPerBlock is the most sensors of a block that fit
+	// in the columns of a TimescaleDB table, with the time and tag columns.
+	MaxTimescaleDBSensorsPerBlock = 1500
+
+	blockFmt  = "block_%d"
+	sensorFmt = "sensor_%d"
+)
+
+// Layout is how the sensors of a device are grouped: the sensors of a block
+// are written together, as the fields of one point of the block measurement.
+type Layout struct {
+	Sensors         int
+	SensorsPerBlock int
+}
+
+// Validate checks that the device has sensors, up to MaxSensors, and that
+// its blocks are not empty.
+func (l Layout) Validate() error {
+	if l.Sensors < 1 || l.Sensors > MaxSensors {
+		return fmt.Errorf("sensors per device must be between 1 and %d, got %d", MaxSensors, l.Sensors)
+	}
+	if l.SensorsPerBlock < 1 || l.SensorsPerBlock > l.Sensors {
+		return fmt.Errorf("sensors per block must be between 1 and the %d sensors per device, got %d", l.Sensors, l.SensorsPerBlock)
+	}
+	return nil
+}
+
+// Blocks returns the number of blocks, the last one holding the remaining
+// sensors.
+func (l Layout) Blocks() int {
+	return (l.Sensors + l.SensorsPerBlock - 1) / l.SensorsPerBlock
+}
+
+// Block returns the block of the i-th sensor.
+func (l Layout) Block(sensor int) int {
+	return sensor / l.SensorsPerBlock
+}
+
+// BlockSensors returns the sensors of the i-th block.
+func (l Layout) BlockSensors(block int) []int {
+	first := block * l.SensorsPerBlock
+	last := first + l.SensorsPerBlock
+	if last > l.Sensors {
+		last = l.Sensors
+	}
+	sensors := make([]int, 0, last-first)
+	for i := first; i < last; i++ {
+		sensors = append(sensors, i)
+	}
+	return sensors
+}
+
+// BlockName returns the name of the measurement of the i-th block.
+func BlockName(block int) string {
+	return fmt.Sprintf(blockFmt, block)
+}
+
+// SensorName returns the name of the field of the i-th sensor. Sensors are
+// numbered across the blocks, so their names are unique in a device.
+func SensorName(sensor int) string {
+	return fmt.Sprintf(sensorFmt, sensor)
+}
@@ -0,0 +1,45 @@
+package industrial
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLayoutValidate(t *testing.T) {
+	cases := []struct {
+		desc      string
+		layout    Layout
+		shouldErr bool
+	}{
+		{desc: "one sensor", layout: Layout{Sensors: 1, SensorsPerBlock: 1}},
+		{desc: "max sensors", layout: Layout{Sensors: MaxSensors, SensorsPerBlock: 100}},
+		{desc: "no sensors", layout: Layout{Sensors: 0, SensorsPerBlock: 1}, shouldErr: true},
+		{desc: "too many sensors", layout: Layout{Sensors: MaxSensors + 1, SensorsPerBlock: 100}, shouldErr: true},
+		{desc: "empty blocks", layout: Layout{Sensors: 10, SensorsPerBlock: 0}, shouldErr: true},
+		{desc: "block larger than device", layout: Layout{Sensors: 10, SensorsPerBlock: 11}, shouldErr: true},
+	}
+	for _, c := range cases {
+		err := c.layout.Validate()
+		if c.shouldErr && err == nil {
+			t.Errorf("%s: unexpected lack of error", c.desc)
+		} else if !c.shouldErr && err != nil {
+			t.Errorf("%s: unexpected error: %v", c.desc, err)
+		}
+	}
+}
+
+func TestLayoutBlocks(t *testing.T) {
+	l := Layout{Sensors: 10, SensorsPerBlock: 4}
+	if got := l.Blocks(); got != 3 {
+		t.Errorf("incorrect number of blocks: got %d want 3", got)
+	}
+	if got := l.Block(9); got != 2 {
+		t.Errorf("incorrect block of the last sensor: got %d want 2", got)
+	}
+	want := [][]int{{0, 1, 2, 3}, {4, 5, 6, 7}, {8, 9}}
+	for b, sensors := range want {
+		if got := l.BlockSensors(b); !reflect.DeepEqual(got, sensors) {
+			t.Errorf("incorrect sensors of block %d: got %v want %v", b, got, sensors)
+		}
+	}
+}
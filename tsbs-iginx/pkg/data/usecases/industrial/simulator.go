@@ -0,0 +1,41 @@
+package industrial
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
+// SimulatorConfig is used to create an industrial Simulator, whose devices
+// have the sensors of the Layout.
+type SimulatorConfig struct {
+	// Start is the beginning time for the Simulator
+	Start time.Time
+	// End is the ending time for the Simulator
+	End time.Time
+	// InitGeneratorScale is the number of devices to start with in the first reporting period
+	InitGeneratorScale uint64
+	// GeneratorScale is the total number of devices to have in the last reporting period
+	GeneratorScale uint64
+	// Layout is how many sensors the devices have, and how they are grouped
+	Layout Layout
+	// Timing makes the timestamps of the devices irregular
+	Timing common.TimingConfig
+}
+
+// NewSimulator produces a Simulator of the devices over the specified interval.
+func (c *SimulatorConfig) NewSimulator(r *rand.Rand, interval time.Duration, limit uint64) common.Simulator {
+	blocks := blockFields(c.Layout)
+	base := &common.BaseSimulatorConfig{
+		Start:              c.Start,
+		End:                c.End,
+		InitGeneratorScale: c.InitGeneratorScale,
+		GeneratorScale:     c.GeneratorScale,
+		GeneratorConstructor: func(r *rand.Rand, i int, start time.Time) common.Generator {
+			return newDevice(r, i, start, blocks)
+		},
+		Timing: c.Timing,
+	}
+	return base.NewSimulator(r, interval, limit)
+}
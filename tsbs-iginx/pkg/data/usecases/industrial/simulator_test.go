@@ -0,0 +1,49 @@
+package industrial
+
+import (
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
+func TestSimulatorNext(t *testing.T) {
+	start := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := &SimulatorConfig{
+		Start:              start,
+		End:                start.Add(2 * time.Second),
+		InitGeneratorScale: 2,
+		GeneratorScale:     2,
+		Layout:             Layout{Sensors: 10, SensorsPerBlock: 4},
+	}
+	sim := c.NewSimulator(common.Rand(), time.Second, 0)
+
+	fields := sim.Headers().FieldKeys
+	if got := len(fields); got != 3 {
+		t.Fatalf("incorrect number of block measurements: got %d want 3", got)
+	}
+	if got := fields["block_2"]; len(got) != 2 || got[0] != "sensor_8" || got[1] != "sensor_9" {
+		t.Errorf("incorrect fields of the last block: %v", got)
+	}
+
+	// 2 epochs of 2 devices of 3 blocks
+	points := 0
+	p := data.NewPoint()
+	for !sim.Finished() {
+		if sim.Next(p) {
+			points++
+			if got := p.TagValues()[0].(string); got != "device_0" && got != "device_1" {
+				t.Errorf("incorrect device tag: %s", got)
+			}
+			want := len(fields[string(p.MeasurementName())])
+			if got := len(p.FieldKeys()); got != want {
+				t.Errorf("incorrect number of sensors of %s: got %d want %d", p.MeasurementName(), got, want)
+			}
+		}
+		p.Reset()
+	}
+	if points != 12 {
+		t.Errorf("incorrect number of points: got %d want 12", points)
+	}
+}
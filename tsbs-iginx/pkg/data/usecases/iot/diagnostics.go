@@ -3,6 +3,7 @@ package iot
 import (
 	"github.com/timescale/tsbs/pkg/data"
 	"github.com/timescale/tsbs/pkg/data/usecases/common"
+	"math/rand"
 	"time"
 )
 
@@ -17,35 +18,31 @@ var (
 	labelFuelState   = []byte("fuel_state")
 	labelCurrentLoad = []byte("current_load")
 	labelStatus      = []byte("status")
-	fuelUD           = common.UD(-0.001, 0)
-	loadUD           = common.UD(0, maxLoad)
-	loadSaddleUD     = common.UD(0, 1)
-	statusND         = common.ND(0, 1)
 
 	diagnosticsFields = []common.LabeledDistributionMaker{
 		{
 			Label: labelFuelState,
-			DistributionMaker: func() common.Distribution {
+			DistributionMaker: func(r *rand.Rand) common.Distribution {
 				return common.FP(
-					&customFuelDistribution{common.CWD(fuelUD, 0, maxFuel, maxFuel)},
+					&customFuelDistribution{common.CWD(fuelUD(r), 0, maxFuel, maxFuel)},
 					1,
 				)
 			},
 		},
 		{
 			Label: labelCurrentLoad,
-			DistributionMaker: func() common.Distribution {
+			DistributionMaker: func(r *rand.Rand) common.Distribution {
 				return common.FP(
-					common.LD(loadSaddleUD, loadUD, 1-loadChangeChance),
+					common.LD(loadSaddleUD(r), loadUD(r), 1-loadChangeChance),
 					0,
 				)
 			},
 		},
 		{
 			Label: labelStatus,
-			DistributionMaker: func() common.Distribution {
+			DistributionMaker: func(r *rand.Rand) common.Distribution {
 				return common.FP(
-					common.CWD(statusND, 0, 5, 0),
+					common.CWD(statusND(r), 0, 5, 0),
 					0,
 				)
 			},
@@ -53,6 +50,14 @@ var (
 	}
 )
 
+// Each distribution has step and load distributions of its own, drawing from
+// the source of randomness of its simulator. A shared load distribution would
+// give all the trucks the load drawn last.
+func fuelUD(r *rand.Rand) common.Distribution       { return common.UD(r, -0.001, 0) }
+func loadUD(r *rand.Rand) common.Distribution       { return common.UD(r, 0, maxLoad) }
+func loadSaddleUD(r *rand.Rand) common.Distribution { return common.UD(r, 0, 1) }
+func statusND(r *rand.Rand) common.Distribution     { return common.ND(r, 0, 1) }
+
 type customFuelDistribution struct {
 	*common.ClampedRandomWalkDistribution
 }
@@ -83,8 +88,8 @@ func (m *DiagnosticsMeasurement) ToPoint(p *data.Point) {
 }
 
 // NewDiagnosticsMeasurement creates a DiagnosticsMeasurement with start time.
-func NewDiagnosticsMeasurement(start time.Time) *DiagnosticsMeasurement {
-	sub := common.NewSubsystemMeasurementWithDistributionMakers(start, diagnosticsFields)
+func NewDiagnosticsMeasurement(r *rand.Rand, start time.Time) *DiagnosticsMeasurement {
+	sub := common.NewSubsystemMeasurementWithDistributionMakers(r, start, diagnosticsFields)
 
 	return &DiagnosticsMeasurement{
 		SubsystemMeasurement: sub,
@@ -9,7 +9,7 @@ import (
 
 func TestDiagnosticsMeasurementToPoint(t *testing.T) {
 	now := time.Now()
-	m := NewDiagnosticsMeasurement(now)
+	m := NewDiagnosticsMeasurement(common.Rand(), now)
 	duration := time.Second
 	m.Tick(duration)
 
@@ -26,71 +26,67 @@ var (
 	labelHeading         = []byte("heading")
 	labelGrade           = []byte("grade")
 	labelFuelConsumption = []byte("fuel_consumption")
-	geoStepUD            = common.UD(-0.005, 0.005)
-
-	bigUD   = common.UD(-10, 10)
-	smallUD = common.UD(-5, 5)
 
 	readingsFields = []common.LabeledDistributionMaker{
 		{
 			Label: labelLatitude,
-			DistributionMaker: func() common.Distribution {
+			DistributionMaker: func(r *rand.Rand) common.Distribution {
 				return common.FP(
-					common.CWD(geoStepUD, -90.0, 90.0, rand.Float64()*maxLatitude),
+					common.CWD(geoStepUD(r), -90.0, 90.0, r.Float64()*maxLatitude),
 					5,
 				)
 			},
 		},
 		{
 			Label: labelLongitude,
-			DistributionMaker: func() common.Distribution {
+			DistributionMaker: func(r *rand.Rand) common.Distribution {
 				return common.FP(
-					common.CWD(geoStepUD, -180, 180, rand.Float64()*maxLongitude),
+					common.CWD(geoStepUD(r), -180, 180, r.Float64()*maxLongitude),
 					5,
 				)
 			},
 		},
 		{
 			Label: labelElevation,
-			DistributionMaker: func() common.Distribution {
+			DistributionMaker: func(r *rand.Rand) common.Distribution {
 				return common.FP(
-					common.CWD(bigUD, 0, maxElevation, rand.Float64()*500),
+					common.CWD(bigUD(r), 0, maxElevation, r.Float64()*500),
 					0,
 				)
 			},
 		},
 		{
 			Label: labelVelocity,
-			DistributionMaker: func() common.Distribution {
+			DistributionMaker: func(r *rand.Rand) common.Distribution {
 				return common.FP(
-					common.CWD(bigUD, 0, maxVelocity, 0),
+					common.CWD(bigUD(r), 0, maxVelocity, 0),
 					0,
 				)
 			},
 		},
 		{
 			Label: labelHeading,
-			DistributionMaker: func() common.Distribution {
+			DistributionMaker: func(r *rand.Rand) common.Distribution {
 				return common.FP(
-					common.CWD(smallUD, 0, maxHeading, rand.Float64()*maxHeading),
+					common.CWD(smallUD(r), 0, maxHeading, r.Float64()*maxHeading),
 					0,
 				)
 			},
 		},
 		{
 			Label: labelGrade,
-			DistributionMaker: func() common.Distribution {
+			DistributionMaker: func(r *rand.Rand) common.Distribution {
 				return common.FP(
-					common.CWD(smallUD, 0, maxGrade, 0),
+					common.CWD(smallUD(r), 0, maxGrade, 0),
 					0,
 				)
 			},
 		},
 		{
 			Label: labelFuelConsumption,
-			DistributionMaker: func() common.Distribution {
+			DistributionMaker: func(r *rand.Rand) common.Distribution {
 				return common.FP(
-					common.CWD(smallUD, 0, maxFuelConsumption, maxFuelConsumption/2),
+					common.CWD(smallUD(r), 0, maxFuelConsumption, maxFuelConsumption/2),
 					1,
 				)
 			},
@@ -98,9 +94,26 @@ var (
 	}
 )
 
+// Each distribution steps with a UD of its own, drawing from the source of
+// randomness of its simulator. The higher-level distribution advances the UD
+// and immediately uses its value.
+func geoStepUD(r *rand.Rand) common.Distribution { return common.UD(r, -0.005, 0.005) }
+func bigUD(r *rand.Rand) common.Distribution     { return common.UD(r, -10, 10) }
+func smallUD(r *rand.Rand) common.Distribution   { return common.UD(r, -5, 5) }
+
 // ReadingsMeasurement represents a subset of truck measurement readings.
 type ReadingsMeasurement struct {
 	*common.SubsystemMeasurement
+	// route drives the truck on roads, if not nil
+	route *route
+}
+
+// Tick advances the route of the truck, if any, and the readings.
+func (m *ReadingsMeasurement) Tick(d time.Duration) {
+	if m.route != nil {
+		m.route.advance(d)
+	}
+	m.SubsystemMeasurement.Tick(d)
 }
 
 // ToPoint serializes ReadingsMeasurement to serialize.Point.
@@ -115,10 +128,22 @@ func (m *ReadingsMeasurement) ToPoint(p *data.Point) {
 }
 
 // NewReadingsMeasurement creates a new ReadingsMeasurement with start time.
-func NewReadingsMeasurement(start time.Time) *ReadingsMeasurement {
-	sub := common.NewSubsystemMeasurementWithDistributionMakers(start, readingsFields)
+func NewReadingsMeasurement(r *rand.Rand, start time.Time) *ReadingsMeasurement {
+	sub := common.NewSubsystemMeasurementWithDistributionMakers(r, start, readingsFields)
+
+	return &ReadingsMeasurement{
+		SubsystemMeasurement: sub,
+	}
+}
+
+// NewRoutedReadingsMeasurement creates a new ReadingsMeasurement with start
+// time, of a truck driving a route on a grid of roads.
+func NewRoutedReadingsMeasurement(r *rand.Rand, start time.Time) *ReadingsMeasurement {
+	rt := newRoute(r)
+	sub := common.NewSubsystemMeasurementWithDistributionMakers(r, start, routedReadingsFields(rt))
 
 	return &ReadingsMeasurement{
 		SubsystemMeasurement: sub,
+		route:                rt,
 	}
 }
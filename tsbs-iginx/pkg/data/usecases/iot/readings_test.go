@@ -2,13 +2,14 @@ package iot
 
 import (
 	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
 	"testing"
 	"time"
 )
 
 func TestReadingsMeasurementToPoint(t *testing.T) {
 	now := time.Now()
-	m := NewReadingsMeasurement(now)
+	m := NewReadingsMeasurement(common.Rand(), now)
 	duration := time.Second
 	m.Tick(duration)
 
@@ -0,0 +1,240 @@
+package iot
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
+const (
+	// RouteMinLatitude, RouteMaxLatitude, RouteMinLongitude and
+	// RouteMaxLongitude bound the road grid the trucks drive on with routes.
+	RouteMinLatitude  = 30.0
+	RouteMaxLatitude  = 48.0
+	RouteMinLongitude = -120.0
+	RouteMaxLongitude = -75.0
+
+	// roadSpacing is the distance in degrees between two parallel roads of
+	// the grid, about 5 km
+	roadSpacing = 0.05
+	// maxTripRoads is the most roads a destination is away from the start of
+	// a trip, along each axis
+	maxTripRoads = 40
+	kmPerDegree  = 111.32
+
+	// acceleration is how fast in km/h a truck speeds up or brakes per hour,
+	// i.e. 2 km/h per second
+	acceleration    = 2 * 3600.0
+	minCruiseSpeed  = 50.0
+	arrivalDistance = 0.05
+
+	// a truck stops at an intersection with stopChance, for up to maxStop,
+	// and unloads at the end of each trip for minDelivery to maxDelivery
+	stopChance  = 0.05
+	maxStop     = 5 * time.Minute
+	minDelivery = 15 * time.Minute
+	maxDelivery = 2 * time.Hour
+)
+
+var (
+	roadRows = int(math.Round((RouteMaxLatitude - RouteMinLatitude) / roadSpacing))
+	roadCols = int(math.Round((RouteMaxLongitude - RouteMinLongitude) / roadSpacing))
+)
+
+// route drives a truck on a grid of roads: from an intersection to the next
+// towards the destination of its trip, speeding up to a cruise speed, braking
+// for stops at intersections, and unloading at the destination.
+type route struct {
+	latitude, longitude float64
+	velocity, heading   float64
+
+	// row and col are the intersection the truck drives to, which it stops
+	// at if stopNext
+	row, col         int
+	stopNext         bool
+	destRow, destCol int
+	cruise           float64
+	stopLeft         time.Duration
+	// rng is the source of randomness of the simulator of the truck
+	rng *rand.Rand
+}
+
+// newRoute places a truck at a random intersection, unloading so that the
+// trucks do not all leave at once.
+func newRoute(rng *rand.Rand) *route {
+	r := &route{
+		row:      rng.Intn(roadRows + 1),
+		col:      rng.Intn(roadCols + 1),
+		stopLeft: time.Duration(rng.Int63n(int64(maxDelivery))),
+		rng:      rng,
+	}
+	r.latitude, r.longitude = intersection(r.row, r.col)
+	r.destRow, r.destCol = r.row, r.col
+	return r
+}
+
+// intersection returns the coordinates of an intersection of the grid.
+func intersection(row, col int) (float64, float64) {
+	return RouteMinLatitude + float64(row)*roadSpacing, RouteMinLongitude + float64(col)*roadSpacing
+}
+
+// advance drives the truck for the duration.
+func (r *route) advance(d time.Duration) {
+	if r.stopLeft > 0 {
+		r.stopLeft -= d
+		if r.stopLeft >= 0 {
+			return
+		}
+		d, r.stopLeft = -r.stopLeft, 0
+		r.nextWaypoint()
+	}
+
+	hours := d.Hours()
+	velocity := math.Min(r.cruise, r.velocity+acceleration*hours)
+	if r.stopNext {
+		velocity = math.Min(velocity, math.Sqrt(2*acceleration*r.distance()))
+	}
+	travel := (r.velocity + velocity) / 2 * hours
+	r.velocity = velocity
+
+	for {
+		left := r.distance()
+		if r.stopNext && left-travel < arrivalDistance {
+			r.latitude, r.longitude = intersection(r.row, r.col)
+			r.velocity = 0
+			r.stop()
+			return
+		}
+		if travel < left {
+			r.move(travel)
+			return
+		}
+		travel -= left
+		r.latitude, r.longitude = intersection(r.row, r.col)
+		r.nextWaypoint()
+	}
+}
+
+// distance returns the distance in km to the next intersection.
+func (r *route) distance() float64 {
+	lat, lon := intersection(r.row, r.col)
+	return (math.Abs(lat-r.latitude) + math.Abs(lon-r.longitude)*math.Cos(r.latitude*math.Pi/180)) * kmPerDegree
+}
+
+// move drives the truck by km towards the next intersection.
+func (r *route) move(km float64) {
+	switch r.heading {
+	case 0:
+		r.latitude += km / kmPerDegree
+	case 180:
+		r.latitude -= km / kmPerDegree
+	case 90:
+		r.longitude += km / (kmPerDegree * math.Cos(r.latitude*math.Pi/180))
+	case 270:
+		r.longitude -= km / (kmPerDegree * math.Cos(r.latitude*math.Pi/180))
+	}
+}
+
+// stop starts the stop of the truck at the intersection it reached: a
+// delivery at the destination, else a short stop.
+func (r *route) stop() {
+	if r.row == r.destRow && r.col == r.destCol {
+		r.stopLeft = minDelivery + time.Duration(r.rng.Int63n(int64(maxDelivery-minDelivery)))
+	} else {
+		r.stopLeft = time.Duration(r.rng.Int63n(int64(maxStop)))
+	}
+	if r.stopLeft == 0 {
+		r.nextWaypoint()
+	}
+}
+
+// nextWaypoint picks the next intersection towards the destination, starting
+// a new trip at the destination. The axis to drive along is picked in
+// proportion to the roads left along it, which makes routes a staircase
+// around the straight line to the destination.
+func (r *route) nextWaypoint() {
+	if r.row == r.destRow && r.col == r.destCol {
+		for r.row == r.destRow && r.col == r.destCol {
+			r.destRow = clampRoad(r.row+r.rng.Intn(2*maxTripRoads+1)-maxTripRoads, roadRows)
+			r.destCol = clampRoad(r.col+r.rng.Intn(2*maxTripRoads+1)-maxTripRoads, roadCols)
+		}
+		r.cruise = minCruiseSpeed + r.rng.Float64()*(maxVelocity-minCruiseSpeed)
+	}
+
+	rows, cols := r.destRow-r.row, r.destCol-r.col
+	if r.rng.Intn(abs(rows)+abs(cols)) < abs(rows) {
+		r.row += sign(rows)
+		r.heading = 0
+		if rows < 0 {
+			r.heading = 180
+		}
+	} else {
+		r.col += sign(cols)
+		r.heading = 90
+		if cols < 0 {
+			r.heading = 270
+		}
+	}
+	r.stopNext = r.row == r.destRow && r.col == r.destCol || r.rng.Float64() < stopChance
+}
+
+func clampRoad(i, max int) int {
+	if i < 0 {
+		return 0
+	}
+	if i > max {
+		return max
+	}
+	return i
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
+
+func sign(i int) int {
+	if i < 0 {
+		return -1
+	}
+	return 1
+}
+
+// routeValue is a Distribution of the readings that reports a value of the
+// route, which the ReadingsMeasurement advances.
+type routeValue struct {
+	get       func() float64
+	precision float64
+}
+
+// Advance does nothing: the route advances with the measurement.
+func (v *routeValue) Advance() {}
+
+// Get returns the value of the route, rounded like the other readings.
+func (v *routeValue) Get() float64 {
+	return math.Round(v.get()*v.precision) / v.precision
+}
+
+// routedReadingsFields returns the readings fields of a truck driving the
+// route: its position, velocity and heading come from the route, the other
+// fields are simulated as without routes.
+func routedReadingsFields(r *route) []common.LabeledDistributionMaker {
+	values := map[string]*routeValue{
+		string(labelLatitude):  {get: func() float64 { return r.latitude }, precision: 1e5},
+		string(labelLongitude): {get: func() float64 { return r.longitude }, precision: 1e5},
+		string(labelVelocity):  {get: func() float64 { return r.velocity }, precision: 1},
+		string(labelHeading):   {get: func() float64 { return r.heading }, precision: 1},
+	}
+	fields := make([]common.LabeledDistributionMaker, len(readingsFields))
+	for i, f := range readingsFields {
+		fields[i] = f
+		if v, ok := values[string(f.Label)]; ok {
+			fields[i].DistributionMaker = func(*rand.Rand) common.Distribution { return v }
+		}
+	}
+	return fields
+}
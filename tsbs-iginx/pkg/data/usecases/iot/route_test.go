@@ -0,0 +1,80 @@
+package iot
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data/usecases/common"
+)
+
+// onRoad tells whether a coordinate is on a road of the grid.
+func onRoad(min, coordinate float64) bool {
+	roads := (coordinate - min) / roadSpacing
+	return math.Abs(roads-math.Round(roads)) < 1e-6
+}
+
+func TestRouteAdvance(t *testing.T) {
+	common.Seed(123)
+	tick := 10 * time.Second
+	for i := 0; i < 10; i++ {
+		r := newRoute(common.Rand())
+		stops, moves := 0, 0
+		for j := 0; j < 24*360; j++ {
+			lat, lon := r.latitude, r.longitude
+			wasStopped := r.stopLeft > 0
+			r.advance(tick)
+
+			if r.latitude < RouteMinLatitude || r.latitude > RouteMaxLatitude || r.longitude < RouteMinLongitude || r.longitude > RouteMaxLongitude {
+				t.Fatalf("route %d left the road grid: %f, %f", i, r.latitude, r.longitude)
+			}
+			if !onRoad(RouteMinLatitude, r.latitude) && !onRoad(RouteMinLongitude, r.longitude) {
+				t.Fatalf("route %d left the roads: %f, %f", i, r.latitude, r.longitude)
+			}
+			if r.velocity < 0 || r.velocity > maxVelocity {
+				t.Fatalf("route %d has incorrect velocity %f", i, r.velocity)
+			}
+
+			// the truck moves by at most its top speed, and stays put when
+			// stopped
+			km := (math.Abs(r.latitude-lat) + math.Abs(r.longitude-lon)*math.Cos(lat*math.Pi/180)) * kmPerDegree
+			if km > maxVelocity*tick.Hours()+1e-6 {
+				t.Fatalf("route %d moved %f km in a tick", i, km)
+			}
+			if wasStopped && r.stopLeft > 0 {
+				stops++
+				if km > 0 || r.velocity != 0 {
+					t.Fatalf("route %d moved while stopped", i)
+				}
+			} else if km > 0 {
+				moves++
+			}
+		}
+		if stops == 0 || moves == 0 {
+			t.Errorf("route %d did not both drive and stop in a day: %d stopped ticks, %d moving ticks", i, stops, moves)
+		}
+	}
+}
+
+func TestRoutedReadingsMeasurementToPoint(t *testing.T) {
+	common.Seed(123)
+	m := NewRoutedReadingsMeasurement(common.Rand(), time.Now())
+	for i := 0; i < 360; i++ {
+		m.Tick(10 * time.Second)
+	}
+
+	p := m.route
+	want := map[string]float64{
+		string(labelLatitude):  math.Round(p.latitude*1e5) / 1e5,
+		string(labelLongitude): math.Round(p.longitude*1e5) / 1e5,
+		string(labelVelocity):  math.Round(p.velocity),
+		string(labelHeading):   p.heading,
+	}
+	for i, f := range readingsFields {
+		if v, ok := want[string(f.Label)]; ok {
+			if got := m.Distributions[i].Get(); got != v {
+				t.Errorf("incorrect %s: got %f want %f", f.Label, got, v)
+			}
+		}
+	}
+}
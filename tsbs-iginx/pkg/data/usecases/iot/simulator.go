@@ -3,6 +3,7 @@ package iot
 import (
 	"github.com/timescale/tsbs/pkg/data"
 	"github.com/timescale/tsbs/pkg/data/usecases/common"
+	"math/rand"
 	"time"
 )
 
@@ -13,12 +14,17 @@ const (
 
 // SimulatorConfig is used to create an IoT Simulator.
 // It fulfills the common.SimulatorConfig interface.
-type SimulatorConfig common.BaseSimulatorConfig
+type SimulatorConfig struct {
+	common.BaseSimulatorConfig
+	// NoBatchDisorder turns off the missing, out-of-order and empty batches
+	// and entries the Simulator introduces, so its entries come in order
+	NoBatchDisorder bool
+}
 
 // NewSimulator produces an IoT Simulator with the given
 // config over the specified interval and points limit.
-func (sc *SimulatorConfig) NewSimulator(interval time.Duration, limit uint64) common.Simulator {
-	s := (*common.BaseSimulatorConfig)(sc).NewSimulator(interval, limit)
+func (sc *SimulatorConfig) NewSimulator(r *rand.Rand, interval time.Duration, limit uint64) common.Simulator {
+	s := sc.BaseSimulatorConfig.NewSimulator(r, interval, limit)
 
 	maxFieldCount := 0
 
@@ -28,10 +34,20 @@ func (sc *SimulatorConfig) NewSimulator(interval time.Duration, limit uint64) co
 		}
 	}
 
+	var batchSize uint = defaultBatchSize
+	if sc.NoBatchDisorder {
+		batchSize = 0
+	}
+
+	// The batches are configured from the source of randomness of the simulator.
+	configGenerator := func(outOfOrderBatchCount, outOfOrderEntryCount, fieldCount, tagCount int) *batchConfig {
+		return newBatchConfig(r, outOfOrderBatchCount, outOfOrderEntryCount, fieldCount, tagCount)
+	}
+
 	return &Simulator{
 		base:            s,
-		batchSize:       defaultBatchSize,
-		configGenerator: newBatchConfig,
+		batchSize:       batchSize,
+		configGenerator: configGenerator,
 		maxFieldCount:   maxFieldCount,
 	}
 }
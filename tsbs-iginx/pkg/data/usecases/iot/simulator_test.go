@@ -559,14 +559,16 @@ func TestSimulatorNext(t *testing.T) {
 
 func TestSimulatorTagTypes(t *testing.T) {
 	sc := &SimulatorConfig{
-		Start: time.Now(),
-		End:   time.Now(),
+		BaseSimulatorConfig: common.BaseSimulatorConfig{
+			Start: time.Now(),
+			End:   time.Now(),
 
-		InitGeneratorScale:   1,
-		GeneratorScale:       1,
-		GeneratorConstructor: NewTruck,
+			InitGeneratorScale:   1,
+			GeneratorScale:       1,
+			GeneratorConstructor: NewTruck,
+		},
 	}
-	s := sc.NewSimulator(time.Second, 1).(*Simulator)
+	s := sc.NewSimulator(common.Rand(), time.Second, 1).(*Simulator)
 	p := data.NewPoint()
 	s.Next(p)
 	tagTypes := s.TagTypes()
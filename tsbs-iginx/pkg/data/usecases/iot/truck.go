@@ -88,31 +88,45 @@ func (t Truck) Tags() []common.Tag {
 	return t.tags
 }
 
-func newTruckMeasurements(start time.Time) []common.SimulatedMeasurement {
+func newTruckMeasurements(r *rand.Rand, start time.Time) []common.SimulatedMeasurement {
 	return []common.SimulatedMeasurement{
-		NewReadingsMeasurement(start),
-		NewDiagnosticsMeasurement(start),
+		NewReadingsMeasurement(r, start),
+		NewDiagnosticsMeasurement(r, start),
+	}
+}
+
+func newRoutedTruckMeasurements(r *rand.Rand, start time.Time) []common.SimulatedMeasurement {
+	return []common.SimulatedMeasurement{
+		NewRoutedReadingsMeasurement(r, start),
+		NewDiagnosticsMeasurement(r, start),
 	}
 }
 
 // NewTruck creates a new truck in a simulated iot use case
-func NewTruck(i int, start time.Time) common.Generator {
-	truck := newTruckWithMeasurementGenerator(i, start, newTruckMeasurements)
+func NewTruck(r *rand.Rand, i int, start time.Time) common.Generator {
+	truck := newTruckWithMeasurementGenerator(r, i, start, newTruckMeasurements)
+	return &truck
+}
+
+// NewRoutedTruck creates a new truck in a simulated iot use case, which
+// drives routes on a grid of roads rather than wandering at random.
+func NewRoutedTruck(r *rand.Rand, i int, start time.Time) common.Generator {
+	truck := newTruckWithMeasurementGenerator(r, i, start, newRoutedTruckMeasurements)
 	return &truck
 }
 
-func newTruckWithMeasurementGenerator(i int, start time.Time, generator func(time.Time) []common.SimulatedMeasurement) Truck {
-	sm := generator(start)
+func newTruckWithMeasurementGenerator(r *rand.Rand, i int, start time.Time, generator func(*rand.Rand, time.Time) []common.SimulatedMeasurement) Truck {
+	sm := generator(r, start)
 
-	m := modelChoices[rand.Intn(len(modelChoices))]
+	m := modelChoices[r.Intn(len(modelChoices))]
 
 	h := Truck{
 		tags: []common.Tag{
 			{Key: []byte("name"), Value: fmt.Sprintf(truckNameFmt, i)},
-			{Key: []byte("fleet"), Value: common.RandomStringSliceChoice(FleetChoices)},
-			{Key: []byte("driver"), Value: common.RandomStringSliceChoice(driverChoices)},
+			{Key: []byte("fleet"), Value: common.RandomStringSliceChoice(r, FleetChoices)},
+			{Key: []byte("driver"), Value: common.RandomStringSliceChoice(r, driverChoices)},
 			{Key: []byte("model"), Value: m.Name},
-			{Key: []byte("device_version"), Value: common.RandomStringSliceChoice(deviceVersionChoices)},
+			{Key: []byte("device_version"), Value: common.RandomStringSliceChoice(r, deviceVersionChoices)},
 			{Key: []byte("load_capacity"), Value: m.LoadCapacity},
 			{Key: []byte("fuel_capacity"), Value: m.FuelCapacity},
 			{Key: []byte("nominal_fuel_consumption"), Value: m.FuelConsumption},
@@ -3,11 +3,12 @@ package iot
 import (
 	"github.com/timescale/tsbs/pkg/data"
 	"github.com/timescale/tsbs/pkg/data/usecases/common"
+	"math/rand"
 	"testing"
 	"time"
 )
 
-func testGenerator(s time.Time) []common.SimulatedMeasurement {
+func testGenerator(_ *rand.Rand, s time.Time) []common.SimulatedMeasurement {
 	return []common.SimulatedMeasurement{
 		&testMeasurement{ticks: 0},
 	}
@@ -23,7 +24,7 @@ func (m *testMeasurement) ToPoint(_ *data.Point) {}
 func TestNewTruckMeasurements(t *testing.T) {
 	start := time.Now()
 
-	measurements := newTruckMeasurements(start)
+	measurements := newTruckMeasurements(common.Rand(), start)
 
 	if got := len(measurements); got != 2 {
 		t.Errorf("incorrect number of measurements: got %d want %d", got, 2)
@@ -43,7 +44,7 @@ func TestNewTruckMeasurements(t *testing.T) {
 
 func TestNewTruck(t *testing.T) {
 	start := time.Now()
-	generator := NewTruck(1, start)
+	generator := NewTruck(common.Rand(), 1, start)
 
 	truck := generator.(*Truck)
 
@@ -58,7 +59,7 @@ func TestNewTruck(t *testing.T) {
 
 func TestTruckTickAll(t *testing.T) {
 	now := time.Now()
-	truck := newTruckWithMeasurementGenerator(0, now, testGenerator)
+	truck := newTruckWithMeasurementGenerator(common.Rand(), 0, now, testGenerator)
 	if got := truck.simulatedMeasurements[0].(*testMeasurement).ticks; got != 0 {
 		t.Errorf("ticks not equal to 0 to start: got %d", got)
 	}
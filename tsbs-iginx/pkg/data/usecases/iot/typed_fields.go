@@ -0,0 +1,16 @@
+package iot
+
+import "github.com/timescale/tsbs/pkg/data/usecases/common"
+
+// TypedFields are the string and boolean fields added to the IoT
+// measurements with --non-numeric-fields.
+var TypedFields = map[string][]common.TypedField{
+	string(labelDiagnostics): {
+		// updated over the air from time to time
+		{Label: []byte("firmware_version"), Values: []string{"4.0.1", "4.1.0", "4.2.3", "5.0.0"}, ChangeChance: 0.001},
+		{Label: []byte("engine_alarm"), TrueChance: 0.05, ChangeChance: 0.02},
+	},
+	string(labelReadings): {
+		{Label: []byte("gps_fix"), TrueChance: 0.95, ChangeChance: 0.1},
+	},
+}
@@ -4,12 +4,19 @@ import (
 	"fmt"
 	"github.com/timescale/tsbs/internal/utils"
 	"github.com/timescale/tsbs/pkg/data/usecases/common"
+	"github.com/timescale/tsbs/pkg/data/usecases/custom"
 	"github.com/timescale/tsbs/pkg/data/usecases/devops"
+	"github.com/timescale/tsbs/pkg/data/usecases/imported"
+	"github.com/timescale/tsbs/pkg/data/usecases/industrial"
 	"github.com/timescale/tsbs/pkg/data/usecases/iot"
+	"github.com/timescale/tsbs/pkg/targets/constants"
 	"math"
 )
 
-const errCannotParseTimeFmt = "cannot parse time from string '%s': %v"
+const (
+	errCannotParseTimeFmt  = "cannot parse time from string '%s': %v"
+	errTimescaleDBBlockFmt = "timescaledb tables hold at most %d sensors per block"
+)
 
 func GetSimulatorConfig(dgc *common.DataGeneratorConfig) (common.SimulatorConfig, error) {
 	var ret common.SimulatorConfig
@@ -23,6 +30,26 @@ func GetSimulatorConfig(dgc *common.DataGeneratorConfig) (common.SimulatorConfig
 		return nil, fmt.Errorf(errCannotParseTimeFmt, dgc.TimeEnd, err)
 	}
 
+	if dgc.Imported() {
+		mapping, err := imported.LoadMapping(dgc.Mapping)
+		if err != nil {
+			return nil, err
+		}
+		scfg := &imported.SimulatorConfig{
+			Mapping:   mapping,
+			Format:    dgc.Source,
+			File:      dgc.SourceFile,
+			Start:     tsStart,
+			End:       tsEnd,
+			TimeShift: dgc.SourceTimeShift,
+			Scale:     dgc.Scale,
+		}
+		if err := scfg.Open(); err != nil {
+			return nil, err
+		}
+		return withDisorder(dgc, scfg), nil
+	}
+
 	switch dgc.Use {
 	case common.UseCaseDevops:
 		ret = &devops.DevopsSimulatorConfig{
@@ -32,15 +59,26 @@ func GetSimulatorConfig(dgc *common.DataGeneratorConfig) (common.SimulatorConfig
 			InitHostCount:   dgc.InitialScale,
 			HostCount:       dgc.Scale,
 			HostConstructor: devops.NewHost,
+			ChurnRate:       dgc.ChurnRate,
 		}
 	case common.UseCaseIoT:
+		truckConstructor := iot.NewTruck
+		if dgc.TruckRoutes {
+			truckConstructor = iot.NewRoutedTruck
+		}
 		ret = &iot.SimulatorConfig{
-			Start: tsStart,
-			End:   tsEnd,
+			BaseSimulatorConfig: common.BaseSimulatorConfig{
+				Start: tsStart,
+				End:   tsEnd,
 
-			InitGeneratorScale:   dgc.InitialScale,
-			GeneratorScale:       dgc.Scale,
-			GeneratorConstructor: iot.NewTruck,
+				InitGeneratorScale:   dgc.InitialScale,
+				GeneratorScale:       dgc.Scale,
+				GeneratorConstructor: truckConstructor,
+				ChurnRate:            dgc.ChurnRate,
+				Timing:               dgc.Timing(),
+			},
+			// the disorder chances, once set, replace the batch disorder
+			NoBatchDisorder: dgc.DisorderSet,
 		}
 	case common.UseCaseCPUOnly:
 		ret = &devops.CPUOnlySimulatorConfig{
@@ -50,6 +88,7 @@ func GetSimulatorConfig(dgc *common.DataGeneratorConfig) (common.SimulatorConfig
 			InitHostCount:   dgc.InitialScale,
 			HostCount:       dgc.Scale,
 			HostConstructor: devops.NewHostCPUOnly,
+			ChurnRate:       dgc.ChurnRate,
 		}
 	case common.UseCaseCPUSingle:
 		ret = &devops.CPUOnlySimulatorConfig{
@@ -59,6 +98,7 @@ func GetSimulatorConfig(dgc *common.DataGeneratorConfig) (common.SimulatorConfig
 			InitHostCount:   dgc.InitialScale,
 			HostCount:       dgc.Scale,
 			HostConstructor: devops.NewHostCPUSingle,
+			ChurnRate:       dgc.ChurnRate,
 		}
 	case common.UseCaseDevopsGeneric:
 		if dgc.InitialScale == dgc.Scale {
@@ -76,8 +116,71 @@ func GetSimulatorConfig(dgc *common.DataGeneratorConfig) (common.SimulatorConfig
 				MaxMetricCount:  dgc.MaxMetricCountPerHost,
 			},
 		}
+	case common.UseCaseCustom:
+		schema, err := custom.LoadSchema(dgc.Schema)
+		if err != nil {
+			return nil, err
+		}
+		if err := schema.CheckInterval(dgc.LogInterval); err != nil {
+			return nil, err
+		}
+		ret = &custom.SimulatorConfig{
+			Schema: schema,
+			Start:  tsStart,
+			End:    tsEnd,
+
+			InitGeneratorScale: dgc.InitialScale,
+			GeneratorScale:     dgc.Scale,
+		}
+	case common.UseCaseIndustrial:
+		layout := industrial.Layout{
+			Sensors:         int(dgc.SensorsPerDevice),
+			SensorsPerBlock: int(dgc.SensorsPerBlock),
+		}
+		if err := layout.Validate(); err != nil {
+			return nil, err
+		}
+		if dgc.Format == constants.FormatTimescaleDB && layout.SensorsPerBlock > industrial.MaxTimescaleDBSensorsPerBlock {
+			return nil, fmt.Errorf(errTimescaleDBBlockFmt, industrial.MaxTimescaleDBSensorsPerBlock)
+		}
+		ret = &industrial.SimulatorConfig{
+			Start: tsStart,
+			End:   tsEnd,
+
+			InitGeneratorScale: dgc.InitialScale,
+			GeneratorScale:     dgc.Scale,
+			Layout:             layout,
+			Timing:             dgc.Timing(),
+		}
 	default:
 		err = fmt.Errorf("unknown use case: '%s'", dgc.Use)
 	}
-	return ret, err
+	if err != nil {
+		return nil, err
+	}
+	return withDisorder(dgc, withNonNumericFields(dgc, ret)), nil
+}
+
+// withNonNumericFields wraps the config so that its simulators add the
+// string and boolean fields of the use case, if the DataGeneratorConfig
+// asks for them.
+func withNonNumericFields(dgc *common.DataGeneratorConfig, scfg common.SimulatorConfig) common.SimulatorConfig {
+	if !dgc.NonNumericFields {
+		return scfg
+	}
+	fields := devops.TypedFields
+	if dgc.Use == common.UseCaseIoT {
+		fields = iot.TypedFields
+	}
+	return &common.TypedFieldsSimulatorConfig{SimulatorConfig: scfg, Fields: fields}
+}
+
+// withDisorder wraps the config so that its simulators drop, delay and
+// duplicate points as the DataGeneratorConfig declares.
+func withDisorder(dgc *common.DataGeneratorConfig, scfg common.SimulatorConfig) common.SimulatorConfig {
+	disorder := dgc.Disorder()
+	if !disorder.Enabled() {
+		return scfg
+	}
+	return &common.DisorderSimulatorConfig{SimulatorConfig: scfg, Disorder: disorder}
 }
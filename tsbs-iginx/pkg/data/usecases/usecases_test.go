@@ -1,9 +1,14 @@
 package usecases
 
 import (
+	"github.com/timescale/tsbs/pkg/data"
 	"github.com/timescale/tsbs/pkg/data/usecases/common"
+	"github.com/timescale/tsbs/pkg/data/usecases/custom"
 	"github.com/timescale/tsbs/pkg/data/usecases/devops"
+	"github.com/timescale/tsbs/pkg/data/usecases/imported"
+	"github.com/timescale/tsbs/pkg/data/usecases/industrial"
 	"github.com/timescale/tsbs/pkg/data/usecases/iot"
+	"github.com/timescale/tsbs/pkg/targets/constants"
 	"reflect"
 	"testing"
 	"time"
@@ -38,6 +43,54 @@ func TestGetSimulatorConfig(t *testing.T) {
 	checkType(common.UseCaseIoT, &iot.SimulatorConfig{})
 	checkType(common.UseCaseCPUOnly, &devops.CPUOnlySimulatorConfig{})
 	checkType(common.UseCaseCPUSingle, &devops.CPUOnlySimulatorConfig{})
+	dgc.Schema = "../../../docs/sample-configs/custom-factory-schema.yaml"
+	checkType(common.UseCaseCustom, &custom.SimulatorConfig{})
+
+	dgc.LogInterval = 7 * time.Second
+	if _, err := GetSimulatorConfig(dgc); err == nil {
+		t.Errorf("unexpected lack of error for schema interval not a multiple of the log interval")
+	}
+	dgc.LogInterval = defaultLogInterval
+	dgc.Schema = ""
+	if _, err := GetSimulatorConfig(dgc); err == nil {
+		t.Errorf("unexpected lack of error for custom use case without schema")
+	}
+
+	dgc.SensorsPerDevice = 2000
+	dgc.SensorsPerBlock = 2000
+	checkType(common.UseCaseIndustrial, &industrial.SimulatorConfig{})
+	dgc.Format = constants.FormatTimescaleDB
+	if _, err := GetSimulatorConfig(dgc); err == nil {
+		t.Errorf("unexpected lack of error for a block too wide for timescaledb")
+	}
+	dgc.Format = ""
+	dgc.SensorsPerBlock = 0
+	if _, err := GetSimulatorConfig(dgc); err == nil {
+		t.Errorf("unexpected lack of error for industrial use case without sensors per block")
+	}
+
+	dgc.Source = common.SourceCSV
+	dgc.SourceFile = "../../../docs/sample-configs/import-plant.csv"
+	dgc.Mapping = "../../../docs/sample-configs/import-plant-mapping.yaml"
+	checkType("", &imported.SimulatorConfig{})
+	dgc.SourceFile += ".missing"
+	if _, err := GetSimulatorConfig(dgc); err == nil {
+		t.Errorf("unexpected lack of error for missing dataset")
+	}
+	dgc.Source = ""
+
+	dgc.DuplicateChance = 0.1
+	checkType(common.UseCaseDevops, &common.DisorderSimulatorConfig{})
+	dgc.DuplicateChance = 0
+
+	dgc.NonNumericFields = true
+	checkType(common.UseCaseIoT, &common.TypedFieldsSimulatorConfig{})
+	sim, _ := GetSimulatorConfig(dgc)
+	fields := sim.NewSimulator(common.Rand(), defaultLogInterval, 0).Headers().FieldKeys["diagnostics"]
+	if got := fields[len(fields)-1]; got != "engine_alarm" {
+		t.Errorf("incorrect last diagnostics field with non-numeric fields: %s", got)
+	}
+	dgc.NonNumericFields = false
 
 	dgc.Use = "bogus use case"
 	_, err := GetSimulatorConfig(dgc)
@@ -45,3 +98,61 @@ func TestGetSimulatorConfig(t *testing.T) {
 		t.Errorf("unexpected lack of error for bogus use case")
 	}
 }
+
+// outOfOrderPoints counts the points older than a point before them.
+func outOfOrderPoints(t *testing.T, dgc *common.DataGeneratorConfig) int {
+	scfg, err := GetSimulatorConfig(dgc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sim := scfg.NewSimulator(common.Rand(), defaultLogInterval, 0)
+	var latest time.Time
+	count := 0
+	for !sim.Finished() {
+		p := data.NewPoint()
+		if !sim.Next(p) {
+			continue
+		}
+		if p.Timestamp().Before(latest) {
+			count++
+		} else {
+			latest = *p.Timestamp()
+		}
+	}
+	return count
+}
+
+func TestGetSimulatorConfigIoTDisorder(t *testing.T) {
+	common.Seed(123)
+	dgc := &common.DataGeneratorConfig{
+		BaseConfig: common.BaseConfig{
+			Use:       common.UseCaseIoT,
+			Scale:     10,
+			TimeStart: "2020-01-01T00:00:00Z",
+			TimeEnd:   "2020-01-01T01:00:00Z",
+		},
+		InitialScale: 10,
+		LogInterval:  defaultLogInterval,
+	}
+	if got := outOfOrderPoints(t, dgc); got == 0 {
+		t.Errorf("no out-of-order points with the batch disorder of the iot use case")
+	}
+
+	// --out-of-order-chance=0 turns the batch disorder off
+	dgc.DisorderSet = true
+	if got := outOfOrderPoints(t, dgc); got != 0 {
+		t.Errorf("incorrect out-of-order points with an out-of-order chance of 0: got %d want 0", got)
+	}
+}
+
+func TestDisorderChanceSet(t *testing.T) {
+	set := map[string]bool{"max-lateness": true}
+	isSet := func(key string) bool { return set[key] }
+	if common.DisorderChanceSet(isSet) {
+		t.Errorf("disorder chance set with only max-lateness")
+	}
+	set["out-of-order-chance"] = true
+	if !common.DisorderChanceSet(isSet) {
+		t.Errorf("disorder chance not set with out-of-order-chance")
+	}
+}
@@ -2,16 +2,20 @@ package query
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"os"
+	"os/signal"
 	"runtime/pprof"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/spf13/pflag"
+	"github.com/timescale/tsbs/pkg/resources"
 	"golang.org/x/time/rate"
 )
 
@@ -25,19 +29,27 @@ const (
 
 // BenchmarkRunnerConfig is the configuration of the benchmark runner.
 type BenchmarkRunnerConfig struct {
-	DBName           string `mapstructure:"db-name"`
-	Limit            uint64 `mapstructure:"max-queries"`
-	LimitRPS         uint64 `mapstructure:"max-rps"`
-	MemProfile       string `mapstructure:"memprofile"`
-	HDRLatenciesFile string `mapstructure:"hdr-latencies"`
-	Workers          uint   `mapstructure:"workers"`
-	PrintResponses   bool   `mapstructure:"print-responses"`
-	Debug            int    `mapstructure:"debug"`
-	FileName         string `mapstructure:"file"`
-	BurnIn           uint64 `mapstructure:"burn-in"`
-	PrintInterval    uint64 `mapstructure:"print-interval"`
-	PrewarmQueries   bool   `mapstructure:"prewarm-queries"`
-	ResultsFile      string `mapstructure:"results-file"`
+	DBName           string        `mapstructure:"db-name"`
+	Limit            uint64        `mapstructure:"max-queries"`
+	LimitRPS         uint64        `mapstructure:"max-rps"`
+	MemProfile       string        `mapstructure:"memprofile"`
+	HDRLatenciesFile string        `mapstructure:"hdr-latencies"`
+	Workers          uint          `mapstructure:"workers"`
+	PrintResponses   bool          `mapstructure:"print-responses"`
+	Debug            int           `mapstructure:"debug"`
+	FileName         string        `mapstructure:"file"`
+	BurnIn           uint64        `mapstructure:"burn-in"`
+	PrintInterval    uint64        `mapstructure:"print-interval"`
+	PrewarmQueries   bool          `mapstructure:"prewarm-queries"`
+	ResultsFile      string        `mapstructure:"results-file"`
+	CaptureResults   string        `mapstructure:"capture-results"`
+	QueryTimeout     time.Duration `mapstructure:"query-timeout"`
+	InputFormat      string        `mapstructure:"input-format"`
+	Ramp             string        `mapstructure:"ramp"`
+	RampStepDuration time.Duration `mapstructure:"ramp-step-duration"`
+
+	ResourceSampleInterval time.Duration `mapstructure:"resource-sample-interval"`
+	ServerPID              int32         `mapstructure:"server-pid"`
 }
 
 // AddToFlagSet adds command line flags needed by the BenchmarkRunnerConfig to the flag set.
@@ -54,7 +66,13 @@ func (c BenchmarkRunnerConfig) AddToFlagSet(fs *pflag.FlagSet) {
 	fs.Bool("print-responses", false, "Pretty print response bodies for correctness checking (default false).")
 	fs.Int("debug", 0, "Whether to print debug messages.")
 	fs.String("file", "/home/humanfy/tmp_query", "File name to read queries from")
+	fs.String("input-format", FormatGob, "Format of the queries read: gob, as generated, or jsonl, as dumped by tsbs_query_tool")
 	fs.String("results-file", "", "Write the test results summary json to this file")
+	fs.Duration("query-timeout", 0, "Abort queries that take longer than this and count them as timeouts, 0 = no timeout")
+	fs.String("capture-results", "", "Write normalized query responses to this directory for comparison with tsbs_compare_results")
+	fs.String("ramp", "", "Comma separated, increasing worker counts (e.g. 1,2,4,8) to step through within one run, overrides --workers")
+	fs.Duration("ramp-step-duration", time.Minute, "How long each --ramp step runs before more workers are added")
+	resources.AddFlags(fs, "")
 }
 
 // BenchmarkRunner contains the common components for running a query benchmarking
@@ -65,6 +83,11 @@ type BenchmarkRunner struct {
 	sp      statProcessor
 	scanner *scanner
 	ch      chan Query
+	capture *resultCapture
+	ctx     context.Context
+	ramp    *ramp
+	// resources samples the usage of the client, nil if disabled
+	resources *resources.Sampler
 }
 
 // NewBenchmarkRunner creates a new instance of BenchmarkRunner which is
@@ -99,6 +122,23 @@ func (b *BenchmarkRunner) DebugLevel() int {
 	return b.Debug
 }
 
+// DoCaptureResults indicates whether normalized query responses should be
+// passed to CaptureResult
+func (b *BenchmarkRunner) DoCaptureResults() bool {
+	return len(b.CaptureResults) > 0
+}
+
+// CaptureResult stores the normalized response rows of query q in the capture
+// directory. It is a no-op unless --capture-results is set.
+func (b *BenchmarkRunner) CaptureResult(q Query, rows []ResultRow) {
+	if b.capture == nil {
+		return
+	}
+	if err := b.capture.write(q, rows); err != nil {
+		log.Fatalf("cannot capture result of query %d: %v", q.GetID(), err)
+	}
+}
+
 // DatabaseName returns the name of the database to run queries against
 func (b *BenchmarkRunner) DatabaseName() string {
 	return b.DBName
@@ -116,6 +156,17 @@ type Processor interface {
 	ProcessQuery(q Query, isWarm bool) ([]*Stat, error)
 }
 
+// ContextProcessor is a Processor that can abort a query when its context is
+// done. Only ContextProcessors are subject to --query-timeout; the runner calls
+// ProcessQueryContext instead of ProcessQuery for them.
+type ContextProcessor interface {
+	Processor
+
+	// ProcessQueryContext handles a given query, returning early with an error
+	// once ctx is done, and reports its stats
+	ProcessQueryContext(ctx context.Context, q Query, isWarm bool) ([]*Stat, error)
+}
+
 // GetBufferedReader returns the buffered Reader that should be used by the loader
 func (b *BenchmarkRunner) GetBufferedReader() *bufio.Reader {
 	if b.br == nil {
@@ -138,6 +189,17 @@ func (b *BenchmarkRunner) GetBufferedReader() *bufio.Reader {
 // It launches a gorountine to track stats, creates workers to process queries,
 // read in the input, execute the queries, and then does cleanup.
 func (b *BenchmarkRunner) Run(queryPool *sync.Pool, processorCreateFn ProcessorCreate) {
+	if len(b.Ramp) > 0 {
+		steps, err := parseRamp(b.Ramp)
+		if err != nil {
+			panic(err)
+		}
+		if b.RampStepDuration <= 0 {
+			panic("ramp step duration must be positive")
+		}
+		b.ramp = newRamp(steps, b.RampStepDuration)
+		b.Workers = b.ramp.maxWorkers()
+	}
 	if b.Workers == 0 {
 		panic("must have at least one worker")
 	}
@@ -148,6 +210,21 @@ func (b *BenchmarkRunner) Run(queryPool *sync.Pool, processorCreateFn ProcessorC
 	}
 	b.ch = make(chan Query, b.Workers)
 
+	// Cancelled on interrupt, so that in-flight queries are aborted and the
+	// statistics collected so far are still reported
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	b.ctx = ctx
+	go cancelOnInterrupt(ctx, cancel)
+
+	if b.DoCaptureResults() {
+		var err error
+		b.capture, err = newResultCapture(b.CaptureResults)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
 	// Launch the stats processor:
 	go b.sp.process(b.Workers)
 
@@ -156,27 +233,73 @@ func (b *BenchmarkRunner) Run(queryPool *sync.Pool, processorCreateFn ProcessorC
 	// Launch query processors
 	var wg sync.WaitGroup
 	for i := 0; i < int(b.Workers); i++ {
+		processor := processorCreateFn()
+		if _, ok := processor.(ContextProcessor); !ok && i == 0 && b.QueryTimeout > 0 {
+			log.Println("warning: this query processor does not support cancellation, --query-timeout is ignored")
+		}
 		wg.Add(1)
-		go b.processorHandler(&wg, rateLimiter, queryPool, processorCreateFn(), i)
+		go b.processorHandler(&wg, rateLimiter, queryPool, processor, i)
 	}
 
+	// When ramping, reading stops once the last step is over
+	scanCtx := ctx
+	if b.ramp != nil {
+		var stopScan context.CancelFunc
+		scanCtx, stopScan = context.WithCancel(ctx)
+		go func() {
+			b.ramp.run(ctx, os.Stderr)
+			stopScan()
+		}()
+	}
+
+	var err error
+	if b.resources, err = resources.NewSampler(b.ResourceSampleInterval, b.ServerPID); err != nil {
+		log.Printf("warning: %v", err)
+	}
+	b.resources.Start()
+
 	// Read in jobs, closing the job channel when done:
 	// Wall clock start time
 	wallStart := time.Now()
-	b.scanner.setReader(b.GetBufferedReader()).scan(queryPool, b.ch)
+	b.scanner.setReader(b.GetBufferedReader()).setContext(scanCtx).setFormat(b.InputFormat).scan(queryPool, b.ch)
 	close(b.ch)
 
 	// Block for workers to finish sending requests, closing the stats channel when done:
 	wg.Wait()
+	b.resources.Stop()
+	if b.ramp != nil {
+		// The queries may run out before the last step is over
+		b.ramp.finish()
+	}
+	interrupted := ctx.Err() != nil
+	if interrupted {
+		_, _ = fmt.Println("run interrupted: statistics below are partial")
+	}
 	b.sp.CloseAndWait()
 
+	if b.ramp != nil {
+		if err := b.ramp.write(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if b.capture != nil {
+		if err := b.capture.close(); err != nil {
+			log.Fatal(err)
+		}
+		_, _ = fmt.Printf("Captured query results to %s\n", b.CaptureResults)
+	}
+
 	// Wall clock end time
 	wallEnd := time.Now()
 	wallTook := wallEnd.Sub(wallStart)
-	_, err := fmt.Printf("wall clock time: %fsec\n", float64(wallTook.Nanoseconds())/1e9)
+	_, err = fmt.Printf("wall clock time: %fsec\n", float64(wallTook.Nanoseconds())/1e9)
 	if err != nil {
 		log.Fatal(err)
 	}
+	if b.resources != nil {
+		_, _ = fmt.Println(b.resources.Summary())
+	}
 
 	// (Optional) create a memory profile:
 	if len(b.MemProfile) > 0 {
@@ -190,19 +313,26 @@ func (b *BenchmarkRunner) Run(queryPool *sync.Pool, processorCreateFn ProcessorC
 
 	// (Optional) save the results file:
 	if len(b.BenchmarkRunnerConfig.ResultsFile) > 0 {
-		b.saveTestResult(wallTook, wallStart, wallEnd)
+		b.saveTestResult(wallTook, wallStart, wallEnd, interrupted)
 	}
 }
 
-func (b *BenchmarkRunner) saveTestResult(took time.Duration, start time.Time, end time.Time) {
+func (b *BenchmarkRunner) saveTestResult(took time.Duration, start time.Time, end time.Time, interrupted bool) {
 	testResult := LoaderTestResult{
 		ResultFormatVersion: BenchmarkTestResultVersion,
 		RunnerConfig:        b.BenchmarkRunnerConfig,
 		StartTime:           start.UTC().Unix() * 1000,
 		EndTime:             end.UTC().Unix() * 1000,
 		DurationMillis:      took.Milliseconds(),
+		Interrupted:         interrupted,
 		Totals:              b.sp.GetTotalsMap(),
 	}
+	if b.ramp != nil {
+		testResult.Totals["ramp"] = b.ramp.totals()
+	}
+	if b.resources != nil {
+		testResult.Totals["resources"] = b.resources.Totals()
+	}
 
 	_, _ = fmt.Printf("Saving results json file to %s\n", b.BenchmarkRunnerConfig.ResultsFile)
 	file, err := json.MarshalIndent(testResult, "", " ")
@@ -218,26 +348,47 @@ func (b *BenchmarkRunner) saveTestResult(took time.Duration, start time.Time, en
 
 func (b *BenchmarkRunner) processorHandler(wg *sync.WaitGroup, rateLimiter *rate.Limiter, queryPool *sync.Pool, processor Processor, workerNum int) {
 	processor.Init(workerNum)
+	ctx := b.context()
+	if b.ramp != nil && !b.ramp.waitActive(workerNum) {
+		// The ramp ended before this worker's step started
+		for query := range b.ch {
+			queryPool.Put(query)
+		}
+		wg.Done()
+		return
+	}
 	for query := range b.ch {
+		if ctx.Err() != nil || (b.ramp != nil && b.ramp.isFinished()) {
+			// The run was interrupted or the ramp is over, drain the remaining
+			// queries without running them
+			queryPool.Put(query)
+			continue
+		}
 		r := rateLimiter.Reserve()
 		time.Sleep(r.Delay())
 
-		stats, err := processor.ProcessQuery(query, false)
+		stats, timedOut, err := b.processQuery(ctx, processor, query, false)
 		if err != nil {
 			panic(err)
 		}
+		if b.ramp != nil {
+			b.ramp.record(stats)
+		}
 		b.sp.send(stats)
 
 		// If PrewarmQueries is set, we run the query as 'cold' first (see above),
 		// then we immediately run it a second time and report that as the 'warm' stat.
 		// This guarantees that the warm stat will reflect optimal cache performance.
 		spArgs := b.sp.getArgs()
-		if spArgs.prewarmQueries {
+		if spArgs.prewarmQueries && !timedOut && ctx.Err() == nil {
 			// Warm run
-			stats, err = processor.ProcessQuery(query, true)
+			stats, _, err = b.processQuery(ctx, processor, query, true)
 			if err != nil {
 				panic(err)
 			}
+			if b.ramp != nil {
+				b.ramp.record(stats)
+			}
 			b.sp.sendWarm(stats)
 		}
 		queryPool.Put(query)
@@ -245,6 +396,55 @@ func (b *BenchmarkRunner) processorHandler(wg *sync.WaitGroup, rateLimiter *rate
 	wg.Done()
 }
 
+// processQuery runs q on the processor. If the processor is a ContextProcessor
+// the query is subject to the query timeout; a query that times out is reported
+// with a timeout Stat instead of an error. Queries aborted because the run was
+// interrupted report nothing.
+func (b *BenchmarkRunner) processQuery(ctx context.Context, processor Processor, q Query, isWarm bool) (stats []*Stat, timedOut bool, err error) {
+	cp, ok := processor.(ContextProcessor)
+	if !ok {
+		stats, err = processor.ProcessQuery(q, isWarm)
+		return stats, false, err
+	}
+
+	qctx := ctx
+	if b.QueryTimeout > 0 {
+		var cancel context.CancelFunc
+		qctx, cancel = context.WithTimeout(ctx, b.QueryTimeout)
+		defer cancel()
+	}
+	stats, err = cp.ProcessQueryContext(qctx, q, isWarm)
+	if err != nil && qctx.Err() != nil {
+		if ctx.Err() != nil {
+			return nil, false, nil
+		}
+		return []*Stat{GetTimeoutStat(q.HumanLabelName())}, true, nil
+	}
+	return stats, false, err
+}
+
+// context returns the context of the current run
+func (b *BenchmarkRunner) context() context.Context {
+	if b.ctx == nil {
+		return context.Background()
+	}
+	return b.ctx
+}
+
+// cancelOnInterrupt calls cancel when the process receives SIGINT or SIGTERM
+// before ctx is done.
+func cancelOnInterrupt(ctx context.Context, cancel context.CancelFunc) {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigs)
+	select {
+	case <-sigs:
+		_, _ = fmt.Fprintln(os.Stderr, "interrupt received, aborting in-flight queries")
+		cancel()
+	case <-ctx.Done():
+	}
+}
+
 func getRateLimiter(limitRPS uint64, workers uint) *rate.Limiter {
 	var requestRate = rate.Inf
 	var requestBurst = 0
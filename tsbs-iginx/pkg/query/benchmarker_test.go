@@ -1,6 +1,7 @@
 package query
 
 import (
+	"context"
 	"golang.org/x/time/rate"
 	"io/ioutil"
 	"math"
@@ -9,6 +10,7 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 )
 
 type testProcessor struct {
@@ -362,3 +364,83 @@ func TestGetRateLimiter(t *testing.T) {
 		})
 	}
 }
+
+type blockingProcessor struct {
+	testProcessor
+}
+
+func (p *blockingProcessor) ProcessQueryContext(ctx context.Context, _ Query, _ bool) ([]*Stat, error) {
+	p.count++
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestProcessorHandlerQueryTimeout(t *testing.T) {
+	qLimit := 3
+	b := NewBenchmarkRunner(BenchmarkRunnerConfig{QueryTimeout: time.Millisecond})
+	b.ch = make(chan Query, qLimit)
+
+	var timeouts []*Stat
+	lock := &sync.Mutex{}
+	b.sp = &mockStatProcessor{
+		args: &statProcessorArgs{prewarmQueries: true},
+		onSend: func(stats []*Stat) {
+			lock.Lock()
+			timeouts = append(timeouts, stats...)
+			lock.Unlock()
+		},
+	}
+
+	p := &blockingProcessor{}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go b.processorHandler(&wg, rate.NewLimiter(rate.Inf, 0), &testQueryPool, p, 0)
+	for i := 0; i < qLimit; i++ {
+		q := testQueryPool.Get().(*testQuery)
+		q.HumanLabel = []byte("label")
+		b.ch <- q
+	}
+	close(b.ch)
+	wg.Wait()
+
+	// timed out queries are not run again warm
+	if p.count != qLimit {
+		t.Errorf("incorrect number of queries run: got %d want %d", p.count, qLimit)
+	}
+	if len(timeouts) != qLimit {
+		t.Fatalf("incorrect number of stats: got %d want %d", len(timeouts), qLimit)
+	}
+	for _, s := range timeouts {
+		if !s.isTimeout || string(s.label) != "label" {
+			t.Errorf("expected timeout stat for 'label', got %+v", s)
+		}
+	}
+}
+
+func TestProcessorHandlerInterrupted(t *testing.T) {
+	qLimit := 5
+	b := NewBenchmarkRunner(BenchmarkRunnerConfig{})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	b.ctx = ctx
+	b.ch = make(chan Query, qLimit)
+	b.sp = &mockStatProcessor{
+		args: &statProcessorArgs{},
+		onSend: func(stats []*Stat) {
+			t.Errorf("no stats expected after interrupt")
+		},
+	}
+
+	p := &blockingProcessor{}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go b.processorHandler(&wg, rate.NewLimiter(rate.Inf, 0), &testQueryPool, p, 0)
+	for i := 0; i < qLimit; i++ {
+		b.ch <- testQueryPool.Get().(*testQuery)
+	}
+	close(b.ch)
+	wg.Wait()
+	if p.count != 0 {
+		t.Errorf("queries ran after interrupt: %d", p.count)
+	}
+}
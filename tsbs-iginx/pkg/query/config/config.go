@@ -27,8 +27,16 @@ type QueryGeneratorConfig struct {
 
 	ClickhouseUseTags bool `mapstructure:"clickhouse-use-tags"`
 
+	// SensorsPerDevice and SensorsPerBlock are the layout of the devices of
+	// the industrial use case, as given to tsbs_generate_data
+	SensorsPerDevice uint `mapstructure:"sensors-per-device"`
+	SensorsPerBlock  uint `mapstructure:"sensors-per-block"`
+
 	MongoUseNaive bool   `mapstructure:"mongo-use-native"`
 	DbName        string `mapstructure:"db-name"`
+
+	// Manifest of the data to query, checked against the config
+	Manifest string `mapstructure:"manifest"`
 }
 
 // Validate checks that the values of the QueryGeneratorConfig are reasonable.
@@ -62,5 +70,10 @@ func (c *QueryGeneratorConfig) AddToFlagSet(fs *pflag.FlagSet) {
 	fs.Bool("timescale-use-tags", true, "TimescaleDB only: Use separate tags table when querying")
 	fs.Bool("timescale-use-time-bucket", true, "TimescaleDB only: Use time bucket. Set to false to test on native PostgreSQL")
 
+	fs.Uint("sensors-per-device", 1000, "Industrial only: Number of sensors of each device, up to 10000")
+	fs.Uint("sensors-per-block", 100, "Industrial only: Number of sensors written together in a block")
+
 	fs.String("db-name", "benchmark", "Specify database name. Timestream requires it in order to generate the queries")
+
+	fs.String("manifest", "", "Check that the use case, scale and start time match this manifest written by tsbs_generate_data")
 }
@@ -0,0 +1,207 @@
+package query
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/HdrHistogram/hdrhistogram-go"
+)
+
+// MergedTestResult is the aggregate of the results of several query runner
+// processes that ran concurrently against the same database.
+type MergedTestResult struct {
+	LoaderTestResult
+	// Histograms holds the merged latency histogram of each (stripped) label
+	Histograms map[string]*hdrhistogram.Histogram `json:"-"`
+}
+
+// AllQueries returns the merged latency histogram over all queries
+func (m *MergedTestResult) AllQueries() *hdrhistogram.Histogram {
+	return m.Histograms[stripRegex(labelAllQueries)]
+}
+
+// ReadTestResult reads a results file written with --results-file
+func ReadTestResult(fileName string) (*LoaderTestResult, error) {
+	b, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	r := &LoaderTestResult{}
+	if err = json.Unmarshal(b, r); err != nil {
+		return nil, fmt.Errorf("cannot decode %s: %v", fileName, err)
+	}
+	return r, nil
+}
+
+// MergeTestResults combines the results of several runner processes into one.
+// Latency quantiles are computed from the merged histograms rather than
+// averaged, throughput is summed over processes, and the run's wall clock
+// window spans from the earliest start to the latest end. The window during
+// which all processes were running is reported as overlapMillis.
+func MergeTestResults(results []*LoaderTestResult) (*MergedTestResult, error) {
+	if len(results) == 0 {
+		return nil, errors.New("no results to merge")
+	}
+
+	first := results[0]
+	merged := &MergedTestResult{
+		LoaderTestResult: LoaderTestResult{
+			ResultFormatVersion: BenchmarkTestResultVersion,
+			RunnerConfig:        first.RunnerConfig,
+			StartTime:           first.StartTime,
+			EndTime:             first.EndTime,
+		},
+		Histograms: make(map[string]*hdrhistogram.Histogram),
+	}
+	merged.RunnerConfig.Workers = 0
+	merged.RunnerConfig.Limit = 0
+	overlapStart, overlapEnd := first.StartTime, first.EndTime
+
+	rates := make(map[string]float64)
+	timeouts := make(map[string]int64)
+	for i, r := range results {
+		if r.ResultFormatVersion != BenchmarkTestResultVersion {
+			return nil, fmt.Errorf("result %d: unsupported format version %s", i, r.ResultFormatVersion)
+		}
+		merged.RunnerConfig.Workers += r.RunnerConfig.Workers
+		merged.RunnerConfig.Limit += r.RunnerConfig.Limit
+		merged.Interrupted = merged.Interrupted || r.Interrupted
+		if r.StartTime < merged.StartTime {
+			merged.StartTime = r.StartTime
+		}
+		if r.EndTime > merged.EndTime {
+			merged.EndTime = r.EndTime
+		}
+		if r.StartTime > overlapStart {
+			overlapStart = r.StartTime
+		}
+		if r.EndTime < overlapEnd {
+			overlapEnd = r.EndTime
+		}
+
+		histograms, ok := r.Totals["histograms"].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("result %d has no latency histograms, it was written by an older runner", i)
+		}
+		for label, v := range histograms {
+			encoded, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("result %d: invalid histogram for %s", i, label)
+			}
+			h, err := hdrhistogram.Decode([]byte(encoded))
+			if err != nil {
+				return nil, fmt.Errorf("result %d: cannot decode histogram for %s: %v", i, label, err)
+			}
+			mergeHistogram(merged.Histograms, label, h)
+		}
+		for label, v := range mapOfTotals(r.Totals, "overallQueryRates") {
+			if f, ok := v.(float64); ok {
+				rates[label] += f
+			}
+		}
+		for label, v := range mapOfTotals(r.Totals, "timeouts") {
+			if f, ok := v.(float64); ok {
+				timeouts[label] += int64(f)
+			}
+		}
+	}
+	merged.DurationMillis = merged.EndTime - merged.StartTime
+
+	overlap := overlapEnd - overlapStart
+	if overlap < 0 {
+		overlap = 0
+	}
+	merged.Totals = map[string]interface{}{
+		"processes":         len(results),
+		"prewarmQueries":    first.Totals["prewarmQueries"],
+		"overlapMillis":     overlap,
+		"overallQueryRates": rates,
+		"timeouts":          timeouts,
+	}
+	quantiles := make(map[string]interface{})
+	histograms := make(map[string]interface{})
+	windowRates := make(map[string]interface{})
+	for label, h := range merged.Histograms {
+		count, q := generateQuantileMap(h)
+		quantiles[label] = q
+		if merged.DurationMillis > 0 {
+			windowRates[label] = float64(count) / (float64(merged.DurationMillis) / 1e3)
+		}
+		encoded, err := h.Encode(hdrhistogram.V2CompressedEncodingCookieBase)
+		if err != nil {
+			return nil, err
+		}
+		histograms[label] = string(encoded)
+	}
+	merged.Totals["overallQuantiles"] = quantiles
+	merged.Totals["wallClockQueryRates"] = windowRates
+	merged.Totals["histograms"] = histograms
+	return merged, nil
+}
+
+// MergeHDRLatencies merges latency histograms from files written with
+// --hdr-latencies. These files only contain the percentile distribution, so
+// each value is recorded at the upper bound of its percentile tick; merging
+// the results files is exact and should be preferred.
+func MergeHDRLatencies(readers []io.Reader) (*hdrhistogram.Histogram, error) {
+	merged := newStatGroup(0).latencyHDRHistogram
+	for i, r := range readers {
+		h, err := readHDRLatencies(r)
+		if err != nil {
+			return nil, fmt.Errorf("histogram %d: %v", i, err)
+		}
+		merged.Merge(h)
+	}
+	return merged, nil
+}
+
+// readHDRLatencies reconstructs a histogram from its percentile distribution
+// as written by WriteHDRLatencies
+func readHDRLatencies(r io.Reader) (*hdrhistogram.Histogram, error) {
+	h := newStatGroup(0).latencyHDRHistogram
+	scanner := bufio.NewScanner(r)
+	prevCount := int64(0)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 4 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		value, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			// header line
+			continue
+		}
+		total, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid total count %s", fields[2])
+		}
+		if total > prevCount {
+			if err = h.RecordValues(int64(value*hdrScaleFactor), total-prevCount); err != nil {
+				return nil, err
+			}
+			prevCount = total
+		}
+	}
+	return h, scanner.Err()
+}
+
+func mergeHistogram(hists map[string]*hdrhistogram.Histogram, label string, h *hdrhistogram.Histogram) {
+	if m, ok := hists[label]; ok {
+		m.Merge(h)
+		return
+	}
+	m := newStatGroup(0).latencyHDRHistogram
+	m.Merge(h)
+	hists[label] = m
+}
+
+func mapOfTotals(totals map[string]interface{}, key string) map[string]interface{} {
+	m, _ := totals[key].(map[string]interface{})
+	return m
+}
@@ -0,0 +1,96 @@
+package query
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/HdrHistogram/hdrhistogram-go"
+)
+
+func testResult(t *testing.T, start, end int64, workers uint, rate float64, latencies ...float64) *LoaderTestResult {
+	sg := newStatGroup(0)
+	for _, l := range latencies {
+		sg.push(l)
+	}
+	encoded, err := sg.latencyHDRHistogram.Encode(hdrhistogram.V2CompressedEncodingCookieBase)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return &LoaderTestResult{
+		ResultFormatVersion: BenchmarkTestResultVersion,
+		RunnerConfig:        BenchmarkRunnerConfig{Workers: workers},
+		StartTime:           start,
+		EndTime:             end,
+		Totals: map[string]interface{}{
+			"overallQueryRates": map[string]interface{}{"all_queries": rate},
+			"timeouts":          map[string]interface{}{"all_queries": float64(1)},
+			"histograms":        map[string]interface{}{"all_queries": string(encoded)},
+		},
+	}
+}
+
+func TestMergeTestResults(t *testing.T) {
+	a := testResult(t, 1000, 11000, 4, 10, 1, 2, 3)
+	b := testResult(t, 3000, 12000, 2, 5, 100, 200)
+	m, err := MergeTestResults([]*LoaderTestResult{a, b})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m.RunnerConfig.Workers != 6 {
+		t.Errorf("incorrect workers: got %d want 6", m.RunnerConfig.Workers)
+	}
+	if m.StartTime != 1000 || m.EndTime != 12000 || m.DurationMillis != 11000 {
+		t.Errorf("incorrect window: %d-%d (%d)", m.StartTime, m.EndTime, m.DurationMillis)
+	}
+	if got := m.Totals["overlapMillis"].(int64); got != 8000 {
+		t.Errorf("incorrect overlap: got %d want 8000", got)
+	}
+	if got := m.Totals["overallQueryRates"].(map[string]float64)["all_queries"]; got != 15 {
+		t.Errorf("incorrect summed rate: got %f want 15", got)
+	}
+	if got := m.Totals["timeouts"].(map[string]int64)["all_queries"]; got != 2 {
+		t.Errorf("incorrect timeouts: got %d want 2", got)
+	}
+	all := m.AllQueries()
+	if all.TotalCount() != 5 {
+		t.Fatalf("incorrect merged count: got %d want 5", all.TotalCount())
+	}
+	// the merged median is the 3rd of 5 values, not an average of medians
+	if med := float64(all.ValueAtQuantile(50)) / hdrScaleFactor; med < 2.99 || med > 3.01 {
+		t.Errorf("incorrect merged median: got %f want 3", med)
+	}
+}
+
+func TestMergeTestResultsErrors(t *testing.T) {
+	if _, err := MergeTestResults(nil); err == nil {
+		t.Errorf("expected error for no results")
+	}
+	old := &LoaderTestResult{ResultFormatVersion: BenchmarkTestResultVersion, Totals: map[string]interface{}{}}
+	if _, err := MergeTestResults([]*LoaderTestResult{old}); err == nil {
+		t.Errorf("expected error for results without histograms")
+	}
+}
+
+func TestMergeHDRLatencies(t *testing.T) {
+	var bufs [2]bytes.Buffer
+	for i, latencies := range [][]float64{{1, 2, 3}, {10, 20}} {
+		sg := newStatGroup(0)
+		for _, l := range latencies {
+			sg.push(l)
+		}
+		if _, err := sg.latencyHDRHistogram.PercentilesPrint(&bufs[i], 10, 1000.0); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	h, err := MergeHDRLatencies([]io.Reader{&bufs[0], &bufs[1]})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if h.TotalCount() != 5 {
+		t.Errorf("incorrect merged count: got %d want 5", h.TotalCount())
+	}
+	if max := float64(h.Max()) / hdrScaleFactor; max < 19.9 || max > 20.1 {
+		t.Errorf("incorrect merged max: got %f want 20", max)
+	}
+}
@@ -0,0 +1,209 @@
+package query
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// FormatGob is the binary format query files are generated in
+	FormatGob = "gob"
+	// FormatJSONLines is a human-editable format with one JSON query per line
+	FormatJSONLines = "jsonl"
+
+	// jsonIDKey is the JSON key under which the query ID is stored
+	jsonIDKey = "ID"
+)
+
+// ValidFormats are the supported query file formats
+var ValidFormats = []string{FormatGob, FormatJSONLines}
+
+// Pools maps the name of each query type to the pool of its queries, for tools
+// that need to decode query files without a database specific runner.
+var Pools = map[string]*sync.Pool{
+	"cassandra":   &CassandraPool,
+	"clickhouse":  &ClickHousePool,
+	"cratedb":     &CrateDBPool,
+	"http":        &HTTPPool,
+	"iginx":       &IginxPool,
+	"mongo":       &MongoPool,
+	"siridb":      &SiriDBPool,
+	"timescaledb": &TimescaleDBPool,
+	"timestream":  &TimestreamPool,
+}
+
+// PoolNames returns the sorted names of the query types in Pools
+func PoolNames() []string {
+	names := make([]string, 0, len(Pools))
+	for n := range Pools {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Decoder decodes queries from a query file
+type Decoder interface {
+	Decode(q interface{}) error
+}
+
+// Encoder encodes queries to a query file
+type Encoder interface {
+	Encode(q interface{}) error
+}
+
+// NewDecoder returns a Decoder for the given query file format. Queries read
+// from a gob stream have no ID; queries read from JSON lines keep theirs.
+func NewDecoder(r io.Reader, format string) (Decoder, error) {
+	switch format {
+	case FormatGob, "":
+		return gob.NewDecoder(r), nil
+	case FormatJSONLines:
+		return &jsonLinesDecoder{r: bufio.NewReader(r)}, nil
+	}
+	return nil, fmt.Errorf("unknown query format '%s', valid: %s", format, strings.Join(ValidFormats, ", "))
+}
+
+// NewEncoder returns an Encoder for the given query file format
+func NewEncoder(w io.Writer, format string) (Encoder, error) {
+	switch format {
+	case FormatGob, "":
+		return gob.NewEncoder(w), nil
+	case FormatJSONLines:
+		return &jsonLinesEncoder{w: w}, nil
+	}
+	return nil, fmt.Errorf("unknown query format '%s', valid: %s", format, strings.Join(ValidFormats, ", "))
+}
+
+type jsonLinesDecoder struct {
+	r *bufio.Reader
+	n uint64
+}
+
+// Decode reads the next non-empty line into q, which must be a Query. Queries
+// without an ID get their line number (counting queries only) as ID.
+func (d *jsonLinesDecoder) Decode(q interface{}) error {
+	for {
+		line, err := d.r.ReadBytes('\n')
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		d.n++
+		if err = DecodeQueryJSON(line, q.(Query), d.n-1); err != nil {
+			return fmt.Errorf("query %d: %v", d.n-1, err)
+		}
+		return nil
+	}
+}
+
+type jsonLinesEncoder struct {
+	w io.Writer
+}
+
+// Encode writes q, which must be a Query, as a single JSON line
+func (e *jsonLinesEncoder) Encode(q interface{}) error {
+	b, err := EncodeQueryJSON(q.(Query))
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+	_, err = e.w.Write(b)
+	return err
+}
+
+// EncodeQueryJSON encodes the exported fields of a query, and its ID, as a JSON
+// object. Byte slice fields are written as strings so that SQL and request
+// bodies stay readable and editable.
+func EncodeQueryJSON(q Query) ([]byte, error) {
+	v, err := queryStruct(q)
+	if err != nil {
+		return nil, err
+	}
+	t := v.Type()
+	m := make(map[string]interface{}, t.NumField()+1)
+	m[jsonIDKey] = q.GetID()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			// unexported
+			continue
+		}
+		fv := v.Field(i)
+		if b, ok := fv.Interface().([]byte); ok {
+			m[f.Name] = string(b)
+		} else {
+			m[f.Name] = fv.Interface()
+		}
+	}
+	// comparison operators in SQL would otherwise be escaped as \u003c etc.
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err = enc.Encode(m); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+// DecodeQueryJSON sets q from a JSON object written by EncodeQueryJSON. Fields
+// missing from the object are zeroed. If the object has no ID, defaultID is used.
+func DecodeQueryJSON(b []byte, q Query, defaultID uint64) error {
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(b, &m); err != nil {
+		return err
+	}
+	v, err := queryStruct(q)
+	if err != nil {
+		return err
+	}
+	v.Set(reflect.Zero(v.Type()))
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		raw, ok := m[f.Name]
+		if f.PkgPath != "" || !ok {
+			continue
+		}
+		fv := v.Field(i)
+		if fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() == reflect.Uint8 {
+			var s string
+			if err = json.Unmarshal(raw, &s); err != nil {
+				return fmt.Errorf("field %s: %v", f.Name, err)
+			}
+			fv.SetBytes([]byte(s))
+			continue
+		}
+		if err = json.Unmarshal(raw, fv.Addr().Interface()); err != nil {
+			return fmt.Errorf("field %s: %v", f.Name, err)
+		}
+	}
+
+	id := defaultID
+	if raw, ok := m[jsonIDKey]; ok {
+		if err = json.Unmarshal(raw, &id); err != nil {
+			return fmt.Errorf("field %s: %v", jsonIDKey, err)
+		}
+	}
+	q.SetID(id)
+	return nil
+}
+
+func queryStruct(q Query) (reflect.Value, error) {
+	v := reflect.ValueOf(q)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("query must be a pointer to a struct, got %T", q)
+	}
+	return v.Elem(), nil
+}
@@ -0,0 +1,133 @@
+package query
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestQueryJSONRoundTrip(t *testing.T) {
+	q := NewHTTP()
+	q.HumanLabel = []byte("label")
+	q.HumanDescription = []byte("desc")
+	q.Method = []byte("POST")
+	q.Path = []byte("/api/v1/datapoints/query")
+	q.Body = []byte(`{"metrics":[{"name":"cpu"}]}`)
+	q.StartTimestamp = 10
+	q.EndTimestamp = 20
+	q.SetID(42)
+
+	b, err := EncodeQueryJSON(q)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(b), `"Method":"POST"`) {
+		t.Errorf("byte fields not encoded as strings: %s", b)
+	}
+	if strings.Contains(string(b), "id") {
+		t.Errorf("unexported field encoded: %s", b)
+	}
+
+	got := NewHTTP()
+	got.RawQuery = []byte("stale")
+	if err = DecodeQueryJSON(b, got, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.GetID() != 42 {
+		t.Errorf("incorrect ID: got %d want 42", got.GetID())
+	}
+	if string(got.Body) != string(q.Body) || string(got.Path) != string(q.Path) {
+		t.Errorf("incorrect fields: got %s", got)
+	}
+	if got.StartTimestamp != 10 || got.EndTimestamp != 20 {
+		t.Errorf("incorrect timestamps: got %d, %d", got.StartTimestamp, got.EndTimestamp)
+	}
+	if len(got.RawQuery) != 0 {
+		t.Errorf("missing field not zeroed: got %s", got.RawQuery)
+	}
+}
+
+func TestQueryJSONDefaultID(t *testing.T) {
+	q := NewHTTP()
+	if err := DecodeQueryJSON([]byte(`{"HumanLabel":"x"}`), q, 7); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if q.GetID() != 7 {
+		t.Errorf("incorrect ID: got %d want 7", q.GetID())
+	}
+	if err := DecodeQueryJSON([]byte(`{"Body":1}`), q, 7); err == nil {
+		t.Errorf("expected error for non-string byte field")
+	}
+}
+
+func TestNewDecoderUnknownFormat(t *testing.T) {
+	if _, err := NewDecoder(strings.NewReader(""), "xml"); err == nil {
+		t.Errorf("expected error for unknown format")
+	}
+	if _, err := NewEncoder(&bytes.Buffer{}, "xml"); err == nil {
+		t.Errorf("expected error for unknown format")
+	}
+}
+
+func TestScannerJSONLinesKeepsIDs(t *testing.T) {
+	input := `{"ID":5,"HumanLabel":"a"}
+
+{"ID":3,"HumanLabel":"b"}
+{"HumanLabel":"c"}
+`
+	limit := uint64(0)
+	queryChan := make(chan Query, 3)
+	newScanner(&limit).setFormat(FormatJSONLines).
+		setReader(bufio.NewReader(strings.NewReader(input))).
+		scan(&testQueryPool, queryChan)
+	close(queryChan)
+
+	want := []struct {
+		id    uint64
+		label string
+	}{{5, "a"}, {3, "b"}, {2, "c"}}
+	i := 0
+	for q := range queryChan {
+		if i >= len(want) {
+			t.Fatalf("too many queries scanned")
+		}
+		if q.GetID() != want[i].id || string(q.HumanLabelName()) != want[i].label {
+			t.Errorf("query %d: got ID %d label %s, want %d %s", i, q.GetID(), q.HumanLabelName(), want[i].id, want[i].label)
+		}
+		i++
+	}
+	if i != len(want) {
+		t.Errorf("incorrect number of queries: got %d want %d", i, len(want))
+	}
+}
+
+func TestJSONLinesEncoderDecoder(t *testing.T) {
+	var buf bytes.Buffer
+	enc, err := NewEncoder(&buf, FormatJSONLines)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := uint64(0); i < 3; i++ {
+		q := &testQuery{ID: i * 10, HumanLabel: []byte("l")}
+		if err = enc.Encode(q); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != 3 {
+		t.Errorf("incorrect number of lines: got %d want 3", lines)
+	}
+	dec, err := NewDecoder(&buf, FormatJSONLines)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := uint64(0); i < 3; i++ {
+		q := &testQuery{}
+		if err = dec.Decode(q); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if q.GetID() != i*10 {
+			t.Errorf("incorrect ID: got %d want %d", q.GetID(), i*10)
+		}
+	}
+}
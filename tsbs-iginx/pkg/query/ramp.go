@@ -0,0 +1,253 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rampKneeEfficiency is the minimum fraction of the ideal (linear) throughput
+// gain a ramp step must achieve over the previous one for throughput to be
+// considered still scaling with the number of workers.
+const rampKneeEfficiency = 0.25
+
+// parseRamp parses a comma separated list of strictly increasing worker counts
+func parseRamp(s string) ([]uint, error) {
+	parts := strings.Split(s, ",")
+	steps := make([]uint, 0, len(parts))
+	for _, p := range parts {
+		w, err := strconv.ParseUint(strings.TrimSpace(p), 10, 32)
+		if err != nil || w == 0 {
+			return nil, fmt.Errorf("invalid ramp step '%s': must be a positive number of workers", p)
+		}
+		if len(steps) > 0 && uint(w) <= steps[len(steps)-1] {
+			return nil, fmt.Errorf("invalid ramp '%s': worker counts must be increasing", s)
+		}
+		steps = append(steps, uint(w))
+	}
+	return steps, nil
+}
+
+// rampStep holds the statistics of the queries completed while a given number
+// of workers was active.
+type rampStep struct {
+	workers uint
+	start   time.Time
+	end     time.Time
+	stats   *statGroup
+}
+
+func (s *rampStep) duration() time.Duration {
+	return s.end.Sub(s.start)
+}
+
+// queryRate is the number of queries completed per second during the step
+func (s *rampStep) queryRate() float64 {
+	if s.duration() <= 0 {
+		return 0
+	}
+	return float64(s.stats.count) / s.duration().Seconds()
+}
+
+// ramp grows the number of active workers in steps of a fixed duration.
+// Workers beyond the current step's count block in waitActive until their
+// step starts. It is safe for concurrent use.
+type ramp struct {
+	mu           sync.Mutex
+	stepDuration time.Duration
+	steps        []*rampStep
+	current      int
+	finished     bool
+	changed      chan struct{} // closed and replaced whenever the step changes
+}
+
+func newRamp(workers []uint, stepDuration time.Duration) *ramp {
+	r := &ramp{stepDuration: stepDuration, changed: make(chan struct{})}
+	for _, w := range workers {
+		r.steps = append(r.steps, &rampStep{workers: w, stats: newStatGroup(0)})
+	}
+	return r
+}
+
+// maxWorkers is the number of workers of the last step
+func (r *ramp) maxWorkers() uint {
+	return r.steps[len(r.steps)-1].workers
+}
+
+// run starts the first step and advances to the next one every stepDuration.
+// It returns, marking the ramp finished, after the last step or once ctx is done.
+func (r *ramp) run(ctx context.Context, w io.Writer) {
+	r.mu.Lock()
+	r.steps[0].start = time.Now()
+	r.mu.Unlock()
+
+	t := time.NewTicker(r.stepDuration)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			r.finish()
+			return
+		}
+		r.mu.Lock()
+		if r.finished {
+			r.mu.Unlock()
+			return
+		}
+		now := time.Now()
+		step := r.steps[r.current]
+		step.end = now
+		_, _ = fmt.Fprintf(w, "ramp step %d/%d complete: %d workers, %0.2f queries/sec, p99 %0.2fms\n",
+			r.current+1, len(r.steps), step.workers, step.queryRate(),
+			float64(step.stats.latencyHDRHistogram.ValueAtQuantile(99.0))/hdrScaleFactor)
+		last := r.current == len(r.steps)-1
+		if last {
+			r.finished = true
+		} else {
+			r.current++
+			r.steps[r.current].start = now
+		}
+		close(r.changed)
+		r.changed = make(chan struct{})
+		r.mu.Unlock()
+		if last {
+			return
+		}
+	}
+}
+
+// finish ends the ramp early, e.g. because the queries ran out or the run was
+// interrupted, truncating the current step.
+func (r *ramp) finish() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.finished {
+		return
+	}
+	r.finished = true
+	r.steps[r.current].end = time.Now()
+	close(r.changed)
+	r.changed = make(chan struct{})
+}
+
+// isFinished reports whether the last step is over
+func (r *ramp) isFinished() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.finished
+}
+
+// waitActive blocks until the worker with the given number is part of the
+// current step. It returns false if the ramp finishes first.
+func (r *ramp) waitActive(workerNum int) bool {
+	for {
+		r.mu.Lock()
+		if r.finished {
+			r.mu.Unlock()
+			return false
+		}
+		if uint(workerNum) < r.steps[r.current].workers {
+			r.mu.Unlock()
+			return true
+		}
+		changed := r.changed
+		r.mu.Unlock()
+		<-changed
+	}
+}
+
+// record adds the stats of a completed query to the current step
+func (r *ramp) record(stats []*Stat) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.finished {
+		return
+	}
+	for _, s := range stats {
+		if s.isTimeout {
+			r.steps[r.current].stats.pushTimeout()
+		} else if !s.isPartial {
+			r.steps[r.current].stats.push(s.value)
+		}
+	}
+}
+
+// completedSteps returns the steps that were started
+func (r *ramp) completedSteps() []*rampStep {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.steps[:r.current+1]
+}
+
+// knee returns the index of the step after which throughput stops scaling,
+// i.e. the next step gains less than rampKneeEfficiency of the throughput that
+// its additional workers would ideally add. ok is false if throughput kept
+// scaling up to the last step.
+func (r *ramp) knee() (idx int, ok bool) {
+	steps := r.completedSteps()
+	for i := 0; i+1 < len(steps); i++ {
+		prev, next := steps[i], steps[i+1]
+		if prev.queryRate() == 0 {
+			continue
+		}
+		gain := next.queryRate()/prev.queryRate() - 1
+		idealGain := float64(next.workers)/float64(prev.workers) - 1
+		if gain < rampKneeEfficiency*idealGain {
+			return i, true
+		}
+	}
+	return len(steps) - 1, false
+}
+
+// write prints a table of the per step statistics and the knee
+func (r *ramp) write(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "Concurrency ramp (%v per step):\n%8s %12s %10s %10s %10s %10s %9s\n",
+		r.stepDuration, "workers", "queries/sec", "p50 ms", "p95 ms", "p99 ms", "max ms", "timeouts")
+	if err != nil {
+		return err
+	}
+	for _, s := range r.completedSteps() {
+		_, q := generateQuantileMap(s.stats.latencyHDRHistogram)
+		_, err = fmt.Fprintf(w, "%8d %12.2f %10.2f %10.2f %10.2f %10.2f %9d\n",
+			s.workers, s.queryRate(), q["q50"], q["q95"], q["q99"], q["q100"], s.stats.timeouts)
+		if err != nil {
+			return err
+		}
+	}
+	idx, ok := r.knee()
+	s := r.completedSteps()[idx]
+	if ok {
+		_, err = fmt.Fprintf(w, "Knee: throughput stops scaling beyond %d workers (%0.2f queries/sec)\n", s.workers, s.queryRate())
+	} else {
+		_, err = fmt.Fprintf(w, "Knee: not reached, throughput still scaling at %d workers (%0.2f queries/sec)\n", s.workers, s.queryRate())
+	}
+	return err
+}
+
+// totals returns the per step statistics and the knee for the results file
+func (r *ramp) totals() map[string]interface{} {
+	var steps []interface{}
+	for _, s := range r.completedSteps() {
+		_, q := generateQuantileMap(s.stats.latencyHDRHistogram)
+		steps = append(steps, map[string]interface{}{
+			"workers":        s.workers,
+			"durationMillis": s.duration().Milliseconds(),
+			"queries":        s.stats.count,
+			"queryRate":      s.queryRate(),
+			"quantiles":      q,
+			"timeouts":       s.stats.timeouts,
+		})
+	}
+	idx, ok := r.knee()
+	return map[string]interface{}{
+		"stepDurationMillis": r.stepDuration.Milliseconds(),
+		"steps":              steps,
+		"kneeWorkers":        r.completedSteps()[idx].workers,
+		"kneeReached":        ok,
+	}
+}
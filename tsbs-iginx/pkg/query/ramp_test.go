@@ -0,0 +1,144 @@
+package query
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func TestParseRamp(t *testing.T) {
+	cases := []struct {
+		in      string
+		want    []uint
+		wantErr bool
+	}{
+		{in: "1,2,4, 8", want: []uint{1, 2, 4, 8}},
+		{in: "3", want: []uint{3}},
+		{in: "1,0", wantErr: true},
+		{in: "2,2", wantErr: true},
+		{in: "4,2", wantErr: true},
+		{in: "1,x", wantErr: true},
+	}
+	for _, c := range cases {
+		got, err := parseRamp(c.in)
+		if c.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected error", c.in)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", c.in, err)
+			continue
+		}
+		if len(got) != len(c.want) {
+			t.Errorf("%s: got %v want %v", c.in, got, c.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != c.want[i] {
+				t.Errorf("%s: got %v want %v", c.in, got, c.want)
+			}
+		}
+	}
+}
+
+func newTestRamp(workers []uint, queries []int64) *ramp {
+	r := newRamp(workers, time.Second)
+	start := time.Unix(0, 0)
+	for i, s := range r.steps {
+		s.start = start.Add(time.Duration(i) * time.Second)
+		s.end = s.start.Add(time.Second)
+		for j := int64(0); j < queries[i]; j++ {
+			s.stats.push(1)
+		}
+	}
+	r.current = len(r.steps) - 1
+	r.finished = true
+	return r
+}
+
+func TestRampKnee(t *testing.T) {
+	cases := []struct {
+		desc        string
+		queries     []int64
+		wantWorkers uint
+		wantOk      bool
+	}{
+		{desc: "linear", queries: []int64{10, 20, 40, 80}, wantWorkers: 8},
+		{desc: "flat after 4", queries: []int64{10, 20, 40, 42}, wantWorkers: 4, wantOk: true},
+		{desc: "drop after 2", queries: []int64{10, 20, 15, 12}, wantWorkers: 2, wantOk: true},
+	}
+	for _, c := range cases {
+		r := newTestRamp([]uint{1, 2, 4, 8}, c.queries)
+		idx, ok := r.knee()
+		if got := r.steps[idx].workers; got != c.wantWorkers || ok != c.wantOk {
+			t.Errorf("%s: got knee at %d workers (%v), want %d (%v)", c.desc, got, ok, c.wantWorkers, c.wantOk)
+		}
+		if err := r.write(ioutil.Discard); err != nil {
+			t.Errorf("%s: unexpected error: %v", c.desc, err)
+		}
+		totals := r.totals()
+		if totals["kneeWorkers"] != c.wantWorkers || len(totals["steps"].([]interface{})) != 4 {
+			t.Errorf("%s: incorrect totals %v", c.desc, totals)
+		}
+	}
+}
+
+func TestRampSteps(t *testing.T) {
+	r := newRamp([]uint{1, 2}, 20*time.Millisecond)
+	done := make(chan struct{})
+	go func() {
+		r.run(context.Background(), ioutil.Discard)
+		close(done)
+	}()
+
+	if !r.waitActive(0) {
+		t.Fatalf("worker 0 should be active in the first step")
+	}
+	r.record([]*Stat{GetStat().Init([]byte("q"), 1), GetPartialStat().Init([]byte("p"), 1)})
+	// worker 1 only becomes active in the second step
+	if !r.waitActive(1) {
+		t.Fatalf("worker 1 should become active in the second step")
+	}
+	if len(r.completedSteps()) != 2 {
+		t.Errorf("expected the second step to be running")
+	}
+	r.record([]*Stat{GetTimeoutStat([]byte("q"))})
+	// worker 2 is never part of the ramp
+	if r.waitActive(2) {
+		t.Errorf("worker 2 should not become active")
+	}
+	<-done
+	if !r.isFinished() {
+		t.Errorf("ramp should be finished")
+	}
+	steps := r.completedSteps()
+	if steps[0].stats.count != 1 || steps[1].stats.timeouts != 1 {
+		t.Errorf("stats recorded in the wrong step: %d queries, %d timeouts", steps[0].stats.count, steps[1].stats.timeouts)
+	}
+	if steps[1].duration() <= 0 {
+		t.Errorf("step not ended")
+	}
+}
+
+func TestRampFinishEarly(t *testing.T) {
+	r := newRamp([]uint{1, 2}, time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		r.run(ctx, ioutil.Discard)
+		close(done)
+	}()
+	waiting := make(chan bool)
+	go func() { waiting <- r.waitActive(1) }()
+	cancel()
+	if <-waiting {
+		t.Errorf("waiting worker should not become active after the ramp is finished")
+	}
+	<-done
+	if len(r.completedSteps()) != 1 || r.steps[0].duration() <= 0 {
+		t.Errorf("first step should be truncated")
+	}
+}
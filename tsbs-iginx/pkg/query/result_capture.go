@@ -0,0 +1,153 @@
+package query
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// capturedResultsFileName is the name of the file, inside the capture directory,
+// that holds one normalized query result per line
+const capturedResultsFileName = "results.jsonl"
+
+// aggPrefixes are the aggregate function prefixes the query generators put in
+// front of column aliases (e.g. max_usage_user). They are stripped when building
+// series keys so that the same field is named identically across databases.
+var aggPrefixes = []string{"max_", "min_", "mean_", "avg_", "sum_", "count_", "first_", "last_"}
+
+// ResultRow is a single cell of a normalized query response: the value of one
+// series in one time bucket.
+type ResultRow struct {
+	Series string  `json:"series"`
+	Bucket int64   `json:"bucket"` // bucket start, in milliseconds since epoch
+	Value  float64 `json:"value"`
+}
+
+// QueryResult is the canonical form of a query response, keyed by query ID so
+// that captures of the same query file against different databases can be
+// compared with each other.
+type QueryResult struct {
+	ID          uint64      `json:"id"`
+	Label       string      `json:"label"`
+	Description string      `json:"description"`
+	Rows        []ResultRow `json:"rows"`
+}
+
+// CanonicalFieldName strips a leading aggregate prefix from a column name, e.g.
+// max_usage_user becomes usage_user.
+func CanonicalFieldName(column string) string {
+	for _, p := range aggPrefixes {
+		if strings.HasPrefix(column, p) && len(column) > len(p) {
+			return column[len(p):]
+		}
+	}
+	return column
+}
+
+// SeriesKey builds the canonical series identifier from a field name and the
+// tags the result was grouped by, e.g. "hostname=host_1/usage_user".
+func SeriesKey(field string, tags map[string]string) string {
+	if len(tags) == 0 {
+		return field
+	}
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + "=" + tags[k]
+	}
+	return strings.Join(parts, ",") + "/" + field
+}
+
+// sortResultRows orders rows by series then bucket so that captures are stable
+// regardless of the order in which a database returns them.
+func sortResultRows(rows []ResultRow) {
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Series != rows[j].Series {
+			return rows[i].Series < rows[j].Series
+		}
+		return rows[i].Bucket < rows[j].Bucket
+	})
+}
+
+// resultCapture writes normalized query results to a capture directory.
+// It is safe for concurrent use by multiple workers.
+type resultCapture struct {
+	mu   sync.Mutex
+	file *os.File
+	w    *bufio.Writer
+	enc  *json.Encoder
+}
+
+// newResultCapture creates dir (if needed) and opens the results file in it.
+func newResultCapture(dir string) (*resultCapture, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("cannot create capture directory %s: %v", dir, err)
+	}
+	f, err := os.Create(filepath.Join(dir, capturedResultsFileName))
+	if err != nil {
+		return nil, fmt.Errorf("cannot create capture file in %s: %v", dir, err)
+	}
+	w := bufio.NewWriter(f)
+	return &resultCapture{file: f, w: w, enc: json.NewEncoder(w)}, nil
+}
+
+// write stores the normalized rows of the response to query q
+func (c *resultCapture) write(q Query, rows []ResultRow) error {
+	sortResultRows(rows)
+	r := &QueryResult{
+		ID:          q.GetID(),
+		Label:       string(q.HumanLabelName()),
+		Description: string(q.HumanDescriptionName()),
+		Rows:        rows,
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.enc.Encode(r)
+}
+
+// close flushes and closes the results file
+func (c *resultCapture) close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.w.Flush(); err != nil {
+		return err
+	}
+	return c.file.Close()
+}
+
+// ReadCapturedResults reads all query results stored in a capture directory
+// created with --capture-results, keyed by query ID.
+func ReadCapturedResults(dir string) (map[uint64]*QueryResult, error) {
+	f, err := os.Open(filepath.Join(dir, capturedResultsFileName))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return decodeCapturedResults(f)
+}
+
+func decodeCapturedResults(r io.Reader) (map[uint64]*QueryResult, error) {
+	results := make(map[uint64]*QueryResult)
+	dec := json.NewDecoder(bufio.NewReader(r))
+	for {
+		qr := &QueryResult{}
+		err := dec.Decode(qr)
+		if err == io.EOF {
+			return results, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		results[qr.ID] = qr
+	}
+}
@@ -0,0 +1,141 @@
+package query
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestCanonicalFieldName(t *testing.T) {
+	cases := map[string]string{
+		"max_usage_user":  "usage_user",
+		"mean_usage_user": "usage_user",
+		"usage_user":      "usage_user",
+		"max_":            "max_",
+	}
+	for in, want := range cases {
+		if got := CanonicalFieldName(in); got != want {
+			t.Errorf("CanonicalFieldName(%s): got %s want %s", in, got, want)
+		}
+	}
+}
+
+func TestSeriesKey(t *testing.T) {
+	if got := SeriesKey("usage_user", nil); got != "usage_user" {
+		t.Errorf("incorrect key without tags: %s", got)
+	}
+	tags := map[string]string{"region": "eu", "hostname": "host_1"}
+	if got, want := SeriesKey("usage_user", tags), "hostname=host_1,region=eu/usage_user"; got != want {
+		t.Errorf("incorrect key with tags: got %s want %s", got, want)
+	}
+}
+
+func TestNormalizeSQLRow(t *testing.T) {
+	ts := time.Unix(3600, 0)
+	cols := []string{"hour", "hostname", "max_usage_user", "max_usage_system"}
+	row := []interface{}{ts, "host_1", 10.5, []byte("3")}
+	got := normalizeSQLRow(cols, row)
+	want := []ResultRow{
+		{Series: "hostname=host_1/usage_user", Bucket: 3600000, Value: 10.5},
+		{Series: "hostname=host_1/usage_system", Bucket: 3600000, Value: 3},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("incorrect number of rows: got %d want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("row %d: got %+v want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestResultCaptureRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "capture")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	c, err := newResultCapture(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	q := &testQuery{ID: 7}
+	err = c.write(q, []ResultRow{{"b", 2, 2}, {"a", 2, 1}, {"a", 1, 0}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err = c.close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	results, err := ReadCapturedResults(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r, ok := results[7]
+	if !ok {
+		t.Fatalf("query 7 missing from capture")
+	}
+	want := []ResultRow{{"a", 1, 0}, {"a", 2, 1}, {"b", 2, 2}}
+	for i := range want {
+		if r.Rows[i] != want[i] {
+			t.Errorf("row %d: got %+v want %+v", i, r.Rows[i], want[i])
+		}
+	}
+}
+
+func TestDecodeCapturedResultsError(t *testing.T) {
+	if _, err := decodeCapturedResults(bytes.NewBufferString("{not json")); err == nil {
+		t.Errorf("expected error on malformed capture")
+	}
+}
+
+func TestCompareResults(t *testing.T) {
+	a := map[uint64]*QueryResult{
+		0: {ID: 0, Label: "x", Rows: []ResultRow{{"s", 1, 1.0}}},
+		1: {ID: 1, Label: "x", Rows: []ResultRow{{"s", 1, 1.0}}},
+		2: {ID: 2, Label: "y", Rows: []ResultRow{{"s", 1, 1.0}, {"s", 2, 2.0}}},
+		3: {ID: 3, Label: "y"},
+	}
+	b := map[uint64]*QueryResult{
+		0: {ID: 0, Label: "x", Rows: []ResultRow{{"s", 1, 1.0 + 1e-9}}},
+		1: {ID: 1, Label: "x", Rows: []ResultRow{{"s", 1, 1.5}}},
+		2: {ID: 2, Label: "y", Rows: []ResultRow{{"s", 1, 1.0}, {"s", 3, 2.0}}},
+		4: {ID: 4, Label: "y"},
+	}
+	c := CompareResults(a, b, 1e-6)
+	if c.Compared != 3 {
+		t.Errorf("incorrect compared count: got %d", c.Compared)
+	}
+	if len(c.OnlyInA) != 1 || c.OnlyInA[0] != 3 {
+		t.Errorf("incorrect OnlyInA: %v", c.OnlyInA)
+	}
+	if len(c.OnlyInB) != 1 || c.OnlyInB[0] != 4 {
+		t.Errorf("incorrect OnlyInB: %v", c.OnlyInB)
+	}
+	if len(c.Mismatches) != 2 || c.Mismatches[0].ID != 1 || c.Mismatches[1].ID != 2 {
+		t.Fatalf("incorrect mismatches: %+v", c.Mismatches)
+	}
+	if want := "series s bucket 2 only in A (and 1 more differences)"; c.Mismatches[1].Reason != want {
+		t.Errorf("incorrect reason: got %q want %q", c.Mismatches[1].Reason, want)
+	}
+	if labels := c.MismatchedLabels(); len(labels) != 2 {
+		t.Errorf("incorrect mismatched labels: %v", labels)
+	}
+	if c.Equal() {
+		t.Errorf("captures should not be equal")
+	}
+}
+
+func TestResultRowJSON(t *testing.T) {
+	b, err := json.Marshal(ResultRow{"s", 1, 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := `{"series":"s","bucket":1,"value":2}`; string(b) != want {
+		t.Errorf("incorrect JSON: got %s want %s", b, want)
+	}
+}
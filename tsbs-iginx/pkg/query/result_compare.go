@@ -0,0 +1,152 @@
+package query
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// ResultMismatch describes a single query whose captured results differ between
+// two captures.
+type ResultMismatch struct {
+	ID     uint64
+	Label  string
+	Reason string
+}
+
+// LabelComparison holds the per query type outcome of a comparison
+type LabelComparison struct {
+	Compared   int
+	Mismatched int
+}
+
+// ResultComparison is the outcome of comparing two result captures
+type ResultComparison struct {
+	Compared   int
+	OnlyInA    []uint64
+	OnlyInB    []uint64
+	Mismatches []ResultMismatch
+	ByLabel    map[string]*LabelComparison
+}
+
+// MismatchedLabels returns the sorted query types with at least one mismatch
+func (c *ResultComparison) MismatchedLabels() []string {
+	var labels []string
+	for l, lc := range c.ByLabel {
+		if lc.Mismatched > 0 {
+			labels = append(labels, l)
+		}
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+// Equal reports whether both captures hold the same queries with matching results
+func (c *ResultComparison) Equal() bool {
+	return len(c.OnlyInA) == 0 && len(c.OnlyInB) == 0 && len(c.Mismatches) == 0
+}
+
+// CompareResults compares two captures query by query. Values are considered
+// equal when they differ by at most tolerance, relative to the larger of their
+// magnitudes (or absolute, for magnitudes below 1).
+func CompareResults(a, b map[uint64]*QueryResult, tolerance float64) *ResultComparison {
+	c := &ResultComparison{ByLabel: make(map[string]*LabelComparison)}
+	ids := make([]uint64, 0, len(a))
+	for id := range a {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	for _, id := range ids {
+		ra := a[id]
+		rb, ok := b[id]
+		if !ok {
+			c.OnlyInA = append(c.OnlyInA, id)
+			continue
+		}
+		lc, ok := c.ByLabel[ra.Label]
+		if !ok {
+			lc = &LabelComparison{}
+			c.ByLabel[ra.Label] = lc
+		}
+		c.Compared++
+		lc.Compared++
+		if reason := compareRows(ra.Rows, rb.Rows, tolerance); reason != "" {
+			lc.Mismatched++
+			c.Mismatches = append(c.Mismatches, ResultMismatch{ID: id, Label: ra.Label, Reason: reason})
+		}
+	}
+	for id := range b {
+		if _, ok := a[id]; !ok {
+			c.OnlyInB = append(c.OnlyInB, id)
+		}
+	}
+	sort.Slice(c.OnlyInB, func(i, j int) bool { return c.OnlyInB[i] < c.OnlyInB[j] })
+	return c
+}
+
+type seriesBucket struct {
+	series string
+	bucket int64
+}
+
+// compareRows returns an empty string if both row sets match, otherwise a
+// description of the first difference found and the total number of differences.
+func compareRows(a, b []ResultRow, tolerance float64) string {
+	vb := make(map[seriesBucket]float64, len(b))
+	for _, r := range b {
+		vb[seriesBucket{r.Series, r.Bucket}] = r.Value
+	}
+
+	diffs := 0
+	first := ""
+	note := func(format string, args ...interface{}) {
+		if diffs == 0 {
+			first = fmt.Sprintf(format, args...)
+		}
+		diffs++
+	}
+	for _, r := range a {
+		key := seriesBucket{r.Series, r.Bucket}
+		v, ok := vb[key]
+		if !ok {
+			note("series %s bucket %d only in A", r.Series, r.Bucket)
+			continue
+		}
+		delete(vb, key)
+		if !floatsEqual(r.Value, v, tolerance) {
+			note("series %s bucket %d: %v != %v", r.Series, r.Bucket, r.Value, v)
+		}
+	}
+	if len(vb) > 0 {
+		// report the extra row with the smallest key so output is deterministic
+		extra := make([]seriesBucket, 0, len(vb))
+		for k := range vb {
+			extra = append(extra, k)
+		}
+		sort.Slice(extra, func(i, j int) bool {
+			if extra[i].series != extra[j].series {
+				return extra[i].series < extra[j].series
+			}
+			return extra[i].bucket < extra[j].bucket
+		})
+		for _, k := range extra {
+			note("series %s bucket %d only in B", k.series, k.bucket)
+		}
+	}
+	if diffs == 0 {
+		return ""
+	}
+	if diffs == 1 {
+		return first
+	}
+	return fmt.Sprintf("%s (and %d more differences)", first, diffs-1)
+}
+
+func floatsEqual(x, y, tolerance float64) bool {
+	if math.IsNaN(x) || math.IsNaN(y) {
+		return math.IsNaN(x) && math.IsNaN(y)
+	}
+	scale := math.Max(1, math.Max(math.Abs(x), math.Abs(y)))
+	return math.Abs(x-y) <= tolerance*scale
+}
@@ -0,0 +1,102 @@
+package query
+
+import (
+	"database/sql"
+	"strconv"
+	"time"
+)
+
+// NormalizeSQLRows converts the rows of a SQL query response into ResultRows.
+// The first timestamp column is used as the bucket, string columns are treated
+// as the tags the result is grouped by, and every numeric column becomes a
+// series named after the column (without its aggregate prefix).
+func NormalizeSQLRows(rows *sql.Rows) ([]ResultRow, error) {
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	var out []ResultRow
+	values := make([]interface{}, len(cols))
+	for i := range values {
+		values[i] = new(interface{})
+	}
+	for rows.Next() {
+		if err := rows.Scan(values...); err != nil {
+			return nil, err
+		}
+		row := make([]interface{}, len(cols))
+		for i := range values {
+			row[i] = *values[i].(*interface{})
+		}
+		out = append(out, normalizeSQLRow(cols, row)...)
+	}
+	return out, rows.Err()
+}
+
+func normalizeSQLRow(cols []string, row []interface{}) []ResultRow {
+	var bucket int64
+	bucketSet := false
+	tags := make(map[string]string)
+	fields := make(map[string]float64)
+	var fieldOrder []string
+	for i, v := range row {
+		switch x := v.(type) {
+		case time.Time:
+			if !bucketSet {
+				bucket = x.UnixNano() / int64(time.Millisecond)
+				bucketSet = true
+			}
+		case string:
+			tags[cols[i]] = x
+		case []byte:
+			// text protocol drivers return numerics as bytes
+			if f, err := strconv.ParseFloat(string(x), 64); err == nil {
+				fields[cols[i]] = f
+				fieldOrder = append(fieldOrder, cols[i])
+			} else {
+				tags[cols[i]] = string(x)
+			}
+		default:
+			if f, ok := toFloat(x); ok {
+				fields[cols[i]] = f
+				fieldOrder = append(fieldOrder, cols[i])
+			}
+		}
+	}
+	out := make([]ResultRow, 0, len(fieldOrder))
+	for _, col := range fieldOrder {
+		out = append(out, ResultRow{
+			Series: SeriesKey(CanonicalFieldName(col), tags),
+			Bucket: bucket,
+			Value:  fields[col],
+		})
+	}
+	return out
+}
+
+// toFloat converts the numeric types returned by database drivers and JSON
+// decoding to float64
+func toFloat(v interface{}) (float64, bool) {
+	switch x := v.(type) {
+	case float64:
+		return x, true
+	case float32:
+		return float64(x), true
+	case int64:
+		return float64(x), true
+	case int32:
+		return float64(x), true
+	case int:
+		return float64(x), true
+	case uint64:
+		return float64(x), true
+	case uint32:
+		return float64(x), true
+	case bool:
+		if x {
+			return 1, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
@@ -1,17 +1,19 @@
 package query
 
 import (
-	"encoding/gob"
+	"context"
 	"io"
 	"log"
 	"sync"
 )
 
 // scanner is used to read in Queries from a Reader where they are
-// Go-encoded and then distribute them to workers
+// Go-encoded (or JSON lines) and then distribute them to workers
 type scanner struct {
-	r     io.Reader
-	limit *uint64
+	r      io.Reader
+	limit  *uint64
+	ctx    context.Context
+	format string
 }
 
 // newScanner returns a new scanner for a given Reader and its limit
@@ -25,9 +27,30 @@ func (s *scanner) setReader(r io.Reader) *scanner {
 	return s
 }
 
+// setContext sets a context which, once done, stops the scanner
+func (s *scanner) setContext(ctx context.Context) *scanner {
+	s.ctx = ctx
+	return s
+}
+
+// setFormat sets the format of the queries read, FormatGob by default
+func (s *scanner) setFormat(format string) *scanner {
+	s.format = format
+	return s
+}
+
 // scan reads encoded Queries and places them into a channel
 func (s *scanner) scan(pool *sync.Pool, c chan Query) {
-	decoder := gob.NewDecoder(s.r)
+	decoder, err := NewDecoder(s.r, s.format)
+	if err != nil {
+		log.Fatal(err)
+	}
+	// JSON lines queries keep the ID they were given, e.g. when filtered by ID
+	keepIDs := s.format == FormatJSONLines
+	var done <-chan struct{} // nil, never done, unless a context is set
+	if s.ctx != nil {
+		done = s.ctx.Done()
+	}
 
 	n := uint64(0)
 	for {
@@ -37,7 +60,7 @@ func (s *scanner) scan(pool *sync.Pool, c chan Query) {
 		}
 
 		q := pool.Get().(Query)
-		err := decoder.Decode(q)
+		err = decoder.Decode(q)
 		if err == io.EOF {
 			// EOF, all done
 			break
@@ -48,8 +71,15 @@ func (s *scanner) scan(pool *sync.Pool, c chan Query) {
 		}
 
 		// We have a query, send it to the runner
-		q.SetID(n)
-		c <- q
+		if !keepIDs {
+			q.SetID(n)
+		}
+		select {
+		case c <- q:
+		case <-done:
+			// interrupted, stop reading
+			return
+		}
 
 		// Queries counter
 		n++
@@ -3,6 +3,7 @@ package query
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/gob"
 	"fmt"
 	"sync"
@@ -150,3 +151,20 @@ func TestScanTimescaleDB(t *testing.T) {
 		return nil
 	})
 }
+
+func TestScannerContextDone(t *testing.T) {
+	var b bytes.Buffer
+	err := encodeQueries(&b, 10, func(i uint64) Query {
+		return &testQuery{HumanLabel: []byte("testlabel")}
+	})
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	limit := uint64(0)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	// unbuffered and never read: the scanner can only return because ctx is done
+	c := make(chan Query)
+	newScanner(&limit).setReader(&b).setContext(ctx).scan(&testQueryPool, c)
+}
@@ -112,25 +112,34 @@ func (sp *defaultStatProcessor) process(workers uint) {
 			sp.statMapping[string(stat.label)] = newStatGroup(*sp.args.limit)
 		}
 
-		sp.statMapping[string(stat.label)].push(stat.value)
+		if stat.isTimeout {
+			// Timed out queries have no latency, they are only counted
+			sp.statMapping[string(stat.label)].pushTimeout()
+			sp.statMapping[allQueriesLabel].pushTimeout()
+			if !sp.args.prewarmQueries || !stat.isWarm {
+				i++
+			}
+		} else {
+			sp.statMapping[string(stat.label)].push(stat.value)
 
-		if !stat.isPartial {
-			sp.statMapping[allQueriesLabel].push(stat.value)
+			if !stat.isPartial {
+				sp.statMapping[allQueriesLabel].push(stat.value)
 
-			// Only needed when differentiating between cold & warm
-			if sp.args.prewarmQueries {
-				if stat.isWarm {
-					sp.statMapping[labelWarmQueries].push(stat.value)
-				} else {
-					sp.statMapping[labelColdQueries].push(stat.value)
+				// Only needed when differentiating between cold & warm
+				if sp.args.prewarmQueries {
+					if stat.isWarm {
+						sp.statMapping[labelWarmQueries].push(stat.value)
+					} else {
+						sp.statMapping[labelColdQueries].push(stat.value)
+					}
 				}
-			}
 
-			// If we're prewarming queries (i.e., running them twice in a row),
-			// only increment the counter for the first (cold) query. Otherwise,
-			// increment for every query.
-			if !sp.args.prewarmQueries || !stat.isWarm {
-				i++
+				// If we're prewarming queries (i.e., running them twice in a row),
+				// only increment the counter for the first (cold) query. Otherwise,
+				// increment for every query.
+				if !sp.args.prewarmQueries || !stat.isWarm {
+					i++
+				}
 			}
 		}
 
@@ -178,22 +187,30 @@ func (sp *defaultStatProcessor) process(workers uint) {
 
 	if len(sp.args.hdrLatenciesFile) > 0 {
 		_, _ = fmt.Printf("Saving High Dynamic Range (HDR) Histogram of Response Latencies to %s\n", sp.args.hdrLatenciesFile)
-		var b bytes.Buffer
-		bw := bufio.NewWriter(&b)
-		_, err = sp.statMapping[allQueriesLabel].latencyHDRHistogram.PercentilesPrint(bw, 10, 1000.0)
-		if err != nil {
-			log.Fatal(err)
-		}
-		err = ioutil.WriteFile(sp.args.hdrLatenciesFile, b.Bytes(), 0644)
+		err = WriteHDRLatencies(sp.args.hdrLatenciesFile, sp.statMapping[allQueriesLabel].latencyHDRHistogram)
 		if err != nil {
 			log.Fatal(err)
 		}
-
 	}
 
 	sp.wg.Done()
 }
 
+// WriteHDRLatencies writes the percentile distribution of a latency histogram
+// to fileName, with values in milliseconds.
+func WriteHDRLatencies(fileName string, hist *hdrhistogram.Histogram) error {
+	var b bytes.Buffer
+	bw := bufio.NewWriter(&b)
+	_, err := hist.PercentilesPrint(bw, 10, 1000.0)
+	if err != nil {
+		return err
+	}
+	if err = bw.Flush(); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fileName, b.Bytes(), 0644)
+}
+
 func generateQuantileMap(hist *hdrhistogram.Histogram) (int64, map[string]float64) {
 	ops := hist.TotalCount()
 	q0 := 0.0
@@ -238,6 +255,22 @@ func (sp *defaultStatProcessor) GetTotalsMap() map[string]interface{} {
 		quantiles[stripRegex(label)] = all
 	}
 	totals["overallQuantiles"] = quantiles
+	// count queries that did not complete within the query timeout
+	timeouts := make(map[string]interface{})
+	for label, statGroup := range sp.statMapping {
+		timeouts[stripRegex(label)] = statGroup.timeouts
+	}
+	totals["timeouts"] = timeouts
+	// encoded latency histograms, so that results of several runner processes can be merged
+	histograms := make(map[string]interface{})
+	for label, statGroup := range sp.statMapping {
+		encoded, err := statGroup.latencyHDRHistogram.Encode(hdrhistogram.V2CompressedEncodingCookieBase)
+		if err != nil {
+			log.Fatal(err)
+		}
+		histograms[stripRegex(label)] = string(encoded)
+	}
+	totals["histograms"] = histograms
 	return totals
 }
 
@@ -20,6 +20,7 @@ type Stat struct {
 	value     float64
 	isWarm    bool
 	isPartial bool
+	isTimeout bool
 }
 
 var statPool = &sync.Pool{
@@ -43,6 +44,14 @@ func GetPartialStat() *Stat {
 	return s
 }
 
+// GetTimeoutStat returns a Stat from a pool recording that a query with the
+// given label did not complete within the query timeout
+func GetTimeoutStat(label []byte) *Stat {
+	s := GetStat().Init(label, 0)
+	s.isTimeout = true
+	return s
+}
+
 // Init safely initializes a Stat while minimizing heap allocations.
 func (s *Stat) Init(label []byte, value float64) *Stat {
 	s.label = s.label[:0] // clear
@@ -57,6 +66,7 @@ func (s *Stat) reset() *Stat {
 	s.value = 0.0
 	s.isWarm = false
 	s.isPartial = false
+	s.isTimeout = false
 	return s
 }
 
@@ -65,6 +75,7 @@ type statGroup struct {
 	latencyHDRHistogram *hdrhistogram.Histogram
 	sum                 float64
 	count               int64
+	timeouts            int64
 }
 
 // newStatGroup returns a new StatGroup with an initial size
@@ -90,9 +101,15 @@ func (s *statGroup) push(n float64) {
 	s.count++
 }
 
+// pushTimeout counts a query that timed out. Timed out queries have no
+// latency and are kept out of the histogram.
+func (s *statGroup) pushTimeout() {
+	s.timeouts++
+}
+
 // string makes a simple description of a statGroup.
 func (s *statGroup) string() string {
-	return fmt.Sprintf("min: %8.2fms, med: %8.2fms, mean: %8.2fms, max: %7.2fms, stddev: %8.2fms, sum: %5.1fsec, count: %d",
+	desc := fmt.Sprintf("min: %8.2fms, med: %8.2fms, mean: %8.2fms, max: %7.2fms, stddev: %8.2fms, sum: %5.1fsec, count: %d",
 		s.Min(),
 		s.Median(),
 		s.Mean(),
@@ -100,6 +117,10 @@ func (s *statGroup) string() string {
 		s.StdDev(),
 		s.sum/hdrScaleFactor,
 		s.count)
+	if s.timeouts > 0 {
+		desc += fmt.Sprintf(", timeouts: %d", s.timeouts)
+	}
+	return desc
 }
 
 func (s *statGroup) write(w io.Writer) error {
@@ -405,3 +405,29 @@ func TestWriteStatGroupMap(t *testing.T) {
 		}
 	}
 }
+
+func TestStatGroupPushTimeout(t *testing.T) {
+	sg := newStatGroup(0)
+	sg.push(10.0)
+	if strings.Contains(sg.string(), "timeouts") {
+		t.Errorf("timeouts shown without any timeout: %s", sg.string())
+	}
+	sg.pushTimeout()
+	sg.pushTimeout()
+	if sg.count != 1 {
+		t.Errorf("timeouts must not be counted as latencies: count %d", sg.count)
+	}
+	if !strings.HasSuffix(sg.string(), "timeouts: 2") {
+		t.Errorf("timeouts missing from description: %s", sg.string())
+	}
+}
+
+func TestGetTimeoutStat(t *testing.T) {
+	s := GetTimeoutStat([]byte("foo"))
+	if !s.isTimeout || string(s.label) != "foo" {
+		t.Errorf("GetTimeoutStat() failed - got %+v", s)
+	}
+	if s.reset().isTimeout {
+		t.Errorf("reset() failed - isTimeout = true")
+	}
+}
@@ -0,0 +1,340 @@
+// Package resources samples the resource usage of the benchmark client, and
+// optionally of the database server, while a benchmark runs, to tell whether
+// the client was the bottleneck.
+package resources
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/net"
+	"github.com/shirou/gopsutil/process"
+	"github.com/spf13/pflag"
+)
+
+// DefaultInterval is the default time between two samples
+const DefaultInterval = time.Second
+
+// AddFlags adds the flags of resource sampling to the flag set.
+func AddFlags(fs *pflag.FlagSet, flagPrefix string) {
+	fs.Duration(flagPrefix+"resource-sample-interval", DefaultInterval, "How often to sample the CPU, memory, GC pauses and network usage of the client (0 = do not sample)")
+	fs.Int32(flagPrefix+"server-pid", 0, "Also sample the CPU and memory usage of the database server process with this PID, when it runs on the same host")
+}
+
+// Sample is the resource usage over one interval. Counters are the increase
+// over the interval, memory is the resident set size at its end.
+type Sample struct {
+	// Time is the end of the interval, in milliseconds since the epoch
+	Time int64 `json:"time"`
+	// CPUPercent is the CPU time used by the client per wall time; 100 is
+	// one core fully used
+	CPUPercent float64 `json:"cpuPercent"`
+	RSSBytes   uint64  `json:"rssBytes"`
+	GCs        uint32  `json:"gcs"`
+	GCPauseNs  uint64  `json:"gcPauseNs"`
+	// NetSentBytes and NetRecvBytes are counted over all network
+	// interfaces of the host, as the traffic of a process is not exposed
+	NetSentBytes uint64 `json:"netSentBytes"`
+	NetRecvBytes uint64 `json:"netRecvBytes"`
+
+	Server *ProcessSample `json:"server,omitempty"`
+}
+
+// ProcessSample is the resource usage of the server process over one interval
+type ProcessSample struct {
+	CPUPercent float64 `json:"cpuPercent"`
+	RSSBytes   uint64  `json:"rssBytes"`
+}
+
+// Summary aggregates the samples of a run
+type Summary struct {
+	Samples        int     `json:"samples"`
+	CPUs           int     `json:"cpus"`
+	MeanCPUPercent float64 `json:"meanCpuPercent"`
+	MaxCPUPercent  float64 `json:"maxCpuPercent"`
+	MaxRSSBytes    uint64  `json:"maxRssBytes"`
+	GCs            uint64  `json:"gcs"`
+	GCPauseNs      uint64  `json:"gcPauseNs"`
+	NetSentBytes   uint64  `json:"netSentBytes"`
+	NetRecvBytes   uint64  `json:"netRecvBytes"`
+
+	ServerMeanCPUPercent float64 `json:"serverMeanCpuPercent,omitempty"`
+	ServerMaxCPUPercent  float64 `json:"serverMaxCpuPercent,omitempty"`
+	ServerMaxRSSBytes    uint64  `json:"serverMaxRssBytes,omitempty"`
+}
+
+// reading holds the cumulative counters and current gauges read at one time
+type reading struct {
+	time       time.Time
+	cpuSeconds float64
+	rss        uint64
+	gcs        uint32
+	gcPauseNs  uint64
+	netSent    uint64
+	netRecv    uint64
+
+	// server is nil if there is no server process to read
+	server *processReading
+}
+
+type processReading struct {
+	cpuSeconds float64
+	rss        uint64
+}
+
+// Sampler records a Sample every interval between Start and Stop. A nil
+// Sampler records nothing.
+type Sampler struct {
+	interval time.Duration
+	readFn   func() reading
+
+	mu      sync.Mutex
+	samples []Sample
+	prev    reading
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewSampler returns a Sampler of this process, and of the server process if
+// serverPID is not 0, or nil if interval is not positive.
+func NewSampler(interval time.Duration, serverPID int32) (*Sampler, error) {
+	if interval <= 0 {
+		return nil, nil
+	}
+	self, err := process.NewProcess(int32(os.Getpid()))
+	if err != nil {
+		return nil, fmt.Errorf("cannot sample the client process: %v", err)
+	}
+	var server *process.Process
+	if serverPID != 0 {
+		server, err = process.NewProcess(serverPID)
+		if err != nil {
+			return nil, fmt.Errorf("cannot sample the server process %d: %v", serverPID, err)
+		}
+	}
+	r := &processReader{self: self, server: server}
+	return newSampler(interval, r.read), nil
+}
+
+func newSampler(interval time.Duration, readFn func() reading) *Sampler {
+	return &Sampler{interval: interval, readFn: readFn}
+}
+
+// Start takes the first reading and starts sampling in the background
+func (s *Sampler) Start() {
+	if s == nil {
+		return
+	}
+	s.prev = s.readFn()
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.run()
+}
+
+func (s *Sampler) run() {
+	defer close(s.done)
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.sample()
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// Stop takes the last sample, covering the time since the previous one, and
+// stops sampling
+func (s *Sampler) Stop() {
+	if s == nil || s.stop == nil {
+		return
+	}
+	close(s.stop)
+	<-s.done
+	s.sample()
+	s.stop = nil
+}
+
+// sample records the usage since the previous reading
+func (s *Sampler) sample() {
+	cur := s.readFn()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if elapsed := cur.time.Sub(s.prev.time); elapsed > 0 {
+		s.samples = append(s.samples, diff(s.prev, cur, elapsed))
+	}
+	s.prev = cur
+}
+
+func diff(prev, cur reading, elapsed time.Duration) Sample {
+	sample := Sample{
+		Time:         cur.time.UnixNano() / int64(time.Millisecond),
+		CPUPercent:   cpuPercent(prev.cpuSeconds, cur.cpuSeconds, elapsed),
+		RSSBytes:     cur.rss,
+		GCs:          cur.gcs - prev.gcs,
+		GCPauseNs:    cur.gcPauseNs - prev.gcPauseNs,
+		NetSentBytes: counterDiff(prev.netSent, cur.netSent),
+		NetRecvBytes: counterDiff(prev.netRecv, cur.netRecv),
+	}
+	if prev.server != nil && cur.server != nil {
+		sample.Server = &ProcessSample{
+			CPUPercent: cpuPercent(prev.server.cpuSeconds, cur.server.cpuSeconds, elapsed),
+			RSSBytes:   cur.server.rss,
+		}
+	}
+	return sample
+}
+
+func cpuPercent(prev, cur float64, elapsed time.Duration) float64 {
+	if cur < prev {
+		return 0
+	}
+	return 100 * (cur - prev) / elapsed.Seconds()
+}
+
+// counterDiff returns the increase of a counter, 0 if it was reset, e.g. when
+// an interface went away
+func counterDiff(prev, cur uint64) uint64 {
+	if cur < prev {
+		return 0
+	}
+	return cur - prev
+}
+
+// Samples returns the samples recorded so far
+func (s *Sampler) Samples() []Sample {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Sample(nil), s.samples...)
+}
+
+// Summary aggregates the samples recorded so far
+func (s *Sampler) Summary() Summary {
+	samples := s.Samples()
+	sum := Summary{Samples: len(samples), CPUs: runtime.NumCPU()}
+	var serverSamples int
+	for _, sample := range samples {
+		sum.MeanCPUPercent += sample.CPUPercent
+		if sample.CPUPercent > sum.MaxCPUPercent {
+			sum.MaxCPUPercent = sample.CPUPercent
+		}
+		if sample.RSSBytes > sum.MaxRSSBytes {
+			sum.MaxRSSBytes = sample.RSSBytes
+		}
+		sum.GCs += uint64(sample.GCs)
+		sum.GCPauseNs += sample.GCPauseNs
+		sum.NetSentBytes += sample.NetSentBytes
+		sum.NetRecvBytes += sample.NetRecvBytes
+		if sample.Server != nil {
+			serverSamples++
+			sum.ServerMeanCPUPercent += sample.Server.CPUPercent
+			if sample.Server.CPUPercent > sum.ServerMaxCPUPercent {
+				sum.ServerMaxCPUPercent = sample.Server.CPUPercent
+			}
+			if sample.Server.RSSBytes > sum.ServerMaxRSSBytes {
+				sum.ServerMaxRSSBytes = sample.Server.RSSBytes
+			}
+		}
+	}
+	if len(samples) > 0 {
+		sum.MeanCPUPercent /= float64(len(samples))
+	}
+	if serverSamples > 0 {
+		sum.ServerMeanCPUPercent /= float64(serverSamples)
+	}
+	return sum
+}
+
+// String describes the summary in one line
+func (sum Summary) String() string {
+	s := fmt.Sprintf("client resources: CPU mean %0.1f%%, max %0.1f%% (of %d%%), max RSS %0.1fMB, %d GCs pausing %0.2fms, host network sent %0.1fMB, received %0.1fMB",
+		sum.MeanCPUPercent, sum.MaxCPUPercent, 100*sum.CPUs, megabytes(sum.MaxRSSBytes),
+		sum.GCs, float64(sum.GCPauseNs)/1e6, megabytes(sum.NetSentBytes), megabytes(sum.NetRecvBytes))
+	if sum.ServerMaxRSSBytes > 0 {
+		s += fmt.Sprintf("; server CPU mean %0.1f%%, max %0.1f%%, max RSS %0.1fMB",
+			sum.ServerMeanCPUPercent, sum.ServerMaxCPUPercent, megabytes(sum.ServerMaxRSSBytes))
+	}
+	return s
+}
+
+func megabytes(b uint64) float64 {
+	return float64(b) / (1 << 20)
+}
+
+// Totals returns the summary and the samples, to be saved in a results file
+func (s *Sampler) Totals() map[string]interface{} {
+	return map[string]interface{}{
+		"summary": s.Summary(),
+		"samples": s.Samples(),
+	}
+}
+
+// processReader reads the usage of the client process and of the server
+// process. Errors are logged once and leave the values they affect at 0.
+type processReader struct {
+	self   *process.Process
+	server *process.Process
+
+	warnOnce sync.Once
+}
+
+func (r *processReader) read() reading {
+	cur := reading{time: time.Now()}
+	var err error
+	cur.cpuSeconds, cur.rss, err = readProcess(r.self)
+	r.warn(err)
+
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	cur.gcs = ms.NumGC
+	cur.gcPauseNs = ms.PauseTotalNs
+
+	counters, err := net.IOCounters(false)
+	r.warn(err)
+	if len(counters) > 0 {
+		cur.netSent = counters[0].BytesSent
+		cur.netRecv = counters[0].BytesRecv
+	}
+
+	if r.server != nil {
+		cpuSeconds, rss, err := readProcess(r.server)
+		if err != nil {
+			log.Printf("warning: cannot sample the server process %d any more: %v", r.server.Pid, err)
+			r.server = nil
+		} else {
+			cur.server = &processReading{cpuSeconds: cpuSeconds, rss: rss}
+		}
+	}
+	return cur
+}
+
+func (r *processReader) warn(err error) {
+	if err == nil {
+		return
+	}
+	r.warnOnce.Do(func() {
+		log.Printf("warning: cannot sample client resource usage: %v", err)
+	})
+}
+
+func readProcess(p *process.Process) (cpuSeconds float64, rss uint64, err error) {
+	times, err := p.Times()
+	if err != nil {
+		return 0, 0, err
+	}
+	mem, err := p.MemoryInfo()
+	if err != nil {
+		return 0, 0, err
+	}
+	return times.User + times.System, mem.RSS, nil
+}
@@ -0,0 +1,88 @@
+package resources
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSamplerDiff(t *testing.T) {
+	start := time.Unix(100, 0)
+	readings := []reading{
+		{time: start, cpuSeconds: 1, rss: 10, gcs: 1, gcPauseNs: 100, netSent: 1000, netRecv: 500,
+			server: &processReading{cpuSeconds: 5, rss: 100}},
+		{time: start.Add(time.Second), cpuSeconds: 1.5, rss: 20, gcs: 3, gcPauseNs: 400, netSent: 3000, netRecv: 600,
+			server: &processReading{cpuSeconds: 7, rss: 300}},
+		// counters reset, server gone
+		{time: start.Add(3 * time.Second), cpuSeconds: 3.5, rss: 15, gcs: 3, gcPauseNs: 400, netSent: 10, netRecv: 20},
+	}
+	s := newSampler(time.Hour, func() reading {
+		r := readings[0]
+		readings = readings[1:]
+		return r
+	})
+	s.prev = s.readFn()
+	s.sample()
+	s.sample()
+
+	samples := s.Samples()
+	if len(samples) != 2 {
+		t.Fatalf("incorrect number of samples: got %d want 2", len(samples))
+	}
+	want := Sample{Time: 101000, CPUPercent: 50, RSSBytes: 20, GCs: 2, GCPauseNs: 300, NetSentBytes: 2000, NetRecvBytes: 100,
+		Server: &ProcessSample{CPUPercent: 200, RSSBytes: 300}}
+	if got := samples[0]; got.Time != want.Time || got.CPUPercent != want.CPUPercent || got.RSSBytes != want.RSSBytes ||
+		got.GCs != want.GCs || got.GCPauseNs != want.GCPauseNs || got.NetSentBytes != want.NetSentBytes ||
+		got.NetRecvBytes != want.NetRecvBytes || got.Server == nil || *got.Server != *want.Server {
+		t.Errorf("incorrect first sample: got %+v want %+v", got, want)
+	}
+	if got := samples[1]; got.CPUPercent != 100 || got.NetSentBytes != 0 || got.Server != nil {
+		t.Errorf("incorrect second sample: got %+v", got)
+	}
+
+	sum := s.Summary()
+	if sum.Samples != 2 || sum.MeanCPUPercent != 75 || sum.MaxCPUPercent != 100 || sum.MaxRSSBytes != 20 ||
+		sum.GCs != 2 || sum.GCPauseNs != 300 || sum.NetSentBytes != 2000 || sum.NetRecvBytes != 100 {
+		t.Errorf("incorrect summary: %#v", sum)
+	}
+	if sum.ServerMeanCPUPercent != 200 || sum.ServerMaxRSSBytes != 300 {
+		t.Errorf("incorrect server summary: %#v", sum)
+	}
+	if !strings.Contains(sum.String(), "server CPU mean 200.0%") {
+		t.Errorf("summary does not describe the server: %s", sum)
+	}
+}
+
+func TestNilSampler(t *testing.T) {
+	s, err := NewSampler(0, 0)
+	if err != nil || s != nil {
+		t.Fatalf("unexpected sampler without interval: %v, %v", s, err)
+	}
+	s.Start()
+	s.Stop()
+	if got := s.Summary(); got.Samples != 0 {
+		t.Errorf("unexpected samples: %+v", got)
+	}
+}
+
+func TestSamplerProcess(t *testing.T) {
+	s, err := NewSampler(time.Hour, int32(os.Getpid()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s.Start()
+	time.Sleep(10 * time.Millisecond)
+	s.Stop()
+	samples := s.Samples()
+	if len(samples) != 1 {
+		t.Fatalf("incorrect number of samples: got %d want 1", len(samples))
+	}
+	if samples[0].RSSBytes == 0 || samples[0].Server == nil || samples[0].Server.RSSBytes == 0 {
+		t.Errorf("memory not sampled: %+v", samples[0])
+	}
+
+	if _, err := NewSampler(time.Second, -1); err == nil {
+		t.Errorf("unexpected lack of error for invalid server PID")
+	}
+}
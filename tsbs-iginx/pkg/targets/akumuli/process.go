@@ -28,26 +28,46 @@ func (p *processor) Init(numWorker int, _, _ bool) {
 }
 
 func (p *processor) Close(doLoad bool) {
-	if doLoad {
+	if doLoad && p.conn != nil {
 		p.conn.Close()
 	}
 }
 
-func (p *processor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uint64) {
+// DropBatch returns the buffer of a batch that will not be retried to the pool
+func (p *processor) DropBatch(b targets.Batch) {
+	batch := b.(*batch)
+	batch.buf.Reset()
+	p.bufPool.Put(batch.buf)
+}
+
+func (p *processor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uint64, error) {
 	batch := b.(*batch)
 	var nmetrics uint64
 	if doLoad {
-		head := batch.buf.Bytes()
-		for len(head) != 0 {
+		if p.conn == nil {
+			// the connection was dropped after a failed write
+			c, err := net.Dial("tcp", p.endpoint)
+			if err != nil {
+				return 0, 0, targets.NewBatchError(targets.ErrorTypeConnection, true, err)
+			}
+			p.conn = c
+		}
+		for batch.buf.Len() != 0 {
+			head := batch.buf.Bytes()
 			nbytes := binary.LittleEndian.Uint16(head[4:6])
 			nfields := binary.LittleEndian.Uint16(head[6:8])
 			payload := head[8:nbytes]
-			p.conn.Write(payload)
+			if _, err := p.conn.Write(payload); err != nil {
+				// keep the rest of the batch, it may be retried on a new connection
+				p.conn.Close()
+				p.conn = nil
+				return nmetrics, 0, targets.NewBatchError(targets.ErrorTypeConnection, true, err)
+			}
 			nmetrics += uint64(nfields)
-			head = head[nbytes:]
+			batch.buf.Next(int(nbytes))
 		}
 	}
 	batch.buf.Reset()
 	p.bufPool.Put(batch.buf)
-	return nmetrics, uint64(batch.rows)
+	return nmetrics, uint64(batch.rows), nil
 }
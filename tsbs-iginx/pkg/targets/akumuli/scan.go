@@ -52,6 +52,16 @@ func (b *batch) Len() uint {
 	return b.rows
 }
 
+// Bytes implements targets.SizedBatch
+func (b *batch) Bytes() uint64 {
+	return uint64(b.buf.Len())
+}
+
+// AppendBytes implements targets.SizedBatch
+func (b *batch) AppendBytes(item data.LoadedPoint) uint64 {
+	return uint64(len(item.Data.([]byte)))
+}
+
 func (b *batch) Append(item data.LoadedPoint) {
 	payload := item.Data.([]byte)
 	b.buf.Write(payload)
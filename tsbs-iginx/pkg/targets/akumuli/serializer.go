@@ -35,6 +35,10 @@ func NewAkumuliSerializer() *Serializer {
 // AKUMULI RESP protocol.  Serializer adds extra data to guide data loader.
 // This function writes output that contains binary and text data in RESP format.
 func (s *Serializer) Serialize(p *data.Point, w io.Writer) (err error) {
+	// Akumuli values are integers or floats
+	if err := serialize.CheckNumericFields("akumuli", p); err != nil {
+		return err
+	}
 	deferPoint := false
 
 	buf := make([]byte, 0, 1024)
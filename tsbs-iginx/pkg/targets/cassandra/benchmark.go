@@ -8,7 +8,6 @@ import (
 	"github.com/timescale/tsbs/load"
 	"github.com/timescale/tsbs/pkg/data/source"
 	"github.com/timescale/tsbs/pkg/targets"
-	"log"
 )
 
 type benchmark struct {
@@ -67,7 +66,7 @@ func (p *processor) Init(_ int, _, _ bool) {}
 
 // ProcessBatch reads eventsBatches which contain rows of CQL strings and
 // creates a gocql.LoggedBatch to insert
-func (p *processor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uint64) {
+func (p *processor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uint64, error) {
 	events := b.(*eventsBatch)
 
 	if doLoad {
@@ -78,11 +77,29 @@ func (p *processor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uint64)
 
 		err := p.dbc.clientSession.ExecuteBatch(batch)
 		if err != nil {
-			log.Fatalf("Error writing: %s\n", err.Error())
+			return 0, 0, classifyCQLError(err)
 		}
 	}
 	metricCnt := uint64(len(events.rows))
 	events.rows = events.rows[:0]
 	ePool.Put(events)
-	return metricCnt, 0
+	return metricCnt, 0, nil
+}
+
+// classifyCQLError wraps the errors of a write that Cassandra could not
+// complete in time or with enough replicas in retryable BatchErrors
+func classifyCQLError(err error) error {
+	switch err.(type) {
+	case *gocql.RequestErrWriteTimeout:
+		return targets.NewBatchError(targets.ErrorTypeTimeout, true, err)
+	case *gocql.RequestErrUnavailable:
+		return targets.NewBatchError(targets.ErrorTypeServer, true, err)
+	}
+	if err == gocql.ErrTimeoutNoResponse {
+		return targets.NewBatchError(targets.ErrorTypeTimeout, true, err)
+	}
+	if err == gocql.ErrNoConnections {
+		return targets.NewBatchError(targets.ErrorTypeConnection, true, err)
+	}
+	return err
 }
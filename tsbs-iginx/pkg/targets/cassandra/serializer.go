@@ -1,6 +1,7 @@
 package cassandra
 
 import (
+	"encoding/hex"
 	"fmt"
 	"github.com/timescale/tsbs/pkg/data"
 	"github.com/timescale/tsbs/pkg/data/serialize"
@@ -87,7 +88,17 @@ func generateFieldBuf(tsNanos int64, tsBucket string, seriesIDPrefix, key []byte
 	buf = append(buf, []byte(tsBucket)...)
 	buf = append(buf, comma...)
 	buf = append(buf, []byte(fmt.Sprintf("%d,", tsNanos))...)
-	buf = serialize.FastFormatAppend(value, buf)
+	switch v := value.(type) {
+	case string:
+		// as a blob literal
+		buf = append(buf, "0x"...)
+		buf = append(buf, hex.EncodeToString([]byte(v))...)
+	case []byte:
+		buf = append(buf, "0x"...)
+		buf = append(buf, hex.EncodeToString(v)...)
+	default:
+		buf = serialize.FastFormatAppend(value, buf)
+	}
 
 	buf = append(buf, []byte("\n")...)
 	return buf
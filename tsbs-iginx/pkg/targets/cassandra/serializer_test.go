@@ -22,6 +22,13 @@ func TestCassandraSerializerSerialize(t *testing.T) {
 			InputPoint: serialize.TestPointNoTags(),
 			Output:     "series_double,cpu,usage_guest_nice,2016-01-01,1451606400000000000,38.24311829\n",
 		},
+		{
+			Desc:       "a Point with string and boolean fields",
+			InputPoint: serialize.TestPointNonNumeric(),
+			Output: "series_blob,cpu,hostname=host_0,region=eu-west-1,datacenter=eu-west-1b,log_level,2016-01-01,1451606400000000000,0x5741524e\n" +
+				"series_boolean,cpu,hostname=host_0,region=eu-west-1,datacenter=eu-west-1b,throttled,2016-01-01,1451606400000000000,true\n" +
+				"series_double,cpu,hostname=host_0,region=eu-west-1,datacenter=eu-west-1b,usage_guest_nice,2016-01-01,1451606400000000000,38.24311829\n",
+		},
 	}
 	serialize.SerializerTest(t, cases, &Serializer{})
 }
@@ -20,6 +20,10 @@ type Serializer struct{}
 // An example of a serialized point:
 //     cpu\t{"hostname":"host_0","rack":"1"}\t1451606400000000000\t38\t0\t50\t41234
 func (s *Serializer) Serialize(p *data.Point, w io.Writer) error {
+	// the loader creates a double column per field
+	if err := serialize.CheckNumericFields("cratedb", p); err != nil {
+		return err
+	}
 	buf := make([]byte, 0, 256)
 
 	// measurement type
@@ -0,0 +1,87 @@
+package targets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+)
+
+// Types of batch errors, under which failed batches are counted
+const (
+	// ErrorTypeConnection is a failure to connect or a dropped connection
+	ErrorTypeConnection = "connection"
+	// ErrorTypeTimeout is a request that did not complete in time
+	ErrorTypeTimeout = "timeout"
+	// ErrorTypeRejected is data or a request refused by the database
+	ErrorTypeRejected = "rejected"
+	// ErrorTypeServer is an internal error or overload of the database
+	ErrorTypeServer = "server"
+	// ErrorTypeOther is any error that was not classified
+	ErrorTypeOther = "other"
+)
+
+// BatchError is an error returned by Processor.ProcessBatch, classified so
+// that the load runner can count it and decide whether to retry the batch.
+type BatchError struct {
+	Type      string
+	Retryable bool
+	Err       error
+}
+
+// NewBatchError returns a BatchError of the given type wrapping err
+func NewBatchError(errType string, retryable bool, err error) *BatchError {
+	return &BatchError{Type: errType, Retryable: retryable, Err: err}
+}
+
+func (e *BatchError) Error() string {
+	return e.Type + " error: " + e.Err.Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// ClassifyError returns the type of a batch error and whether retrying the
+// batch may succeed. BatchErrors are classified as they declare; network
+// errors are recognized, any other error is of ErrorTypeOther and not
+// retryable.
+func ClassifyError(err error) (errType string, retryable bool) {
+	var be *BatchError
+	if errors.As(err, &be) {
+		return be.Type, be.Retryable
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrorTypeTimeout, true
+	}
+	var ne net.Error
+	if errors.As(err, &ne) && ne.Timeout() {
+		return ErrorTypeTimeout, true
+	}
+	var oe *net.OpError
+	if errors.As(err, &oe) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return ErrorTypeConnection, true
+	}
+	return ErrorTypeOther, false
+}
+
+// HTTPStatusError returns the BatchError for an unsuccessful HTTP response:
+// the database rejected the data on 4xx statuses and failed on 5xx ones.
+// Server errors, timeouts and throttling are retryable.
+func HTTPStatusError(statusCode int, body []byte) *BatchError {
+	err := fmt.Errorf("unexpected response status %d: %s", statusCode, body)
+	switch {
+	case statusCode == http.StatusRequestTimeout || statusCode == http.StatusGatewayTimeout:
+		return NewBatchError(ErrorTypeTimeout, true, err)
+	case statusCode == http.StatusTooManyRequests:
+		return NewBatchError(ErrorTypeServer, true, err)
+	case statusCode >= 500:
+		return NewBatchError(ErrorTypeServer, true, err)
+	}
+	return NewBatchError(ErrorTypeRejected, false, err)
+}
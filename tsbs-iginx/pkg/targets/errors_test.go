@@ -0,0 +1,114 @@
+package targets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"testing"
+)
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestClassifyError(t *testing.T) {
+	cases := []struct {
+		desc          string
+		err           error
+		wantType      string
+		wantRetryable bool
+	}{
+		{
+			desc:          "batch error",
+			err:           NewBatchError(ErrorTypeRejected, false, errors.New("bad line")),
+			wantType:      ErrorTypeRejected,
+			wantRetryable: false,
+		},
+		{
+			desc:          "wrapped batch error",
+			err:           fmt.Errorf("insert: %w", NewBatchError(ErrorTypeServer, true, errors.New("overloaded"))),
+			wantType:      ErrorTypeServer,
+			wantRetryable: true,
+		},
+		{
+			desc:          "deadline exceeded",
+			err:           fmt.Errorf("insert: %w", context.DeadlineExceeded),
+			wantType:      ErrorTypeTimeout,
+			wantRetryable: true,
+		},
+		{
+			desc:          "net timeout",
+			err:           &net.OpError{Op: "read", Err: timeoutError{}},
+			wantType:      ErrorTypeTimeout,
+			wantRetryable: true,
+		},
+		{
+			desc:          "connection refused",
+			err:           &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED},
+			wantType:      ErrorTypeConnection,
+			wantRetryable: true,
+		},
+		{
+			desc:          "connection reset",
+			err:           fmt.Errorf("write: %w", syscall.ECONNRESET),
+			wantType:      ErrorTypeConnection,
+			wantRetryable: true,
+		},
+		{
+			desc:          "unexpected EOF",
+			err:           io.ErrUnexpectedEOF,
+			wantType:      ErrorTypeConnection,
+			wantRetryable: true,
+		},
+		{
+			desc:          "other",
+			err:           errors.New("something else"),
+			wantType:      ErrorTypeOther,
+			wantRetryable: false,
+		},
+	}
+	for _, c := range cases {
+		errType, retryable := ClassifyError(c.err)
+		if errType != c.wantType {
+			t.Errorf("%s: incorrect type: got %s want %s", c.desc, errType, c.wantType)
+		}
+		if retryable != c.wantRetryable {
+			t.Errorf("%s: incorrect retryable: got %v want %v", c.desc, retryable, c.wantRetryable)
+		}
+	}
+}
+
+func TestHTTPStatusError(t *testing.T) {
+	cases := []struct {
+		code          int
+		wantType      string
+		wantRetryable bool
+	}{
+		{http.StatusBadRequest, ErrorTypeRejected, false},
+		{http.StatusNotFound, ErrorTypeRejected, false},
+		{http.StatusRequestTimeout, ErrorTypeTimeout, true},
+		{http.StatusTooManyRequests, ErrorTypeServer, true},
+		{http.StatusInternalServerError, ErrorTypeServer, true},
+		{http.StatusServiceUnavailable, ErrorTypeServer, true},
+		{http.StatusGatewayTimeout, ErrorTypeTimeout, true},
+	}
+	for _, c := range cases {
+		err := HTTPStatusError(c.code, []byte("body"))
+		if err.Type != c.wantType {
+			t.Errorf("status %d: incorrect type: got %s want %s", c.code, err.Type, c.wantType)
+		}
+		if err.Retryable != c.wantRetryable {
+			t.Errorf("status %d: incorrect retryable: got %v want %v", c.code, err.Retryable, c.wantRetryable)
+		}
+		want := fmt.Sprintf("%s error: unexpected response status %d: body", c.wantType, c.code)
+		if err.Error() != want {
+			t.Errorf("status %d: incorrect message: got %q want %q", c.code, err.Error(), want)
+		}
+	}
+}
@@ -83,6 +83,11 @@ func appendField(buf, key []byte, v interface{}) []byte {
 	buf = append(buf, key...)
 	buf = append(buf, '=')
 
+	// strings are quoted, so the loader passes them to IginX as JSON strings
+	// and booleans as JSON booleans
+	if str, ok := v.(string); ok {
+		return serialize.AppendQuoted(str, buf)
+	}
 	buf = serialize.FastFormatAppend(v, buf)
 
 	// Influx uses 'i' to indicate integers:
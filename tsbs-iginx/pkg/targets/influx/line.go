@@ -0,0 +1,54 @@
+package influx
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseLine returns the measurement, the field names and the timestamp of a
+// line of the InfluxDB line protocol, as written by Serializer: escaped
+// spaces and commas are not supported outside of string field values.
+func ParseLine(line []byte) (measurement string, fields []string, timestamp int64, err error) {
+	parts := SplitLine(string(line), ' ')
+	if len(parts) != 3 {
+		return "", nil, 0, fmt.Errorf("line does not have 3 tuples, has %d", len(parts))
+	}
+	tags := parts[0]
+	if i := strings.IndexByte(tags, ','); i >= 0 {
+		tags = tags[:i]
+	}
+	for _, field := range SplitLine(parts[1], ',') {
+		i := strings.IndexByte(field, '=')
+		if i <= 0 {
+			return "", nil, 0, fmt.Errorf("invalid field %q", field)
+		}
+		fields = append(fields, field[:i])
+	}
+	timestamp, err = strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return "", nil, 0, fmt.Errorf("invalid timestamp: %v", err)
+	}
+	return tags, fields, timestamp, nil
+}
+
+// SplitLine splits a line, or a part of it, around each sep like
+// strings.Split, except inside the double quotes of a string field value,
+// where a backslash escapes the byte after it.
+func SplitLine(s string, sep byte) []string {
+	var parts []string
+	quoted := false
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch {
+		case quoted && s[i] == '\\':
+			i++
+		case s[i] == '"':
+			quoted = !quoted
+		case !quoted && s[i] == sep:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
@@ -0,0 +1,117 @@
+package influx
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseLine(t *testing.T) {
+	cases := []struct {
+		desc            string
+		line            string
+		wantMeasurement string
+		wantFields      []string
+		wantTimestamp   int64
+		wantErr         bool
+	}{
+		{
+			desc:            "tags and fields",
+			line:            "cpu,hostname=host_0,region=eu-west-1 usage_guest=38i,usage_guest_nice=38.24311829 1451606400000000000",
+			wantMeasurement: "cpu",
+			wantFields:      []string{"usage_guest", "usage_guest_nice"},
+			wantTimestamp:   1451606400000000000,
+		},
+		{
+			desc:            "no tags",
+			line:            "cpu usage_guest_nice=38.24311829 1451606400000000000",
+			wantMeasurement: "cpu",
+			wantFields:      []string{"usage_guest_nice"},
+			wantTimestamp:   1451606400000000000,
+		},
+		{
+			desc:            "string field with a space, a comma and an equal sign",
+			line:            `cpu,hostname=host_0 log="a b,c=d \\\" e",usage_guest=38i 1451606400000000000`,
+			wantMeasurement: "cpu",
+			wantFields:      []string{"log", "usage_guest"},
+			wantTimestamp:   1451606400000000000,
+		},
+		{
+			desc:    "missing timestamp",
+			line:    "cpu usage_guest_nice=38.24311829",
+			wantErr: true,
+		},
+		{
+			desc:    "invalid field",
+			line:    "cpu usage_guest_nice 1451606400000000000",
+			wantErr: true,
+		},
+		{
+			desc:    "invalid timestamp",
+			line:    "cpu usage_guest_nice=1 now",
+			wantErr: true,
+		},
+	}
+	for _, c := range cases {
+		measurement, fields, timestamp, err := ParseLine([]byte(c.line))
+		if c.wantErr {
+			if err == nil {
+				t.Errorf("%s: unexpected lack of error", c.desc)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", c.desc, err)
+			continue
+		}
+		if measurement != c.wantMeasurement || strings.Join(fields, ",") != strings.Join(c.wantFields, ",") || timestamp != c.wantTimestamp {
+			t.Errorf("%s: incorrect result: got %s %v %d want %s %v %d", c.desc,
+				measurement, fields, timestamp, c.wantMeasurement, c.wantFields, c.wantTimestamp)
+		}
+	}
+}
+
+func TestSplitLine(t *testing.T) {
+	cases := []struct {
+		desc string
+		s    string
+		sep  byte
+		want []string
+	}{
+		{
+			desc: "no quotes",
+			s:    "a=1,b=2",
+			sep:  ',',
+			want: []string{"a=1", "b=2"},
+		},
+		{
+			desc: "separators in quotes",
+			s:    `a="x,y z",b=2`,
+			sep:  ',',
+			want: []string{`a="x,y z"`, "b=2"},
+		},
+		{
+			desc: "escaped quote",
+			s:    `a="x\" y" b=2`,
+			sep:  ' ',
+			want: []string{`a="x\" y"`, "b=2"},
+		},
+		{
+			desc: "escaped backslash",
+			s:    `a="x\\" b=2`,
+			sep:  ' ',
+			want: []string{`a="x\\"`, "b=2"},
+		},
+		{
+			desc: "empty",
+			s:    "",
+			sep:  ',',
+			want: []string{""},
+		},
+	}
+	for _, c := range cases {
+		got := SplitLine(c.s, c.sep)
+		if strings.Join(got, "|") != strings.Join(c.want, "|") || len(got) != len(c.want) {
+			t.Errorf("%s: incorrect parts: got %q want %q", c.desc, got, c.want)
+		}
+	}
+}
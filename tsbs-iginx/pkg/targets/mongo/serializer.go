@@ -4,6 +4,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/serialize"
 	"io"
 	"sync"
 
@@ -21,6 +22,10 @@ type Serializer struct{}
 
 // Serialize writes Point data to the given Writer, using basic gob encoding
 func (s *Serializer) Serialize(p *data.Point, w io.Writer) (err error) {
+	// readings hold float64 values
+	if err := serialize.CheckNumericFields("mongo", p); err != nil {
+		return err
+	}
 	b := fbBuilderPool.Get().(*flatbuffers.Builder)
 
 	timestampNanos := p.Timestamp().UTC().UnixNano()
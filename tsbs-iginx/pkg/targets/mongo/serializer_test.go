@@ -179,23 +179,20 @@ func deserializeMongo(r *bufio.Reader) *MongoPoint {
 	return item
 }
 
-func TestMongoSerializerTypePanic(t *testing.T) {
-	testPanic := func() {
-		defer func() {
-			if r := recover(); r == nil {
-				t.Errorf("did not panic when should")
-			}
-		}()
-		p := &data.Point{}
-		p.SetMeasurementName(serialize.TestMeasurement)
-		p.SetTimestamp(&serialize.TestNow)
-		p.AppendField([]byte("broken"), "a string?")
-		ps := &Serializer{}
-		b := new(bytes.Buffer)
+func TestMongoSerializerTypeErr(t *testing.T) {
+	p := &data.Point{}
+	p.SetMeasurementName(serialize.TestMeasurement)
+	p.SetTimestamp(&serialize.TestNow)
+	p.AppendField([]byte("broken"), "a string?")
+	ps := &Serializer{}
+	b := new(bytes.Buffer)
 
-		ps.Serialize(p, b)
+	if err := ps.Serialize(p, b); err == nil {
+		t.Errorf("no error returned for a string field")
+	}
+	if b.Len() != 0 {
+		t.Errorf("unexpected output for a string field: %v", b.Bytes())
 	}
-	testPanic()
 }
 
 func TestMongoSerializerSerializeErr(t *testing.T) {
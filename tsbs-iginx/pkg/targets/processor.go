@@ -4,8 +4,11 @@ package targets
 type Processor interface {
 	// Init does per-worker setup needed before receiving data
 	Init(workerNum int, doLoad, hashWorkers bool)
-	// ProcessBatch handles a single batch of data
-	ProcessBatch(b Batch, doLoad bool) (metricCount, rowCount uint64)
+	// ProcessBatch handles a single batch of data. If the batch could not be
+	// (fully) inserted it returns an error, preferably a *BatchError, along with
+	// the counts of what was inserted before the failure. The rest of a batch
+	// that failed must be left intact, since the load runner may retry it.
+	ProcessBatch(b Batch, doLoad bool) (metricCount, rowCount uint64, err error)
 }
 
 // ProcessorCloser is a Processor that also needs to close or cleanup afterwards
@@ -14,3 +17,11 @@ type ProcessorCloser interface {
 	// Close cleans up after a Processor
 	Close(doLoad bool)
 }
+
+// ProcessorDropper is a Processor that keeps resources of a failed batch, such
+// as pooled buffers, for a retry and must be told when the batch is dropped
+type ProcessorDropper interface {
+	Processor
+	// DropBatch releases a failed batch that will not be retried
+	DropBatch(b Batch)
+}
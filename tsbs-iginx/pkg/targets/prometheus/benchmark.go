@@ -29,7 +29,7 @@ func NewBenchmark(promSpecificConfig *SpecificConfig, dataSourceConfig *source.D
 		if err != nil {
 			return nil, err
 		}
-		ds = newSimulationDataSource(simulator, promSpecificConfig.UseCurrentTime)
+		ds = newSimulationDataSource(dataSourceConfig.Paced(simulator), promSpecificConfig.UseCurrentTime)
 	}
 
 	batchPool := &sync.Pool{New: func() interface{} {
@@ -87,19 +87,19 @@ type Processor struct {
 func (pp *Processor) Init(_ int, _, _ bool) {}
 
 // ProcessBatch ..
-func (pp *Processor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uint64) {
+func (pp *Processor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uint64, error) {
 	promBatch := b.(*Batch)
 	nrSamples := uint64(promBatch.Len())
 	if doLoad {
 		err := pp.client.Post(promBatch.series)
 		if err != nil {
-			panic(err)
+			return 0, 0, err
 		}
 	}
 	// reset batch
 	promBatch.series = promBatch.series[:0]
 	pp.batchPool.Put(promBatch)
-	return nrSamples, nrSamples
+	return nrSamples, nrSamples, nil
 }
 
 // PrometheusBatchFactory implements Factory interface
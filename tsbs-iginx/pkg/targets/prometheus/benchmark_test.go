@@ -23,7 +23,10 @@ func TestPrometheusLoader(t *testing.T) {
 	}
 	pp := pb.GetProcessor().(*Processor)
 	batch := &Batch{series: []prompb.TimeSeries{{}}}
-	samples, _ := pp.ProcessBatch(batch, true)
+	samples, _, err := pp.ProcessBatch(batch, true)
+	if err != nil {
+		t.Fatal(err)
+	}
 	if samples != 1 {
 		t.Error("wrong number of samples")
 	}
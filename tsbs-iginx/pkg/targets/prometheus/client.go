@@ -13,6 +13,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/golang/snappy"
 	"github.com/timescale/promscale/pkg/prompb"
+	"github.com/timescale/tsbs/pkg/targets"
 )
 
 // Client is a wrapper around http.Client
@@ -93,7 +94,8 @@ func (c *Client) Post(series []prompb.TimeSeries) error {
 	}()
 
 	if httpResp.StatusCode/100 != 2 {
-		return fmt.Errorf("Prometheus adapter returned status: %s", httpResp.Status)
+		body, _ := ioutil.ReadAll(httpResp.Body)
+		return fmt.Errorf("Prometheus adapter returned status: %s: %w", httpResp.Status, targets.HTTPStatusError(httpResp.StatusCode, body))
 	}
 	return nil
 }
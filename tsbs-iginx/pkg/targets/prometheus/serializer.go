@@ -17,6 +17,7 @@ import (
 	"github.com/prometheus/common/model"
 	"github.com/timescale/promscale/pkg/prompb"
 	"github.com/timescale/tsbs/pkg/data"
+	"github.com/timescale/tsbs/pkg/data/serialize"
 )
 
 const serializerVersion uint64 = 1
@@ -45,6 +46,10 @@ func (ps *Serializer) writeHeader(w io.Writer) (int, error) {
 
 // Serialize point into our custom binary format
 func (ps *Serializer) Serialize(p *data.Point, w io.Writer) error {
+	// samples hold float64 values
+	if err := serialize.CheckNumericFields("prometheus", p); err != nil {
+		return err
+	}
 	if !ps.headerWritten {
 		if _, err := ps.writeHeader(w); err != nil {
 			return err
@@ -83,6 +83,10 @@ func appendField(buf, key []byte, v interface{}) []byte {
 	buf = append(buf, key...)
 	buf = append(buf, '=')
 
+	// Influx quotes strings, unlike booleans
+	if str, ok := v.(string); ok {
+		return serialize.AppendQuoted(str, buf)
+	}
 	buf = serialize.FastFormatAppend(v, buf)
 
 	// Influx uses 'i' to indicate integers:
@@ -34,6 +34,10 @@ func TestInfluxSerializerSerialize(t *testing.T) {
 			Desc:       "a Point with a nil field",
 			InputPoint: serialize.TestPointWithNilField(),
 			Output:     "cpu usage_guest_nice=38.24311829 1451606400000000000\n",
+		}, {
+			Desc:       "a Point with string and boolean fields",
+			InputPoint: serialize.TestPointNonNumeric(),
+			Output:     "cpu,hostname=host_0,region=eu-west-1,datacenter=eu-west-1b log_level=\"WARN\",throttled=true,usage_guest_nice=38.24311829 1451606400000000000\n",
 		},
 	}
 
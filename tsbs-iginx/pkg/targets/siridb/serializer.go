@@ -57,6 +57,10 @@ func (s *Serializer) Serialize(p *data.Point, w io.Writer) error {
 	fieldValues := p.FieldValues()
 	fieldKeys := p.FieldKeys()
 	for i, value := range fieldValues {
+		// SiriDB series are integers, floats or strings
+		if _, ok := value.(bool); ok {
+			return fmt.Errorf("siridb serializer does not support the bool value of field %s", fieldKeys[i])
+		}
 
 		indexLenData := len(line) + 4
 
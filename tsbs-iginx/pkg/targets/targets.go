@@ -30,6 +30,16 @@ type Batch interface {
 	Append(data.LoadedPoint)
 }
 
+// SizedBatch is a Batch that knows the size of its payload, so that it can
+// be cut by bytes as well as by number of items
+type SizedBatch interface {
+	Batch
+	// Bytes returns the size of the request the batch is sent with
+	Bytes() uint64
+	// AppendBytes returns how much Bytes grows when the item is appended
+	AppendBytes(data.LoadedPoint) uint64
+}
+
 // PointIndexer determines the index of the Batch (and subsequently the channel)
 // that a particular point belongs to
 type PointIndexer interface {
@@ -75,3 +85,11 @@ type DataSource interface {
 	NextItem() data.LoadedPoint
 	Headers() *common.GeneratedDataHeaders
 }
+
+// DataSourceCloser is a DataSource that needs to release its resources, such
+// as open files, once the scan stops, even before all its items were read
+type DataSourceCloser interface {
+	DataSource
+	// Close stops reading and releases the resources of the DataSource
+	Close()
+}
@@ -19,7 +19,7 @@ func NewBenchmark(dbName string, opts *LoadingOptions, dataSourceConfig *source.
 		if err != nil {
 			return nil, err
 		}
-		ds = newSimulationDataSource(simulator)
+		ds = newSimulationDataSource(dataSourceConfig.Paced(simulator))
 	}
 
 	return &benchmark{
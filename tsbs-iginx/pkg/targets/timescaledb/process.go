@@ -7,7 +7,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
-	"os"
 	"strconv"
 	"strings"
 	"sync"
@@ -183,7 +182,7 @@ func (p *processor) splitTagsAndMetrics(rows []*insertData, dataCols int) ([][]s
 	return tagRows, dataRows, numMetrics
 }
 
-func (p *processor) processCSI(hypertable string, rows []*insertData) uint64 {
+func (p *processor) processCSI(hypertable string, rows []*insertData) (uint64, error) {
 	colLen := len(tableCols[hypertable]) + numExtraCols
 	if p.opts.InTableTag {
 		colLen++
@@ -223,10 +222,14 @@ func (p *processor) processCSI(hypertable string, rows []*insertData) uint64 {
 	cols = append(cols, tableCols[hypertable]...)
 
 	if p.opts.ForceTextFormat {
-		tx := MustBegin(p._db)
+		tx, err := p._db.Begin()
+		if err != nil {
+			return 0, err
+		}
 		stmt, err := tx.Prepare(pq.CopyIn(hypertable, cols...))
 		if err != nil {
-			panic(err)
+			tx.Rollback()
+			return 0, err
 		}
 
 		for _, r := range dataRows {
@@ -234,17 +237,19 @@ func (p *processor) processCSI(hypertable string, rows []*insertData) uint64 {
 		}
 		_, err = stmt.Exec()
 		if err != nil {
-			panic(err)
+			tx.Rollback()
+			return 0, err
 		}
 
 		err = stmt.Close()
 		if err != nil {
-			panic(err)
+			tx.Rollback()
+			return 0, err
 		}
 
 		err = tx.Commit()
 		if err != nil {
-			panic(err)
+			return 0, err
 		}
 	} else {
 		if !p.opts.UseInsert {
@@ -252,39 +257,46 @@ func (p *processor) processCSI(hypertable string, rows []*insertData) uint64 {
 			inserted, err := p._pgxConn.CopyFrom(context.Background(), pgx.Identifier{hypertable}, cols, rows)
 
 			if err != nil {
-				panic(err)
+				return 0, err
 			}
 
 			if inserted != int64(len(dataRows)) {
-				fmt.Fprintf(os.Stderr, "Failed to insert all the data! Expected: %d, Got: %d", len(dataRows), inserted)
-				os.Exit(1)
+				return 0, targets.NewBatchError(targets.ErrorTypeRejected, false,
+					fmt.Errorf("failed to insert all the data, expected: %d, got: %d", len(dataRows), inserted))
 			}
 		} else {
-			tx := MustBegin(p._db)
-			var stmt *sql.Stmt
-			var err error
+			tx, err := p._db.Begin()
+			if err != nil {
+				return 0, err
+			}
 
 			stmtString := genBatchInsertStmt(hypertable, cols, len(dataRows))
-			stmt, err = tx.Prepare(stmtString)
+			stmt, err := tx.Prepare(stmtString)
+			if err != nil {
+				tx.Rollback()
+				return 0, err
+			}
 
 			_, err = stmt.Exec(flatten(dataRows)...)
 			if err != nil {
-				panic(err)
+				tx.Rollback()
+				return 0, err
 			}
 
 			err = stmt.Close()
 			if err != nil {
-				panic(err)
+				tx.Rollback()
+				return 0, err
 			}
 
 			err = tx.Commit()
 			if err != nil {
-				panic(err)
+				return 0, err
 			}
 		}
 	}
 
-	return numMetrics
+	return numMetrics, nil
 }
 
 func newProcessor(opts *LoadingOptions, driver, dbName string) *processor {
@@ -381,15 +393,20 @@ func (p *processor) Close(doLoad bool) {
 	}
 }
 
-func (p *processor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uint64) {
+func (p *processor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uint64, error) {
 	batches := b.(*hypertableArr)
 	rowCnt := 0
 	metricCnt := uint64(0)
 	for hypertable, rows := range batches.m {
-		rowCnt += len(rows)
 		if doLoad {
 			start := time.Now()
-			metricCnt += p.processCSI(hypertable, rows)
+			n, err := p.processCSI(hypertable, rows)
+			if err != nil {
+				// the hypertables inserted so far are removed from the batch,
+				// a retry only inserts the rest
+				return metricCnt, uint64(rowCnt), err
+			}
+			metricCnt += n
 
 			if p.opts.LogBatches {
 				now := time.Now()
@@ -398,10 +415,12 @@ func (p *processor) ProcessBatch(b targets.Batch, doLoad bool) (uint64, uint64)
 				fmt.Printf("BATCH: batchsize %d row rate %f/sec (took %v)\n", batchSize, float64(batchSize)/float64(took.Seconds()), took)
 			}
 		}
+		rowCnt += len(rows)
+		delete(batches.m, hypertable)
 	}
 	batches.m = map[string][]*insertData{}
 	batches.cnt = 0
-	return metricCnt, uint64(rowCnt)
+	return metricCnt, uint64(rowCnt), nil
 }
 func convertValsToSQLBasedOnType(values []string, types []string) []string {
 	return convertValsToBasedOnType(values, types, "'", "NULL")
@@ -18,6 +18,10 @@ type Serializer struct{}
 // tags,<tag1>,<tag2>,<tag3>,...
 // <measurement>,<timestamp>,<field1>,<field2>,<field3>,...
 func (s *Serializer) Serialize(p *data.Point, w io.Writer) error {
+	// the loaders create a DOUBLE PRECISION or Float64 column per field
+	if err := serialize.CheckNumericFields("timescaledb", p); err != nil {
+		return err
+	}
 	// Tag row first, prefixed with name 'tags'
 	buf := make([]byte, 0, 256)
 	buf = append(buf, []byte("tags")...)
@@ -1,6 +1,7 @@
 package timescaledb
 
 import (
+	"bytes"
 	"github.com/timescale/tsbs/pkg/data/serialize"
 	"testing"
 )
@@ -42,3 +43,10 @@ func TestTimescaleDBSerializerSerializeErr(t *testing.T) {
 		t.Errorf("unexpected writer error: %v", err)
 	}
 }
+
+func TestTimescaleDBSerializerSerializeNonNumeric(t *testing.T) {
+	s := &Serializer{}
+	if err := s.Serialize(serialize.TestPointNonNumeric(), &bytes.Buffer{}); err == nil {
+		t.Errorf("no error returned for string and boolean fields")
+	}
+}
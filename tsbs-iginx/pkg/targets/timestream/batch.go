@@ -1,6 +1,9 @@
 package timestream
 
 import (
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/timestreamwrite"
+	"github.com/pkg/errors"
 	"github.com/timescale/tsbs/pkg/data"
 	"github.com/timescale/tsbs/pkg/targets"
 	"sync"
@@ -44,6 +47,44 @@ func (b *batch) reset() {
 	b.cnt = 0
 }
 
+// writeTables writes the rows of every table of the batch with write and
+// returns the batch to the pool. If a write fails, the tables written so far
+// are removed from the batch so that a retry only writes the remaining ones.
+func writeTables(b *batch, doLoad bool, pool *sync.Pool, write func(table string, rows []deserializedPoint) (uint64, error)) (metricCount, rowCount uint64, err error) {
+	for table, rows := range b.rows {
+		if doLoad {
+			newMetricCount, err := write(table, rows)
+			if err != nil {
+				return metricCount, rowCount, classifyWriteError(errors.Wrap(err, "could not write to table"))
+			}
+			metricCount += newMetricCount
+		}
+		rowCount += uint64(len(rows))
+		b.cnt -= uint(len(rows))
+		delete(b.rows, table)
+	}
+	b.reset()
+	pool.Put(b)
+	return metricCount, rowCount, nil
+}
+
+// classifyWriteError wraps the errors Timestream reports for throttled or
+// failed requests in retryable BatchErrors, and rejected records in
+// non-retryable ones
+func classifyWriteError(err error) error {
+	var awsErr awserr.Error
+	if !errors.As(err, &awsErr) {
+		return err
+	}
+	switch awsErr.Code() {
+	case timestreamwrite.ErrCodeThrottlingException, timestreamwrite.ErrCodeInternalServerException:
+		return targets.NewBatchError(targets.ErrorTypeServer, true, err)
+	case timestreamwrite.ErrCodeRejectedRecordsException, timestreamwrite.ErrCodeValidationException:
+		return targets.NewBatchError(targets.ErrorTypeRejected, false, err)
+	}
+	return err
+}
+
 // batchFactory implements the targets.BatchFactory interface
 type batchFactory struct {
 	pool *sync.Pool
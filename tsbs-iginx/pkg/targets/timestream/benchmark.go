@@ -101,7 +101,7 @@ func initDataSource(config *source.DataSourceConfig, useCurrentTs bool) (targets
 			return nil, err
 		}
 		return &simulatorDataSource{
-			simulator:    simulator,
+			simulator:    config.Paced(simulator),
 			useCurrentTs: useCurrentTs,
 		}, nil
 	}
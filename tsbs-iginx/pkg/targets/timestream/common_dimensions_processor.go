@@ -6,7 +6,6 @@ import (
 	"github.com/pkg/errors"
 	"github.com/timescale/tsbs/pkg/data/usecases/common"
 	"github.com/timescale/tsbs/pkg/targets"
-	"log"
 	"sync"
 )
 
@@ -30,22 +29,8 @@ func (c *commonDimensionsProcessor) Init(workerNum int, doLoad, hashWorkers bool
 	c._recordsBuffer = make([]*timestreamwrite.Record, maxFields)
 }
 
-func (c *commonDimensionsProcessor) ProcessBatch(b targets.Batch, doLoad bool) (metricCount, rowCount uint64) {
-	var timestreamBatch batch
-	timestreamBatch = *b.(*batch)
-	for table, rows := range timestreamBatch.rows {
-		rowCount += uint64(len(rows))
-		if doLoad {
-			newMetricCount, err := c.writeToTable(table, rows)
-			if err != nil {
-				log.Fatal("could not write to table: " + err.Error())
-			}
-			metricCount += newMetricCount
-		}
-	}
-	timestreamBatch.reset()
-	c.batchPool.Put(b)
-	return metricCount, rowCount
+func (c *commonDimensionsProcessor) ProcessBatch(b targets.Batch, doLoad bool) (metricCount, rowCount uint64, err error) {
+	return writeTables(b.(*batch), doLoad, c.batchPool, c.writeToTable)
 }
 
 func (c *commonDimensionsProcessor) expandDimensionBuffer(requiredDimensions int) {
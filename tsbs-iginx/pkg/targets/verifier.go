@@ -0,0 +1,38 @@
+package targets
+
+import (
+	"time"
+
+	"github.com/timescale/tsbs/pkg/data"
+)
+
+// MeasurementStats describes the points of a measurement, either the ones a
+// load sent or the ones the database stores
+type MeasurementStats struct {
+	// Points is the number of field values
+	Points uint64
+	First  time.Time
+	Last   time.Time
+}
+
+// Verifier reads back what a load stored, so that it can be compared with
+// what was sent.
+type Verifier interface {
+	// Inspect returns the measurement, the field names and the timestamp of
+	// an item, at the precision the database stores timestamps with
+	Inspect(item data.LoadedPoint) (measurement string, fields []string, ts time.Time, err error)
+
+	// Stored returns what the database stores for the given measurements,
+	// whose field names are the ones inspected. Measurements without any
+	// point may be left out.
+	Stored(dbName string, fields map[string][]string) (map[string]MeasurementStats, error)
+}
+
+// VerifiableBenchmark is a Benchmark whose loads can be verified after
+// they are done
+type VerifiableBenchmark interface {
+	Benchmark
+
+	// GetVerifier returns the Verifier of the target database
+	GetVerifier() Verifier
+}
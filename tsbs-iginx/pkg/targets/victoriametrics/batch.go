@@ -24,6 +24,16 @@ func (b *batch) Len() uint {
 	return uint(b.rows)
 }
 
+// Bytes implements targets.SizedBatch
+func (b *batch) Bytes() uint64 {
+	return uint64(b.buf.Len())
+}
+
+// AppendBytes implements targets.SizedBatch, the line is appended with a newline
+func (b *batch) AppendBytes(item data.LoadedPoint) uint64 {
+	return uint64(len(item.Data.([]byte)) + len(newLine))
+}
+
 func (b *batch) Append(item data.LoadedPoint) {
 	that := item.Data.([]byte)
 	b.rows++
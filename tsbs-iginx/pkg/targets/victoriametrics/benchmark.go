@@ -34,11 +34,11 @@ func NewBenchmark(vmSpecificConfig *SpecificConfig, dataSourceConfig *source.Dat
 		return nil, errors.New("only FILE data source type is supported for VictoriaMetrics")
 	}
 
-	br := load.GetBufferedReader(dataSourceConfig.File.Location)
+	ds := load.GetDataSource(dataSourceConfig.File.Location, func(br *bufio.Reader) targets.DataSource {
+		return &fileDataSource{scanner: bufio.NewScanner(br)}
+	})
 	return &benchmark{
-		dataSource: &fileDataSource{
-			scanner: bufio.NewScanner(br),
-		},
+		dataSource: ds,
 		serverURLs: vmSpecificConfig.ServerURLs,
 	}, nil
 }
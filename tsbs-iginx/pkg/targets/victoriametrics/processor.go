@@ -2,12 +2,20 @@ package victoriametrics
 
 import (
 	"bytes"
+	"fmt"
 	"github.com/timescale/tsbs/pkg/targets"
+	"io/ioutil"
 	"log"
 	"net/http"
 	"time"
 )
 
+// Backoff between the attempts of a batch the server failed to insert
+const (
+	initialServerBackoff = 10 * time.Millisecond
+	maxServerBackoff     = time.Second
+)
+
 type processor struct {
 	url    string
 	vmURLs []string
@@ -17,32 +25,43 @@ func (p *processor) Init(workerNum int, doLoad, hashWorkers bool) {
 	p.url = p.vmURLs[workerNum%len(p.vmURLs)]
 }
 
-func (p *processor) ProcessBatch(b targets.Batch, doLoad bool) (metricCount, rowCount uint64) {
+func (p *processor) ProcessBatch(b targets.Batch, doLoad bool) (metricCount, rowCount uint64, err error) {
 	batch := b.(*batch)
 	if !doLoad {
-		return batch.metrics, batch.rows
+		return batch.metrics, batch.rows, nil
 	}
-	mc, rc := p.do(batch)
-	return mc, rc
+	return p.do(batch)
 }
 
-func (p *processor) do(b *batch) (uint64, uint64) {
+// do sends the batch until the server accepts it. Server errors (5xx) are
+// transient and retried here with a growing backoff; any other unsuccessful
+// status is returned.
+func (p *processor) do(b *batch) (uint64, uint64, error) {
+	backoff := initialServerBackoff
 	for {
 		r := bytes.NewReader(b.buf.Bytes())
 		req, err := http.NewRequest("POST", p.url, r)
 		if err != nil {
-			log.Fatalf("error while creating new request: %s", err)
+			return 0, 0, fmt.Errorf("error while creating new request: %w", err)
 		}
 		resp, err := http.DefaultClient.Do(req)
 		if err != nil {
-			log.Fatalf("error while executing request: %s", err)
+			return 0, 0, fmt.Errorf("error while executing request: %w", err)
 		}
+		body, _ := ioutil.ReadAll(resp.Body)
 		resp.Body.Close()
 		if resp.StatusCode == http.StatusNoContent {
 			b.buf.Reset()
-			return b.metrics, b.rows
+			return b.metrics, b.rows, nil
+		}
+		if resp.StatusCode < http.StatusInternalServerError {
+			return 0, 0, targets.HTTPStatusError(resp.StatusCode, body)
 		}
 		log.Printf("server returned HTTP status %d. Retrying", resp.StatusCode)
-		time.Sleep(time.Millisecond * 10)
+		time.Sleep(backoff)
+		backoff *= 2
+		if backoff > maxServerBackoff {
+			backoff = maxServerBackoff
+		}
 	}
 }
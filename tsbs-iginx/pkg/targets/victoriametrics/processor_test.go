@@ -69,7 +69,10 @@ func TestProcessorProcessBatch(t *testing.T) {
 			const ignored = false
 			p.Init(1, ignored, ignored)
 			callsBefore := vm.getCalls()
-			metrics, rows := p.ProcessBatch(b, tc.doLoad)
+			metrics, rows, err := p.ProcessBatch(b, tc.doLoad)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
 			if metrics != tc.metrics {
 				t.Fatalf("expected %d metrics; got %d", tc.metrics, metrics)
 			}
@@ -87,20 +90,74 @@ func TestProcessorProcessBatch(t *testing.T) {
 	}
 }
 
+func TestProcessorProcessBatchStatus(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		statuses  []int
+		wantErr   bool
+		wantCalls uint64
+	}{
+		{
+			desc:      "server errors are retried",
+			statuses:  []int{http.StatusInternalServerError, http.StatusServiceUnavailable},
+			wantCalls: 3,
+		},
+		{
+			desc:      "rejected data is returned",
+			statuses:  []int{http.StatusBadRequest},
+			wantErr:   true,
+			wantCalls: 1,
+		},
+	}
+
+	f := &factory{bufPool: &sync.Pool{
+		New: func() interface{} {
+			return bytes.NewBuffer(make([]byte, 0, 1024))
+		},
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			vm := startFakeVMServer(t)
+			defer vm.server.Close()
+			vm.statuses = tc.statuses
+
+			b := f.New().(*batch)
+			b.Append(data.LoadedPoint{Data: []byte("tag1=tag1val col1=0.0 140")})
+			p := &processor{vmURLs: []string{vm.server.URL}}
+			p.Init(0, true, false)
+			metrics, _, err := p.ProcessBatch(b, true)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("unexpected error: got %v want error %v", err, tc.wantErr)
+			}
+			if !tc.wantErr && metrics != 1 {
+				t.Errorf("expected 1 metric; got %d", metrics)
+			}
+			if calls := vm.getCalls(); calls != tc.wantCalls {
+				t.Errorf("expected %d calls; got %d", tc.wantCalls, calls)
+			}
+		})
+	}
+}
+
 type fakeVMServer struct {
 	t      *testing.T
 	calls  uint64
 	server *httptest.Server
+	// statuses are returned to the first calls, later calls succeed
+	statuses []int
 }
 
-func (vm *fakeVMServer) incCalls()        { atomic.AddUint64(&vm.calls, 1) }
 func (vm *fakeVMServer) getCalls() uint64 { return atomic.LoadUint64(&vm.calls) }
 
 func (vm *fakeVMServer) handler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		vm.t.Fatalf("unexpected HTTP method %q", r.Method)
 	}
-	vm.incCalls()
+	n := atomic.AddUint64(&vm.calls, 1)
+	if n <= uint64(len(vm.statuses)) {
+		w.WriteHeader(vm.statuses[n-1])
+		return
+	}
 	w.WriteHeader(http.StatusNoContent)
 }
 